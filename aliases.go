@@ -0,0 +1,181 @@
+package doremid
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// noteAlias is an alternate spelling of the note with index note
+type noteAlias struct {
+	spelling string
+	note     int
+}
+
+// alias resolves the InputAliases of config against the notes of g
+func (g *Generator) alias(config Config) error {
+	if len(config.InputAliases) == 0 {
+		return nil
+	}
+
+	words := make([]string, 0, g.justIntonationLen+len(config.InputAliases))
+	for _, note := range g.justIntonationBytes {
+		words = append(words, string(note))
+	}
+	for _, spelling := range slices.Sorted(maps.Keys(config.InputAliases)) {
+		note, found := g.justIntonationMap[config.InputAliases[spelling]]
+		if !found {
+			return &ConfigError{Field: "InputAliases", Reason: fmt.Sprintf("%q is not a note", config.InputAliases[spelling])}
+		}
+		if spelling == "" || strings.ContainsFunc(spelling, unicode.IsSpace) {
+			return &ConfigError{Field: "InputAliases", Reason: fmt.Sprintf("alias %q must be non-empty without whitespace", spelling)}
+		}
+		if _, isNote := g.justIntonationMap[spelling]; isNote {
+			return &ConfigError{Field: "InputAliases", Reason: fmt.Sprintf("alias %q is a note itself", spelling)}
+		}
+		g.aliases = append(g.aliases, noteAlias{spelling: spelling, note: note})
+		words = append(words, spelling)
+	}
+
+	if err := validateGrouping(config, words, string(g.equalTemperamentBytes)); err != nil {
+		return err
+	}
+	if !uniquelyDecodable(words) {
+		return &ConfigError{Field: "InputAliases", Reason: "notes and aliases must parse unambiguously"}
+	}
+	return nil
+}
+
+// unalias applies Config.UnicodeNormalize to id, rewrites the aliases among
+// its notes to the notes they stand for, see Config.InputAliases, and with
+// Config.LenientCase spells the prefix, notes and characters of id in the
+// configured case. Group separators are kept. It returns id unchanged if there
+// is nothing to rewrite or it is malformed, leaving decode to report the problem.
+func (g *Generator) unalias(id string) string {
+	if g.normalize != nil {
+		id = g.normalize(id)
+	}
+	prefixed := strings.HasPrefix(id, g.prefix)
+	if !prefixed && g.lenientCase && len(id) >= len(g.prefix) && strings.EqualFold(id[:len(g.prefix)], g.prefix) {
+		id, prefixed = g.prefix+id[len(g.prefix):], true
+	}
+	if g.aliases == nil && !g.lenientCase || !prefixed {
+		return id
+	}
+
+	// Aliases may be prefixes of notes or the other way round, e.g. "sol" and
+	// "so", so the notes are matched depth first. failed memoizes the
+	// (note, offset) pairs from which no match completes the ID.
+	type span struct{ start, end, note int }
+	var spans []span
+	var failed map[[2]int]bool
+	var characters int
+	shortestRest, longestRest := g.restLengths()
+	spelled := func(offset int, word string) bool {
+		end := offset + len(word)
+		return end <= len(id) && (id[offset:end] == word || g.lenientCase && strings.EqualFold(id[offset:end], word))
+	}
+	var match func(i, offset int) bool
+	match = func(i, offset int) bool {
+		if i == g.JustIntonationDigits {
+			characters = offset + len(g.Separator)
+			rest := len(id) - characters
+			return strings.HasPrefix(id[offset:], g.Separator) && rest >= shortestRest && rest <= longestRest
+		}
+		if failed[[2]int{i, offset}] {
+			return false
+		}
+		if sep := g.noteGroupBreak(i); sep != "" && strings.HasPrefix(id[offset:], sep) {
+			offset += len(sep)
+		}
+		if _, width, found := g.nextNote(id, offset); found && match(i+1, offset+width) {
+			return true
+		}
+		if g.lenientCase {
+			for note, spelling := range g.justIntonationBytes {
+				end := offset + len(spelling)
+				if spelled(offset, string(spelling)) && id[offset:end] != string(spelling) && match(i+1, end) {
+					spans = append(spans, span{offset, end, note})
+					return true
+				}
+			}
+		}
+		for _, alias := range g.aliases {
+			if spelled(offset, alias.spelling) && match(i+1, offset+len(alias.spelling)) {
+				spans = append(spans, span{offset, offset + len(alias.spelling), alias.note})
+				return true
+			}
+		}
+		if failed == nil {
+			failed = make(map[[2]int]bool)
+		}
+		failed[[2]int{i, offset}] = true
+		return false
+	}
+	if !match(0, len(g.prefix)) || len(spans) == 0 && !g.lenientCase {
+		return id
+	}
+
+	// Spans were recorded from the last note back
+	b := make([]byte, 0, len(id))
+	copied := 0
+	for _, s := range slices.Backward(spans) {
+		b = append(b, id[copied:s.start]...)
+		b = append(b, g.justIntonationBytes[s.note]...)
+		copied = s.end
+	}
+	b = append(b, id[copied:characters]...)
+	for i := characters; i < len(id); i++ {
+		if g.lenientCase {
+			b = append(b, g.foldCharacter(id[i]))
+		} else {
+			b = append(b, id[i])
+		}
+	}
+	return string(b)
+}
+
+// restLengths returns the shortest and longest length of the part of an ID
+// after the separator, without and with group separators
+func (g *Generator) restLengths() (shortest, longest int) {
+	_, characters := g.groupOverhead()
+	shortest = g.EqualTemperamentDigits + g.checksumLen()
+	return shortest, shortest + characters
+}
+
+// uniquelyDecodable reports whether every concatenation of words splits into
+// words in one way only, using the Sardinas-Patterson test
+func uniquelyDecodable(words []string) bool {
+	seen := make(map[string]bool)
+	var queue []string
+	add := func(prefix, word string) {
+		if len(prefix) < len(word) && strings.HasPrefix(word, prefix) && !seen[word[len(prefix):]] {
+			seen[word[len(prefix):]] = true
+			queue = append(queue, word[len(prefix):])
+		}
+	}
+	for i, a := range words {
+		for j, b := range words {
+			if a == b && i != j {
+				return false
+			}
+			add(a, b)
+		}
+	}
+
+	// A dangling suffix that is a word itself completes two different splits
+	for len(queue) > 0 {
+		suffix := queue[0]
+		queue = queue[1:]
+		for _, word := range words {
+			if word == suffix {
+				return false
+			}
+			add(suffix, word)
+			add(word, suffix)
+		}
+	}
+	return true
+}
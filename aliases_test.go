@@ -0,0 +1,120 @@
+package doremid
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestInputAliases(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 3,
+		Separator:              "-",
+		InputAliases:           map[string]string{"sol": "so", "si": "ti"},
+	})
+
+	tests := []struct {
+		name     string
+		id       string
+		expected string
+	}{
+		{"canonical", "sola-1a2", "sola-1a2"},
+		{"alias before a note it prefixes", "solla-1a2", "sola-1a2"},
+		{"alias at the end", "dosol-1a2", "doso-1a2"},
+		{"two aliases", "solsi-000", "soti-000"},
+		{"alias and note", "siti-bbb", "titi-bbb"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pos, err := generator.IDToPositionE(tt.id)
+			if err != nil {
+				t.Fatalf("expected '%s' to parse, got %v", tt.id, err)
+			}
+			if id := generator.PositionToID(pos); id != tt.expected {
+				t.Errorf("expected '%s', got '%s'", tt.expected, id)
+			}
+			if compact, err := generator.Compact(tt.id); err != nil || compact != tt.expected {
+				t.Errorf("expected Compact to give '%s', got '%s' (err: %v)", tt.expected, compact, err)
+			}
+			if parsed, err := generator.Parse(tt.id); err != nil || parsed.Just != tt.expected[:len(tt.expected)-4] {
+				t.Errorf("expected the canonical notes of '%s', got %+v (err: %v)", tt.expected, parsed, err)
+			}
+		})
+	}
+
+	for _, id := range []string{"sollla-1a2", "sol-1a2", "solsolsol-1a2", "sil-1a2"} {
+		if err := generator.Validate(id); !errors.Is(err, ErrInvalidID) {
+			t.Errorf("expected '%s' to be invalid, got %v", id, err)
+		}
+	}
+	if positions, errs := generator.BatchIDToPositions([]string{"solla-1a2", "sola-1a2"}); errs != nil || positions[0] != positions[1] {
+		t.Errorf("expected both spellings to decode alike, got %v (errs: %v)", positions, errs)
+	}
+}
+
+func TestInputAliasesGrouped(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   4,
+		EqualTemperamentDigits: 2,
+		Separator:              "_",
+		GroupSize:              2,
+		NoteGroupSeparator:     ".",
+		Checksum:               true,
+		InputAliases:           map[string]string{"sol": "so"},
+	})
+
+	id := generator.PositionToID(4 * 7 * 7 * 7 * 144)
+	aliased := "sol" + id[2:]
+	if canonical, err := generator.Canonicalize(aliased); err != nil || canonical != id {
+		t.Errorf("expected '%s', got '%s' (err: %v)", id, canonical, err)
+	}
+	if big, err := generator.IDToPositionBig(aliased); err != nil || big.Int64() != generator.IDToPosition(id) {
+		t.Errorf("expected the position of '%s', got %v (err: %v)", id, big, err)
+	}
+}
+
+func TestInputAliasesConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		aliases map[string]string
+		field   string
+	}{
+		{"unknown note", map[string]string{"sol": "sow"}, "InputAliases"},
+		{"alias is a note", map[string]string{"re": "do"}, "InputAliases"},
+		{"empty alias", map[string]string{"": "do"}, "InputAliases"},
+		{"whitespace", map[string]string{"s l": "so"}, "InputAliases"},
+		{"ambiguous", map[string]string{"dore": "mi"}, "InputAliases"},
+		{"overlaps group separator", map[string]string{".so": "so"}, "NoteGroupSeparator"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewE(Config{JustIntonationDigits: 2, EqualTemperamentDigits: 2, GroupSize: 1, NoteGroupSeparator: ".", InputAliases: tt.aliases})
+			var configErr *ConfigError
+			if !errors.As(err, &configErr) || configErr.Field != tt.field {
+				t.Errorf("expected a %s ConfigError, got %v", tt.field, err)
+			}
+		})
+	}
+}
+
+func TestUniquelyDecodable(t *testing.T) {
+	tests := []struct {
+		words    []string
+		expected bool
+	}{
+		{[]string{"do", "re", "mi"}, true},
+		{[]string{"so", "la", "sol"}, true},
+		{[]string{"a", "ab", "b"}, false},
+		{[]string{"a", "a"}, false},
+		{[]string{"0", "01", "10"}, false},
+		{[]string{"0", "01", "11"}, true},
+	}
+
+	for _, tt := range tests {
+		if got := uniquelyDecodable(tt.words); got != tt.expected {
+			t.Errorf("expected %v for %v, got %v", tt.expected, tt.words, got)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package doremid
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultNotes are the just intonation syllables used when Config.Notes is empty
+const DefaultNotes = "do re mi fa so la ti"
+
+// DefaultCharacters is the equal temperament character set used when
+// Config.Characters is empty
+const DefaultCharacters = "0123456789ab"
+
+// BaseCharacters are the characters Config.EqualTemperamentBase draws from
+// when Config.Characters is empty, DefaultCharacters followed by the rest of the
+// lowercase alphabet
+const BaseCharacters = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+// NewE is like New but returns a *ConfigError instead of panicking if the
+// configured notes or characters are invalid, or too few characters are
+// configured for Checksum or Version, or MinCombinations cannot be met, or a
+// reserved ID or position is invalid
+func NewE(config Config) (*Generator, error) {
+	return newGenerator(config)
+}
+
+// ValidateSymbol checks that s is valid UTF-8 that starts with a new character,
+// so it cannot combine with whatever precedes it in an ID
+func ValidateSymbol(s string) error {
+	if !utf8.ValidString(s) {
+		return errors.New("is not valid UTF-8")
+	}
+	if r, _ := utf8.DecodeRuneInString(s); unicode.In(r, unicode.Mn, unicode.Me) {
+		return errors.New("starts with a combining character")
+	}
+	return nil
+}
+
+// combiningDiacritics are the marks that decompose accented letters. Notes may
+// not contain them, so "é" has one spelling without needing NFC tables
+var combiningDiacritics = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{Lo: 0x0300, Hi: 0x036f, Stride: 1},
+		{Lo: 0x1ab0, Hi: 0x1aff, Stride: 1},
+		{Lo: 0x1dc0, Hi: 0x1dff, Stride: 1},
+		{Lo: 0x20d0, Hi: 0x20ff, Stride: 1},
+		{Lo: 0xfe20, Hi: 0xfe2f, Stride: 1},
+	},
+}
+
+// validateAlphabet checks that custom notes, characters and prefixes can be
+// parsed back unambiguously
+func validateAlphabet(config Config) error {
+	if config.Prefix != "" && strings.Contains(config.Prefix, prefixSeparator(config)) {
+		return &ConfigError{Field: "Prefix", Reason: fmt.Sprintf("must not contain the prefix separator %q", prefixSeparator(config))}
+	}
+
+	if config.Notes != "" {
+		notes := strings.Fields(config.Notes)
+		if len(notes) < 2 {
+			return &ConfigError{Field: "Notes", Reason: "must contain at least 2 notes"}
+		}
+		for i, note := range notes {
+			if err := ValidateSymbol(note); err != nil {
+				return &ConfigError{Field: "Notes", Reason: fmt.Sprintf("note %q %v", note, err)}
+			}
+			if strings.ContainsFunc(note, func(r rune) bool { return unicode.Is(combiningDiacritics, r) }) {
+				return &ConfigError{Field: "Notes", Reason: fmt.Sprintf("note %q is not in composed form", note)}
+			}
+
+			// Notes are parsed greedily, so no note may start another one
+			for j, other := range notes {
+				if i != j && note == other {
+					return &ConfigError{Field: "Notes", Reason: fmt.Sprintf("duplicate note %q", note)}
+				}
+				if i != j && strings.HasPrefix(other, note) {
+					return &ConfigError{Field: "Notes", Reason: fmt.Sprintf("%q is a prefix of %q", note, other)}
+				}
+			}
+		}
+	}
+
+	if config.Characters != "" {
+		if len(config.Characters) < 2 {
+			return &ConfigError{Field: "Characters", Reason: "must contain at least 2 characters"}
+		}
+		for i := 0; i < len(config.Characters); i++ {
+			if config.Characters[i] >= 0x80 {
+				return &ConfigError{Field: "Characters", Reason: "must be ASCII"}
+			}
+			if strings.IndexByte(config.Characters[:i], config.Characters[i]) >= 0 {
+				return &ConfigError{Field: "Characters", Reason: fmt.Sprintf("duplicate character %q", config.Characters[i])}
+			}
+		}
+	}
+
+	notes, characters := strings.Fields(config.Notes), config.Characters
+	if len(notes) == 0 {
+		notes = strings.Fields(DefaultNotes)
+	}
+	if characters == "" {
+		characters = DefaultCharacters
+	}
+	if err := validateGrouping(config, notes, characters); err != nil {
+		return err
+	}
+	if config.Version < 0 || config.Version >= len(characters) {
+		return &ConfigError{Field: "Version", Reason: fmt.Sprintf("must be between 0 and %d", len(characters)-1)}
+	}
+
+	// Luhn mod N only detects substitutions of symbol values below N
+	if config.Checksum {
+		notes, characters := len(strings.Fields(config.Notes)), len(config.Characters)
+		if notes == 0 {
+			notes = len(strings.Fields(DefaultNotes))
+		}
+		if characters == 0 {
+			characters = len(DefaultCharacters)
+		}
+		if notes > characters {
+			return &ConfigError{Field: "Checksum", Reason: "requires at least as many characters as notes"}
+		}
+	}
+	return nil
+}
+
+// baseCharacters returns the characters of config, the first
+// EqualTemperamentBase of BaseCharacters if only the base is set
+func baseCharacters(config Config) (string, error) {
+	base := config.EqualTemperamentBase
+	switch {
+	case base == 0 || config.Characters != "" && len(config.Characters) == base:
+		return config.Characters, nil
+	case config.Characters != "":
+		return "", &ConfigError{Field: "EqualTemperamentBase", Reason: fmt.Sprintf("must match the %d Characters", len(config.Characters))}
+	case base < 2 || base > len(BaseCharacters):
+		return "", &ConfigError{Field: "EqualTemperamentBase", Reason: fmt.Sprintf("must be between 2 and %d without Characters", len(BaseCharacters))}
+	}
+	return BaseCharacters[:base], nil
+}
+
+// nextNote finds the note starting at offset in s.
+// Notes are prefix-free, so at most one matches.
+func (g *Generator) nextNote(s string, offset int) (index, width int, found bool) {
+	for width := g.minNoteLen; width <= g.maxNoteLen && offset+width <= len(s); width++ {
+		if index, found := g.justIntonationMap[s[offset:offset+width]]; found {
+			return index, width, true
+		}
+	}
+	return 0, 0, false
+}
+
+// idLengths returns the shortest and longest length of an ID, compact or
+// grouped. They are equal unless custom notes of different lengths or grouping
+// are configured.
+func (g *Generator) idLengths() (shortest, longest int) {
+	shortest, longest = g.compactLengths()
+	notes, characters := g.groupOverhead()
+	return shortest, longest + notes + characters
+}
+
+// compactLengths returns the shortest and longest length of an ID without
+// group separators
+func (g *Generator) compactLengths() (shortest, longest int) {
+	rest := len(g.prefix) + len(g.Separator) + g.EqualTemperamentDigits + g.checksumLen()
+	return g.JustIntonationDigits*g.minNoteLen + rest, g.JustIntonationDigits*g.maxNoteLen + rest
+}
+
+// compactRuneLengths is like compactLengths but counts runes, i.e. symbols,
+// rather than bytes
+func (g *Generator) compactRuneLengths() (shortest, longest int) {
+	minNote, maxNote := g.maxNoteLen, 0
+	for _, note := range g.justIntonationBytes {
+		minNote = min(minNote, utf8.RuneCount(note))
+		maxNote = max(maxNote, utf8.RuneCount(note))
+	}
+	rest := utf8.RuneCountInString(g.prefix+g.Separator) + g.EqualTemperamentDigits + g.checksumLen()
+	return g.JustIntonationDigits*minNote + rest, g.JustIntonationDigits*maxNote + rest
+}
+
+// Sortable reports whether IDs sort lexicographically in position order. This
+// requires notes and characters each listed in ascending byte order, e.g. Notes
+// "do fa la mi re so ti" or Config.LexSortable; the default notes are not.
+func (g *Generator) Sortable() bool {
+	for i := 1; i < g.justIntonationLen; i++ {
+		if bytes.Compare(g.justIntonationBytes[i-1], g.justIntonationBytes[i]) >= 0 {
+			return false
+		}
+	}
+	for i := 1; i < g.equalTemperamentLen; i++ {
+		if g.equalTemperamentBytes[i-1] >= g.equalTemperamentBytes[i] {
+			return false
+		}
+	}
+	return true
+}
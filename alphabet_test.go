@@ -0,0 +1,283 @@
+package doremid
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"strings"
+	"testing"
+	"testing/quick"
+)
+
+func TestCustomAlphabet(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 2,
+		Separator:              "-",
+		Notes:                  "ut re mi fa sol la si",
+		Characters:             "0123456789ABCDEF",
+	})
+
+	if max := generator.MaxCombinations(); max != 7*7*16*16 {
+		t.Errorf("expected %d combinations, got %d", 7*7*16*16, max)
+	}
+
+	tests := []struct {
+		position int64
+		id       string
+	}{
+		{0, "utut-00"},
+		{255, "utut-FF"},
+		{256, "utre-00"},
+		{4*256 + 0xA3, "utsol-A3"},
+		{4*7*256 + 4*256 + 0x10, "solsol-10"},
+		{generator.MaxCombinations() - 1, "sisi-FF"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			if id := generator.PositionToID(tt.position); id != tt.id {
+				t.Errorf("expected '%s', got '%s'", tt.id, id)
+			}
+			if pos := generator.IDToPosition(tt.id); pos != tt.position {
+				t.Errorf("expected position %d, got %d", tt.position, pos)
+			}
+		})
+	}
+
+	for i := 0; i < 100; i++ {
+		id := generator.NewID()
+		if pos := generator.IDToPosition(id); pos < 0 || generator.PositionToID(pos) != id {
+			t.Fatalf("random ID '%s' does not round-trip", id)
+		}
+	}
+
+	invalid := []string{"utut-ab", "dodo-00", "utso-00", "solsol00", "solsol-000", "ut-00"}
+	for _, id := range invalid {
+		if _, err := generator.IDToPositionE(id); !errors.Is(err, ErrInvalidID) {
+			t.Errorf("expected '%s' to be rejected, got %v", id, err)
+		}
+	}
+}
+
+func TestEqualTemperamentBase(t *testing.T) {
+	tests := []struct {
+		name       string
+		config     Config
+		characters string
+		last       string
+	}{
+		{"binary", Config{EqualTemperamentBase: 2}, "01", "titi-111"},
+		{"hex", Config{EqualTemperamentBase: 16}, "0123456789abcdef", "titi-fff"},
+		{"quarter tones", Config{EqualTemperamentBase: 24}, BaseCharacters[:24], "titi-nnn"},
+		{"31-EDO", Config{EqualTemperamentBase: 31}, BaseCharacters[:31], "titi-uuu"},
+		{"36", Config{EqualTemperamentBase: 36}, BaseCharacters, "titi-zzz"},
+		{"with characters", Config{EqualTemperamentBase: 16, Characters: "0123456789ABCDEF"}, "0123456789ABCDEF", "titi-FFF"},
+		{"default", Config{}, DefaultCharacters, "titi-bbb"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.JustIntonationDigits, tt.config.EqualTemperamentDigits, tt.config.Separator = 2, 3, "-"
+			generator := New(tt.config)
+
+			base := int64(len(tt.characters))
+			if max := generator.MaxCombinations(); max != 49*base*base*base {
+				t.Errorf("expected %d combinations, got %d", 49*base*base*base, max)
+			}
+			if alphabet := strings.Join(generator.Debug().EqualTemperamentAlphabet, ""); alphabet != tt.characters {
+				t.Errorf("expected characters '%s', got '%s'", tt.characters, alphabet)
+			}
+			if id := generator.PositionToID(generator.MaxCombinations() - 1); id != tt.last {
+				t.Errorf("expected '%s', got '%s'", tt.last, id)
+			}
+			for _, position := range []int64{0, base - 1, base * base * base, generator.MaxCombinations() - 1} {
+				id := generator.PositionToID(position)
+				if pos := generator.IDToPosition(id); pos != position {
+					t.Errorf("expected '%s' to parse to %d, got %d", id, position, pos)
+				}
+			}
+		})
+	}
+
+	// A base of 16 counts in hex
+	generator := New(Config{JustIntonationDigits: 1, EqualTemperamentDigits: 2, Separator: "-", EqualTemperamentBase: 16})
+	if id := generator.PositionToID(0xa7); id != "do-a7" {
+		t.Errorf("expected 'do-a7', got '%s'", id)
+	}
+	if _, err := generator.IDToPositionE("do-g0"); !errors.Is(err, ErrBadCharacter) {
+		t.Errorf("expected ErrBadCharacter for a character beyond the base, got %v", err)
+	}
+}
+
+func TestCustomAlphabetLookups(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 1,
+		Separator:              "-",
+		Notes:                  "do re mi fa sol la ti",
+	})
+
+	lower, upper, err := generator.PrefixKeyRange("sol")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedLower, expectedUpper := generator.KeyRange(4*7*12, 5*7*12)
+	if !bytes.Equal(lower, expectedLower) || !bytes.Equal(upper, expectedUpper) {
+		t.Errorf("expected keys %x-%x, got %x-%x", expectedLower, expectedUpper, lower, upper)
+	}
+	if _, _, err := generator.PrefixKeyRange("dododo"); !errors.Is(err, ErrInvalidFormat) {
+		t.Errorf("expected ErrInvalidFormat for a prefix of 3 notes, got %v", err)
+	}
+
+	frozen := generator.Freeze()
+	if frozen.MaxInputLength() != 8 {
+		t.Errorf("expected max input length 8, got %d", frozen.MaxInputLength())
+	}
+	if pos := frozen.IDToPosition("dosol-5"); pos != 4*12+5 {
+		t.Errorf("expected position %d, got %d", 4*12+5, pos)
+	}
+
+	if layout := generator.Debug().Layout; layout[0].Length != -1 || layout[2].Offset != -1 {
+		t.Errorf("expected variable layout, got %+v", layout)
+	}
+}
+
+func TestCustomAlphabetValidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+		field  string
+	}{
+		{"prefix", Config{Notes: "do re mi fa so sol la ti"}, "Notes"},
+		{"duplicate note", Config{Notes: "do re do"}, "Notes"},
+		{"single note", Config{Notes: "do"}, "Notes"},
+		{"blank notes", Config{Notes: "   "}, "Notes"},
+		{"invalid UTF-8 note", Config{Notes: "do \xff\xfe mi"}, "Notes"},
+		{"decomposed note", Config{Notes: "do re\u0301 mi"}, "Notes"},
+		{"leading combining mark", Config{Notes: "do \u0301re mi"}, "Notes"},
+		{"duplicate character", Config{Characters: "0123401"}, "Characters"},
+		{"single character", Config{Characters: "0"}, "Characters"},
+		{"non-ASCII character", Config{Characters: "01é"}, "Characters"},
+		{"base of one", Config{EqualTemperamentBase: 1}, "EqualTemperamentBase"},
+		{"base too large", Config{EqualTemperamentBase: 37}, "EqualTemperamentBase"},
+		{"negative base", Config{EqualTemperamentBase: -12}, "EqualTemperamentBase"},
+		{"base and characters differ", Config{EqualTemperamentBase: 12, Characters: "0123456789ABCDEF"}, "EqualTemperamentBase"},
+		{"base too small for checksum", Config{EqualTemperamentBase: 6, Checksum: true}, "Checksum"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewE(tt.config)
+			var configErr *ConfigError
+			if !errors.As(err, &configErr) || configErr.Field != tt.field {
+				t.Errorf("expected ConfigError for %s, got %v", tt.field, err)
+			}
+
+			defer func() {
+				if recover() == nil {
+					t.Error("expected New to panic")
+				}
+			}()
+			New(tt.config)
+		})
+	}
+
+	for _, notes := range []string{"ut re mi", "do r\u00e9 mi"} {
+		if _, err := NewE(Config{JustIntonationDigits: 1, EqualTemperamentDigits: 1, Notes: notes}); err != nil {
+			t.Errorf("expected valid config for %q, got %v", notes, err)
+		}
+	}
+}
+
+func TestLexSortable(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+	}{
+		{"default", Config{JustIntonationDigits: 4, EqualTemperamentDigits: 5, Separator: "-"}},
+		{"checksum and prefix", Config{JustIntonationDigits: 3, EqualTemperamentDigits: 4, Separator: "-", Checksum: true, Prefix: "ord"}},
+		{"grouped", Config{JustIntonationDigits: 4, EqualTemperamentDigits: 6, Separator: "_", GroupSize: 2, NoteGroupSeparator: "-", CharacterGroupSeparator: "."}},
+		{"notes of different lengths", Config{JustIntonationDigits: 5, EqualTemperamentDigits: 3, Separator: "-", Notes: "sol ut re mi fa la si"}},
+		{"unsorted characters", Config{JustIntonationDigits: 2, EqualTemperamentDigits: 6, Separator: "-", Characters: "zyxwvu9876"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.LexSortable = true
+			generator := New(tt.config)
+			if !generator.Sortable() {
+				t.Fatal("expected a LexSortable generator to be Sortable")
+			}
+
+			space := uint64(generator.MaxCombinations())
+			property := func(a, b uint64) bool {
+				x, y := int64(a%space), int64(b%space)
+				return strings.Compare(generator.PositionToID(x), generator.PositionToID(y)) == cmp.Compare(x, y)
+			}
+			if err := quick.Check(property, &quick.Config{MaxCount: 2000}); err != nil {
+				t.Error(err)
+			}
+
+			// Neighbours, including across every digit boundary
+			for _, pos := range []int64{0, 1, 11, 12, 143, 144, int64(space) / 2, int64(space) - 2} {
+				if a, b := generator.PositionToID(pos), generator.PositionToID(pos+1); a >= b {
+					t.Errorf("expected '%s' < '%s' for positions %d and %d", a, b, pos, pos+1)
+				}
+			}
+		})
+	}
+}
+
+func TestMultiByteAlphabet(t *testing.T) {
+	// Notes do and mi, or their Japanese spellings, then characters 1a2
+	const position = 2*1728 + 1*144 + 10*12 + 2
+
+	tests := []struct {
+		name   string
+		config Config
+		id     string
+	}{
+		{"separator", Config{Separator: "·"}, "domi·1a2"},
+		{"notes", Config{Notes: "ド レ ミ ファ ソ ラ シ", Separator: "-"}, "ドミ-1a2"},
+		{"notes and separator", Config{Notes: "ド レ ミ ファ ソ ラ シ", Separator: "・"}, "ドミ・1a2"},
+		{"prefix", Config{Prefix: "日本", PrefixSeparator: "·", Separator: "·"}, "日本·domi·1a2"},
+		{"checksum", Config{Separator: "→", Checksum: true}, ""},
+		{"grouping", Config{Separator: "·", GroupSize: 1, NoteGroupSeparator: "‿", CharacterGroupSeparator: "•"}, "do‿mi·1•a•2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.JustIntonationDigits, tt.config.EqualTemperamentDigits = 2, 3
+			generator := New(tt.config)
+
+			if tt.id != "" {
+				if id := generator.PositionToID(position); id != tt.id {
+					t.Errorf("expected '%s', got '%s'", tt.id, id)
+				}
+			}
+
+			for _, position := range []int64{0, 1, position, generator.MaxCombinations() - 1} {
+				id := generator.PositionToID(position)
+				if pos, err := generator.IDToPositionE(id); err != nil || pos != position {
+					t.Errorf("expected '%s' to parse to %d, got %d (%v)", id, position, pos, err)
+				}
+				if canonical, err := generator.Canonicalize(id); err != nil || canonical != id {
+					t.Errorf("expected '%s' to be canonical, got '%s' (%v)", id, canonical, err)
+				}
+				delimited, err := generator.Delimited(id)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if ids := generator.ExtractIDs("see " + delimited + "."); len(ids) != 1 || ids[0] != id {
+					t.Errorf("expected to extract '%s' from '%s', got %v", id, delimited, ids)
+				}
+			}
+
+			positions, errs := generator.BatchIDToPositions([]string{generator.PositionToID(7), "dodo-000"})
+			if positions[0] != 7 || errs[0] != nil || errs[1] == nil {
+				t.Errorf("expected position 7 and an error, got %v and %v", positions, errs)
+			}
+		})
+	}
+}
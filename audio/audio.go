@@ -0,0 +1,193 @@
+// Package audio renders the melody of a DoReMi ID as sound, for checking IDs by
+// ear.
+//
+// Notes are tuned in just intonation above the base pitch, do re mi fa so la ti
+// at ratios 1, 9/8, 5/4, 4/3, 3/2, 5/3 and 15/8, and characters in twelve-tone
+// equal temperament, 0 to b as C to B. Custom alphabets of other sizes divide the
+// octave equally. The separator is a rest and any check character is silent.
+// IDToPitches returns the same frequencies for other uses, e.g. audio
+// fingerprints.
+package audio
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/doremi-id/doremid"
+)
+
+// Defaults used when the corresponding Options field is zero
+const (
+	DefaultBase         = 261.63 // Middle C in Hz
+	DefaultA4           = 440.0  // Concert pitch in Hz
+	DefaultNoteDuration = 300 * time.Millisecond
+	DefaultSampleRate   = 44100
+)
+
+// justRatios tunes the seven default notes
+var justRatios = []float64{1, 9.0 / 8, 5.0 / 4, 4.0 / 3, 3.0 / 2, 5.0 / 3, 15.0 / 8}
+
+// majorSemitones places the seven default notes on the major scale
+var majorSemitones = []float64{0, 2, 4, 5, 7, 9, 11}
+
+// scaleDegrees gives the degree of each solfège syllable, so notes keep their
+// pitch when Config.LexSortable or Config.Case reorders or respells them
+var scaleDegrees = map[string]int{
+	"do": 0, "ut": 0, "re": 1, "mi": 2, "fa": 3, "so": 4, "sol": 4, "la": 5, "ti": 6, "si": 6,
+}
+
+// Tuning selects how the seven default notes are tuned. Notes of custom
+// alphabets of other sizes and characters always divide the octave equally.
+type Tuning int
+
+const (
+	// JustIntonation tunes the notes at the ratios 1, 9/8, 5/4, 4/3, 3/2, 5/3
+	// and 15/8 above the base pitch
+	JustIntonation Tuning = iota
+
+	// EqualTemperament tunes the notes on the major scale of twelve-tone equal
+	// temperament, as on a piano
+	EqualTemperament
+)
+
+// Options configures rendering
+type Options struct {
+	Base         float64       // Pitch of do and 0 in Hz, zero for the C below A4
+	A4           float64       // Reference pitch of A4 in Hz when Base is zero
+	Tuning       Tuning        // Tuning of the notes
+	NoteDuration time.Duration // Length of each note, character and rest
+	SampleRate   int           // Samples per second of rendered audio
+}
+
+// Tone is one step of a melody
+type Tone struct {
+	Frequency float64 // Pitch in Hz, zero for a rest
+	Duration  time.Duration
+}
+
+// Melody returns the tones of id: one per note, a rest for the separator if
+// there is one, then one per character.
+// Returns a *doremid.FormatError if id is invalid.
+func Melody(g *doremid.Generator, id string, options Options) ([]Tone, error) {
+	pitches, err := IDToPitches(g, id, options)
+	if err != nil {
+		return nil, err
+	}
+	options = options.withDefaults()
+
+	tones := make([]Tone, 0, len(pitches)+1)
+	for i, pitch := range pitches {
+		if i == g.JustIntonationDigits && g.Separator != "" {
+			tones = append(tones, Tone{Duration: options.NoteDuration})
+		}
+		tones = append(tones, Tone{Frequency: pitch, Duration: options.NoteDuration})
+	}
+	return tones, nil
+}
+
+// IDToPitches returns the frequencies in Hz of the notes and then the characters
+// of id, without the separator rest or check character, as tuned by
+// options.Base or options.A4 and options.Tuning. Seven notes named by solfège
+// syllables are tuned by name, in whatever order the generator lists them.
+// Returns a *doremid.FormatError if id is invalid.
+func IDToPitches(g *doremid.Generator, id string, options Options) ([]float64, error) {
+	digits, err := g.Digits(id)
+	if err != nil {
+		return nil, err
+	}
+	options = options.withDefaults()
+	info := g.Debug()
+	notes, characters := len(info.JustIntonationAlphabet), len(info.EqualTemperamentAlphabet)
+
+	pitches := make([]float64, len(digits))
+	for i, digit := range digits {
+		if i < g.JustIntonationDigits && notes == len(justRatios) {
+			if degree, found := scaleDegrees[strings.ToLower(info.JustIntonationAlphabet[digit])]; found {
+				digit = degree
+			}
+		}
+		var ratio float64
+		switch {
+		case i < g.JustIntonationDigits && notes == len(justRatios) && options.Tuning == EqualTemperament:
+			ratio = math.Pow(2, majorSemitones[digit]/12)
+		case i < g.JustIntonationDigits && notes == len(justRatios):
+			ratio = justRatios[digit]
+		case i < g.JustIntonationDigits:
+			ratio = math.Pow(2, float64(digit)/float64(notes))
+		default:
+			ratio = math.Pow(2, float64(digit)/float64(characters))
+		}
+		pitches[i] = options.Base * ratio
+	}
+	return pitches, nil
+}
+
+// WriteWAV writes tones as a 16-bit mono PCM WAV file. Each tone fades in and
+// out so consecutive notes of the same pitch stay distinct.
+func WriteWAV(w io.Writer, tones []Tone, options Options) error {
+	options = options.withDefaults()
+	rate := float64(options.SampleRate)
+
+	var samples []int16
+	for _, tone := range tones {
+		n := int(tone.Duration.Seconds() * rate)
+		fade := max(n/10, 1)
+		for i := range n {
+			if tone.Frequency == 0 {
+				samples = append(samples, 0)
+				continue
+			}
+			envelope := min(float64(i)/float64(fade), float64(n-i)/float64(fade), 1)
+			value := math.Sin(2*math.Pi*tone.Frequency*float64(i)/rate) * envelope * 0.5
+			samples = append(samples, int16(value*math.MaxInt16))
+		}
+	}
+
+	dataSize := uint32(len(samples) * 2)
+	header := struct {
+		RIFF          [4]byte
+		Size          uint32
+		WAVE          [4]byte
+		Fmt           [4]byte
+		FmtSize       uint32
+		Format        uint16
+		Channels      uint16
+		SampleRate    uint32
+		ByteRate      uint32
+		BlockAlign    uint16
+		BitsPerSample uint16
+		Data          [4]byte
+		DataSize      uint32
+	}{
+		RIFF: [4]byte{'R', 'I', 'F', 'F'}, Size: 36 + dataSize, WAVE: [4]byte{'W', 'A', 'V', 'E'},
+		Fmt: [4]byte{'f', 'm', 't', ' '}, FmtSize: 16, Format: 1, Channels: 1,
+		SampleRate: uint32(options.SampleRate), ByteRate: uint32(options.SampleRate * 2), BlockAlign: 2, BitsPerSample: 16,
+		Data: [4]byte{'d', 'a', 't', 'a'}, DataSize: dataSize,
+	}
+	if err := binary.Write(w, binary.LittleEndian, header); err != nil {
+		return err
+	}
+	return binary.Write(w, binary.LittleEndian, samples)
+}
+
+// withDefaults fills in zero fields
+func (o Options) withDefaults() Options {
+	switch {
+	case o.Base > 0:
+	case o.A4 > 0:
+		// C is nine semitones below A
+		o.Base = o.A4 * math.Pow(2, -9.0/12)
+	default:
+		o.Base = DefaultBase
+	}
+	if o.NoteDuration <= 0 {
+		o.NoteDuration = DefaultNoteDuration
+	}
+	if o.SampleRate <= 0 {
+		o.SampleRate = DefaultSampleRate
+	}
+	return o
+}
@@ -0,0 +1,125 @@
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/doremi-id/doremid"
+)
+
+func TestMelody(t *testing.T) {
+	generator := doremid.New(doremid.Config{JustIntonationDigits: 2, EqualTemperamentDigits: 2, Separator: "-", Checksum: true})
+	id := generator.PositionToID(2*7*144 + 4*144 + 9*12 + 11) // "miso-9b" with its check character
+
+	tones, err := Melody(generator, id, Options{Base: 100, NoteDuration: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []float64{125, 150, 0, 100 * math.Pow(2, 9.0/12), 100 * math.Pow(2, 11.0/12)}
+	if len(tones) != len(expected) {
+		t.Fatalf("expected %d tones, got %v", len(expected), tones)
+	}
+	for i, tone := range tones {
+		if math.Abs(tone.Frequency-expected[i]) > 1e-9 || tone.Duration != time.Second {
+			t.Errorf("tone %d: expected %.2f Hz for 1s, got %.2f Hz for %v", i, expected[i], tone.Frequency, tone.Duration)
+		}
+	}
+
+	custom := doremid.New(doremid.Config{JustIntonationDigits: 1, EqualTemperamentDigits: 1, Notes: "lo hi", Characters: "ab"})
+	tones, _ = Melody(custom, "hib", Options{})
+	if len(tones) != 2 || math.Abs(tones[0].Frequency-DefaultBase*math.Sqrt2) > 1e-9 || tones[1].Duration != DefaultNoteDuration {
+		t.Errorf("expected octave divided equally without a rest, got %v", tones)
+	}
+
+	if _, err := Melody(generator, "miso-9b", Options{}); !errors.Is(err, doremid.ErrInvalidID) {
+		t.Errorf("expected ErrInvalidID, got %v", err)
+	}
+}
+
+func TestIDToPitches(t *testing.T) {
+	generator := doremid.New(doremid.Config{JustIntonationDigits: 2, EqualTemperamentDigits: 2, Separator: "-", Checksum: true})
+	id := generator.PositionToID(2*7*144 + 4*144 + 9*12 + 11) // "miso-9b" with its check character
+	middleC := 440 * math.Pow(2, -9.0/12)
+
+	tests := []struct {
+		name     string
+		options  Options
+		expected []float64
+	}{
+		{"base", Options{Base: 100}, []float64{125, 150, 100 * math.Pow(2, 9.0/12), 100 * math.Pow(2, 11.0/12)}},
+		{"A4", Options{A4: 440}, []float64{middleC * 5 / 4, middleC * 3 / 2, 440, middleC * math.Pow(2, 11.0/12)}},
+		{"baroque A4", Options{A4: 415}, []float64{415 * math.Pow(2, -9.0/12) * 5 / 4, 415 * math.Pow(2, -9.0/12) * 3 / 2, 415, 415 * math.Pow(2, 2.0/12)}},
+		{"equal temperament", Options{A4: 440, Tuning: EqualTemperament}, []float64{middleC * math.Pow(2, 4.0/12), middleC * math.Pow(2, 7.0/12), 440, middleC * math.Pow(2, 11.0/12)}},
+		{"base wins over A4", Options{Base: 100, A4: 440}, []float64{125, 150, 100 * math.Pow(2, 9.0/12), 100 * math.Pow(2, 11.0/12)}},
+		{"defaults", Options{}, []float64{DefaultBase * 5 / 4, DefaultBase * 3 / 2, DefaultBase * math.Pow(2, 9.0/12), DefaultBase * math.Pow(2, 11.0/12)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pitches, err := IDToPitches(generator, id, tt.options)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(pitches) != len(tt.expected) {
+				t.Fatalf("expected %d pitches, got %v", len(tt.expected), pitches)
+			}
+			for i, pitch := range pitches {
+				if math.Abs(pitch-tt.expected[i]) > 1e-9 {
+					t.Errorf("pitch %d: expected %.4f Hz, got %.4f Hz", i, tt.expected[i], pitch)
+				}
+			}
+		})
+	}
+
+	// Notes keep their pitch when sorted or recased, e.g. fa at 4/3
+	for name, config := range map[string]doremid.Config{
+		"sorted":  {LexSortable: true},
+		"recased": {Case: doremid.UpperCase, LenientCase: true},
+	} {
+		config.JustIntonationDigits, config.EqualTemperamentDigits, config.Separator = 2, 1, "-"
+		g := doremid.New(config)
+		id, err := g.Canonicalize("fati-0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		pitches, err := IDToPitches(g, id, Options{Base: 261.63})
+		if err != nil || math.Abs(pitches[0]-348.84) > 0.01 || math.Abs(pitches[1]-261.63*15/8) > 1e-9 {
+			t.Errorf("expected fa at 348.84 Hz and ti at 490.56 Hz %s, got %v (err: %v)", name, pitches, err)
+		}
+	}
+
+	if _, err := IDToPitches(generator, "miso-9b", Options{}); !errors.Is(err, doremid.ErrInvalidID) {
+		t.Errorf("expected ErrInvalidID, got %v", err)
+	}
+}
+
+func TestWriteWAV(t *testing.T) {
+	var buf bytes.Buffer
+	tones := []Tone{{Frequency: 440, Duration: 100 * time.Millisecond}, {Duration: 50 * time.Millisecond}}
+	if err := WriteWAV(&buf, tones, Options{SampleRate: 8000}); err != nil {
+		t.Fatal(err)
+	}
+
+	data := buf.Bytes()
+	if string(data[0:4]) != "RIFF" || string(data[8:12]) != "WAVE" || string(data[36:40]) != "data" {
+		t.Fatalf("expected WAV header, got %q", data[:44])
+	}
+	samples := binary.LittleEndian.Uint32(data[40:44]) / 2
+	if samples != 1200 || len(data) != 44+2400 {
+		t.Errorf("expected 1200 samples, got %d in %d bytes", samples, len(data))
+	}
+	if rate := binary.LittleEndian.Uint32(data[24:28]); rate != 8000 {
+		t.Errorf("expected sample rate 8000, got %d", rate)
+	}
+
+	// The tone fades in from silence and the rest is silent
+	first := int16(binary.LittleEndian.Uint16(data[44:46]))
+	last := int16(binary.LittleEndian.Uint16(data[len(data)-2:]))
+	if first != 0 || last != 0 {
+		t.Errorf("expected silent edges, got %d and %d", first, last)
+	}
+}
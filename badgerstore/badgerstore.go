@@ -0,0 +1,270 @@
+// Package badgerstore implements the doremid Registry, Allocator and CounterStore interfaces on
+// top of BadgerDB, giving single-binary services durable deduplication and
+// counters without an external database.
+//
+// It lives in its own module so that the core doremid package stays free of
+// third-party dependencies.
+package badgerstore
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+
+	"github.com/dgraph-io/badger/v4"
+	"github.com/doremi-id/doremid"
+)
+
+// Key prefixes separating registry entries, allocator counters and named counters
+var (
+	registryPrefix  = []byte("doremid/registry/")
+	allocatorPrefix = []byte("doremid/allocator/")
+	counterPrefix   = []byte("doremid/counter/")
+)
+
+// tombstoneValue marks a registry entry as retired
+var tombstoneValue = []byte{1}
+
+// Store is a Badger-backed doremid.Registry, doremid.Tombstoner and doremid.CounterStore
+// that publishes change notifications. Allocators sharing the same database are created with Allocator.
+// It is safe for concurrent use.
+type Store struct {
+	doremid.ChangeNotifier
+
+	db *badger.DB
+}
+
+// Open opens (or creates) a Badger database at dir
+func Open(dir string) (*Store, error) {
+	db, err := badger.Open(badger.DefaultOptions(dir).WithLogger(nil))
+	if err != nil {
+		return nil, err
+	}
+	return &Store{db: db}, nil
+}
+
+// New wraps an already opened Badger database. The caller keeps ownership of db.
+func New(db *badger.DB) *Store {
+	return &Store{db: db}
+}
+
+// Close closes the underlying database
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Register marks position as issued.
+// Returns false if the position was already registered.
+func (s *Store) Register(ctx context.Context, position int64) (bool, error) {
+	key := registryKey(position)
+	registered := false
+
+	err := s.update(ctx, func(txn *badger.Txn) error {
+		_, err := txn.Get(key)
+		if err == nil {
+			registered = false
+			return nil
+		}
+		if !errors.Is(err, badger.ErrKeyNotFound) {
+			return err
+		}
+		registered = true
+		return txn.Set(key, nil)
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if registered {
+		s.Notify(doremid.Change{Kind: doremid.ChangeRegistered, Position: position, Target: -1})
+	}
+	return registered, nil
+}
+
+// Tombstone retires position, registering it first if necessary
+func (s *Store) Tombstone(ctx context.Context, position int64) error {
+	key := registryKey(position)
+	changed := false
+
+	err := s.update(ctx, func(txn *badger.Txn) error {
+		item, err := txn.Get(key)
+		if err == nil && item.ValueSize() > 0 {
+			changed = false
+			return nil
+		}
+		if err != nil && !errors.Is(err, badger.ErrKeyNotFound) {
+			return err
+		}
+		changed = true
+		return txn.Set(key, tombstoneValue)
+	})
+	if err != nil {
+		return err
+	}
+
+	if changed {
+		s.Notify(doremid.Change{Kind: doremid.ChangeTombstoned, Position: position, Target: -1})
+	}
+	return nil
+}
+
+// Tombstoned reports whether position has been retired
+func (s *Store) Tombstoned(ctx context.Context, position int64) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	tombstoned := false
+	err := s.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(registryKey(position))
+		if errors.Is(err, badger.ErrKeyNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		tombstoned = item.ValueSize() > 0
+		return nil
+	})
+	return tombstoned, err
+}
+
+// Contains reports whether position has been registered
+func (s *Store) Contains(ctx context.Context, position int64) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	found := false
+	err := s.db.View(func(txn *badger.Txn) error {
+		_, err := txn.Get(registryKey(position))
+		if errors.Is(err, badger.ErrKeyNotFound) {
+			return nil
+		}
+		found = err == nil
+		return err
+	})
+	return found, err
+}
+
+// Load returns the value of counter name, or false if it has never been saved
+func (s *Store) Load(ctx context.Context, name string) (int64, bool, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, false, err
+	}
+
+	value, found := int64(0), false
+	err := s.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(counterKey(name))
+		if errors.Is(err, badger.ErrKeyNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		found = true
+		return item.Value(func(val []byte) error {
+			value = int64(binary.BigEndian.Uint64(val))
+			return nil
+		})
+	})
+	return value, found, err
+}
+
+// Save sets counter name to value
+func (s *Store) Save(ctx context.Context, name string, value int64) error {
+	encoded := make([]byte, 8)
+	binary.BigEndian.PutUint64(encoded, uint64(value))
+
+	return s.update(ctx, func(txn *badger.Txn) error {
+		return txn.Set(counterKey(name), encoded)
+	})
+}
+
+// Allocator returns a durable doremid.Allocator for positions in [0, limit).
+// Allocators with the same name share one counter.
+func (s *Store) Allocator(name string, limit int64) *Allocator {
+	key := append(append([]byte{}, allocatorPrefix...), name...)
+	return &Allocator{store: s, key: key, limit: limit}
+}
+
+// Allocator is a Badger-backed doremid.Allocator.
+// It is safe for concurrent use, including from multiple goroutines sharing one Store.
+type Allocator struct {
+	store *Store
+	key   []byte
+	limit int64
+}
+
+// Allocate reserves count consecutive positions and returns the first one.
+// Returns doremid.ErrSpaceExhausted if fewer than count positions remain.
+func (a *Allocator) Allocate(ctx context.Context, count int64) (int64, error) {
+	if count <= 0 {
+		return -1, doremid.ErrInvalidCount
+	}
+
+	start := int64(-1)
+	err := a.store.update(ctx, func(txn *badger.Txn) error {
+		next := int64(0)
+		item, err := txn.Get(a.key)
+		switch {
+		case err == nil:
+			err = item.Value(func(val []byte) error {
+				next = int64(binary.BigEndian.Uint64(val))
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		case !errors.Is(err, badger.ErrKeyNotFound):
+			return err
+		}
+
+		if count > a.limit-next {
+			return doremid.ErrSpaceExhausted
+		}
+
+		start = next
+		value := make([]byte, 8)
+		binary.BigEndian.PutUint64(value, uint64(next+count))
+		return txn.Set(a.key, value)
+	})
+	if err != nil {
+		return -1, err
+	}
+	return start, nil
+}
+
+// update runs fn in a read-write transaction, retrying on write conflicts
+// until it commits or ctx is done
+func (s *Store) update(ctx context.Context, fn func(txn *badger.Txn) error) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		err := s.db.Update(fn)
+		if !errors.Is(err, badger.ErrConflict) {
+			return err
+		}
+	}
+}
+
+func registryKey(position int64) []byte {
+	key := make([]byte, len(registryPrefix)+8)
+	copy(key, registryPrefix)
+	binary.BigEndian.PutUint64(key[len(registryPrefix):], uint64(position))
+	return key
+}
+
+func counterKey(name string) []byte {
+	return append(append([]byte{}, counterPrefix...), name...)
+}
+
+// Compile-time interface checks
+var (
+	_ doremid.Registry     = (*Store)(nil)
+	_ doremid.Tombstoner   = (*Store)(nil)
+	_ doremid.Watchable    = (*Store)(nil)
+	_ doremid.CounterStore = (*Store)(nil)
+	_ doremid.Allocator    = (*Allocator)(nil)
+)
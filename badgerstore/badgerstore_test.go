@@ -0,0 +1,209 @@
+package badgerstore
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/doremi-id/doremid"
+)
+
+func TestRegistry(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	store, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ok, err := store.Register(ctx, 7); !ok || err != nil {
+		t.Errorf("first registration should succeed, got %v (err: %v)", ok, err)
+	}
+	if ok, err := store.Register(ctx, 7); ok || err != nil {
+		t.Errorf("second registration should fail, got %v (err: %v)", ok, err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Registrations must survive a restart
+	store, err = Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	if found, err := store.Contains(ctx, 7); !found || err != nil {
+		t.Errorf("expected position 7 to be registered after reopening (err: %v)", err)
+	}
+	if found, _ := store.Contains(ctx, 8); found {
+		t.Error("position 8 should not be registered")
+	}
+}
+
+func TestAllocator(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	store, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	allocator := store.Allocator("orders", 100)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	seen := make(map[int64]bool)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			start, err := allocator.Allocate(ctx, 5)
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if seen[start] {
+				t.Errorf("block start %d allocated twice", start)
+			}
+			seen[start] = true
+		}()
+	}
+	wg.Wait()
+	store.Close()
+
+	// The counter must resume after a restart
+	store, err = Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	allocator = store.Allocator("orders", 100)
+	if start, err := allocator.Allocate(ctx, 50); start != 50 || err != nil {
+		t.Errorf("expected block at 50, got %d (err: %v)", start, err)
+	}
+	if _, err := allocator.Allocate(ctx, 1); !errors.Is(err, doremid.ErrSpaceExhausted) {
+		t.Errorf("expected ErrSpaceExhausted, got %v", err)
+	}
+
+	// Independent counters do not interfere
+	if start, err := store.Allocator("users", 100).Allocate(ctx, 1); start != 0 || err != nil {
+		t.Errorf("expected independent counter to start at 0, got %d (err: %v)", start, err)
+	}
+}
+
+func TestWithGenerator(t *testing.T) {
+	ctx := context.Background()
+	store, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	generator := doremid.New(doremid.Config{
+		JustIntonationDigits:   1,
+		EqualTemperamentDigits: 1,
+		Separator:              "-",
+	})
+
+	ids, err := generator.AllocateIDs(ctx, store.Allocator("ids", generator.MaxCombinations()), 3)
+	if err != nil || len(ids) != 3 || ids[0] != "do-0" {
+		t.Errorf("unexpected allocation %v (err: %v)", ids, err)
+	}
+
+	id, err := generator.NewRegisteredID(ctx, store)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if found, _ := store.Contains(ctx, generator.IDToPosition(id)); !found {
+		t.Errorf("ID '%s' should be registered", id)
+	}
+}
+
+func TestTombstoneNotifications(t *testing.T) {
+	ctx := context.Background()
+	store, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	var changes []doremid.Change
+	unsubscribe := store.OnChange(func(c doremid.Change) { changes = append(changes, c) })
+
+	store.Register(ctx, 3)
+	store.Register(ctx, 3)
+	if err := store.Tombstone(ctx, 3); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	store.Tombstone(ctx, 3)
+
+	if tombstoned, err := store.Tombstoned(ctx, 3); !tombstoned || err != nil {
+		t.Errorf("expected position 3 to be tombstoned (err: %v)", err)
+	}
+	if found, _ := store.Contains(ctx, 3); !found {
+		t.Error("tombstoned position should stay registered")
+	}
+	if ok, _ := store.Register(ctx, 3); ok {
+		t.Error("tombstoned position must not be registered again")
+	}
+
+	unsubscribe()
+	store.Register(ctx, 4)
+
+	expected := []doremid.ChangeKind{doremid.ChangeRegistered, doremid.ChangeTombstoned}
+	if len(changes) != len(expected) {
+		t.Fatalf("expected %d changes, got %v", len(expected), changes)
+	}
+	for i, kind := range expected {
+		if changes[i].Kind != kind || changes[i].Position != 3 {
+			t.Errorf("change[%d]: expected %s of 3, got %s of %d", i, kind, changes[i].Kind, changes[i].Position)
+		}
+	}
+}
+
+func TestResumableBatchCheckpoints(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	generator := doremid.New(doremid.Config{
+		JustIntonationDigits:   1,
+		EqualTemperamentDigits: 2,
+		Separator:              "-",
+	})
+
+	store, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	batch, _ := generator.NewResumableBatch(store, "prealloc", 0, 100)
+	batch.CheckpointInterval = 30
+	errCrash := errors.New("crash")
+	batch.Run(ctx, func(ids []string) error {
+		if ids[0] != generator.PositionToID(0) {
+			return errCrash
+		}
+		return nil
+	})
+	store.Close()
+
+	// The checkpoint must survive a restart
+	store, err = Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	resumed, _ := generator.NewResumableBatch(store, "prealloc", 0, 100)
+	if err := resumed.Resume(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if resumed.Cursor() != 30 {
+		t.Errorf("expected cursor 30 after restart, got %d", resumed.Cursor())
+	}
+}
@@ -0,0 +1,155 @@
+package doremid
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// batchCheckInterval is the number of IDs generated between checks of the
+// context in the ctx-aware batch methods
+const batchCheckInterval = 1024
+
+// batchBuffers holds the scratch buffers batches of IDs are encoded into
+var batchBuffers = sync.Pool{New: func() any { return new([]byte) }}
+
+// BatchGenerateIDsCtx is like BatchGenerateIDs but stops early once ctx is done,
+// returning the IDs generated so far, in order, together with ctx.Err().
+// The context is checked every few thousand IDs, so it bounds generation time
+// for huge counts without slowing small batches.
+func (g *Generator) BatchGenerateIDsCtx(ctx context.Context, count, startPosition int64) ([]string, error) {
+	mintRange := g.mintRange()
+	if count <= 0 || startPosition < mintRange.Start || startPosition >= mintRange.End {
+		return []string{}, nil
+	}
+	window := Range{Start: startPosition, End: startPosition + min(count, mintRange.End-startPosition)}
+
+	ids := make([]string, 0, window.Len()-g.reserved.count(window))
+	walker := g.walker()
+	next := walker.next(startPosition)
+	chunk := make([]int64, 0, min(window.Len(), batchCheckInterval))
+	for next < window.End {
+		if ctx.Err() != nil {
+			return ids, ctx.Err()
+		}
+		// Collect the allowed positions of the chunk before encoding them
+		chunk = chunk[:0]
+		for ; next < window.End && len(chunk) < batchCheckInterval; next = walker.next(next + 1) {
+			chunk = append(chunk, next)
+		}
+		ids = g.appendChunk(ids, len(chunk), func(i int) int64 {
+			return chunk[i]
+		})
+	}
+	return ids, nil
+}
+
+// BatchGenerateRandomIDsCtx is like BatchGenerateRandomIDs but stops early once
+// ctx is done, returning the unique IDs generated so far together with ctx.Err().
+// Returns an error wrapping ErrInvalidCount if count exceeds the positions the
+// generator may mint, or the IDs Config.WordFilter leaves.
+func (g *Generator) BatchGenerateRandomIDsCtx(ctx context.Context, count int64) ([]string, error) {
+	mintRange := g.mintRange()
+	size := g.EffectiveCombinations()
+	if count <= 0 {
+		return []string{}, nil
+	}
+	if count > size {
+		return []string{}, fmt.Errorf("%w: count exceeds the %d IDs the generator may mint", ErrInvalidCount, size)
+	}
+
+	var skip func(int) bool
+	if g.wordFilter != nil {
+		skip = func(k int) bool { return g.filtered(g.reserved.nth(mintRange, int64(k))) }
+	}
+	positions, err := g.randomSample(ctx, int(size), int(count), skip)
+	if err == nil && int64(len(positions)) < count {
+		return []string{}, fmt.Errorf("%w: count exceeds the %d IDs Config.WordFilter leaves", ErrInvalidCount, len(positions))
+	}
+	ids := make([]string, 0, len(positions))
+	for len(ids) < len(positions) {
+		// A cancelled sample is still converted, being no larger than the work done
+		if err == nil && ctx.Err() != nil {
+			return ids, ctx.Err()
+		}
+		chunk := positions[len(ids):min(len(positions), len(ids)+batchCheckInterval)]
+		ids = g.appendChunk(ids, len(chunk), func(i int) int64 {
+			return g.reserved.nth(mintRange, int64(chunk[i]))
+		})
+	}
+	return ids, err
+}
+
+// writeBatchBufferSize is the number of bytes WriteBatch buffers between writes
+const writeBatchBufferSize = 64 << 10
+
+// WriteBatch streams count sequential IDs starting at startPosition to w, each
+// followed by delim, e.g. '\n' for one ID per line. IDs are encoded into a
+// pooled buffer written out every 64 KiB, so memory use stays constant however
+// many IDs are written, e.g. when exporting hundreds of millions to a file.
+//
+// The same limits apply as for BatchGenerateIDs. Returns the number of IDs
+// written in full, and the first error returned by w.
+func (g *Generator) WriteBatch(w io.Writer, count, startPosition int64, delim byte) (int64, error) {
+	mintRange := g.mintRange()
+	if count <= 0 || startPosition < mintRange.Start || startPosition >= mintRange.End {
+		return 0, nil
+	}
+	end := startPosition + min(count, mintRange.End-startPosition)
+
+	buf := batchBuffers.Get().(*[]byte)
+	defer batchBuffers.Put(buf)
+	*buf = (*buf)[:0]
+
+	written, buffered := int64(0), int64(0)
+	flush := func() error {
+		if _, err := w.Write(*buf); err != nil {
+			return err
+		}
+		written += buffered
+		*buf, buffered = (*buf)[:0], 0
+		return nil
+	}
+	walker := g.walker()
+	for pos := walker.next(startPosition); pos < end; pos = walker.next(pos + 1) {
+		*buf = append(g.AppendPositionID(*buf, pos), delim)
+		buffered++
+		if len(*buf) >= writeBatchBufferSize {
+			if err := flush(); err != nil {
+				return written, err
+			}
+		}
+	}
+	if buffered > 0 {
+		if err := flush(); err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+// appendChunk appends the IDs at position(0) to position(n-1) to ids, for n of
+// at most batchCheckInterval. The IDs are encoded into a pooled buffer and
+// copied out as a single string they are sliced from, so a chunk costs one
+// allocation rather than one per ID, and a retained ID keeps no more than its
+// own chunk in memory.
+func (g *Generator) appendChunk(ids []string, n int, position func(i int) int64) []string {
+	buf := batchBuffers.Get().(*[]byte)
+	defer batchBuffers.Put(buf)
+
+	var ends [batchCheckInterval]int
+	*buf = (*buf)[:0]
+	for i := range n {
+		*buf = g.AppendPositionID(*buf, position(i))
+		ends[i] = len(*buf)
+	}
+
+	chunk := string(*buf)
+	start := 0
+	for _, end := range ends[:n] {
+		ids = append(ids, chunk[start:end])
+		start = end
+	}
+	return ids
+}
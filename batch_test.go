@@ -0,0 +1,228 @@
+package doremid
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestBatchGenerateIDsCtx(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 3,
+		Separator:              "-",
+	})
+
+	tests := []struct {
+		name          string
+		count         int64
+		startPosition int64
+		expected      int
+	}{
+		{"within range", 5000, 100, 5000},
+		{"limited by range", 100, 84600, 72},
+		{"zero count", 0, 0, 0},
+		{"start beyond range", 10, 84672, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ids, err := generator.BatchGenerateIDsCtx(context.Background(), tt.count, tt.startPosition)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(ids, generator.BatchGenerateIDs(tt.count, tt.startPosition)) || len(ids) != tt.expected {
+				t.Errorf("expected the %d IDs of BatchGenerateIDs, got %d", tt.expected, len(ids))
+			}
+		})
+	}
+}
+
+func TestBatchGenerateCtxCancelled(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 3,
+		Separator:              "-",
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	ids, err := generator.BatchGenerateIDsCtx(ctx, 5000, 0)
+	if !errors.Is(err, context.Canceled) || len(ids) != 0 {
+		t.Errorf("expected no IDs and context.Canceled, got %d IDs (err: %v)", len(ids), err)
+	}
+
+	ids, err = generator.BatchGenerateRandomIDsCtx(ctx, 5000)
+	if !errors.Is(err, context.Canceled) || len(ids) != 0 {
+		t.Errorf("expected no IDs and context.Canceled, got %d IDs (err: %v)", len(ids), err)
+	}
+}
+
+// cancelAfter is a context reporting cancellation after its Err method has been called n times
+type cancelAfter struct {
+	context.Context
+	n int
+}
+
+func (c *cancelAfter) Err() error {
+	if c.n--; c.n < 0 {
+		return context.Canceled
+	}
+	return nil
+}
+
+func TestBatchGenerateCtxPartial(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 3,
+		Separator:              "-",
+	})
+
+	// Cancelled after two checks, so two intervals of IDs are generated
+	ids, err := generator.BatchGenerateIDsCtx(&cancelAfter{Context: context.Background(), n: 2}, 5000, 10)
+	if !errors.Is(err, context.Canceled) || len(ids) != 2*batchCheckInterval {
+		t.Fatalf("expected %d IDs and context.Canceled, got %d (err: %v)", 2*batchCheckInterval, len(ids), err)
+	}
+	if !slices.Equal(ids, generator.BatchGenerateIDs(int64(len(ids)), 10)) {
+		t.Error("expected the partial result to be the leading IDs")
+	}
+
+	// Sampling fewer than all IDs, and shuffling all of them
+	for _, count := range []int64{5000, generator.MaxCombinations()} {
+		ids, err = generator.BatchGenerateRandomIDsCtx(&cancelAfter{Context: context.Background(), n: 2}, count)
+		if !errors.Is(err, context.Canceled) || len(ids) == 0 || int64(len(ids)) >= count {
+			t.Fatalf("expected a partial result and context.Canceled, got %d IDs (err: %v)", len(ids), err)
+		}
+		seen := make(map[string]bool)
+		for _, id := range ids {
+			if seen[id] || generator.Validate(id) != nil {
+				t.Fatalf("expected unique valid IDs, got '%s'", id)
+			}
+			seen[id] = true
+		}
+	}
+}
+
+func TestBatchGenerateRandomIDsCtx(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   1,
+		EqualTemperamentDigits: 2,
+		Separator:              "-",
+	})
+
+	ids, err := generator.BatchGenerateRandomIDsCtx(context.Background(), 1008)
+	if err != nil || len(ids) != 1008 {
+		t.Fatalf("expected 1008 IDs, got %d (err: %v)", len(ids), err)
+	}
+	if len(slices.Compact(slices.Sorted(slices.Values(ids)))) != 1008 {
+		t.Error("expected unique IDs")
+	}
+
+	if _, err := generator.BatchGenerateRandomIDsCtx(context.Background(), 1009); !errors.Is(err, ErrInvalidCount) {
+		t.Errorf("expected ErrInvalidCount, got %v", err)
+	}
+	if ids, err := generator.BatchGenerateRandomIDsCtx(context.Background(), 0); err != nil || len(ids) != 0 {
+		t.Errorf("expected no IDs, got %d (err: %v)", len(ids), err)
+	}
+}
+
+func TestBatchGenerateChunks(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 3,
+		Separator:              "-",
+		Checksum:               true,
+	})
+
+	// Spans several chunks, ending in a partial one
+	ids := generator.BatchGenerateIDs(3*batchCheckInterval+7, 500)
+	for i, id := range ids {
+		if expected := generator.PositionToID(500 + int64(i)); id != expected {
+			t.Fatalf("expected '%s' at index %d, got '%s'", expected, i, id)
+		}
+	}
+	for _, id := range generator.BatchGenerateRandomIDs(2*batchCheckInterval + 1) {
+		if err := generator.Validate(id); err != nil {
+			t.Fatalf("expected '%s' to be valid, got %v", id, err)
+		}
+	}
+
+	// Each chunk is allocated at once rather than per ID
+	if raceEnabled {
+		t.Skip("allocations are not counted reliably under the race detector")
+	}
+	if allocs := testing.AllocsPerRun(10, func() { generator.BatchGenerateIDs(10*batchCheckInterval, 0) }); allocs > 20 {
+		t.Errorf("expected at most 20 allocations for 10 chunks, got %v", allocs)
+	}
+}
+
+func TestWriteBatch(t *testing.T) {
+	generator := New(Config{JustIntonationDigits: 2, EqualTemperamentDigits: 3, Separator: "-"})
+
+	tests := []struct {
+		name          string
+		count         int64
+		startPosition int64
+		delim         byte
+		expected      int64
+	}{
+		{"lines", 10, 100, '\n', 10},
+		{"commas", 3, 0, ',', 3},
+		{"several buffers", 20000, 5000, '\n', 20000},
+		{"clamped at the end", 10, generator.MaxCombinations() - 4, '\n', 4},
+		{"zero count", 0, 0, '\n', 0},
+		{"invalid start", 10, -1, '\n', 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			n, err := generator.WriteBatch(&buf, tt.count, tt.startPosition, tt.delim)
+			if err != nil || n != tt.expected {
+				t.Fatalf("expected %d IDs, got %d (err: %v)", tt.expected, n, err)
+			}
+
+			var expected strings.Builder
+			for _, id := range generator.BatchGenerateIDs(tt.count, tt.startPosition) {
+				expected.WriteString(id)
+				expected.WriteByte(tt.delim)
+			}
+			if buf.String() != expected.String() {
+				t.Errorf("expected the IDs of BatchGenerateIDs, got %d bytes instead of %d", buf.Len(), expected.Len())
+			}
+		})
+	}
+
+	restricted := generator.Restrict(Range{Start: 10, End: 15})
+	var buf bytes.Buffer
+	if n, _ := restricted.WriteBatch(&buf, 100, 12, '\n'); n != 3 || strings.Count(buf.String(), "\n") != 3 {
+		t.Errorf("expected 3 IDs within the restriction, got %d: %q", n, buf.String())
+	}
+}
+
+// failingWriter accepts its first write and fails every later one
+type failingWriter struct {
+	writes int
+}
+
+func (f *failingWriter) Write(p []byte) (int, error) {
+	if f.writes++; f.writes > 1 {
+		return 0, io.ErrShortWrite
+	}
+	return len(p), nil
+}
+
+func TestWriteBatchError(t *testing.T) {
+	generator := New(Config{JustIntonationDigits: 2, EqualTemperamentDigits: 3, Separator: "-"})
+	lineLen := int64(len("dodo-000\n"))
+	perBuffer := (writeBatchBufferSize + lineLen - 1) / lineLen
+
+	n, err := generator.WriteBatch(&failingWriter{}, 3*perBuffer, 0, '\n')
+	if !errors.Is(err, io.ErrShortWrite) || n != perBuffer {
+		t.Errorf("expected %d IDs and io.ErrShortWrite, got %d (err: %v)", perBuffer, n, err)
+	}
+}
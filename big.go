@@ -0,0 +1,85 @@
+package doremid
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// PositionToIDBig is like PositionToIDE for positions of any size, so
+// configurations whose capacity exceeds int64 can be used without overflow.
+// Returns an error matching ErrOutOfRange for positions outside [0, MaxCombinationsBig()).
+func (g *Generator) PositionToIDBig(position *big.Int) (string, error) {
+	maxCombinations := g.MaxCombinationsBig()
+	if position.Sign() < 0 || position.Cmp(maxCombinations) >= 0 {
+		return "", fmt.Errorf("%w: position %s outside [0, %s)", ErrOutOfRange, position, maxCombinations)
+	}
+
+	// Peel off digits from the least significant end: characters, then notes
+	digits := make([]int, g.JustIntonationDigits+g.EqualTemperamentDigits)
+	temp := new(big.Int).Set(position)
+	digit := new(big.Int)
+	justRadix := big.NewInt(int64(g.justIntonationLen))
+	equalRadix := big.NewInt(int64(g.equalTemperamentLen))
+	for i := len(digits) - 1; i >= 0; i-- {
+		radix := equalRadix
+		if i < g.JustIntonationDigits {
+			radix = justRadix
+		}
+		temp.DivMod(temp, radix, digit)
+		digits[i] = int(digit.Int64())
+	}
+
+	_, capacity := g.idLengths()
+	result := make([]byte, 0, capacity)
+	result = append(result, g.prefix...)
+	sum := 0
+	for i, d := range digits {
+		if i == g.JustIntonationDigits {
+			result = append(result, g.Separator...)
+		}
+		if i < g.JustIntonationDigits {
+			result = append(result, g.noteGroupBreak(i)...)
+			result = append(result, g.justIntonationBytes[d]...)
+		} else {
+			result = append(result, g.characterGroupBreak(i-g.JustIntonationDigits)...)
+			result = append(result, g.equalTemperamentBytes[d])
+		}
+		if g.checksum {
+			sum = g.checksumAdd(sum, i, d)
+		}
+	}
+	if g.JustIntonationDigits == len(digits) {
+		result = append(result, g.Separator...)
+	}
+	if g.checksum {
+		result = append(result, g.checkCharacter(sum))
+	}
+	return string(result), nil
+}
+
+// IDToPositionBig is like IDToPositionE for IDs of any length, returning the
+// exact position even when it exceeds int64.
+// Returns a *FormatError if id is invalid.
+func (g *Generator) IDToPositionBig(id string) (*big.Int, error) {
+	// decode validates the whole ID; only its int64 position may have overflowed
+	if _, err := g.decode(id); err != nil {
+		return nil, err
+	}
+	id = g.ungroup(g.unalias(id))
+
+	position := new(big.Int)
+	justRadix := big.NewInt(int64(g.justIntonationLen))
+	equalRadix := big.NewInt(int64(g.equalTemperamentLen))
+	offset := len(g.prefix)
+	for i := 0; i < g.JustIntonationDigits; i++ {
+		index, width, _ := g.nextNote(id, offset)
+		position.Mul(position, justRadix).Add(position, big.NewInt(int64(index)))
+		offset += width
+	}
+	offset += len(g.Separator)
+	for i := 0; i < g.EqualTemperamentDigits; i++ {
+		index := g.equalTemperamentMap[id[offset+i]]
+		position.Mul(position, equalRadix).Add(position, big.NewInt(int64(index)))
+	}
+	return position, nil
+}
@@ -0,0 +1,79 @@
+package doremid
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestBigPositions(t *testing.T) {
+	// 7^20 * 12^10 overflows int64
+	generator := New(Config{
+		JustIntonationDigits:   20,
+		EqualTemperamentDigits: 10,
+		Separator:              "-",
+	})
+	maxCombinations := generator.MaxCombinationsBig()
+	if maxCombinations.IsInt64() {
+		t.Fatalf("expected capacity beyond int64, got %s", maxCombinations)
+	}
+
+	last := new(big.Int).Sub(maxCombinations, big.NewInt(1))
+	tests := []struct {
+		name     string
+		position *big.Int
+		expected string
+	}{
+		{"zero", big.NewInt(0), strings.Repeat("do", 20) + "-0000000000"},
+		{"small", big.NewInt(3722), strings.Repeat("do", 20) + "-00000021a2"},
+		{"last", last, strings.Repeat("ti", 20) + "-bbbbbbbbbb"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := generator.PositionToIDBig(tt.position)
+			if err != nil || id != tt.expected {
+				t.Fatalf("expected '%s', got '%s' (err: %v)", tt.expected, id, err)
+			}
+			position, err := generator.IDToPositionBig(id)
+			if err != nil || position.Cmp(tt.position) != 0 {
+				t.Errorf("expected position %s, got %s (err: %v)", tt.position, position, err)
+			}
+		})
+	}
+
+	for _, position := range []*big.Int{big.NewInt(-1), maxCombinations} {
+		if _, err := generator.PositionToIDBig(position); !errors.Is(err, ErrOutOfRange) {
+			t.Errorf("expected ErrOutOfRange for %s, got %v", position, err)
+		}
+	}
+	if _, err := generator.IDToPositionBig("invalid"); !errors.Is(err, ErrInvalidID) {
+		t.Errorf("expected ErrInvalidID, got %v", err)
+	}
+}
+
+func TestBigPositionsMatchInt64(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 3,
+		Separator:              "-",
+		Notes:                  "c d e f g a b",
+		Checksum:               true,
+	})
+
+	for _, pos := range []int64{0, 1, 3722, generator.MaxCombinations() - 1} {
+		id, err := generator.PositionToIDBig(big.NewInt(pos))
+		if err != nil || id != generator.PositionToID(pos) {
+			t.Errorf("expected '%s' at %d, got '%s' (err: %v)", generator.PositionToID(pos), pos, id, err)
+		}
+		if position, _ := generator.IDToPositionBig(id); position.Int64() != pos {
+			t.Errorf("expected position %d, got %s", pos, position)
+		}
+	}
+
+	notesOnly := New(Config{JustIntonationDigits: 2, Separator: "-"})
+	if got, _ := notesOnly.PositionToIDBig(big.NewInt(3)); got != notesOnly.PositionToID(3) {
+		t.Errorf("expected '%s' without characters, got '%s'", notesOnly.PositionToID(3), got)
+	}
+}
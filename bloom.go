@@ -0,0 +1,204 @@
+package doremid
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Hash64 returns a stable 64-bit hash of id for bloom filters, sketches and
+// sharding. It hashes the decoded position rather than the string, using the
+// SplitMix64 finalizer, so every service with the same generator configuration
+// computes the same value in any language:
+//
+//	z := uint64(position) + 0x9e3779b97f4a7c15
+//	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+//	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+//	return z ^ (z >> 31)
+//
+// Returns a *FormatError if id is invalid.
+func (g *Generator) Hash64(id string) (uint64, error) {
+	pos, err := g.decode(id)
+	if err != nil {
+		return 0, err
+	}
+	return splitmix64(uint64(pos)), nil
+}
+
+// splitmix64 mixes x with the SplitMix64 finalizer
+func splitmix64(x uint64) uint64 {
+	z := x + 0x9e3779b97f4a7c15
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+// BloomFilter is a probabilistic set of IDs built on Hash64. It never reports a
+// false negative, and reports false positives at roughly the configured rate.
+// When an exact bitmap over the generator's range would be no larger, it uses one
+// instead and has no false positives. It is not safe for concurrent use.
+type BloomFilter struct {
+	g      *Generator
+	bits   []uint64
+	m      uint64 // Number of bits
+	k      int    // Number of hash functions
+	exact  bool   // Bit i is position mintRange.Start+i
+	offset int64  // First position of an exact filter
+}
+
+// NewBloomFilter creates a bloom filter for up to expected IDs with the given
+// false positive rate. expected is capped at the number of IDs the generator
+// may mint. Returns a *ConfigError if expected is not positive or the rate is
+// not between 0 and 1.
+func (g *Generator) NewBloomFilter(expected int64, falsePositiveRate float64) (*BloomFilter, error) {
+	if expected <= 0 {
+		return nil, &ConfigError{Field: "expected", Reason: "must be positive"}
+	}
+	if falsePositiveRate <= 0 || falsePositiveRate >= 1 {
+		return nil, &ConfigError{Field: "falsePositiveRate", Reason: "must be between 0 and 1"}
+	}
+
+	mintRange := g.mintRange()
+	n := float64(min(expected, mintRange.Len()))
+	m := math.Ceil(-n * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2))
+
+	if m >= float64(mintRange.Len()) {
+		return newBloomFilter(g, uint64(mintRange.Len()), 1, true, mintRange.Start), nil
+	}
+	k := max(int(math.Round(m/n*math.Ln2)), 1)
+	return newBloomFilter(g, uint64(m), k, false, 0), nil
+}
+
+// NewExactFilter creates a filter that is an exact bitmap over the positions the
+// generator may mint, using one bit per position and having no false positives
+func (g *Generator) NewExactFilter() *BloomFilter {
+	mintRange := g.mintRange()
+	return newBloomFilter(g, uint64(mintRange.Len()), 1, true, mintRange.Start)
+}
+
+func newBloomFilter(g *Generator, m uint64, k int, exact bool, offset int64) *BloomFilter {
+	return &BloomFilter{g: g, bits: make([]uint64, (m+63)/64), m: m, k: k, exact: exact, offset: offset}
+}
+
+// Add inserts id. Returns a *FormatError if id is invalid, or a *RangeError if an
+// exact filter does not cover its position.
+func (b *BloomFilter) Add(id string) error {
+	return b.each(id, func(bit uint64) bool {
+		b.bits[bit/64] |= 1 << (bit % 64)
+		return true
+	})
+}
+
+// Contains reports whether id may have been added. False means it definitely was not.
+// Returns a *FormatError if id is invalid.
+func (b *BloomFilter) Contains(id string) (bool, error) {
+	contains := true
+	err := b.each(id, func(bit uint64) bool {
+		contains = b.bits[bit/64]&(1<<(bit%64)) != 0
+		return contains
+	})
+	if errors.Is(err, ErrOutOfRange) {
+		// An exact filter cannot hold positions outside its range
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return contains, nil
+}
+
+// Bits returns the size of the filter in bits
+func (b *BloomFilter) Bits() uint64 {
+	return b.m
+}
+
+// Hashes returns the number of bits set per ID, 1 for an exact filter
+func (b *BloomFilter) Hashes() int {
+	return b.k
+}
+
+// Exact reports whether the filter is an exact bitmap without false positives
+func (b *BloomFilter) Exact() bool {
+	return b.exact
+}
+
+// Merge adds every ID of other, which must have the same size and hash count,
+// e.g. a filter built by another service with the same parameters
+func (b *BloomFilter) Merge(other *BloomFilter) error {
+	if b.m != other.m || b.k != other.k || b.exact != other.exact || b.offset != other.offset {
+		return fmt.Errorf("doremid: cannot merge bloom filters with different parameters")
+	}
+	for i := range b.bits {
+		b.bits[i] |= other.bits[i]
+	}
+	return nil
+}
+
+// bloomHeaderSize is the encoded size of m, k, exact and offset
+const bloomHeaderSize = 8 + 4 + 1 + 8
+
+// MarshalBinary encodes the filter so it can be shared between services
+func (b *BloomFilter) MarshalBinary() ([]byte, error) {
+	data := make([]byte, bloomHeaderSize, bloomHeaderSize+8*len(b.bits))
+	binary.BigEndian.PutUint64(data, b.m)
+	binary.BigEndian.PutUint32(data[8:], uint32(b.k))
+	if b.exact {
+		data[12] = 1
+	}
+	binary.BigEndian.PutUint64(data[13:], uint64(b.offset))
+	for _, word := range b.bits {
+		data = binary.BigEndian.AppendUint64(data, word)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary replaces the filter with one encoded by MarshalBinary, keeping
+// its generator. Create the receiver with NewBloomFilter of the same generator.
+func (b *BloomFilter) UnmarshalBinary(data []byte) error {
+	if len(data) < bloomHeaderSize {
+		return fmt.Errorf("doremid: bloom filter data too short")
+	}
+	m := binary.BigEndian.Uint64(data)
+	k := int(binary.BigEndian.Uint32(data[8:]))
+	words := (m + 63) / 64
+	if m == 0 || k < 1 || uint64(len(data)-bloomHeaderSize) != 8*words {
+		return fmt.Errorf("doremid: malformed bloom filter data")
+	}
+
+	*b = *newBloomFilter(b.g, m, k, data[12] == 1, int64(binary.BigEndian.Uint64(data[13:])))
+	for i := range b.bits {
+		b.bits[i] = binary.BigEndian.Uint64(data[bloomHeaderSize+8*i:])
+	}
+	return nil
+}
+
+// each calls fn with the bit index of every hash of id until fn returns false
+func (b *BloomFilter) each(id string, fn func(bit uint64) bool) error {
+	pos, err := b.g.decode(id)
+	if err != nil {
+		return err
+	}
+	return b.eachPosition(pos, fn)
+}
+
+// eachPosition calls fn with the bit index of every hash of pos until fn returns false
+func (b *BloomFilter) eachPosition(pos int64, fn func(bit uint64) bool) error {
+	if b.exact {
+		if err := checkRange(pos, Range{Start: b.offset, End: b.offset + int64(b.m)}); err != nil {
+			return err
+		}
+		fn(uint64(pos - b.offset))
+		return nil
+	}
+
+	// Double hashing derives k indexes from two independent 64-bit hashes
+	h1 := splitmix64(uint64(pos))
+	h2 := splitmix64(h1) | 1
+	for i := 0; i < b.k; i++ {
+		if !fn((h1 + uint64(i)*h2) % b.m) {
+			return nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package doremid
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestHash64(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 3,
+		Separator:              "-",
+	})
+
+	// Values are fixed by the documented algorithm and must never change
+	tests := []struct {
+		id       string
+		expected uint64
+	}{
+		{"dodo-000", 0xe220a8397b1dcdaf},
+		{"dodo-001", 0x910a2dec89025cc1},
+		{"domi-1a2", splitmix64(3722)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			hash, err := generator.Hash64(tt.id)
+			if err != nil || hash != tt.expected {
+				t.Errorf("expected %#x, got %#x (err: %v)", tt.expected, hash, err)
+			}
+		})
+	}
+
+	// The hash depends on the position, not the string
+	checked := New(Config{JustIntonationDigits: 2, EqualTemperamentDigits: 3, Separator: "_", Checksum: true})
+	if a, _ := generator.Hash64("domi-1a2"); a != mustHash(t, checked, checked.PositionToID(3722)) {
+		t.Error("expected equal hashes for the same position in different formats")
+	}
+
+	if _, err := generator.Hash64("invalid"); !errors.Is(err, ErrInvalidID) {
+		t.Errorf("expected ErrInvalidID, got %v", err)
+	}
+}
+
+func mustHash(t *testing.T, g *Generator, id string) uint64 {
+	t.Helper()
+	hash, err := g.Hash64(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return hash
+}
+
+func TestBloomFilter(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   6,
+		EqualTemperamentDigits: 6,
+		Separator:              "-",
+	})
+
+	filter, err := generator.NewBloomFilter(10000, 0.01)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filter.Exact() || filter.Bits() != 95851 || filter.Hashes() != 7 {
+		t.Errorf("unexpected sizing: %d bits, %d hashes, exact %v", filter.Bits(), filter.Hashes(), filter.Exact())
+	}
+
+	for pos := int64(0); pos < 10000; pos++ {
+		if err := filter.Add(generator.PositionToID(pos * 7919)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for pos := int64(0); pos < 10000; pos++ {
+		if found, _ := filter.Contains(generator.PositionToID(pos * 7919)); !found {
+			t.Fatalf("false negative at position %d", pos*7919)
+		}
+	}
+
+	falsePositives := 0
+	for pos := int64(0); pos < 10000; pos++ {
+		if found, _ := filter.Contains(generator.PositionToID(pos*7919 + 1)); found {
+			falsePositives++
+		}
+	}
+	if falsePositives > 200 {
+		t.Errorf("expected about 1%% false positives, got %d in 10000", falsePositives)
+	}
+
+	// A filter shared with another service answers identically
+	data, _ := filter.MarshalBinary()
+	other, _ := generator.NewBloomFilter(1, 0.5)
+	if err := other.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if found, _ := other.Contains(generator.PositionToID(7919)); !found {
+		t.Error("expected decoded filter to contain added ID")
+	}
+	if err := other.UnmarshalBinary(data[:len(data)-1]); err == nil {
+		t.Error("expected error for truncated data")
+	}
+}
+
+func TestBloomFilterExact(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 2,
+		Separator:              "-",
+	}).Restrict(Range{Start: 100, End: 600})
+
+	// Sizing for the whole range is larger than a bitmap, so the filter is exact
+	filter, err := generator.NewBloomFilter(1000, 0.001)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !filter.Exact() || filter.Bits() != 500 {
+		t.Fatalf("expected an exact 500 bit filter, got %d bits (exact %v)", filter.Bits(), filter.Exact())
+	}
+
+	for pos := int64(100); pos < 600; pos += 2 {
+		filter.Add(generator.PositionToID(pos))
+	}
+	for pos := int64(100); pos < 600; pos++ {
+		if found, _ := filter.Contains(generator.PositionToID(pos)); found != (pos%2 == 0) {
+			t.Fatalf("expected %v at position %d, got %v", pos%2 == 0, pos, found)
+		}
+	}
+
+	if found, err := filter.Contains(generator.PositionToID(50)); found || err != nil {
+		t.Errorf("expected position outside the range to be absent, got %v (err: %v)", found, err)
+	}
+	if err := filter.Add(generator.PositionToID(50)); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("expected ErrOutOfRange, got %v", err)
+	}
+
+	merged, _ := generator.NewBloomFilter(1000, 0.001)
+	merged.Merge(filter)
+	if found, _ := merged.Contains(generator.PositionToID(100)); !found {
+		t.Error("expected merged filter to contain added ID")
+	}
+}
+
+func TestBloomFilterConfig(t *testing.T) {
+	generator := NewWithDefaults()
+	for _, tt := range []struct {
+		expected int64
+		rate     float64
+	}{{0, 0.01}, {10, 0}, {10, 1}} {
+		if _, err := generator.NewBloomFilter(tt.expected, tt.rate); !errors.Is(err, ErrInvalidConfig) {
+			t.Errorf("expected ErrInvalidConfig for (%d, %v), got %v", tt.expected, tt.rate, err)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package doremid
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// BruteForceParams describes the deployment an attacker guesses against
+type BruteForceParams struct {
+	// Issued is the number of IDs that currently resolve to something
+	Issued int64
+
+	// GuessesPerSecond is the rate limit applied to a single client.
+	// Zero means the rate is unknown and no times are estimated.
+	GuessesPerSecond float64
+
+	// Sources is the number of clients (IPs, accounts, API keys) the attacker
+	// controls, each with its own rate limit. Zero means one.
+	Sources int
+}
+
+// BruteForceReport estimates the effort needed to guess any valid ID by
+// submitting random candidates. It assumes IDs were issued uniformly at random
+// across the mintable range; sequentially issued IDs are trivially enumerable
+// regardless of these numbers.
+type BruteForceReport struct {
+	Keyspace         int64   // Positions the generator may mint
+	Issued           int64   // Valid IDs, capped at Keyspace
+	EntropyBits      float64 // log2(Keyspace)
+	Density          float64 // Probability that a single guess hits a valid ID
+	ExpectedGuesses  float64 // Mean guesses until the first hit, without repeats
+	MedianGuesses    float64 // Guesses giving a 50% chance of at least one hit
+	GuessesPerSecond float64 // Aggregate rate across all sources
+
+	// ExpectedTime and MedianTime are zero if the rate is unknown and saturate
+	// at the largest time.Duration (about 292 years)
+	ExpectedTime time.Duration
+	MedianTime   time.Duration
+
+	// PredictableRNG is true if random IDs come from a math/rand source, which an
+	// attacker observing enough IDs could reconstruct, or from a generator built
+	// with NewSeeded, whose IDs anyone knowing the seed can reproduce. Use
+	// Config.SecureRandom where guessing resistance matters. Other sources set
+	// through Config.Rand are trusted to be unpredictable.
+	PredictableRNG bool
+}
+
+// BruteForceCost estimates the effort an attacker needs to guess a valid ID
+// given the generator's keyspace, the number of IDs issued and the rate limits
+func (g *Generator) BruteForceCost(params BruteForceParams) BruteForceReport {
+	keyspace := g.mintRange().Len()
+	issued := min(max(params.Issued, 0), keyspace)
+
+	report := BruteForceReport{
+		Keyspace:        keyspace,
+		Issued:          issued,
+		EntropyBits:     math.Log2(float64(keyspace)),
+		ExpectedGuesses: math.Inf(1),
+		MedianGuesses:   math.Inf(1),
+		PredictableRNG:  g.seeded || !g.secureRandom && g.randSource == nil,
+	}
+
+	if issued > 0 {
+		report.Density = float64(issued) / float64(keyspace)
+		// Guessing distinct candidates is sampling without replacement
+		report.ExpectedGuesses = float64(keyspace+1) / float64(issued+1)
+		if report.Density >= 1 {
+			report.MedianGuesses = 1
+		} else {
+			report.MedianGuesses = math.Ceil(math.Log(0.5) / math.Log1p(-report.Density))
+		}
+	}
+
+	if params.GuessesPerSecond > 0 {
+		report.GuessesPerSecond = params.GuessesPerSecond * float64(max(params.Sources, 1))
+		report.ExpectedTime = secondsToDuration(report.ExpectedGuesses / report.GuessesPerSecond)
+		report.MedianTime = secondsToDuration(report.MedianGuesses / report.GuessesPerSecond)
+	}
+	return report
+}
+
+// String returns a human-readable summary for security reviews
+func (r BruteForceReport) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "keyspace:         %d (%.1f bits)\n", r.Keyspace, r.EntropyBits)
+	fmt.Fprintf(&b, "issued:           %d (1 in %.0f guesses hits)\n", r.Issued, 1/r.Density)
+	fmt.Fprintf(&b, "expected guesses: %.0f\n", r.ExpectedGuesses)
+	fmt.Fprintf(&b, "median guesses:   %.0f\n", r.MedianGuesses)
+	if r.GuessesPerSecond > 0 {
+		fmt.Fprintf(&b, "guess rate:       %.0f/s\n", r.GuessesPerSecond)
+		fmt.Fprintf(&b, "expected time:    %s\n", formatEffort(r.ExpectedTime))
+		fmt.Fprintf(&b, "median time:      %s\n", formatEffort(r.MedianTime))
+	}
+	if r.PredictableRNG {
+		b.WriteString("warning:          predictable random source; enable Config.SecureRandom\n")
+	}
+	return b.String()
+}
+
+// secondsToDuration converts seconds to a Duration, saturating instead of overflowing
+func secondsToDuration(seconds float64) time.Duration {
+	if seconds >= float64(math.MaxInt64)/float64(time.Second) {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// formatEffort formats d in units a reviewer can compare, using days and years
+// for long durations
+func formatEffort(d time.Duration) string {
+	const day = 24 * time.Hour
+	const year = 365 * day
+	switch {
+	case d == time.Duration(math.MaxInt64):
+		return "more than 292 years"
+	case d >= year:
+		return fmt.Sprintf("%.1f years", d.Hours()/year.Hours())
+	case d >= day:
+		return fmt.Sprintf("%.1f days", d.Hours()/day.Hours())
+	default:
+		return d.Round(time.Second).String()
+	}
+}
@@ -0,0 +1,72 @@
+package doremid
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBruteForceCost(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 3,
+		Separator:              "-",
+	})
+
+	tests := []struct {
+		name            string
+		generator       *Generator
+		params          BruteForceParams
+		keyspace        int64
+		expectedGuesses float64
+		medianGuesses   float64
+		expectedTime    time.Duration
+	}{
+		{"nothing issued", generator, BruteForceParams{}, 84672, math.Inf(1), math.Inf(1), 0},
+		{"rate limited", generator, BruteForceParams{Issued: 1000, GuessesPerSecond: 10}, 84672, 84673.0 / 1001, 59, 8458841158},
+		{"many sources", generator, BruteForceParams{Issued: 1000, GuessesPerSecond: 10, Sources: 10}, 84672, 84673.0 / 1001, 59, 845884115},
+		{"fully issued", generator, BruteForceParams{Issued: 1 << 40}, 84672, 1, 1, 0},
+		{"restricted", generator.Restrict(Range{Start: 0, End: 999}), BruteForceParams{Issued: 99}, 999, 10, 7, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := tt.generator.BruteForceCost(tt.params)
+			if report.Keyspace != tt.keyspace {
+				t.Errorf("expected keyspace %d, got %d", tt.keyspace, report.Keyspace)
+			}
+			if math.Abs(report.ExpectedGuesses-tt.expectedGuesses) > 1e-9 && !math.IsInf(tt.expectedGuesses, 1) {
+				t.Errorf("expected %f expected guesses, got %f", tt.expectedGuesses, report.ExpectedGuesses)
+			}
+			if report.MedianGuesses != tt.medianGuesses {
+				t.Errorf("expected %f median guesses, got %f", tt.medianGuesses, report.MedianGuesses)
+			}
+			if report.ExpectedTime != tt.expectedTime {
+				t.Errorf("expected time %v, got %v", tt.expectedTime, report.ExpectedTime)
+			}
+		})
+	}
+}
+
+func TestBruteForceCostSaturates(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   8,
+		EqualTemperamentDigits: 8,
+		Separator:              "-",
+		SecureRandom:           true,
+	})
+
+	report := generator.BruteForceCost(BruteForceParams{Issued: 1, GuessesPerSecond: 1})
+	if report.ExpectedTime != time.Duration(math.MaxInt64) {
+		t.Errorf("expected saturated time, got %v", report.ExpectedTime)
+	}
+	if report.PredictableRNG {
+		t.Error("expected secure random generator not to be flagged")
+	}
+
+	summary := report.String()
+	if !strings.Contains(summary, "more than 292 years") || strings.Contains(summary, "warning") {
+		t.Errorf("unexpected summary:\n%s", summary)
+	}
+}
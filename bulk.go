@@ -0,0 +1,117 @@
+package doremid
+
+// BatchIDToPositions converts many IDs at once, e.g. when importing IDs in
+// bulk. positions[i] is the position of ids[i], or -1 if it is invalid. errs is
+// nil if every ID is valid; otherwise it holds the *FormatError of each invalid
+// ID at its index and nil elsewhere.
+//
+// Lookup tables are built once per call instead of consulting the generator's
+// maps for every symbol, so large batches decode several times faster than
+// looping over IDToPosition. Results are identical.
+func (g *Generator) BatchIDToPositions(ids []string) (positions []int64, errs []error) {
+	d := g.newBatchDecoder()
+	positions = make([]int64, len(ids))
+	for i, id := range ids {
+		if pos, ok := d.decode(id); ok {
+			positions[i] = pos
+			continue
+		}
+
+		// Let decode handle everything unusual and describe the problem
+		pos, err := g.decode(id)
+		if err != nil {
+			if errs == nil {
+				errs = make([]error, len(ids))
+			}
+			errs[i] = err
+		}
+		positions[i] = pos
+	}
+	return positions, errs
+}
+
+// batchDecoder decodes compact IDs of a fixed length with array lookups. It
+// reports false for anything else, e.g. grouped or invalid IDs, notes of
+// differing lengths or check characters, leaving those to Generator.decode.
+type batchDecoder struct {
+	g         *Generator
+	length    int // Length of every ID the decoder handles, 0 to handle none
+	noteWidth int // Length of every note
+	notes     []string
+	chars     [256]uint8 // Index of each character plus one, 0 for other bytes
+	equalMax  int64
+}
+
+// maxScannedNotes is the most notes looked up by linear scan rather than map
+const maxScannedNotes = 16
+
+func (g *Generator) newBatchDecoder() *batchDecoder {
+	d := &batchDecoder{g: g, noteWidth: g.minNoteLen}
+	shortest, longest := g.compactLengths()
+	if shortest != longest || g.checksum || g.JustIntonationDigits < 0 || g.EqualTemperamentDigits < 0 ||
+		(g.MaxParseLength >= 0 && shortest > g.MaxParseLength) {
+		return d
+	}
+
+	d.length = shortest
+	d.equalMax = int64(g.intPow(g.equalTemperamentLen, g.EqualTemperamentDigits))
+	if g.justIntonationLen <= maxScannedNotes {
+		d.notes = make([]string, g.justIntonationLen)
+		for i, note := range g.justIntonationBytes {
+			d.notes[i] = string(note)
+		}
+	}
+	for i, char := range g.equalTemperamentBytes {
+		d.chars[char] = uint8(i + 1)
+	}
+	return d
+}
+
+// note returns the index of note, -1 if it is unknown
+func (d *batchDecoder) note(note string) int {
+	if d.notes == nil {
+		if index, found := d.g.justIntonationMap[note]; found {
+			return index
+		}
+		return -1
+	}
+	for i, candidate := range d.notes {
+		if candidate == note {
+			return i
+		}
+	}
+	return -1
+}
+
+// decode returns the position of id, or false if the decoder cannot handle it
+func (d *batchDecoder) decode(id string) (int64, bool) {
+	g := d.g
+	if d.length == 0 || len(id) != d.length || id[:len(g.prefix)] != g.prefix {
+		return 0, false
+	}
+
+	offset := len(g.prefix)
+	justValue := int64(0)
+	for i := 0; i < g.JustIntonationDigits; i++ {
+		index := d.note(id[offset : offset+d.noteWidth])
+		if index < 0 {
+			return 0, false
+		}
+		justValue = justValue*int64(g.justIntonationLen) + int64(index)
+		offset += d.noteWidth
+	}
+	if id[offset:offset+len(g.Separator)] != g.Separator {
+		return 0, false
+	}
+	offset += len(g.Separator)
+
+	equalValue := int64(0)
+	for i := offset; i < len(id); i++ {
+		index := d.chars[id[i]]
+		if index == 0 {
+			return 0, false
+		}
+		equalValue = equalValue*int64(g.equalTemperamentLen) + int64(index-1)
+	}
+	return justValue*d.equalMax + equalValue, true
+}
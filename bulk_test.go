@@ -0,0 +1,81 @@
+package doremid
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestBatchIDToPositions(t *testing.T) {
+	manyNotes := "ba be bi bo bu ca ce ci co cu da de di do du fa fe fi fo fu"
+	configs := []Config{
+		{JustIntonationDigits: 2, EqualTemperamentDigits: 3, Separator: "-"},
+		{JustIntonationDigits: 2, EqualTemperamentDigits: 3, Separator: "-", Prefix: "usr", Version: 2},
+		{JustIntonationDigits: 2, EqualTemperamentDigits: 3, Separator: "-", Checksum: true},
+		{JustIntonationDigits: 4, EqualTemperamentDigits: 4, Separator: "_", GroupSize: 2, NoteGroupSeparator: "-"},
+		{JustIntonationDigits: 2, EqualTemperamentDigits: 3, Notes: "do re mi fa sol la ti"},
+		{JustIntonationDigits: 2, EqualTemperamentDigits: 3, Separator: "-", Notes: manyNotes},
+	}
+
+	for _, config := range configs {
+		generator := New(config)
+		ids := generator.BatchGenerateIDs(100, 1000)
+		ids = append(ids, generator.BatchGenerateRandomIDs(100)...)
+
+		positions, errs := generator.BatchIDToPositions(ids)
+		if errs != nil {
+			t.Fatalf("expected no errors for %+v, got %v", config, errs)
+		}
+		for i, id := range ids {
+			if expected := generator.IDToPosition(id); positions[i] != expected {
+				t.Fatalf("expected position %d for '%s', got %d", expected, id, positions[i])
+			}
+		}
+
+		// Invalid IDs report the same errors as IDToPositionE
+		invalid := []string{"", "invalid", ids[0] + "0", ids[0][:len(ids[0])-1] + "z", "z" + ids[0][1:]}
+		positions, errs = generator.BatchIDToPositions(append(invalid, ids[0]))
+		if len(errs) != len(invalid)+1 || errs[len(invalid)] != nil || positions[len(invalid)] != generator.IDToPosition(ids[0]) {
+			t.Fatalf("expected the valid ID to decode among invalid ones, got %v and %v", positions, errs)
+		}
+		for i, id := range invalid {
+			_, expected := generator.IDToPositionE(id)
+			if positions[i] != -1 || !errors.Is(errs[i], ErrInvalidID) || errs[i].Error() != expected.Error() {
+				t.Errorf("expected -1 and %v for '%s', got %d and %v", expected, id, positions[i], errs[i])
+			}
+		}
+	}
+
+	if positions, errs := NewWithDefaults().BatchIDToPositions(nil); len(positions) != 0 || errs != nil {
+		t.Errorf("expected no results for no IDs, got %v and %v", positions, errs)
+	}
+}
+
+func TestBatchIDToPositionsCompact(t *testing.T) {
+	generator := New(Config{JustIntonationDigits: 4, EqualTemperamentDigits: 4, Separator: "_", GroupSize: 2, NoteGroupSeparator: "-"})
+	positions, errs := generator.BatchIDToPositions([]string{"dodo-domi_1a2b", "dododomi_1a2b"})
+	if errs != nil || !slices.Equal(positions, []int64{generator.IDToPosition("dododomi_1a2b"), generator.IDToPosition("dododomi_1a2b")}) {
+		t.Errorf("expected grouped and compact IDs to decode alike, got %v and %v", positions, errs)
+	}
+}
+
+func BenchmarkBatchIDToPositions(b *testing.B) {
+	generator := NewWithDefaults()
+	ids := generator.BatchGenerateIDs(10000, 1_000_000)
+	b.ReportAllocs()
+	for b.Loop() {
+		_, _ = generator.BatchIDToPositions(ids)
+	}
+}
+
+func BenchmarkIDToPositionLoop(b *testing.B) {
+	generator := NewWithDefaults()
+	ids := generator.BatchGenerateIDs(10000, 1_000_000)
+	b.ReportAllocs()
+	for b.Loop() {
+		positions := make([]int64, len(ids))
+		for i, id := range ids {
+			positions[i] = generator.IDToPosition(id)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package doremid
+
+import (
+	"math"
+	"strings"
+)
+
+// CapacityBias chooses between digit counts that reach Config.MinCombinations
+// with the same number of symbols
+type CapacityBias int
+
+const (
+	// BalancedParts splits the symbols as evenly as possible between the parts
+	BalancedParts CapacityBias = iota
+
+	// FewerNotes uses as few notes as possible, giving the shortest IDs since
+	// notes are longer than characters
+	FewerNotes
+
+	// FewerCharacters uses as many notes as possible, giving the most musical IDs
+	FewerCharacters
+)
+
+// NewForCapacity creates a generator with the default alphabet and separator
+// whose ID space holds at least n IDs, using as few symbols as possible.
+// Returns a *ConfigError if n is not positive or no ID space of that size
+// fits in an int64.
+func NewForCapacity(n int64) (*Generator, error) {
+	if n <= 0 {
+		return nil, &ConfigError{Field: "MinCombinations", Reason: "must be positive"}
+	}
+	config := DefaultConfig()
+	config.MinCombinations = n
+	return NewE(config)
+}
+
+// capacityDigits returns the fewest just intonation and equal temperament
+// digits, at least one of each, whose combinations reach config.MinCombinations
+func capacityDigits(config Config) (just, equal int, err error) {
+	if config.MinCombinations <= 0 {
+		return 0, 0, &ConfigError{Field: "MinCombinations", Reason: "must be positive"}
+	}
+
+	notes := len(strings.Fields(config.Notes))
+	if notes == 0 {
+		notes = len(strings.Fields(DefaultNotes))
+	}
+	characters := len(config.Characters)
+	if characters == 0 {
+		characters = len(DefaultCharacters)
+	}
+
+	// Every symbol at least doubles the space, so 63 symbols exceed any int64
+	for symbols := 2; symbols <= 64; symbols++ {
+		just = -1
+		for j := 1; j < symbols; j++ {
+			combinations, ok := capacityOf(notes, j, characters, symbols-j)
+			if !ok || combinations < config.MinCombinations {
+				continue
+			}
+			switch {
+			case just < 0,
+				config.CapacityBias == FewerCharacters,
+				config.CapacityBias == BalancedParts && abs(2*j-symbols) < abs(2*just-symbols):
+				just = j
+			}
+		}
+		if just >= 0 {
+			return just, symbols - just, nil
+		}
+	}
+	return 0, 0, &ConfigError{Field: "MinCombinations", Reason: "exceeds the largest ID space that fits in an int64"}
+}
+
+// capacityOf returns notes^just * characters^equal, or false if it overflows an int64
+func capacityOf(notes, just, characters, equal int) (int64, bool) {
+	combinations := int64(1)
+	for i := 0; i < just+equal; i++ {
+		base := int64(characters)
+		if i < just {
+			base = int64(notes)
+		}
+		if combinations > math.MaxInt64/base {
+			return 0, false
+		}
+		combinations *= base
+	}
+	return combinations, true
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,71 @@
+package doremid
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestMinCombinations(t *testing.T) {
+	tests := []struct {
+		name          string
+		config        Config
+		expectedJust  int
+		expectedEqual int
+	}{
+		{"smallest space", Config{MinCombinations: 1}, 1, 1},
+		{"exact fit", Config{MinCombinations: 84}, 1, 1},
+		{"one more symbol", Config{MinCombinations: 600}, 1, 2},
+		{"balanced tie prefers fewer notes", Config{MinCombinations: 85}, 1, 2},
+		{"balanced", Config{MinCombinations: 1000000}, 2, 4},
+		{"fewer notes", Config{MinCombinations: 1000000, CapacityBias: FewerNotes}, 1, 5},
+		{"fewer characters", Config{MinCombinations: 1000000, CapacityBias: FewerCharacters}, 2, 4},
+		{"fewer characters tie", Config{MinCombinations: 85, CapacityBias: FewerCharacters}, 2, 1},
+		{"default space", Config{MinCombinations: 597445632}, 4, 5},
+		{"overrides digits", Config{JustIntonationDigits: 9, EqualTemperamentDigits: 9, MinCombinations: 84}, 1, 1},
+		{"custom alphabet", Config{MinCombinations: 1024, Notes: "do re", Characters: "01"}, 5, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			generator, err := NewE(tt.config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if generator.JustIntonationDigits != tt.expectedJust || generator.EqualTemperamentDigits != tt.expectedEqual {
+				t.Errorf("expected %d and %d digits, got %d and %d", tt.expectedJust, tt.expectedEqual, generator.JustIntonationDigits, generator.EqualTemperamentDigits)
+			}
+			if generator.MaxCombinations() < tt.config.MinCombinations {
+				t.Errorf("expected at least %d combinations, got %d", tt.config.MinCombinations, generator.MaxCombinations())
+			}
+		})
+	}
+}
+
+func TestNewForCapacity(t *testing.T) {
+	generator, err := NewForCapacity(1000000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id := generator.PositionToID(999999); generator.IDToPosition(id) != 999999 {
+		t.Errorf("expected '%s' to round-trip", id)
+	}
+	if generator.Separator != "-" {
+		t.Errorf("expected the default separator, got %q", generator.Separator)
+	}
+
+	for _, n := range []int64{0, -1, math.MaxInt64} {
+		_, err := NewForCapacity(n)
+		var configErr *ConfigError
+		if !errors.As(err, &configErr) || configErr.Field != "MinCombinations" {
+			t.Errorf("expected ConfigError for MinCombinations %d, got %v", n, err)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected New to panic for an unreachable capacity")
+		}
+	}()
+	New(Config{MinCombinations: -1})
+}
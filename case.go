@@ -0,0 +1,119 @@
+package doremid
+
+import (
+	"maps"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Case selects the letter case of the notes and characters of IDs, see
+// Config.Case
+type Case int
+
+const (
+	// AsConfigured keeps notes and characters as listed, lower case by default
+	AsConfigured Case = iota
+
+	// LowerCase writes notes and characters in lower case, e.g. "domi-1a2"
+	LowerCase
+
+	// UpperCase writes notes and characters in upper case, e.g. "DOMI-1A2"
+	UpperCase
+
+	// TitleCase capitalizes every note and keeps characters as listed, e.g.
+	// "DoMi-1a2"
+	TitleCase
+)
+
+// applyCase returns config with its notes, characters and input aliases
+// rewritten in config.Case
+func applyCase(config Config) (Config, error) {
+	var recase func(string) string
+	switch config.Case {
+	case AsConfigured:
+		return config, nil
+	case LowerCase:
+		recase = strings.ToLower
+	case UpperCase:
+		recase = strings.ToUpper
+	case TitleCase:
+		recase = func(s string) string {
+			first, size := utf8.DecodeRuneInString(s)
+			return string(unicode.ToUpper(first)) + strings.ToLower(s[size:])
+		}
+	default:
+		return config, &ConfigError{Field: "Case", Reason: "unknown case"}
+	}
+
+	notes := strings.Fields(config.Notes)
+	if len(notes) == 0 {
+		notes = strings.Fields(DefaultNotes)
+	}
+	for i, note := range notes {
+		notes[i] = recase(note)
+	}
+	config.Notes = strings.Join(notes, " ")
+
+	if config.Case != TitleCase {
+		if config.Characters == "" {
+			config.Characters = DefaultCharacters
+		}
+		config.Characters = recase(config.Characters)
+	}
+
+	if config.InputAliases != nil {
+		aliases := maps.Clone(config.InputAliases)
+		clear(aliases)
+		for spelling, note := range config.InputAliases {
+			aliases[recase(spelling)] = recase(note)
+		}
+		config.InputAliases = aliases
+	}
+	return config, nil
+}
+
+// foldCase enables Config.LenientCase, which requires notes, aliases and
+// characters that differ other than by case
+func (g *Generator) foldCase(config Config) error {
+	if !config.LenientCase {
+		return nil
+	}
+
+	words := make([]string, 0, g.justIntonationLen+len(g.aliases))
+	for _, note := range g.justIntonationBytes {
+		words = append(words, strings.ToLower(string(note)))
+	}
+	for _, alias := range g.aliases {
+		words = append(words, strings.ToLower(alias.spelling))
+	}
+	if !uniquelyDecodable(words) {
+		return &ConfigError{Field: "LenientCase", Reason: "notes and aliases must parse unambiguously in any case"}
+	}
+	for _, char := range g.equalTemperamentBytes {
+		if _, found := g.equalTemperamentMap[swapCase(char)]; found && swapCase(char) != char {
+			return &ConfigError{Field: "LenientCase", Reason: "characters must differ other than by case"}
+		}
+	}
+	g.lenientCase = true
+	return nil
+}
+
+// swapCase returns the other case of an ASCII letter and c itself otherwise
+func swapCase(c byte) byte {
+	if c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' {
+		return c ^ 0x20
+	}
+	return c
+}
+
+// foldCharacter returns the character of the generator c stands for in any
+// case, or c itself if there is none
+func (g *Generator) foldCharacter(c byte) byte {
+	if _, found := g.equalTemperamentMap[c]; !found {
+		if _, found := g.equalTemperamentMap[swapCase(c)]; found {
+			return swapCase(c)
+		}
+	}
+	return c
+}
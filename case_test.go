@@ -0,0 +1,131 @@
+package doremid
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCase(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   Config
+		expected string
+	}{
+		{"as configured", Config{}, "domi-1a2"},
+		{"lower case", Config{Case: LowerCase, Characters: "0123456789AB"}, "domi-1a2"},
+		{"upper case", Config{Case: UpperCase}, "DOMI-1A2"},
+		{"title case", Config{Case: TitleCase}, "DoMi-1a2"},
+		{"title case keeps characters", Config{Case: TitleCase, Characters: "0123456789AB"}, "DoMi-1A2"},
+		{"custom notes", Config{Case: UpperCase, Notes: "ut re mi fa sol la si"}, "UTFA-1A2"},
+		{"prefix keeps its case", Config{Case: UpperCase, Prefix: "usr"}, "usr_DOMI-1A2"},
+		{"version marker", Config{Case: UpperCase, Version: 10}, "ADOMI-1A2"},
+		{"checksum", Config{Case: UpperCase, Checksum: true}, "DOMI-1A26"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.JustIntonationDigits, tt.config.EqualTemperamentDigits, tt.config.Separator = 2, 3, "-"
+			generator := New(tt.config)
+			pos := int64(2*12*12*12 + 1*144 + 10*12 + 2)
+			if tt.config.Notes != "" {
+				pos = 3*12*12*12 + 1*144 + 10*12 + 2
+			}
+
+			if id := generator.PositionToID(pos); id != tt.expected {
+				t.Errorf("expected '%s', got '%s'", tt.expected, id)
+			}
+			if got, err := generator.IDToPositionE(tt.expected); err != nil || got != pos {
+				t.Errorf("expected position %d, got %d (err: %v)", pos, got, err)
+			}
+			if err := generator.Validate(generator.NewID()); err != nil {
+				t.Errorf("expected a valid random ID, got %v", err)
+			}
+		})
+	}
+}
+
+func TestCaseStrict(t *testing.T) {
+	generator := New(Config{JustIntonationDigits: 2, EqualTemperamentDigits: 3, Separator: "-", Case: UpperCase})
+
+	for _, id := range []string{"domi-1a2", "DOMI-1a2", "DoMI-1A2"} {
+		if err := generator.Validate(id); !errors.Is(err, ErrInvalidID) {
+			t.Errorf("expected '%s' to be invalid, got %v", id, err)
+		}
+	}
+}
+
+func TestLenientCase(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 3,
+		Separator:              "-",
+		Prefix:                 "usr",
+		Case:                   UpperCase,
+		LenientCase:            true,
+		InputAliases:           map[string]string{"sol": "so"},
+	})
+
+	tests := []struct {
+		name string
+		id   string
+	}{
+		{"configured case", "usr_DOMI-1A2"},
+		{"lower case", "usr_domi-1a2"},
+		{"mixed case", "usr_dOMi-1A2"},
+		{"prefix in any case", "USR_DOMI-1a2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if compact, err := generator.Compact(tt.id); err != nil || compact != "usr_DOMI-1A2" {
+				t.Errorf("expected 'usr_DOMI-1A2', got '%s' (err: %v)", compact, err)
+			}
+			if parsed, err := generator.Parse(tt.id); err != nil || parsed.Just != "DOMI" || parsed.Equal != "1A2" {
+				t.Errorf("expected the configured case, got %+v (err: %v)", parsed, err)
+			}
+		})
+	}
+
+	if compact, err := generator.Compact("usr_Solmi-1a2"); err != nil || compact != "usr_SOMI-1A2" {
+		t.Errorf("expected 'usr_SOMI-1A2', got '%s' (err: %v)", compact, err)
+	}
+	if positions, errs := generator.BatchIDToPositions([]string{"usr_domi-1a2", "usr_DOMI-1A2"}); errs != nil || positions[0] != positions[1] {
+		t.Errorf("expected both cases to decode alike, got %v (errs: %v)", positions, errs)
+	}
+	for _, id := range []string{"usr_DOMI-1C2", "usr_DONI-1A2", "usr_DOMI_1A2", "urs_DOMI-1A2"} {
+		if err := generator.Validate(id); !errors.Is(err, ErrInvalidID) {
+			t.Errorf("expected '%s' to be invalid, got %v", id, err)
+		}
+	}
+
+	grouped := New(Config{JustIntonationDigits: 4, EqualTemperamentDigits: 4, Separator: "_", GroupSize: 2, NoteGroupSeparator: ".", CharacterGroupSeparator: ".", LenientCase: true})
+	if canonical, err := grouped.Canonicalize("DOMI.FaSo_1A.2b"); err != nil || canonical != "domi.faso_1a.2b" {
+		t.Errorf("expected 'domi.faso_1a.2b', got '%s' (err: %v)", canonical, err)
+	}
+}
+
+func TestCaseConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+		field  string
+	}{
+		{"unknown case", Config{Case: TitleCase + 1}, "Case"},
+		{"duplicate notes", Config{Case: LowerCase, Notes: "do DO mi"}, "Notes"},
+		{"duplicate characters", Config{Case: UpperCase, Characters: "01aA"}, "Characters"},
+		{"lenient notes", Config{LenientCase: true, Notes: "do DO mi"}, "LenientCase"},
+		{"lenient characters", Config{LenientCase: true, Characters: "01aA"}, "LenientCase"},
+		{"lenient aliases", Config{LenientCase: true, InputAliases: map[string]string{"DO": "re"}}, "LenientCase"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.JustIntonationDigits, tt.config.EqualTemperamentDigits = 2, 2
+			_, err := NewE(tt.config)
+			var configErr *ConfigError
+			if !errors.As(err, &configErr) || configErr.Field != tt.field {
+				t.Errorf("expected a %s ConfigError, got %v", tt.field, err)
+			}
+		})
+	}
+}
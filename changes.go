@@ -0,0 +1,86 @@
+package doremid
+
+import "sync"
+
+// ChangeKind describes what happened to a stored position
+type ChangeKind int
+
+const (
+	// ChangeRegistered means a position was issued
+	ChangeRegistered ChangeKind = iota
+	// ChangeTombstoned means an issued position was retired and must no longer resolve
+	ChangeTombstoned
+	// ChangeRemapped means an alias now points to a different target position
+	ChangeRemapped
+	// ChangeUnmapped means an alias was removed
+	ChangeUnmapped
+)
+
+// String returns the name of the change kind
+func (k ChangeKind) String() string {
+	switch k {
+	case ChangeRegistered:
+		return "registered"
+	case ChangeTombstoned:
+		return "tombstoned"
+	case ChangeRemapped:
+		return "remapped"
+	case ChangeUnmapped:
+		return "unmapped"
+	default:
+		return "unknown"
+	}
+}
+
+// Change is a notification emitted by a store after a successful mutation
+type Change struct {
+	Kind     ChangeKind
+	Position int64 // Position whose cached lookups must be invalidated
+	Target   int64 // New target position for ChangeRemapped, -1 otherwise
+}
+
+// ChangeFunc receives change notifications. It is called synchronously after
+// the mutation is committed and must not call back into the notifying store.
+type ChangeFunc func(Change)
+
+// ChangeNotifier manages change subscriptions. Stores embed it to implement
+// OnChange; the zero value is ready to use and it is safe for concurrent use.
+type ChangeNotifier struct {
+	mu        sync.RWMutex
+	nextID    int
+	listeners map[int]ChangeFunc
+}
+
+// OnChange registers fn to be called after every change.
+// The returned function removes the subscription.
+func (n *ChangeNotifier) OnChange(fn ChangeFunc) (unsubscribe func()) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.listeners == nil {
+		n.listeners = make(map[int]ChangeFunc)
+	}
+	id := n.nextID
+	n.nextID++
+	n.listeners[id] = fn
+
+	return func() {
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		delete(n.listeners, id)
+	}
+}
+
+// Notify delivers change to every subscriber
+func (n *ChangeNotifier) Notify(change Change) {
+	n.mu.RLock()
+	listeners := make([]ChangeFunc, 0, len(n.listeners))
+	for _, fn := range n.listeners {
+		listeners = append(listeners, fn)
+	}
+	n.mu.RUnlock()
+
+	for _, fn := range listeners {
+		fn(change)
+	}
+}
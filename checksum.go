@@ -0,0 +1,39 @@
+package doremid
+
+// Validate returns a *FormatError if id is not a valid ID. For generators with
+// Config.Checksum set, a mistyped or transposed symbol usually makes the check
+// character mismatch, reported with ErrChecksum as the cause.
+func (g *Generator) Validate(id string) error {
+	_, err := g.decode(id)
+	return err
+}
+
+// checksumAdd adds the symbol value at index i (counting notes and characters
+// from the left, excluding the separator) to a Luhn mod N sum, N being the
+// number of equal temperament characters.
+//
+// Luhn doubles every second symbol counting leftwards from the check character.
+// IDs have a fixed number of symbols, so the doubled ones are known up front and
+// the sum can be built left to right while generating or parsing.
+func (g *Generator) checksumAdd(sum, i, value int) int {
+	n := g.equalTemperamentLen
+	if (g.JustIntonationDigits+g.EqualTemperamentDigits-1-i)%2 == 0 {
+		value *= 2
+	}
+	// Add the base-N digit sum of the value
+	return sum + value/n + value%n
+}
+
+// checkCharacter returns the character that makes the Luhn mod N sum divisible by N
+func (g *Generator) checkCharacter(sum int) byte {
+	n := g.equalTemperamentLen
+	return g.equalTemperamentBytes[(n-sum%n)%n]
+}
+
+// checksumLen returns the length of the check character, 0 if checksums are disabled
+func (g *Generator) checksumLen() int {
+	if g.checksum {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,129 @@
+package doremid
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestChecksum(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 2,
+		Separator:              "-",
+		Checksum:               true,
+	})
+
+	id := generator.PositionToID(100)
+	if len(id) != 8 {
+		t.Fatalf("expected a check character to be appended, got '%s'", id)
+	}
+
+	for pos := int64(0); pos < generator.MaxCombinations(); pos++ {
+		id := generator.PositionToID(pos)
+		if got := generator.IDToPosition(id); got != pos {
+			t.Fatalf("expected '%s' to round-trip to %d, got %d", id, pos, got)
+		}
+	}
+
+	for i := 0; i < 100; i++ {
+		if id := generator.NewID(); generator.Validate(id) != nil {
+			t.Fatalf("random ID '%s' failed validation: %v", id, generator.Validate(id))
+		}
+	}
+
+	tests := []struct {
+		name     string
+		id       string
+		expected error
+	}{
+		{"valid", generator.PositionToID(100), nil},
+		{"missing check character", generator.PositionToID(100)[:7], ErrInvalidFormat},
+		{"wrong check character", generator.PositionToID(100)[:7] + "x", ErrBadCharacter},
+		{"unknown note", "xxre-00" + generator.PositionToID(0)[7:], ErrBadCharacter},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := generator.Validate(tt.id)
+			if tt.expected == nil && err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+			if tt.expected != nil && !errors.Is(err, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestChecksumDetectsTypos(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 2,
+		Separator:              "-",
+		Checksum:               true,
+	})
+	notes := [][]byte{[]byte("do"), []byte("re"), []byte("mi"), []byte("fa"), []byte("so"), []byte("la"), []byte("ti")}
+	chars := []byte(DefaultCharacters)
+
+	for pos := int64(0); pos < generator.MaxCombinations(); pos++ {
+		id := []byte(generator.PositionToID(pos))
+
+		// Every single-symbol substitution is detected
+		for i := 0; i < 4; i += 2 {
+			for _, note := range notes {
+				typo := append([]byte(nil), id...)
+				copy(typo[i:], note)
+				if string(typo) != string(id) && !errors.Is(generator.Validate(string(typo)), ErrChecksum) {
+					t.Fatalf("substitution '%s' of '%s' not detected", typo, id)
+				}
+			}
+		}
+		for _, i := range []int{5, 6, 7} {
+			for _, char := range chars {
+				typo := append([]byte(nil), id...)
+				typo[i] = char
+				if string(typo) != string(id) && !errors.Is(generator.Validate(string(typo)), ErrChecksum) {
+					t.Fatalf("substitution '%s' of '%s' not detected", typo, id)
+				}
+			}
+		}
+	}
+
+	// Adjacent transpositions of the characters are detected unless the
+	// swapped values are 0 and N-1, a known blind spot of Luhn mod N
+	missed, total := 0, 0
+	for pos := int64(0); pos < generator.MaxCombinations(); pos++ {
+		id := []byte(generator.PositionToID(pos))
+		for _, i := range []int{5, 6} {
+			if id[i] == id[i+1] {
+				continue
+			}
+			typo := append([]byte(nil), id...)
+			typo[i], typo[i+1] = typo[i+1], typo[i]
+			total++
+			if generator.Validate(string(typo)) == nil {
+				missed++
+			}
+		}
+	}
+	if float64(missed)/float64(total) > 0.05 {
+		t.Errorf("expected at least 95%% of transpositions detected, missed %d of %d", missed, total)
+	}
+}
+
+func TestChecksumConfig(t *testing.T) {
+	_, err := NewE(Config{Notes: "a b c d", Characters: "01", Checksum: true})
+	var configErr *ConfigError
+	if !errors.As(err, &configErr) || configErr.Field != "Checksum" {
+		t.Errorf("expected ConfigError for Checksum, got %v", err)
+	}
+
+	generator := New(Config{JustIntonationDigits: 1, EqualTemperamentDigits: 1, Separator: "-", Checksum: true})
+	layout := generator.Debug().Layout
+	if last := layout[len(layout)-1]; last.Name != "checksum" || last.Offset != 4 {
+		t.Errorf("expected checksum at offset 4, got %+v", last)
+	}
+	if generator.Freeze().MaxInputLength() != 5 {
+		t.Errorf("expected max input length 5, got %d", generator.Freeze().MaxInputLength())
+	}
+}
@@ -0,0 +1,34 @@
+package doremid
+
+import "time"
+
+// Clock tells the time to the time-based generators, so their behavior can be
+// tested and injected. See doremidtest.FakeClock for a clock set by hand.
+type Clock interface {
+	// Now returns the current time
+	Now() time.Time
+}
+
+// WallClock is the Clock reading the system time, used when none is configured
+type WallClock struct{}
+
+// Now returns time.Now()
+func (WallClock) Now() time.Time {
+	return time.Now()
+}
+
+// ClockFunc adapts a function to the Clock interface
+type ClockFunc func() time.Time
+
+// Now returns f()
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
+
+// clockOrWall returns clock, or WallClock if it is nil
+func clockOrWall(clock Clock) Clock {
+	if clock == nil {
+		return WallClock{}
+	}
+	return clock
+}
@@ -0,0 +1,199 @@
+package doremid
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// ClusterConfig configures a ClusterGenerator
+type ClusterConfig struct {
+	// NodeID identifies this machine or shard. Every node of a cluster must use a
+	// different NodeID and the same NodeSymbols; it must be below Nodes().
+	NodeID int64
+
+	// NodeSymbols is the number of leading symbols, notes first and then
+	// characters, that encode the node ID. At least one symbol must remain for
+	// the local part. Zero uses one symbol.
+	NodeSymbols int
+
+	// Epoch, if set, makes the local part a timestamp followed by a sequence
+	// number within the tick, like a snowflake ID. Otherwise the local part is a
+	// counter.
+	Epoch time.Time
+
+	// Resolution is the duration of one timestamp tick. Zero uses one millisecond.
+	Resolution time.Duration
+
+	// SequenceSymbols is the number of trailing symbols holding the sequence
+	// number when Epoch is set; the symbols between the node ID and the sequence
+	// encode the timestamp and at least one must remain. Zero uses two.
+	SequenceSymbols int
+
+	// Store persists the counter when Epoch is not set, so issuance resumes after
+	// a restart. Each node needs its own store. Nil keeps the counter in memory
+	// only, so a restarted node issues its IDs again.
+	Store StateStore
+
+	// ReserveSize is the number of counter positions reserved per write to Store.
+	// Zero or negative reserves one position at a time.
+	ReserveSize int64
+
+	// Clock tells the time. Nil uses the system clock.
+	Clock Clock
+}
+
+// ClusterGenerator issues IDs that are unique across machines without
+// coordination: the leading symbols encode a node ID and the rest a local part
+// only that node issues, either a counter or a timestamp and sequence number.
+//
+// In timestamp mode, IDs of a node are strictly increasing; within one tick, or
+// if the clock goes backwards, the previous ID is incremented, spilling into the
+// next tick if its sequence runs out, and reserved and filtered positions are
+// skipped. Uniqueness across restarts then relies on the clock not going back
+// further than the downtime.
+//
+// A ClusterGenerator uses the full ID space of its generator and ignores any
+// restriction. It is safe for concurrent use.
+type ClusterGenerator struct {
+	g        *Generator
+	nodeID   int64
+	nodeSpan int64 // Positions per node, covered by the local part
+
+	// Counter mode
+	counter *SequentialGenerator
+
+	// Timestamp mode
+	epoch      time.Time
+	resolution time.Duration
+	tickSpan   int64 // Positions per tick, covered by the sequence
+	clock      Clock
+
+	mu   sync.Mutex
+	last int64 // Position of the previous ID, -1 before the first
+}
+
+// Cluster returns a ClusterGenerator issuing IDs for config.NodeID with the
+// configuration of g. In counter mode it continues after the position saved in
+// config.Store.
+// Returns a *ConfigError if NodeSymbols leaves no local symbol, NodeID is out of
+// range, Resolution is negative or SequenceSymbols leaves no timestamp symbol,
+// or any error returned by the store.
+func (g *Generator) Cluster(ctx context.Context, config ClusterConfig) (*ClusterGenerator, error) {
+	symbols := g.JustIntonationDigits + g.EqualTemperamentDigits
+	nodeSymbols := config.NodeSymbols
+	if nodeSymbols == 0 {
+		nodeSymbols = 1
+	}
+	if nodeSymbols < 0 || nodeSymbols >= symbols {
+		return nil, &ConfigError{Field: "NodeSymbols", Reason: "must leave at least one local symbol"}
+	}
+
+	c := &ClusterGenerator{g: g, nodeID: config.NodeID, nodeSpan: g.trailingSpan(nodeSymbols), last: -1}
+	if config.NodeID < 0 || config.NodeID >= c.Nodes() {
+		return nil, &ConfigError{Field: "NodeID", Reason: "must be at least 0 and below the number of nodes"}
+	}
+
+	if config.Epoch.IsZero() {
+		// The counter is a sequential generator confined to the node's positions
+		node, r := *g, c.NodeRange(config.NodeID)
+		node.restriction = &r
+		counter, err := node.NewSequentialGenerator(ctx, SequentialConfig{Store: config.Store, ReserveSize: config.ReserveSize})
+		if err != nil {
+			return nil, err
+		}
+		c.counter = counter
+		return c, nil
+	}
+
+	sequenceSymbols := config.SequenceSymbols
+	if sequenceSymbols == 0 {
+		sequenceSymbols = 2
+	}
+	switch {
+	case config.Resolution < 0:
+		return nil, &ConfigError{Field: "Resolution", Reason: "must not be negative"}
+	case sequenceSymbols < 0 || nodeSymbols+sequenceSymbols >= symbols:
+		return nil, &ConfigError{Field: "SequenceSymbols", Reason: "must leave at least one timestamp symbol"}
+	}
+
+	c.epoch = config.Epoch
+	c.resolution = config.Resolution
+	if c.resolution == 0 {
+		c.resolution = time.Millisecond
+	}
+	c.tickSpan = g.trailingSpan(symbols - sequenceSymbols)
+	c.clock = clockOrWall(config.Clock)
+	return c, nil
+}
+
+// NewID issues the next ID of the node.
+// Returns ErrSpaceExhausted once the node's positions are used up or the clock
+// passes the last timestamp, a *RangeError if the clock is before the epoch, or
+// any error returned by the store.
+func (c *ClusterGenerator) NewID(ctx context.Context) (string, error) {
+	if c.counter != nil {
+		return c.counter.Next(ctx)
+	}
+
+	now := c.clock.Now()
+	if now.Before(c.epoch) {
+		return "", &RangeError{Position: -1, Min: 0, Max: c.ticks()}
+	}
+	tick := int64(now.Sub(c.epoch) / c.resolution)
+	if tick >= c.ticks() {
+		return "", ErrSpaceExhausted
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Reserved and filtered positions are skipped like a used sequence number
+	start := c.nodeID * c.nodeSpan
+	pos := c.g.nextAllowed(max(start+tick*c.tickSpan, c.last+1))
+	if pos >= start+c.nodeSpan {
+		return "", ErrSpaceExhausted
+	}
+	c.last = pos
+	return c.g.PositionToID(pos), nil
+}
+
+// NodeIDFromID returns the node ID encoded in id, e.g. to route a request to the
+// shard that issued it. Returns a *FormatError if id is invalid.
+func (c *ClusterGenerator) NodeIDFromID(id string) (int64, error) {
+	pos, err := c.g.decode(id)
+	if err != nil {
+		return -1, err
+	}
+	return pos / c.nodeSpan, nil
+}
+
+// ExtractTimestamp returns the creation time encoded in id, truncated to the
+// resolution. In counter mode it returns the zero time.
+// Returns a *FormatError if id is invalid.
+func (c *ClusterGenerator) ExtractTimestamp(id string) (time.Time, error) {
+	pos, err := c.g.decode(id)
+	if err != nil || c.counter != nil {
+		return time.Time{}, err
+	}
+	return c.epoch.Add(time.Duration(pos%c.nodeSpan/c.tickSpan) * c.resolution), nil
+}
+
+// Nodes returns the number of node IDs the node symbols can encode
+func (c *ClusterGenerator) Nodes() int64 {
+	return c.g.MaxCombinations() / c.nodeSpan
+}
+
+// NodeRange returns the positions reserved for nodeID, e.g. for range scans over
+// binary keys. The range is empty if nodeID is out of range.
+func (c *ClusterGenerator) NodeRange(nodeID int64) Range {
+	if nodeID < 0 || nodeID >= c.Nodes() {
+		return Range{}
+	}
+	return Range{Start: nodeID * c.nodeSpan, End: (nodeID + 1) * c.nodeSpan}
+}
+
+// ticks returns the number of timestamps the local part covers
+func (c *ClusterGenerator) ticks() int64 {
+	return c.nodeSpan / c.tickSpan
+}
@@ -0,0 +1,199 @@
+package doremid
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestClusterGeneratorCounter(t *testing.T) {
+	ctx := context.Background()
+	generator := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 3,
+		Separator:              "-",
+	})
+
+	// Two notes for the node ID leave 12^3 positions per node
+	seen := make(map[string]bool)
+	for _, node := range []int64{0, 1, 48} {
+		c, err := generator.Cluster(ctx, ClusterConfig{NodeID: node, NodeSymbols: 2})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c.Nodes() != 49 {
+			t.Fatalf("expected 49 nodes, got %d", c.Nodes())
+		}
+
+		for i := int64(0); i < 3; i++ {
+			id, err := c.NewID(ctx)
+			if expected := generator.PositionToID(node*1728 + i); err != nil || id != expected {
+				t.Fatalf("node %d: expected '%s', got '%s' (err: %v)", node, expected, id, err)
+			}
+			if seen[id] {
+				t.Fatalf("expected unique IDs across nodes, got '%s' twice", id)
+			}
+			seen[id] = true
+
+			if got, err := c.NodeIDFromID(id); err != nil || got != node {
+				t.Errorf("expected node %d for '%s', got %d (err: %v)", node, id, got, err)
+			}
+		}
+	}
+
+	// With a store the counter resumes after a restart
+	counters := NewMemoryCounterStore()
+	config := ClusterConfig{NodeID: 2, Store: CounterState(counters, "node-2"), ReserveSize: 5}
+	c, _ := generator.Cluster(ctx, config)
+	if id, _ := c.NewID(ctx); id != "mido-000" {
+		t.Errorf("expected 'mido-000', got '%s'", id)
+	}
+	restarted, err := generator.Cluster(ctx, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id, _ := restarted.NewID(ctx); id != "mido-005" {
+		t.Errorf("expected the reservation to be skipped, got '%s'", id)
+	}
+
+	// The node's positions run out rather than spilling into the next node
+	small := New(Config{JustIntonationDigits: 1, EqualTemperamentDigits: 1})
+	last, _ := small.Cluster(ctx, ClusterConfig{NodeID: 6})
+	for range 12 {
+		if _, err := last.NewID(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := last.NewID(ctx); !errors.Is(err, ErrSpaceExhausted) {
+		t.Errorf("expected ErrSpaceExhausted, got %v", err)
+	}
+
+	if _, err := c.NodeIDFromID("invalid"); !errors.Is(err, ErrInvalidID) {
+		t.Errorf("expected ErrInvalidID, got %v", err)
+	}
+}
+
+func TestClusterGeneratorTimestamp(t *testing.T) {
+	ctx := context.Background()
+	epoch := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	generator := New(Config{
+		JustIntonationDigits:   8,
+		EqualTemperamentDigits: 6,
+		Separator:              "-",
+	})
+	c, err := generator.Cluster(ctx, ClusterConfig{NodeID: 5, Epoch: epoch})
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock := epoch.Add(time.Hour + 1500*time.Microsecond)
+	c.clock = ClockFunc(func() time.Time { return clock })
+
+	// 144 IDs per millisecond, then the sequence spills into the next tick
+	tick := epoch.Add(time.Hour + time.Millisecond)
+	var previous int64 = -1
+	for i := 0; i < 150; i++ {
+		id, err := c.NewID(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pos := generator.IDToPosition(id)
+		if pos <= previous {
+			t.Fatalf("expected increasing positions, got %d after %d", pos, previous)
+		}
+		previous = pos
+
+		expected := tick
+		if i >= 144 {
+			expected = tick.Add(time.Millisecond)
+		}
+		if timestamp, err := c.ExtractTimestamp(id); err != nil || !timestamp.Equal(expected) {
+			t.Fatalf("expected timestamp %v for '%s', got %v (err: %v)", expected, id, timestamp, err)
+		}
+		if node, _ := c.NodeIDFromID(id); node != 5 {
+			t.Fatalf("expected node 5 for '%s', got %d", id, node)
+		}
+	}
+	if r := c.NodeRange(5); !r.Contains(previous) || c.NodeRange(4).Contains(previous) {
+		t.Errorf("expected position %d in the range of node 5 only", previous)
+	}
+
+	// A clock going backwards keeps IDs increasing
+	clock = epoch
+	if id, _ := c.NewID(ctx); generator.IDToPosition(id) <= previous {
+		t.Errorf("expected an increasing ID after clock moved back, got '%s'", id)
+	}
+
+	clock = epoch.Add(-time.Second)
+	if _, err := c.NewID(ctx); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("expected ErrOutOfRange before the epoch, got %v", err)
+	}
+}
+
+func TestClusterGeneratorSkipsReserved(t *testing.T) {
+	ctx := context.Background()
+	epoch := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	reserved := []int64{0, 1, 2}
+	for pos := int64(12); pos < 24; pos++ {
+		reserved = append(reserved, pos) // All of tick 1
+	}
+	generator := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 2,
+		Separator:              "-",
+		ReservedPositions:      reserved,
+	})
+	c, err := generator.Cluster(ctx, ClusterConfig{NodeID: 0, Epoch: epoch, SequenceSymbols: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock := epoch
+	c.clock = ClockFunc(func() time.Time { return clock })
+
+	for _, expected := range []int64{3, 4} {
+		if id, err := c.NewID(ctx); err != nil || generator.IDToPosition(id) != expected {
+			t.Errorf("expected position %d, got '%s' (err: %v)", expected, id, err)
+		}
+	}
+	clock = epoch.Add(time.Millisecond)
+	if id, err := c.NewID(ctx); err != nil || generator.IDToPosition(id) != 24 {
+		t.Errorf("expected a reserved tick to spill into the next, got '%s' (err: %v)", id, err)
+	}
+}
+
+func TestClusterConfigErrors(t *testing.T) {
+	ctx := context.Background()
+	generator := New(Config{JustIntonationDigits: 2, EqualTemperamentDigits: 3})
+	epoch := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		config ClusterConfig
+		field  string
+	}{
+		{"negative node", ClusterConfig{NodeID: -1}, "NodeID"},
+		{"node beyond symbols", ClusterConfig{NodeID: 7}, "NodeID"},
+		{"no local symbol", ClusterConfig{NodeSymbols: 5}, "NodeSymbols"},
+		{"negative node symbols", ClusterConfig{NodeSymbols: -1}, "NodeSymbols"},
+		{"negative resolution", ClusterConfig{Epoch: epoch, Resolution: -time.Second}, "Resolution"},
+		{"no timestamp symbol", ClusterConfig{Epoch: epoch, SequenceSymbols: 4}, "SequenceSymbols"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := generator.Cluster(ctx, tt.config)
+			var configErr *ConfigError
+			if !errors.As(err, &configErr) || configErr.Field != tt.field {
+				t.Errorf("expected ConfigError for %s, got %v", tt.field, err)
+			}
+		})
+	}
+
+	c, err := generator.Cluster(ctx, ClusterConfig{Store: failingState{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.NewID(ctx); err == nil {
+		t.Error("expected the store error")
+	}
+}
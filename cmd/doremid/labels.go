@@ -0,0 +1,306 @@
+package main
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/doremi-id/doremid"
+	"github.com/doremi-id/doremid/internal/qr"
+)
+
+// labelTemplate describes a sheet of labels in points, measured from the top
+// left corner of the page
+type labelTemplate struct {
+	pageWidth, pageHeight   float64
+	columns, rows           int
+	labelWidth, labelHeight float64
+	left, top               float64 // Position of the first label
+	pitchX, pitchY          float64 // Distance between neighbouring labels
+}
+
+var labelTemplates = map[string]labelTemplate{
+	// Avery 5160: US Letter, 30 labels of 2 5/8 x 1 in
+	"avery5160": {612, 792, 3, 10, 189, 72, 13.5, 36, 198, 72},
+	// Avery L7160: A4, 21 labels of 63.5 x 38.1 mm
+	"averyl7160": {595.28, 841.89, 3, 7, 180, 108, 20.41, 42.94, 187.2, 108},
+}
+
+// pngDPI is the resolution of PNG sheets
+const pngDPI = 300
+
+// canvas is one page being drawn, in points from the top left corner
+type canvas interface {
+	// fill paints a black rectangle
+	fill(x, y, w, h float64)
+
+	// text writes s in a monospaced font whose advance is 0.6 size
+	text(x, baseline, size float64, s string)
+}
+
+// labels writes a printable sheet of ID labels with QR codes
+func labels(args []string, w io.Writer) error {
+	c := newCommand("labels")
+	c.config.Checksum = true // Labels carry check characters unless -checksum=false
+	count := c.flags.Int64("count", 30, "number of labels")
+	start := c.flags.Int64("start", -1, "first position of sequential IDs; random if negative")
+	name := c.flags.String("template", "avery5160", "label sheet: avery5160 or averyl7160")
+	output := c.flags.String("o", "labels.pdf", "output file, .pdf or .png")
+	generator, err := c.parse(args)
+	if err != nil {
+		return err
+	}
+
+	template, ok := labelTemplates[*name]
+	if !ok {
+		return fmt.Errorf("unknown template %q", *name)
+	}
+	ext := strings.ToLower(filepath.Ext(*output))
+	if ext != ".pdf" && ext != ".png" {
+		return fmt.Errorf("output %q must end in .pdf or .png", *output)
+	}
+	ids, err := mintIDs(generator, *count, *start)
+	if err != nil {
+		return err
+	}
+
+	var pdf pdfDocument
+	perPage := template.columns * template.rows
+	for page := 0; page*perPage < len(ids); page++ {
+		var sheet canvas
+		if ext == ".pdf" {
+			sheet = pdf.addPage(template)
+		} else {
+			sheet = newPNGPage(template)
+		}
+
+		for i, id := range ids[page*perPage : min((page+1)*perPage, len(ids))] {
+			x := template.left + float64(i%template.columns)*template.pitchX
+			y := template.top + float64(i/template.columns)*template.pitchY
+			if err := drawLabel(sheet, template, x, y, id); err != nil {
+				return err
+			}
+		}
+
+		if ext == ".png" {
+			// Sheets after the first are numbered, e.g. labels-2.png
+			path := *output
+			if page > 0 {
+				path = fmt.Sprintf("%s-%d%s", strings.TrimSuffix(path, filepath.Ext(path)), page+1, filepath.Ext(path))
+			}
+			if err := writeFile(path, func(f io.Writer) error { return png.Encode(f, sheet.(*pngPage).img) }); err != nil {
+				return err
+			}
+			fmt.Fprintln(w, "wrote", path)
+		}
+	}
+
+	if ext == ".pdf" {
+		if err := writeFile(*output, pdf.write); err != nil {
+			return err
+		}
+		fmt.Fprintln(w, "wrote", *output)
+	}
+	return nil
+}
+
+// drawLabel draws the label of id with its top left corner at x, y: a QR code
+// on the left and the ID on the right
+func drawLabel(c canvas, template labelTemplate, x, y float64, id string) error {
+	code, err := qr.Encode([]byte(id))
+	if err != nil {
+		return fmt.Errorf("%s: %w", id, err)
+	}
+
+	// The QR code fills the label height with a two-module quiet zone
+	pad := template.labelHeight * 0.08
+	side := template.labelHeight - 2*pad
+	module := side / float64(code.Size+4)
+	left, top := x+pad+2*module, y+pad+2*module
+	for row := range code.Size {
+		// Draw each run of dark modules as one rectangle
+		for col := 0; col < code.Size; col++ {
+			if !code.Dark(col, row) {
+				continue
+			}
+			run := col
+			for run < code.Size && code.Dark(run, row) {
+				run++
+			}
+			c.fill(left+float64(col)*module, top+float64(row)*module, float64(run-col)*module, module)
+			col = run
+		}
+	}
+
+	textLeft := x + 2*pad + side
+	width := x + template.labelWidth - pad - textLeft
+	size := min(12, width/(0.6*float64(utf8.RuneCountInString(id))))
+	c.text(textLeft, y+template.labelHeight/2+0.35*size, size, id)
+	return nil
+}
+
+// mintIDs returns count unique random IDs, or sequential IDs from start if it is not negative
+func mintIDs(generator *doremid.Generator, count, start int64) ([]string, error) {
+	if count <= 0 {
+		return nil, fmt.Errorf("%w: count must be positive", doremid.ErrInvalidCount)
+	}
+
+	if start >= 0 {
+		ids := generator.BatchGenerateIDs(count, start)
+		if len(ids) == 0 {
+			return nil, &doremid.RangeError{Position: start, Min: 0, Max: generator.MaxCombinations()}
+		}
+		return ids, nil
+	}
+
+	ids := generator.BatchGenerateRandomIDs(count)
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("%w: count exceeds the %d available IDs", doremid.ErrInvalidCount, generator.MaxCombinations())
+	}
+	return ids, nil
+}
+
+// writeFile creates path and writes it with write
+func writeFile(path string, write func(io.Writer) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// pdfDocument collects vector pages and writes them as a PDF file
+type pdfDocument struct {
+	width, height float64
+	pages         []*pdfPage
+}
+
+// pdfPage is the content stream of one page
+type pdfPage struct {
+	height  float64
+	content bytes.Buffer
+}
+
+func (d *pdfDocument) addPage(template labelTemplate) *pdfPage {
+	d.width, d.height = template.pageWidth, template.pageHeight
+	page := &pdfPage{height: template.pageHeight}
+	d.pages = append(d.pages, page)
+	return page
+}
+
+func (p *pdfPage) fill(x, y, w, h float64) {
+	fmt.Fprintf(&p.content, "%.3f %.3f %.3f %.3f re f\n", x, p.height-y-h, w, h)
+}
+
+func (p *pdfPage) text(x, baseline, size float64, s string) {
+	// The standard Courier font covers ASCII; anything else is replaced
+	var escaped strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			escaped.WriteByte('\\')
+			escaped.WriteRune(r)
+		case r < 0x20 || r > 0x7e:
+			escaped.WriteByte('?')
+		default:
+			escaped.WriteRune(r)
+		}
+	}
+	fmt.Fprintf(&p.content, "BT /F1 %.2f Tf %.3f %.3f Td (%s) Tj ET\n", size, x, p.height-baseline, escaped.String())
+}
+
+// write writes the document with one object per page and content stream after
+// the catalog, the page tree and the font
+func (d *pdfDocument) write(w io.Writer) error {
+	var out bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n")
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 4+2*i)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Courier >>")
+	for i, page := range d.pages {
+		var stream bytes.Buffer
+		compressor := zlib.NewWriter(&stream)
+		compressor.Write(page.content.Bytes())
+		compressor.Close()
+
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>",
+			d.width, d.height, 5+2*i))
+		object(fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", stream.Len(), stream.Bytes()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	_, err := w.Write(out.Bytes())
+	return err
+}
+
+// pngPage is a page rendered at pngDPI
+type pngPage struct {
+	img   *image.Gray
+	scale float64 // Pixels per point
+}
+
+func newPNGPage(template labelTemplate) *pngPage {
+	scale := float64(pngDPI) / 72
+	img := image.NewGray(image.Rect(0, 0, int(template.pageWidth*scale), int(template.pageHeight*scale)))
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+	return &pngPage{img: img, scale: scale}
+}
+
+func (p *pngPage) fill(x, y, w, h float64) {
+	// Round edges rather than sizes so adjacent rectangles leave no gaps
+	r := image.Rect(p.px(x), p.px(y), p.px(x+w), p.px(y+h))
+	draw.Draw(p.img, r, image.Black, image.Point{}, draw.Src)
+}
+
+func (p *pngPage) text(x, baseline, size float64, s string) {
+	// Each glyph cell is 6 by 7 dots, so a dot is a tenth of the font size
+	dot := max(1, int(math.Round(size/10*p.scale)))
+	left, top := p.px(x), p.px(baseline)-7*dot
+	for i, r := range []rune(s) {
+		glyph, ok := glyphs[r]
+		if !ok {
+			glyph = missingGlyph
+		}
+		for row, line := range glyph {
+			for col, c := range line {
+				if c == '#' {
+					x0, y0 := left+(6*i+col)*dot, top+row*dot
+					draw.Draw(p.img, image.Rect(x0, y0, x0+dot, y0+dot), image.Black, image.Point{}, draw.Src)
+				}
+			}
+		}
+	}
+}
+
+// px converts points to pixels
+func (p *pngPage) px(points float64) int {
+	return int(math.Round(points * p.scale))
+}
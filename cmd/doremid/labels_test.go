@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/doremi-id/doremid/internal/qr"
+)
+
+func TestLabelsPDF(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sheet.pdf")
+	var out bytes.Buffer
+	if err := run([]string{"labels", "-count", "31", "-start", "0", "-o", path}, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "wrote "+path+"\n" {
+		t.Errorf("expected path to be reported, got %q", out.String())
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pdf := string(data)
+	for _, expected := range []string{"%PDF-1.4\n", "/Count 2 >>", "/MediaBox [0 0 612.00 792.00]", "/BaseFont /Courier", "trailer\n<< /Size 8 /Root 1 0 R >>"} {
+		if !strings.Contains(pdf, expected) {
+			t.Errorf("expected PDF to contain %q", expected)
+		}
+	}
+	if !strings.HasSuffix(pdf, "%%EOF\n") {
+		t.Error("expected PDF to end with an EOF marker")
+	}
+}
+
+func TestLabelsPNG(t *testing.T) {
+	dir := t.TempDir()
+	var out bytes.Buffer
+	if err := run([]string{"labels", "-count", "22", "-template", "averyl7160", "-o", filepath.Join(dir, "sheet.png")}, &out); err != nil {
+		t.Fatal(err)
+	}
+
+	// A4 at 300 dpi, with the 22nd label on a second sheet
+	for _, name := range []string{"sheet.png", "sheet-2.png"} {
+		f, err := os.Open(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		config, err := png.DecodeConfig(f)
+		f.Close()
+		if err != nil || config.Width != 2480 || config.Height != 3507 {
+			t.Errorf("%s: expected 2480x3507, got %dx%d (err: %v)", name, config.Width, config.Height, err)
+		}
+	}
+	if lines := strings.Count(out.String(), "wrote "); lines != 2 {
+		t.Errorf("expected 2 files to be reported, got %d", lines)
+	}
+}
+
+func TestDrawLabel(t *testing.T) {
+	template := labelTemplates["avery5160"]
+	var page pdfPage
+	page.height = template.pageHeight
+	if err := drawLabel(&page, template, 0, 0, "dodododo-000000"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The ID is written once, after the rectangles of the QR code
+	content := page.content.String()
+	if strings.Count(content, "(dodododo-000000) Tj") != 1 {
+		t.Errorf("expected the ID to be written once, got %q", content)
+	}
+	if !strings.Contains(content, " re f\n") {
+		t.Error("expected the QR code to be drawn")
+	}
+
+	if err := drawLabel(&page, template, 0, 0, strings.Repeat("a", 300)); !errors.Is(err, qr.ErrTooLong) {
+		t.Errorf("expected qr.ErrTooLong, got %v", err)
+	}
+}
+
+func TestPDFText(t *testing.T) {
+	var page pdfPage
+	page.height = 100
+	page.text(10, 20, 12, `a(b)\c–`)
+
+	if expected := "BT /F1 12.00 Tf 10.000 80.000 Td (a\\(b\\)\\\\c?) Tj ET\n"; page.content.String() != expected {
+		t.Errorf("expected %q, got %q", expected, page.content.String())
+	}
+}
+
+func TestLabelsErrors(t *testing.T) {
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"labels", "-template", "avery1234"},
+		{"labels", "-o", filepath.Join(dir, "sheet.svg")},
+		{"labels", "-count", "0"},
+		{"labels", "-o", filepath.Join(dir, "missing", "sheet.pdf")},
+	} {
+		if err := run(args, &bytes.Buffer{}); err == nil {
+			t.Errorf("expected error for %q", args)
+		}
+	}
+}
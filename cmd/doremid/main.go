@@ -0,0 +1,317 @@
+// Command doremid mints and inspects DoReMi IDs.
+//
+// Usage:
+//
+//	doremid new [flags]
+//	doremid batch [-count 10] [-start P] [flags]
+//	doremid decode [flags] <id>...
+//	doremid encode [flags] <position>...
+//	doremid labels [-count 30] [-start P] [-template avery5160] [-o labels.pdf] [flags]
+//	doremid play [-o file.wav] [-duration 300ms] [flags] <id>
+//	doremid info [flags]
+//
+// Every command accepts flags mirroring Config:
+//
+//	-just 4 -equal 5 -sep - -notes 'do re mi ...' -chars 0123456789ab
+//	-checksum -prefix usr -prefix-sep _ -secure
+//
+// new, batch, decode and encode print plain text by default; -format json
+// prints one JSON object per line and -format csv prints id,position rows
+// after a header. batch mints random unique IDs, or sequential IDs from -start
+// if it is given. labels writes a printable PDF, or PNG sheets, of labels
+// showing each ID with a QR code, with check characters unless -checksum=false;
+// templates are avery5160 (US Letter) and averyl7160 (A4). play renders the
+// ID's melody and plays it with the first
+// audio player found (afplay, paplay, aplay or ffplay), or writes it to
+// <id>.wav if there is none or -o is given. The info command prints the generator's internals as JSON,
+// suitable for attaching to a support bundle.
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+
+	"github.com/doremi-id/doremid"
+	"github.com/doremi-id/doremid/audio"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "doremid:", err)
+		os.Exit(2)
+	}
+}
+
+// run executes the command line args, writing results to w
+func run(args []string, w io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: doremid new|batch|decode|encode|labels|play|info [flags]")
+	}
+
+	switch args[0] {
+	case "new":
+		return newID(args[1:], w)
+	case "batch":
+		return batch(args[1:], w)
+	case "decode":
+		return decode(args[1:], w)
+	case "encode":
+		return encode(args[1:], w)
+	case "labels":
+		return labels(args[1:], w)
+	case "play":
+		return play(args[1:], w)
+	case "info":
+		return info(args[1:], w)
+	default:
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+}
+
+// record is one line of output
+type record struct {
+	ID       string `json:"id"`
+	Position int64  `json:"position"`
+}
+
+// command holds the flags shared by all commands
+type command struct {
+	flags  *flag.FlagSet
+	config doremid.Config
+	format string
+}
+
+// newCommand returns a command whose flag set mirrors Config
+func newCommand(name string) *command {
+	c := &command{config: doremid.DefaultConfig()}
+	c.flags = flag.NewFlagSet(name, flag.ContinueOnError)
+	c.flags.SetOutput(io.Discard)
+	c.flags.IntVar(&c.config.JustIntonationDigits, "just", c.config.JustIntonationDigits, "number of musical note pairs")
+	c.flags.IntVar(&c.config.EqualTemperamentDigits, "equal", c.config.EqualTemperamentDigits, "number of twelve-tone characters")
+	c.flags.Int64Var(&c.config.MinCombinations, "capacity", 0, "pick -just and -equal for at least this many IDs")
+	c.flags.StringVar(&c.config.Separator, "sep", c.config.Separator, "separator between the two parts")
+	c.flags.StringVar(&c.config.Notes, "notes", "", "space-separated custom notes")
+	c.flags.StringVar(&c.config.Characters, "chars", "", "custom twelve-tone character set")
+	c.flags.IntVar(&c.config.EqualTemperamentBase, "base", 0, "number of equal temperament characters, e.g. 16 or 31")
+	c.flags.BoolVar(&c.config.Checksum, "checksum", false, "append a check character")
+	c.flags.StringVar(&c.config.Prefix, "prefix", "", "namespace prefix")
+	c.flags.StringVar(&c.config.PrefixSeparator, "prefix-sep", "", "separator after the prefix")
+	c.flags.BoolVar(&c.config.SecureRandom, "secure", false, "draw random IDs from crypto/rand")
+	c.flags.StringVar(&c.format, "format", "plain", "output format: plain, json or csv")
+	return c
+}
+
+// parse parses args and returns the configured generator
+func (c *command) parse(args []string) (*doremid.Generator, error) {
+	if err := c.flags.Parse(args); err != nil {
+		return nil, err
+	}
+	if c.format != "plain" && c.format != "json" && c.format != "csv" {
+		return nil, fmt.Errorf("unknown format %q", c.format)
+	}
+	return doremid.NewE(c.config)
+}
+
+// write prints records in the command's format, using plain for each line of plain text
+func (c *command) write(w io.Writer, records []record, plain func(record) string) error {
+	switch c.format {
+	case "json":
+		encoder := json.NewEncoder(w)
+		for _, r := range records {
+			if err := encoder.Encode(r); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "csv":
+		writer := csv.NewWriter(w)
+		writer.Write([]string{"id", "position"})
+		for _, r := range records {
+			writer.Write([]string{r.ID, strconv.FormatInt(r.Position, 10)})
+		}
+		writer.Flush()
+		return writer.Error()
+	default:
+		for _, r := range records {
+			if _, err := fmt.Fprintln(w, plain(r)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// plainID prints the ID of a record
+func plainID(r record) string { return r.ID }
+
+// newID prints a random ID
+func newID(args []string, w io.Writer) error {
+	c := newCommand("new")
+	generator, err := c.parse(args)
+	if err != nil {
+		return err
+	}
+
+	id := generator.NewID()
+	if id == "" {
+		return doremid.ErrSpaceExhausted
+	}
+	return c.write(w, []record{{ID: id, Position: generator.IDToPosition(id)}}, plainID)
+}
+
+// batch prints count unique random IDs, or sequential IDs from -start
+func batch(args []string, w io.Writer) error {
+	c := newCommand("batch")
+	count := c.flags.Int64("count", 10, "number of IDs")
+	start := c.flags.Int64("start", -1, "first position of sequential IDs; random if negative")
+	generator, err := c.parse(args)
+	if err != nil {
+		return err
+	}
+
+	ids, err := mintIDs(generator, *count, *start)
+	if err != nil {
+		return err
+	}
+
+	records := make([]record, len(ids))
+	for i, id := range ids {
+		records[i] = record{ID: id, Position: generator.IDToPosition(id)}
+	}
+	return c.write(w, records, plainID)
+}
+
+// decode prints the position of each ID argument
+func decode(args []string, w io.Writer) error {
+	c := newCommand("decode")
+	generator, err := c.parse(args)
+	if err != nil {
+		return err
+	}
+	if c.flags.NArg() == 0 {
+		return fmt.Errorf("usage: doremid decode [flags] <id>...")
+	}
+
+	records := make([]record, c.flags.NArg())
+	for i, id := range c.flags.Args() {
+		pos, err := generator.IDToPositionE(id)
+		if err != nil {
+			return err
+		}
+		records[i] = record{ID: id, Position: pos}
+	}
+	return c.write(w, records, func(r record) string { return strconv.FormatInt(r.Position, 10) })
+}
+
+// encode prints the ID at each position argument
+func encode(args []string, w io.Writer) error {
+	c := newCommand("encode")
+	generator, err := c.parse(args)
+	if err != nil {
+		return err
+	}
+	if c.flags.NArg() == 0 {
+		return fmt.Errorf("usage: doremid encode [flags] <position>...")
+	}
+
+	records := make([]record, c.flags.NArg())
+	for i, arg := range c.flags.Args() {
+		pos, err := strconv.ParseInt(arg, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid position %q", arg)
+		}
+		id, err := generator.PositionToIDE(pos)
+		if err != nil {
+			return err
+		}
+		records[i] = record{ID: id, Position: pos}
+	}
+	return c.write(w, records, plainID)
+}
+
+// players are the commands tried in order to play a WAV file, each followed by
+// its arguments before the file name
+var players = [][]string{
+	{"afplay"},
+	{"paplay"},
+	{"aplay", "-q"},
+	{"ffplay", "-nodisp", "-autoexit", "-loglevel", "quiet"},
+}
+
+// play renders the melody of an ID and plays it, or writes it as a WAV file
+func play(args []string, w io.Writer) error {
+	c := newCommand("play")
+	output := c.flags.String("o", "", "write the WAV file to this path instead of playing it")
+	duration := c.flags.Duration("duration", audio.DefaultNoteDuration, "length of each note")
+	generator, err := c.parse(args)
+	if err != nil {
+		return err
+	}
+	if c.flags.NArg() != 1 {
+		return fmt.Errorf("usage: doremid play [flags] <id>")
+	}
+	id := c.flags.Arg(0)
+
+	options := audio.Options{NoteDuration: *duration}
+	tones, err := audio.Melody(generator, id, options)
+	if err != nil {
+		return err
+	}
+
+	var player []string
+	if *output == "" {
+		for _, candidate := range players {
+			if _, err := exec.LookPath(candidate[0]); err == nil {
+				player = candidate
+				break
+			}
+		}
+	}
+
+	var file *os.File
+	if player != nil {
+		file, err = os.CreateTemp("", "doremid-*.wav")
+		if err == nil {
+			defer os.Remove(file.Name())
+		}
+	} else {
+		if *output == "" {
+			*output = id + ".wav"
+		}
+		file, err = os.Create(*output)
+	}
+	if err != nil {
+		return err
+	}
+	if err := audio.WriteWAV(file, tones, options); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	if player == nil {
+		_, err := fmt.Fprintln(w, "wrote", file.Name())
+		return err
+	}
+	return exec.Command(player[0], append(player[1:], file.Name())...).Run()
+}
+
+// info prints the Debug output of the configured generator
+func info(args []string, w io.Writer) error {
+	generator, err := newCommand("info").parse(args)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(generator.Debug())
+}
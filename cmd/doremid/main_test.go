@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/doremi-id/doremid"
+)
+
+func TestInfo(t *testing.T) {
+	var out bytes.Buffer
+	if err := run([]string{"info", "-just", "2", "-equal", "3", "-sep", "_"}, &out); err != nil {
+		t.Fatal(err)
+	}
+
+	var info doremid.DebugInfo
+	if err := json.Unmarshal(out.Bytes(), &info); err != nil {
+		t.Fatalf("expected JSON output, got %v: %s", err, out.String())
+	}
+	if info.MaxCombinations != 84672 || len(info.Radices) != 5 {
+		t.Errorf("unexpected info %+v", info)
+	}
+}
+
+func TestCommands(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{"encode", []string{"encode", "-just", "2", "-equal", "3", "0", "3722"}, "dodo-000\ndomi-1a2\n"},
+		{"encode with prefix and checksum", []string{"encode", "-just", "2", "-equal", "3", "-prefix", "usr", "-checksum", "3722"}, "usr_domi-1a26\n"},
+		{"decode", []string{"decode", "-just", "2", "-equal", "3", "domi-1a2"}, "3722\n"},
+		{"encode in hex", []string{"encode", "-just", "1", "-equal", "2", "-base", "16", "167"}, "do-a7\n"},
+		{"encode by capacity", []string{"encode", "-capacity", "84672", "3722"}, "domi-1a2\n"},
+		{"decode json", []string{"decode", "-just", "2", "-equal", "3", "-format", "json", "domi-1a2"}, `{"id":"domi-1a2","position":3722}` + "\n"},
+		{"batch csv", []string{"batch", "--count", "2", "--start", "5", "-just", "2", "-equal", "3", "-format", "csv"}, "id,position\ndodo-005,5\ndodo-006,6\n"},
+		{"batch clipped", []string{"batch", "-count", "5", "-start", "83", "-just", "1", "-equal", "1", "-sep", ""}, "tib\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := run(tt.args, &out); err != nil {
+				t.Fatal(err)
+			}
+			if out.String() != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, out.String())
+			}
+		})
+	}
+}
+
+func TestRandomCommands(t *testing.T) {
+	var out bytes.Buffer
+	if err := run([]string{"batch", "-count", "20", "-just", "2", "-equal", "3", "-secure"}, &out); err != nil {
+		t.Fatal(err)
+	}
+	ids := strings.Fields(out.String())
+	seen := map[string]bool{}
+	for _, id := range ids {
+		seen[id] = true
+	}
+	if len(ids) != 20 || len(seen) != 20 {
+		t.Errorf("expected 20 unique IDs, got %q", ids)
+	}
+
+	out.Reset()
+	if err := run([]string{"new", "-format", "json"}, &out); err != nil {
+		t.Fatal(err)
+	}
+	var r record
+	if err := json.Unmarshal(out.Bytes(), &r); err != nil || doremid.NewWithDefaults().IDToPosition(r.ID) != r.Position {
+		t.Errorf("expected a JSON record, got %s (err: %v)", out.String(), err)
+	}
+}
+
+func TestPlay(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "melody.wav")
+	var out bytes.Buffer
+	if err := run([]string{"play", "-o", path, "-duration", "10ms", "domi-1a2"}, &out); err == nil {
+		t.Error("expected error for an ID of another configuration")
+	}
+	if err := run([]string{"play", "-o", path, "-duration", "10ms", "-just", "2", "-equal", "3", "domi-1a2"}, &out); err != nil {
+		t.Fatal(err)
+	}
+
+	// Two notes, a rest and three characters of 441 samples each
+	data, err := os.ReadFile(path)
+	if err != nil || string(data[:4]) != "RIFF" || len(data) != 44+6*441*2 {
+		t.Errorf("expected a WAV file of 6 tones, got %d bytes (err: %v)", len(data), err)
+	}
+	if out.String() != "wrote "+path+"\n" {
+		t.Errorf("expected path to be reported, got %q", out.String())
+	}
+
+	// Without an audio player the melody is written next to the caller
+	t.Setenv("PATH", "")
+	t.Chdir(dir)
+	out.Reset()
+	if err := run([]string{"play", "-duration", "10ms", "dodododo-00000"}, &out); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "dodododo-00000.wav")); err != nil {
+		t.Errorf("expected dodododo-00000.wav, got %v (output %q)", err, out.String())
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	for _, args := range [][]string{nil, {"bogus"}, {"info", "-unknown"}, {"info", "-notes", "so sol"},
+		{"new", "-format", "xml"}, {"decode"}, {"decode", "domi-1a2"}, {"encode", "-1"}, {"encode", "x"},
+		{"batch", "-count", "0"}, {"play"}, {"play", "dodododo-00000", "dodododo-00001"}, {"batch", "-count", "85", "-just", "1", "-equal", "1"}, {"batch", "-start", "84", "-just", "1", "-equal", "1"},
+	} {
+		if err := run(args, &bytes.Buffer{}); err == nil {
+			t.Errorf("expected error for %q", args)
+		}
+	}
+}
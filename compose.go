@@ -0,0 +1,178 @@
+package doremid
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ComponentSpec describes one segment of a composite ID
+type ComponentSpec struct {
+	// Name identifies the segment in error messages, e.g. "region"
+	Name string
+
+	// Generator encodes and decodes the segment
+	Generator *Generator
+
+	// Separator is placed before the segment; it is ignored for the first segment
+	Separator string
+
+	// Random makes Composite.NewID fill the segment with a random ID from Generator
+	// instead of taking its position from the caller
+	Random bool
+}
+
+// Composite builds and parses IDs whose segments come from different generators,
+// e.g. a region generator, an entity generator and a random tail.
+type Composite struct {
+	parts     []ComponentSpec
+	widths    []int // Width of each segment's longest ID
+	minLength int   // Shortest composite ID, including separators
+	length    int   // Longest composite ID, including separators
+	fixed     int   // Number of segments whose position is supplied by the caller
+}
+
+// Compose creates a Composite from its segments, in order. Segments whose
+// custom notes differ in length vary in width, so Parse measures each segment
+// by the notes it reads.
+func Compose(parts ...ComponentSpec) *Composite {
+	c := &Composite{
+		parts:  append([]ComponentSpec(nil), parts...),
+		widths: make([]int, len(parts)),
+	}
+
+	for i, part := range c.parts {
+		g := part.Generator
+		shortest, longest := g.compactLengths()
+		notes, characters := g.groupOverhead()
+		c.widths[i] = longest + notes + characters
+		c.minLength += shortest + notes + characters
+		c.length += c.widths[i]
+		if i > 0 {
+			c.minLength += len(part.Separator)
+			c.length += len(part.Separator)
+		}
+		if !part.Random {
+			c.fixed++
+		}
+	}
+
+	return c
+}
+
+// Len returns the length of the longest composite ID, which is the length of
+// every composite ID unless custom notes differ in length
+func (c *Composite) Len() int {
+	return c.length
+}
+
+// Format builds a composite ID from one position per segment.
+//
+// Returns an error if the number of positions does not match the number of
+// segments, or an error wrapping a *RangeError if a position is out of range
+// for its segment.
+func (c *Composite) Format(positions ...int64) (string, error) {
+	if len(positions) != len(c.parts) {
+		return "", fmt.Errorf("doremid: composite needs %d positions, got %d", len(c.parts), len(positions))
+	}
+
+	var b strings.Builder
+	b.Grow(c.length)
+	for i, part := range c.parts {
+		if err := checkRange(positions[i], Range{Start: 0, End: part.Generator.MaxCombinations()}); err != nil {
+			return "", fmt.Errorf("segment %q: %w", part.Name, err)
+		}
+		if i > 0 {
+			b.WriteString(part.Separator)
+		}
+		b.WriteString(part.Generator.PositionToID(positions[i]))
+	}
+	return b.String(), nil
+}
+
+// NewID builds a composite ID taking the positions of non-random segments from
+// positions, in order, and filling random segments with their generator's NewID.
+//
+// Returns an error if the number of positions does not match the number of
+// non-random segments, or an error wrapping a *RangeError if a position is out
+// of range for its segment.
+func (c *Composite) NewID(positions ...int64) (string, error) {
+	if len(positions) != c.fixed {
+		return "", fmt.Errorf("doremid: composite needs %d positions, got %d", c.fixed, len(positions))
+	}
+
+	all := make([]int64, len(c.parts))
+	next := 0
+	for i, part := range c.parts {
+		if part.Random {
+			all[i] = part.Generator.IDToPosition(part.Generator.NewID())
+			continue
+		}
+		all[i] = positions[next]
+		next++
+	}
+	return c.Format(all...)
+}
+
+// Parse splits a composite ID into the position of each segment.
+//
+// Returns a *FormatError, or an error wrapping one that names the first
+// malformed segment.
+func (c *Composite) Parse(id string) ([]int64, error) {
+	if c.minLength == c.length && len(id) != c.length {
+		return nil, &FormatError{Input: truncate(id, c.length+1), Offset: -1, Reason: fmt.Sprintf("composite length must be %d, got %d", c.length, len(id)), Err: ErrInvalidFormat}
+	}
+	if len(id) < c.minLength || len(id) > c.length {
+		return nil, &FormatError{Input: truncate(id, c.length+1), Offset: -1, Reason: fmt.Sprintf("composite length must be between %d and %d, got %d", c.minLength, c.length, len(id)), Err: ErrInvalidFormat}
+	}
+
+	positions := make([]int64, len(c.parts))
+	offset := 0
+	for i, part := range c.parts {
+		if i > 0 {
+			if !strings.HasPrefix(id[offset:], part.Separator) {
+				symbol := symbolAt(id, offset, len(part.Separator))
+				return nil, fmt.Errorf("segment %q: %w", part.Name, &FormatError{Input: id, Offset: offset, Symbol: symbol, Reason: "unexpected separator", Err: ErrInvalidFormat})
+			}
+			offset += len(part.Separator)
+		}
+
+		width, found := part.Generator.leadingIDLength(id[offset:])
+		if !found {
+			width = c.widths[i]
+		}
+		width = min(width, len(id)-offset)
+		pos, err := part.Generator.decode(id[offset : offset+width])
+		if err != nil {
+			return nil, fmt.Errorf("segment %q: %w", part.Name, err)
+		}
+		positions[i] = pos
+		offset += width
+	}
+	if offset != len(id) {
+		return nil, &FormatError{Input: id, Offset: offset, Symbol: symbolAt(id, offset, 1), Reason: "unexpected trailing input", Err: ErrInvalidFormat}
+	}
+
+	return positions, nil
+}
+
+// leadingIDLength returns the length of the ID, as generated, at the start of s,
+// reading its notes to tell their widths, or false if s does not start with
+// the prefix and notes of one
+func (g *Generator) leadingIDLength(s string) (int, bool) {
+	if !strings.HasPrefix(s, g.prefix) {
+		return 0, false
+	}
+	offset := len(g.prefix)
+	for i := 0; i < g.JustIntonationDigits; i++ {
+		if sep := g.noteGroupBreak(i); sep != "" && strings.HasPrefix(s[offset:], sep) {
+			offset += len(sep)
+		}
+		_, width, found := g.nextNote(s, offset)
+		if !found {
+			return 0, false
+		}
+		offset += width
+	}
+	_, rest := g.restLengths()
+	return offset + len(g.Separator) + rest, true
+}
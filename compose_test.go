@@ -0,0 +1,135 @@
+package doremid
+
+import (
+	"strings"
+	"testing"
+)
+
+func newTestComposite() *Composite {
+	region := New(Config{JustIntonationDigits: 1, EqualTemperamentDigits: 1, Separator: "-"})
+	entity := New(Config{JustIntonationDigits: 2, EqualTemperamentDigits: 3, Separator: "-"})
+	tail := New(Config{JustIntonationDigits: 1, EqualTemperamentDigits: 2, Separator: "_"})
+
+	return Compose(
+		ComponentSpec{Name: "region", Generator: region},
+		ComponentSpec{Name: "entity", Generator: entity, Separator: "."},
+		ComponentSpec{Name: "tail", Generator: tail, Separator: ".", Random: true},
+	)
+}
+
+func TestComposeFormatAndParse(t *testing.T) {
+	composite := newTestComposite()
+
+	id, err := composite.Format(13, 1000, 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if id != "re-1.dodo-6b4.do_05" {
+		t.Errorf("expected 're-1.dodo-6b4.do_05', got '%s'", id)
+	}
+	if len(id) != composite.Len() {
+		t.Errorf("expected length %d, got %d", composite.Len(), len(id))
+	}
+
+	positions, err := composite.Parse(id)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []int64{13, 1000, 5}
+	for i := range expected {
+		if positions[i] != expected[i] {
+			t.Errorf("segment %d: expected position %d, got %d", i, expected[i], positions[i])
+		}
+	}
+}
+
+func TestComposeNewID(t *testing.T) {
+	composite := newTestComposite()
+
+	seen := make(map[string]bool)
+	for i := 0; i < 20; i++ {
+		id, err := composite.NewID(2, 42)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.HasPrefix(id, "do-2.dodo-036.") {
+			t.Errorf("fixed segments not preserved in '%s'", id)
+		}
+		seen[id] = true
+	}
+	if len(seen) < 2 {
+		t.Error("random tail should vary between IDs")
+	}
+
+	if _, err := composite.NewID(2); err == nil {
+		t.Error("expected error for missing position")
+	}
+}
+
+func TestComposeErrors(t *testing.T) {
+	composite := newTestComposite()
+
+	if _, err := composite.Format(0, 0); err == nil {
+		t.Error("expected error for wrong number of positions")
+	}
+	if _, err := composite.Format(84, 0, 0); err == nil {
+		t.Error("expected error for out-of-range position")
+	}
+
+	tests := []struct {
+		id      string
+		segment string
+	}{
+		{"re-1.dodo-6b4.do_0", "length"},
+		{"re-1_dodo-6b4.do_05", "entity"},
+		{"re-1.dxdo-6b4.do_05", "entity"},
+		{"re-c.dodo-6b4.do_05", "region"},
+		{"re-1.dodo-6b4.dx_05", "tail"},
+	}
+	for _, tt := range tests {
+		_, err := composite.Parse(tt.id)
+		if err == nil || !strings.Contains(err.Error(), tt.segment) {
+			t.Errorf("Parse('%s'): expected error mentioning %q, got %v", tt.id, tt.segment, err)
+		}
+	}
+}
+
+func TestComposeVariableWidthNotes(t *testing.T) {
+	notes := New(Config{JustIntonationDigits: 2, EqualTemperamentDigits: 2, Separator: "-", Notes: "ut re mi fa sol la si"})
+	tail := New(Config{JustIntonationDigits: 1, EqualTemperamentDigits: 2, Separator: "_"})
+	composite := Compose(
+		ComponentSpec{Name: "entity", Generator: notes},
+		ComponentSpec{Name: "tail", Generator: tail, Separator: "."},
+	)
+
+	tests := []struct {
+		positions []int64
+		expected  string
+	}{
+		{[]int64{0, 5}, "utut-00.do_05"},
+		{[]int64{4*7*144 + 4*144, 5}, "solsol-00.do_05"},
+		{[]int64{4 * 144, 5}, "utsol-00.do_05"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			id, err := composite.Format(tt.positions...)
+			if err != nil || id != tt.expected {
+				t.Fatalf("expected '%s', got '%s' (err: %v)", tt.expected, id, err)
+			}
+			positions, err := composite.Parse(id)
+			if err != nil || positions[0] != tt.positions[0] || positions[1] != tt.positions[1] {
+				t.Errorf("expected %v, got %v (err: %v)", tt.positions, positions, err)
+			}
+		})
+	}
+
+	if composite.Len() != len("solsol-00.do_05") {
+		t.Errorf("expected the longest length, got %d", composite.Len())
+	}
+	for _, id := range []string{"utut-00.do_05x", "utut-00.do_0", "utsol-00do_05x"} {
+		if _, err := composite.Parse(id); err == nil {
+			t.Errorf("expected '%s' to be rejected", id)
+		}
+	}
+}
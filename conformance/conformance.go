@@ -0,0 +1,225 @@
+// Package conformance provides a test suite that certifies third-party
+// implementations of the DoReMi ID format against this Go reference implementation.
+//
+// A port (for example a TypeScript or Rust library) is wrapped in an Implementation
+// adapter and handed to Run from a regular Go test:
+//
+//	func TestMyPort(t *testing.T) {
+//		conformance.Run(t, myPortAdapter{})
+//	}
+package conformance
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/doremi-id/doremid"
+)
+
+// Implementation is the adapter a third-party implementation provides to the suite.
+// Every method receives the configuration the implementation must apply.
+type Implementation interface {
+	// PositionToID converts a position to its ID, returning an error for invalid positions.
+	PositionToID(config doremid.Config, position int64) (string, error)
+
+	// IDToPosition converts an ID back to its position, returning an error for invalid IDs.
+	IDToPosition(config doremid.Config, id string) (int64, error)
+
+	// MaxCombinations returns the number of distinct IDs for the configuration.
+	MaxCombinations(config doremid.Config) (int64, error)
+}
+
+// Vector is a single reference test vector: a configuration, a position and the
+// ID the reference implementation produces for it.
+type Vector struct {
+	Config   doremid.Config `json:"config"`
+	Position int64          `json:"position"`
+	ID       string         `json:"id"`
+}
+
+// DefaultConfigs returns the configurations the suite runs against by default.
+func DefaultConfigs() []doremid.Config {
+	return []doremid.Config{
+		{JustIntonationDigits: 1, EqualTemperamentDigits: 1, Separator: "-"},
+		{JustIntonationDigits: 1, EqualTemperamentDigits: 2, Separator: "-"},
+		{JustIntonationDigits: 2, EqualTemperamentDigits: 3, Separator: "_"},
+		{JustIntonationDigits: 3, EqualTemperamentDigits: 4, Separator: "::"},
+		doremid.DefaultConfig(),
+	}
+}
+
+// Run executes the full suite against impl using DefaultConfigs.
+func Run(t *testing.T, impl Implementation) {
+	RunWithConfigs(t, impl, DefaultConfigs())
+}
+
+// RunWithConfigs executes the full suite against impl for each of the given configurations.
+func RunWithConfigs(t *testing.T, impl Implementation, configs []doremid.Config) {
+	for _, config := range configs {
+		name := fmt.Sprintf("%d_%d_%q", config.JustIntonationDigits, config.EqualTemperamentDigits, config.Separator)
+		t.Run(name, func(t *testing.T) {
+			t.Run("capacity", func(t *testing.T) { testCapacity(t, impl, config) })
+			t.Run("round-trip", func(t *testing.T) { testRoundTrip(t, impl, config) })
+			t.Run("boundary", func(t *testing.T) { testBoundary(t, impl, config) })
+			t.Run("alphabet", func(t *testing.T) { testAlphabet(t, impl, config) })
+		})
+	}
+}
+
+// Vectors returns count reference vectors for config, spread across the whole
+// position space. The vectors are deterministic so they can be checked into a port's repository.
+func Vectors(config doremid.Config, count int) []Vector {
+	reference := doremid.New(config)
+	positions := samplePositions(reference.MaxCombinations(), count)
+
+	vectors := make([]Vector, len(positions))
+	for i, pos := range positions {
+		vectors[i] = Vector{Config: config, Position: pos, ID: reference.PositionToID(pos)}
+	}
+	return vectors
+}
+
+func testCapacity(t *testing.T, impl Implementation, config doremid.Config) {
+	expected := doremid.New(config).MaxCombinations()
+	got, err := impl.MaxCombinations(config)
+	if err != nil {
+		t.Fatalf("MaxCombinations returned error: %v", err)
+	}
+	if got != expected {
+		t.Errorf("expected max combinations %d, got %d", expected, got)
+	}
+}
+
+func testRoundTrip(t *testing.T, impl Implementation, config doremid.Config) {
+	for _, v := range Vectors(config, 256) {
+		id, err := impl.PositionToID(config, v.Position)
+		if err != nil {
+			t.Errorf("PositionToID(%d) returned error: %v", v.Position, err)
+			continue
+		}
+		if id != v.ID {
+			t.Errorf("PositionToID(%d): expected '%s', got '%s'", v.Position, v.ID, id)
+		}
+
+		pos, err := impl.IDToPosition(config, v.ID)
+		if err != nil {
+			t.Errorf("IDToPosition('%s') returned error: %v", v.ID, err)
+			continue
+		}
+		if pos != v.Position {
+			t.Errorf("IDToPosition('%s'): expected %d, got %d", v.ID, v.Position, pos)
+		}
+	}
+}
+
+func testBoundary(t *testing.T, impl Implementation, config doremid.Config) {
+	reference := doremid.New(config)
+	last := reference.MaxCombinations() - 1
+
+	for _, pos := range []int64{0, 1, last - 1, last} {
+		if pos < 0 {
+			continue
+		}
+		expected := reference.PositionToID(pos)
+		id, err := impl.PositionToID(config, pos)
+		if err != nil || id != expected {
+			t.Errorf("PositionToID(%d): expected '%s', got '%s' (err: %v)", pos, expected, id, err)
+		}
+		if got, err := impl.IDToPosition(config, expected); err != nil || got != pos {
+			t.Errorf("IDToPosition('%s'): expected %d, got %d (err: %v)", expected, pos, got, err)
+		}
+	}
+
+	// MaxCombinations itself is the first position out of range
+	for _, pos := range []int64{-1, -last, last + 1} {
+		if id, err := impl.PositionToID(config, pos); err == nil {
+			t.Errorf("PositionToID(%d): expected error, got '%s'", pos, id)
+		}
+	}
+
+	first := reference.PositionToID(0)
+	invalid := []string{
+		"",
+		config.Separator,
+		first + config.Separator,
+		first[:len(first)-1],
+		first + "0",
+		"do" + first,
+	}
+	if config.Separator != "" {
+		invalid = append(invalid, first[:config.JustIntonationDigits*2]+first[config.JustIntonationDigits*2+len(config.Separator):])
+	}
+	expectInvalid(t, impl, config, invalid)
+}
+
+func testAlphabet(t *testing.T, impl Implementation, config doremid.Config) {
+	reference := doremid.New(config)
+	first := reference.PositionToID(0)
+	justLen := config.JustIntonationDigits * 2
+	equalStart := justLen + len(config.Separator)
+
+	// Every symbol of the reference alphabets must decode identically in the last digit of each part
+	for pos := int64(0); pos < 12; pos++ {
+		id := reference.PositionToID(pos)
+		got, err := impl.IDToPosition(config, id)
+		if err != nil || got != pos {
+			t.Errorf("equal temperament symbol '%c': expected position %d, got %d (err: %v)", id[len(id)-1], pos, got, err)
+		}
+	}
+	equalMax := reference.MaxCombinations() / int64(pow(7, config.JustIntonationDigits))
+	for note := int64(0); note < 7; note++ {
+		pos := note * equalMax
+		id := reference.PositionToID(pos)
+		got, err := impl.IDToPosition(config, id)
+		if err != nil || got != pos {
+			t.Errorf("just intonation note '%s': expected position %d, got %d (err: %v)", id[justLen-2:justLen], pos, got, err)
+		}
+	}
+
+	// Symbols outside the alphabets, including upper case variants, must be rejected
+	var invalid []string
+	for _, note := range []string{"DO", "Do", "si", "ut", "xx"} {
+		invalid = append(invalid, note+first[2:])
+	}
+	for _, char := range []byte{'c', 'A', 'B', 'z', '-', ' '} {
+		invalid = append(invalid, first[:equalStart]+string(char)+first[equalStart+1:])
+	}
+	expectInvalid(t, impl, config, invalid)
+}
+
+func expectInvalid(t *testing.T, impl Implementation, config doremid.Config, ids []string) {
+	t.Helper()
+	for _, id := range ids {
+		if pos, err := impl.IDToPosition(config, id); err == nil {
+			t.Errorf("IDToPosition('%s'): expected error, got position %d", id, pos)
+		}
+	}
+}
+
+// samplePositions returns up to count positions in [0, max) including both ends,
+// chosen with a fixed seed so the result is stable across runs.
+func samplePositions(max int64, count int) []int64 {
+	if int64(count) >= max {
+		positions := make([]int64, max)
+		for i := range positions {
+			positions[i] = int64(i)
+		}
+		return positions
+	}
+
+	r := rand.New(rand.NewSource(int64(count) ^ max))
+	positions := []int64{0, max - 1}
+	for len(positions) < count {
+		positions = append(positions, r.Int63n(max))
+	}
+	return positions
+}
+
+func pow(base, exp int) int {
+	result := 1
+	for i := 0; i < exp; i++ {
+		result *= base
+	}
+	return result
+}
@@ -0,0 +1,66 @@
+package conformance
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/doremi-id/doremid"
+)
+
+func TestReference(t *testing.T) {
+	Run(t, Reference())
+}
+
+func TestVectors(t *testing.T) {
+	config := doremid.Config{JustIntonationDigits: 2, EqualTemperamentDigits: 3, Separator: "-"}
+
+	first := Vectors(config, 50)
+	second := Vectors(config, 50)
+	if len(first) != 50 {
+		t.Fatalf("expected 50 vectors, got %d", len(first))
+	}
+
+	for i := range first {
+		if !reflect.DeepEqual(first[i], second[i]) {
+			t.Errorf("vector[%d] is not deterministic: %+v vs %+v", i, first[i], second[i])
+		}
+	}
+
+	t.Run("small space is exhaustive", func(t *testing.T) {
+		small := doremid.Config{JustIntonationDigits: 1, EqualTemperamentDigits: 1, Separator: "-"}
+		vectors := Vectors(small, 1000)
+		if len(vectors) != 84 {
+			t.Errorf("expected 84 vectors, got %d", len(vectors))
+		}
+	})
+}
+
+func TestVectorsJSON(t *testing.T) {
+	config := doremid.Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 3,
+		Separator:              "-",
+		UnicodeNormalize:       doremid.FoldWidth,
+	}
+	vectors := Vectors(config, 10)
+
+	data, err := json.Marshal(vectors)
+	if err != nil {
+		t.Fatalf("expected vectors to marshal, got %v", err)
+	}
+	var decoded []Vector
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("expected vectors to unmarshal, got %v", err)
+	}
+
+	reference := doremid.New(decoded[0].Config)
+	for i, v := range decoded {
+		if v.Position != vectors[i].Position || v.ID != vectors[i].ID {
+			t.Errorf("vector[%d]: expected %+v, got %+v", i, vectors[i], v)
+		}
+		if id := reference.PositionToID(v.Position); id != v.ID {
+			t.Errorf("vector[%d]: expected %q from decoded config, got %q", i, v.ID, id)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package conformance
+
+import (
+	"errors"
+
+	"github.com/doremi-id/doremid"
+)
+
+// errInvalid is returned by the reference adapter for rejected inputs
+var errInvalid = errors.New("conformance: invalid input")
+
+// Reference returns an Implementation backed by this package's Go source of truth.
+// It is mainly useful as a template for writing adapters for other implementations.
+func Reference() Implementation {
+	return reference{}
+}
+
+type reference struct{}
+
+func (reference) PositionToID(config doremid.Config, position int64) (string, error) {
+	// PositionToID wraps positions past the end around, PositionToIDE rejects them
+	id, err := doremid.New(config).PositionToIDE(position)
+	if err != nil {
+		return "", errInvalid
+	}
+	return id, nil
+}
+
+func (reference) IDToPosition(config doremid.Config, id string) (int64, error) {
+	pos := doremid.New(config).IDToPosition(id)
+	if pos < 0 {
+		return -1, errInvalid
+	}
+	return pos, nil
+}
+
+func (reference) MaxCombinations(config doremid.Config) (int64, error) {
+	return doremid.New(config).MaxCombinations(), nil
+}
@@ -0,0 +1,109 @@
+package doremid
+
+import "fmt"
+
+// DebugInfo is a structured dump of a generator's internals, intended for support
+// bundles and diagnosing misconfigurations. It contains no issued IDs or random state.
+type DebugInfo struct {
+	JustIntonationAlphabet   []string      `json:"just_intonation_alphabet"`
+	EqualTemperamentAlphabet []string      `json:"equal_temperament_alphabet"`
+	Radices                  []int         `json:"radices"` // Radix of each digit, most significant first
+	MaxCombinations          int64         `json:"max_combinations"`
+	MintRange                Range         `json:"mint_range"`
+	MaxParseLength           int           `json:"max_parse_length"`
+	RNG                      string        `json:"rng"`
+	Layout                   []LayoutField `json:"layout"`
+	Transforms               []string      `json:"transforms"` // Applied in order, empty for a plain generator
+}
+
+// LayoutField describes where one part of an ID is located
+type LayoutField struct {
+	Name   string `json:"name"`
+	Offset int    `json:"offset"` // Byte offset from the start of the ID, -1 if it varies
+	Length int    `json:"length"` // Length in bytes, -1 if it varies
+}
+
+// Debug returns a structured description of the generator's configuration and
+// derived values
+func (g *Generator) Debug() DebugInfo {
+	info := DebugInfo{
+		MaxCombinations: g.MaxCombinations(),
+		MintRange:       g.mintRange(),
+		MaxParseLength:  g.MaxParseLength,
+		RNG:             "math/rand (time seeded)",
+		Transforms:      []string{},
+	}
+
+	for _, note := range g.justIntonationBytes {
+		info.JustIntonationAlphabet = append(info.JustIntonationAlphabet, string(note))
+	}
+	for _, char := range g.equalTemperamentBytes {
+		info.EqualTemperamentAlphabet = append(info.EqualTemperamentAlphabet, string(char))
+	}
+
+	for i := 0; i < g.JustIntonationDigits; i++ {
+		info.Radices = append(info.Radices, g.justIntonationLen)
+	}
+	for i := 0; i < g.EqualTemperamentDigits; i++ {
+		info.Radices = append(info.Radices, g.equalTemperamentLen)
+	}
+
+	// Lengths of the grouped form, which generated IDs take
+	noteSeparators, characterSeparators := g.groupOverhead()
+	justLen := g.JustIntonationDigits*g.maxNoteLen + noteSeparators
+	if g.minNoteLen != g.maxNoteLen {
+		justLen = -1
+	}
+	equalLen := g.EqualTemperamentDigits + characterSeparators
+	start := len(g.prefix)
+	info.Layout = []LayoutField{
+		{Name: "just_intonation", Offset: start, Length: justLen},
+		{Name: "separator", Offset: start + justLen, Length: len(g.Separator)},
+		{Name: "equal_temperament", Offset: start + justLen + len(g.Separator), Length: equalLen},
+	}
+	if g.checksum {
+		info.Layout = append(info.Layout, LayoutField{Name: "checksum", Offset: start + justLen + len(g.Separator) + equalLen, Length: 1})
+	}
+	if justLen < 0 {
+		for i := 1; i < len(info.Layout); i++ {
+			info.Layout[i].Offset = -1
+		}
+	}
+	if g.version > 0 {
+		info.Layout = append([]LayoutField{{Name: "version", Offset: start - 1, Length: 1}}, info.Layout...)
+		start--
+	}
+	if start > 0 {
+		info.Layout = append([]LayoutField{{Name: "prefix", Offset: 0, Length: start}}, info.Layout...)
+	}
+
+	switch {
+	case g.rand == nil:
+		info.RNG = "none"
+	case g.randSource != nil:
+		info.RNG = fmt.Sprintf("%T (Config.Rand)", g.randSource)
+	case g.secureRandom:
+		info.RNG = "crypto/rand"
+	case g.randPool != nil:
+		info.RNG = "math/rand (per-goroutine pool)"
+	}
+	if g.restriction != nil {
+		info.Transforms = append(info.Transforms, fmt.Sprintf("restrict [%d, %d)", g.restriction.Start, g.restriction.End))
+	}
+	if len(g.reserved) > 0 {
+		info.Transforms = append(info.Transforms, fmt.Sprintf("skip %d reserved positions", len(g.reserved)))
+	}
+	if g.weighted() {
+		info.Transforms = append(info.Transforms, "draw random symbols by weight")
+	}
+	if g.wordFilter != nil {
+		info.Transforms = append(info.Transforms, "skip notes spelling filtered words")
+	}
+	if g.grouped() {
+		info.Transforms = append(info.Transforms, fmt.Sprintf("group every %d symbols", g.groupSize))
+	}
+	if g.permutation != nil {
+		info.Transforms = append(info.Transforms, "feistel permutation (obfuscated positions only)")
+	}
+	return info
+}
@@ -0,0 +1,45 @@
+package doremid
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDebug(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 3,
+		Separator:              "--",
+	})
+
+	info := generator.Debug()
+	if len(info.JustIntonationAlphabet) != 7 || len(info.EqualTemperamentAlphabet) != 12 {
+		t.Errorf("unexpected alphabets %v %v", info.JustIntonationAlphabet, info.EqualTemperamentAlphabet)
+	}
+	if !reflect.DeepEqual(info.Radices, []int{7, 7, 12, 12, 12}) {
+		t.Errorf("unexpected radices %v", info.Radices)
+	}
+	if info.MaxCombinations != 84672 || info.MintRange != (Range{Start: 0, End: 84672}) {
+		t.Errorf("unexpected capacity %d and mint range %+v", info.MaxCombinations, info.MintRange)
+	}
+
+	expectedLayout := []LayoutField{
+		{Name: "just_intonation", Offset: 0, Length: 4},
+		{Name: "separator", Offset: 4, Length: 2},
+		{Name: "equal_temperament", Offset: 6, Length: 3},
+	}
+	if !reflect.DeepEqual(info.Layout, expectedLayout) {
+		t.Errorf("unexpected layout %+v", info.Layout)
+	}
+	if len(info.Transforms) != 0 {
+		t.Errorf("expected no transforms, got %v", info.Transforms)
+	}
+
+	restricted := generator.Restrict(Range{Start: 10, End: 20}).Debug()
+	if !reflect.DeepEqual(restricted.Transforms, []string{"restrict [10, 20)"}) {
+		t.Errorf("unexpected transforms %v", restricted.Transforms)
+	}
+	if generator.Freeze().Debug().RNG != "none" {
+		t.Error("expected frozen generator to report no RNG")
+	}
+}
@@ -0,0 +1,201 @@
+package doremid
+
+import (
+	"bufio"
+	"container/heap"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+)
+
+// dedupeChunkSize is the number of positions sorted in memory per run
+var dedupeChunkSize = 1 << 22
+
+// DedupeLargeFile removes duplicate IDs from a newline-delimited file that may be
+// too large to fit in memory.
+//
+// Parameters:
+//   - in: path of the input file, one ID per line (blank lines are ignored)
+//   - out: path of the output file, created or truncated
+//   - tmpDir: directory for temporary run files ("" uses the system default)
+//
+// IDs are decoded to positions, sorted in bounded-size runs that are spilled to
+// tmpDir, and merged with a k-way external merge sort. The output contains each
+// distinct ID exactly once, in sequential position order.
+// Returns an error wrapping a *FormatError if the input contains an invalid ID.
+func (g *Generator) DedupeLargeFile(in, out string, tmpDir string) error {
+	input, err := os.Open(in)
+	if err != nil {
+		return err
+	}
+	defer input.Close()
+
+	runs, err := g.writeSortedRuns(input, tmpDir)
+	defer func() {
+		for _, run := range runs {
+			run.Close()
+			os.Remove(run.Name())
+		}
+	}()
+	if err != nil {
+		return err
+	}
+
+	output, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(output)
+	if err := g.mergeRuns(runs, w); err != nil {
+		output.Close()
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		output.Close()
+		return err
+	}
+	return output.Close()
+}
+
+// writeSortedRuns decodes IDs from r and writes them as sorted, deduplicated runs
+// of big-endian positions into temporary files
+func (g *Generator) writeSortedRuns(r io.Reader, tmpDir string) ([]*os.File, error) {
+	var runs []*os.File
+	var chunk []int64 // Grown by append up to dedupeChunkSize, so small inputs stay small
+
+	flush := func() error {
+		if len(chunk) == 0 {
+			return nil
+		}
+		slices.Sort(chunk)
+		chunk = slices.Compact(chunk)
+
+		run, err := os.CreateTemp(tmpDir, "doremid-dedupe-*")
+		if err != nil {
+			return err
+		}
+		runs = append(runs, run)
+
+		w := bufio.NewWriter(run)
+		var buf [8]byte
+		for _, pos := range chunk {
+			binary.BigEndian.PutUint64(buf[:], uint64(pos))
+			if _, err := w.Write(buf[:]); err != nil {
+				return err
+			}
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		if _, err := run.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+
+		chunk = chunk[:0]
+		return nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		id := strings.TrimSpace(scanner.Text())
+		if id == "" {
+			continue
+		}
+
+		pos, err := g.decode(id)
+		if err != nil {
+			return runs, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		chunk = append(chunk, pos)
+		if len(chunk) >= dedupeChunkSize {
+			if err := flush(); err != nil {
+				return runs, err
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return runs, err
+	}
+
+	return runs, flush()
+}
+
+// mergeRuns merges sorted runs into w, writing each distinct position once as an ID
+func (g *Generator) mergeRuns(runs []*os.File, w io.Writer) error {
+	h := make(runHeap, 0, len(runs))
+	for _, run := range runs {
+		cursor := &runCursor{r: bufio.NewReader(run)}
+		ok, err := cursor.next()
+		if err != nil {
+			return err
+		}
+		if ok {
+			h = append(h, cursor)
+		}
+	}
+	heap.Init(&h)
+
+	last := int64(-1)
+	for h.Len() > 0 {
+		cursor := h[0]
+		if cursor.pos != last {
+			if _, err := io.WriteString(w, g.PositionToID(cursor.pos)+"\n"); err != nil {
+				return err
+			}
+			last = cursor.pos
+		}
+
+		ok, err := cursor.next()
+		if err != nil {
+			return err
+		}
+		if ok {
+			heap.Fix(&h, 0)
+		} else {
+			heap.Pop(&h)
+		}
+	}
+
+	return nil
+}
+
+// runCursor reads positions sequentially from a sorted run
+type runCursor struct {
+	r   *bufio.Reader
+	pos int64
+	buf [8]byte
+}
+
+// next advances the cursor, returning false when the run is exhausted
+func (c *runCursor) next() (bool, error) {
+	if _, err := io.ReadFull(c.r, c.buf[:]); err != nil {
+		if err == io.EOF {
+			return false, nil
+		}
+		return false, err
+	}
+	c.pos = int64(binary.BigEndian.Uint64(c.buf[:]))
+	return true, nil
+}
+
+// runHeap is a min-heap of run cursors ordered by their current position
+type runHeap []*runCursor
+
+func (h runHeap) Len() int           { return len(h) }
+func (h runHeap) Less(i, j int) bool { return h[i].pos < h[j].pos }
+func (h runHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *runHeap) Push(x any)        { *h = append(*h, x.(*runCursor)) }
+func (h *runHeap) Pop() any {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
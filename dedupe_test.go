@@ -0,0 +1,100 @@
+package doremid
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestDedupeLargeFile(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   1,
+		EqualTemperamentDigits: 2,
+		Separator:              "-",
+	})
+
+	// Force several runs so the merge path is exercised
+	original := dedupeChunkSize
+	dedupeChunkSize = 7
+	defer func() { dedupeChunkSize = original }()
+
+	dir := t.TempDir()
+	in := filepath.Join(dir, "in.txt")
+	out := filepath.Join(dir, "out.txt")
+	tmp := filepath.Join(dir, "tmp")
+	if err := os.Mkdir(tmp, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	var lines []string
+	for i := 0; i < 5; i++ {
+		for _, pos := range []int64{40, 3, 1007, 0, 3, 12} {
+			lines = append(lines, generator.PositionToID(pos))
+		}
+		lines = append(lines, "")
+	}
+	if err := os.WriteFile(in, []byte(strings.Join(lines, "\n")), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := generator.DedupeLargeFile(in, out, tmp); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := strings.Fields(string(data))
+	expected := []string{"do-00", "do-03", "do-10", "do-34", "ti-bb"}
+	if strings.Join(got, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+
+	// Temporary runs must be cleaned up
+	entries, _ := os.ReadDir(tmp)
+	if len(entries) != 0 {
+		t.Errorf("expected temporary directory to be empty, found %d files", len(entries))
+	}
+}
+
+func TestDedupeLargeFileInvalidID(t *testing.T) {
+	generator := NewWithDefaults()
+
+	dir := t.TempDir()
+	in := filepath.Join(dir, "in.txt")
+	if err := os.WriteFile(in, []byte("dodododo-00000\nnot-an-id\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	err := generator.DedupeLargeFile(in, filepath.Join(dir, "out.txt"), dir)
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("expected error mentioning line 2, got %v", err)
+	}
+
+	if err := generator.DedupeLargeFile(filepath.Join(dir, "missing.txt"), filepath.Join(dir, "out.txt"), dir); err == nil {
+		t.Error("expected error for missing input file")
+	}
+}
+
+func TestDedupeLargeFileSmallInput(t *testing.T) {
+	generator := NewWithDefaults()
+	dir := t.TempDir()
+	in := filepath.Join(dir, "in.txt")
+	if err := os.WriteFile(in, []byte(generator.PositionToID(42)+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// A single ID must not allocate a full chunk of positions
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	if err := generator.DedupeLargeFile(in, filepath.Join(dir, "out.txt"), dir); err != nil {
+		t.Fatal(err)
+	}
+	runtime.ReadMemStats(&after)
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 1<<20 {
+		t.Errorf("expected under 1MB allocated for one ID, got %d bytes", allocated)
+	}
+}
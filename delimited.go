@@ -0,0 +1,55 @@
+package doremid
+
+import (
+	"strconv"
+	"strings"
+)
+
+// DelimiterMark opens and closes the length marker of a delimited ID
+const DelimiterMark = '~'
+
+// Delimited returns id in a self-delimiting form for embedding in free text such
+// as logs or emails: its byte length between marks, then the ID, e.g.
+// "~8~domi-1a2". The length lets ExtractIDs find the exact end of the ID even
+// when other text follows without a space.
+// Returns a *FormatError if id is invalid.
+func (g *Generator) Delimited(id string) (string, error) {
+	if _, err := g.decode(id); err != nil {
+		return "", err
+	}
+	return string(DelimiterMark) + strconv.Itoa(len(id)) + string(DelimiterMark) + id, nil
+}
+
+// ExtractIDs returns the valid IDs embedded in text in delimited form, in order of
+// appearance. Marks that do not introduce a valid ID of the generator are skipped.
+func (g *Generator) ExtractIDs(text string) []string {
+	ids := []string{}
+	shortest, longest := g.idLengths()
+	for i := 0; i < len(text); i++ {
+		if text[i] != DelimiterMark {
+			continue
+		}
+
+		// Read the decimal length, at most 3 digits, up to the closing mark
+		end := i + 1
+		for end < len(text) && end-i <= 3 && text[end] >= '0' && text[end] <= '9' {
+			end++
+		}
+		if end == i+1 || end >= len(text) || text[end] != DelimiterMark {
+			continue
+		}
+		length, _ := strconv.Atoi(text[i+1 : end])
+		start := end + 1
+		if length < shortest || length > longest || start+length > len(text) {
+			continue
+		}
+
+		id := text[start : start+length]
+		if _, err := g.decode(id); err != nil {
+			continue
+		}
+		ids = append(ids, strings.Clone(id)) // Do not keep text alive
+		i = start + length - 1
+	}
+	return ids
+}
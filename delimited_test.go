@@ -0,0 +1,66 @@
+package doremid
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestDelimited(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 3,
+		Separator:              "-",
+	})
+
+	delimited, err := generator.Delimited("domi-1a2")
+	if err != nil || delimited != "~8~domi-1a2" {
+		t.Errorf("expected '~8~domi-1a2', got '%s' (err: %v)", delimited, err)
+	}
+	if _, err := generator.Delimited("invalid"); !errors.Is(err, ErrInvalidID) {
+		t.Errorf("expected ErrInvalidID, got %v", err)
+	}
+}
+
+func TestExtractIDs(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 3,
+		Separator:              "-",
+	})
+
+	tests := []struct {
+		name     string
+		text     string
+		expected []string
+	}{
+		{"none", "order domi-1a2 shipped", []string{}},
+		{"one", "order ~8~domi-1a2 shipped", []string{"domi-1a2"}},
+		{"adjacent text", "ids:~8~domi-1a2~8~fati-bb0abc", []string{"domi-1a2", "fati-bb0"}},
+		{"email quoting", "> Re: ~8~sola-123, thanks~", []string{"sola-123"}},
+		{"wrong length", "~9~domi-1a2 ~7~domi-1a2", []string{}},
+		{"invalid ID", "~8~domi-1z2", []string{}},
+		{"stray marks", "~~ ~8 ~123456~ ~8~", []string{}},
+		{"truncated", "~8~domi-1", []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := generator.ExtractIDs(tt.text); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+
+	// Round trip through Delimited with custom notes of different lengths
+	custom := New(Config{JustIntonationDigits: 3, EqualTemperamentDigits: 2, Notes: "do re mi fa sol la ti"})
+	var text string
+	for _, pos := range []int64{0, 4, 100} {
+		delimited, _ := custom.Delimited(custom.PositionToID(pos))
+		text += "see " + delimited + "."
+	}
+	expected := []string{custom.PositionToID(0), custom.PositionToID(4), custom.PositionToID(100)}
+	if got := custom.ExtractIDs(text); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
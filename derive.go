@@ -0,0 +1,86 @@
+package doremid
+
+// DerivationConfig configures a Derivation
+type DerivationConfig struct {
+	// Parents are the positions whose IDs may have derived children, e.g. the
+	// positions orders are issued from. It must not be empty.
+	Parents Range
+
+	// Children is the region reserved for derived IDs. It must not overlap
+	// Parents and must hold FanOut children for every parent.
+	Children Range
+
+	// FanOut is the number of children each parent can have. It must be positive.
+	FanOut int64
+}
+
+// Derivation maps a parent ID and an index to a child ID in a reserved region,
+// e.g. giving the line items of an order IDs provably derived from the order's.
+// Each parent owns FanOut consecutive positions of the child region, so children
+// never collide and the parent of any child can be recovered without a lookup.
+// The generator the derivation was created from may still mint positions of
+// Children; mint parent IDs from Parents so they never collide with children.
+// It is safe for concurrent use.
+type Derivation struct {
+	g       *Generator
+	parents *Generator
+	config  DerivationConfig
+}
+
+// Derivation creates a derivation with the configuration of g.
+// Returns a *ConfigError if Parents is empty, FanOut is not positive, either
+// range lies outside the ID space, the ranges overlap or Children cannot hold
+// FanOut children for every parent.
+func (g *Generator) Derivation(config DerivationConfig) (*Derivation, error) {
+	space := Range{Start: 0, End: g.MaxCombinations()}
+	switch {
+	case config.Parents.Len() == 0 || config.Parents.intersect(space) != config.Parents:
+		return nil, &ConfigError{Field: "Parents", Reason: "must be a non-empty range of the ID space"}
+	case config.FanOut <= 0:
+		return nil, &ConfigError{Field: "FanOut", Reason: "must be positive"}
+	case config.Children.intersect(space) != config.Children || config.Children.intersect(config.Parents).Len() > 0:
+		return nil, &ConfigError{Field: "Children", Reason: "must be a range of the ID space not overlapping Parents"}
+	case config.Children.Len()/config.FanOut < config.Parents.Len():
+		return nil, &ConfigError{Field: "Children", Reason: "must hold FanOut children for every parent"}
+	}
+	return &Derivation{g: g, parents: g.Restrict(config.Parents), config: config}, nil
+}
+
+// Parents returns a generator minting only parent IDs, restricted to Parents
+// and so never to a position of Children
+func (d *Derivation) Parents() *Generator {
+	return d.parents
+}
+
+// Derive returns the child ID number index of parent.
+// Returns a *FormatError if parent is invalid, or a *RangeError if its position
+// lies outside Parents or index is not in [0, FanOut).
+func (d *Derivation) Derive(parent string, index int) (string, error) {
+	pos, err := d.g.decode(parent)
+	if err != nil {
+		return "", err
+	}
+	if err := checkRange(pos, d.config.Parents); err != nil {
+		return "", err
+	}
+	if err := checkRange(int64(index), Range{Start: 0, End: d.config.FanOut}); err != nil {
+		return "", err
+	}
+	return d.g.PositionToID(d.config.Children.Start + (pos-d.config.Parents.Start)*d.config.FanOut + int64(index)), nil
+}
+
+// ParentOfDerived returns the parent ID id was derived from and its index.
+// Returns a *FormatError if id is invalid, or a *RangeError if it does not lie
+// in the part of Children used by derived IDs.
+func (d *Derivation) ParentOfDerived(id string) (string, int, error) {
+	pos, err := d.g.decode(id)
+	if err != nil {
+		return "", -1, err
+	}
+	used := Range{Start: d.config.Children.Start, End: d.config.Children.Start + d.config.Parents.Len()*d.config.FanOut}
+	if err := checkRange(pos, used); err != nil {
+		return "", -1, err
+	}
+	offset := pos - d.config.Children.Start
+	return d.g.PositionToID(d.config.Parents.Start + offset/d.config.FanOut), int(offset % d.config.FanOut), nil
+}
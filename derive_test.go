@@ -0,0 +1,109 @@
+package doremid
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDerivation(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 3,
+		Separator:              "-",
+	})
+
+	// Orders in the first 1000 positions, up to 50 line items each above them
+	d, err := generator.Derivation(DerivationConfig{
+		Parents:  Range{Start: 0, End: 1000},
+		Children: Range{Start: 10000, End: 60000},
+		FanOut:   50,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		parent   int64
+		index    int
+		expected int64
+	}{
+		{0, 0, 10000},
+		{0, 49, 10049},
+		{1, 0, 10050},
+		{999, 49, 59999},
+	}
+
+	for _, tt := range tests {
+		parent := generator.PositionToID(tt.parent)
+		child, err := d.Derive(parent, tt.index)
+		if err != nil || child != generator.PositionToID(tt.expected) {
+			t.Errorf("expected '%s' for child %d of '%s', got '%s' (err: %v)", generator.PositionToID(tt.expected), tt.index, parent, child, err)
+			continue
+		}
+
+		gotParent, index, err := d.ParentOfDerived(child)
+		if err != nil || gotParent != parent || index != tt.index {
+			t.Errorf("expected parent '%s' and index %d of '%s', got '%s' and %d (err: %v)", parent, tt.index, child, gotParent, index, err)
+		}
+	}
+
+	// Random parents never land among the children
+	for _, id := range d.Parents().BatchGenerateRandomIDs(500) {
+		if pos := generator.IDToPosition(id); pos < 0 || pos >= 1000 {
+			t.Fatalf("expected a parent position, got '%s' at %d", id, pos)
+		}
+	}
+}
+
+func TestDerivationErrors(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 3,
+		Separator:              "-",
+	})
+	d, _ := generator.Derivation(DerivationConfig{
+		Parents:  Range{Start: 0, End: 1000},
+		Children: Range{Start: 10000, End: 60000},
+		FanOut:   50,
+	})
+
+	if _, err := d.Derive(generator.PositionToID(1000), 0); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("expected ErrOutOfRange for a parent outside Parents, got %v", err)
+	}
+	for _, index := range []int{-1, 50} {
+		if _, err := d.Derive(generator.PositionToID(0), index); !errors.Is(err, ErrOutOfRange) {
+			t.Errorf("expected ErrOutOfRange for index %d, got %v", index, err)
+		}
+	}
+	if _, err := d.Derive("invalid", 0); !errors.Is(err, ErrInvalidID) {
+		t.Errorf("expected ErrInvalidID, got %v", err)
+	}
+	for _, pos := range []int64{9999, 60000, 5} {
+		if _, _, err := d.ParentOfDerived(generator.PositionToID(pos)); !errors.Is(err, ErrOutOfRange) {
+			t.Errorf("expected ErrOutOfRange for position %d, got %v", pos, err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		config DerivationConfig
+		field  string
+	}{
+		{"empty parents", DerivationConfig{Children: Range{Start: 100, End: 200}, FanOut: 1}, "Parents"},
+		{"parents beyond space", DerivationConfig{Parents: Range{Start: 84000, End: 85000}, Children: Range{Start: 0, End: 1000}, FanOut: 1}, "Parents"},
+		{"zero fan-out", DerivationConfig{Parents: Range{Start: 0, End: 10}, Children: Range{Start: 100, End: 200}}, "FanOut"},
+		{"overlapping", DerivationConfig{Parents: Range{Start: 0, End: 10}, Children: Range{Start: 5, End: 200}, FanOut: 10}, "Children"},
+		{"children beyond space", DerivationConfig{Parents: Range{Start: 0, End: 10}, Children: Range{Start: 84600, End: 85000}, FanOut: 1}, "Children"},
+		{"too small", DerivationConfig{Parents: Range{Start: 0, End: 10}, Children: Range{Start: 100, End: 199}, FanOut: 10}, "Children"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := generator.Derivation(tt.config)
+			var configErr *ConfigError
+			if !errors.As(err, &configErr) || configErr.Field != tt.field {
+				t.Errorf("expected ConfigError for %s, got %v", tt.field, err)
+			}
+		})
+	}
+}
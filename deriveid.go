@@ -0,0 +1,84 @@
+package doremid
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math/big"
+	"math/bits"
+)
+
+// DeriveVersion is the version of the algorithm behind DeriveID, DeriveIDBytes
+// and DeriveIDInNamespace. Derived IDs are stable across releases: for a given
+// Config and input they never change unless the version does, and a new
+// version would come with new functions rather than replace the old ones.
+//
+// Version 1 reduces the first 128 bits of a SHA-256 hash modulo the number of
+// positions, or the whole hash once the ID space exceeds int64. DeriveID hashes
+// its input as is; DeriveIDInNamespace hashes the version byte, the namespace
+// length as a uvarint, the namespace and the name.
+const DeriveVersion = 1
+
+// DeriveID returns an ID derived from input alone, e.g. for content-addressed
+// identifiers and idempotency keys: equal inputs always give the same ID, so a
+// retried request maps to the ID of its first attempt. The SHA-256 hash of input
+// is reduced into the ID space, or into the range of a restricted generator,
+// skipping reserved positions. A position whose notes Config.WordFilter bans
+// gives way to the next allowed one, wrapping around at the end of the range.
+//
+// Distinct inputs collide as often as random IDs do, so size the space for the
+// number of inputs. Returns an empty string if the restricted range is empty
+// or every position in it is filtered.
+func (g *Generator) DeriveID(input string) string {
+	return g.deriveID(sha256.Sum256([]byte(input)))
+}
+
+// DeriveIDBytes is like DeriveID for binary input, e.g. file contents.
+// DeriveIDBytes(b) equals DeriveID(string(b)).
+func (g *Generator) DeriveIDBytes(input []byte) string {
+	return g.deriveID(sha256.Sum256(input))
+}
+
+// DeriveIDInNamespace is like DeriveID for a name scoped to namespace, in the
+// spirit of UUIDv5: the same name in different namespaces yields unrelated IDs,
+// e.g. DeriveIDInNamespace("users", "42") and DeriveIDInNamespace("orders",
+// "42"). The namespace is length-prefixed, so ("ab", "c") and ("a", "bc")
+// differ too. IDs never match DeriveID of any input short of a hash collision.
+func (g *Generator) DeriveIDInNamespace(namespace, name string) string {
+	h := sha256.New()
+	h.Write([]byte{DeriveVersion})
+	h.Write(binary.AppendUvarint(nil, uint64(len(namespace))))
+	h.Write([]byte(namespace))
+	h.Write([]byte(name))
+
+	var sum [sha256.Size]byte
+	h.Sum(sum[:0])
+	return g.deriveID(sum)
+}
+
+// deriveID maps a hash into the ID space
+func (g *Generator) deriveID(sum [sha256.Size]byte) string {
+	if g.restriction == nil && !g.MaxCombinationsBig().IsInt64() {
+		space := g.MaxCombinationsBig()
+		id, _ := g.PositionToIDBig(new(big.Int).Mod(new(big.Int).SetBytes(sum[:]), space))
+		return id
+	}
+
+	n := g.EffectiveCombinations()
+	if n == 0 {
+		return ""
+	}
+	// Reducing 128 bits keeps the bias below 2^-64 for any range
+	hi, lo := binary.BigEndian.Uint64(sum[:8]), binary.BigEndian.Uint64(sum[8:16])
+	offset := bits.Rem64(hi, lo, uint64(n))
+	r := g.mintRange()
+	pos := g.reserved.nth(r, int64(offset))
+	if g.filtered(pos) {
+		if pos = g.nextAllowed(pos); pos >= r.End {
+			pos = g.nextAllowed(r.Start)
+		}
+		if pos >= r.End {
+			return ""
+		}
+	}
+	return g.PositionToID(pos)
+}
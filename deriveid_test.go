@@ -0,0 +1,107 @@
+package doremid
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDeriveID(t *testing.T) {
+	generator := New(Config{JustIntonationDigits: 2, EqualTemperamentDigits: 3, Separator: "-"})
+
+	id := generator.DeriveID("order-1234")
+	if generator.IDToPosition(id) < 0 {
+		t.Fatalf("derived invalid ID '%s'", id)
+	}
+	if again := generator.DeriveID("order-1234"); again != id {
+		t.Errorf("expected '%s' again, got '%s'", id, again)
+	}
+	if fromBytes := generator.DeriveIDBytes([]byte("order-1234")); fromBytes != id {
+		t.Errorf("expected DeriveIDBytes to match DeriveID '%s', got '%s'", id, fromBytes)
+	}
+	// Derived IDs must not change between releases
+	if id != "fati-136" {
+		t.Errorf("expected a stable derived ID, got '%s'", id)
+	}
+
+	seen := make(map[string]bool)
+	for i := 0; i < 1000; i++ {
+		seen[generator.DeriveID(fmt.Sprint("order-", i))] = true
+	}
+	if len(seen) < 950 {
+		t.Errorf("too few distinct derived IDs: %d", len(seen))
+	}
+}
+
+func TestDeriveIDRanges(t *testing.T) {
+	tests := []struct {
+		name      string
+		generator *Generator
+	}{
+		{"restricted", New(Config{JustIntonationDigits: 2, EqualTemperamentDigits: 3, Separator: "-"}).Restrict(Range{Start: 1000, End: 1010})},
+		{"prefix and checksum", New(Config{JustIntonationDigits: 2, EqualTemperamentDigits: 3, Separator: "-", Prefix: "ord", Checksum: true})},
+		{"beyond int64", New(Config{JustIntonationDigits: 20, EqualTemperamentDigits: 20, Separator: "-"})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 100; i++ {
+				input := fmt.Sprint("key-", i)
+				id := tt.generator.DeriveID(input)
+				pos, err := tt.generator.IDToPositionBig(id)
+				if err != nil {
+					t.Fatalf("derived invalid ID '%s': %v", id, err)
+				}
+				if tt.generator.restriction != nil && !tt.generator.restriction.Contains(pos.Int64()) {
+					t.Fatalf("expected a position in %v, got %d", *tt.generator.restriction, pos)
+				}
+				if again := tt.generator.DeriveID(input); again != id {
+					t.Fatalf("expected '%s' again, got '%s'", id, again)
+				}
+			}
+		})
+	}
+
+	empty := NewWithDefaults().Restrict(Range{Start: 5, End: 5})
+	if id := empty.DeriveID("key"); id != "" {
+		t.Errorf("expected empty string for an empty range, got '%s'", id)
+	}
+}
+
+func TestDeriveIDInNamespace(t *testing.T) {
+	generator := New(Config{JustIntonationDigits: 4, EqualTemperamentDigits: 5, Separator: "-"})
+
+	id := generator.DeriveIDInNamespace("users", "42")
+	if generator.IDToPosition(id) < 0 {
+		t.Fatalf("derived invalid ID '%s'", id)
+	}
+	if again := generator.DeriveIDInNamespace("users", "42"); again != id {
+		t.Errorf("expected '%s' again, got '%s'", id, again)
+	}
+	// Derived IDs must not change while DeriveVersion stays the same
+	if DeriveVersion != 1 || id != "latilado-74484" {
+		t.Errorf("expected a stable derived ID for version 1, got '%s' for version %d", id, DeriveVersion)
+	}
+
+	tests := []struct {
+		name                  string
+		namespace, input      string
+		otherNamespace, other string
+	}{
+		{"different namespaces", "users", "42", "orders", "42"},
+		{"shifted boundary", "ab", "c", "a", "bc"},
+		{"empty namespace", "", "42", "4", "2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := generator.DeriveIDInNamespace(tt.namespace, tt.input)
+			b := generator.DeriveIDInNamespace(tt.otherNamespace, tt.other)
+			if a == b {
+				t.Errorf("expected different IDs, got '%s' for both", a)
+			}
+		})
+	}
+
+	if plain := generator.DeriveID("42"); plain == generator.DeriveIDInNamespace("", "42") {
+		t.Errorf("expected namespaced IDs to differ from DeriveID, got '%s' for both", plain)
+	}
+}
@@ -0,0 +1,252 @@
+package doremid
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// DisplayAlphabet maps each note and character of a generator to a display token,
+// e.g. katakana notes for a Japanese UI
+type DisplayAlphabet struct {
+	// Notes lists one display token per generator note, in the same order,
+	// separated by spaces, e.g. "ド レ ミ ファ ソ ラ シ"
+	Notes string
+
+	// Characters lists one display token per generator character, in the same
+	// order, separated by spaces, e.g. "０ １ ２ ３ ４ ５ ６ ７ ８ ９ Ａ Ｂ"
+	Characters string
+
+	// Separator is placed between the two parts of a display ID. It may be empty.
+	Separator string
+}
+
+// DisplayForm converts between a generator's canonical IDs, suited to storage and
+// URLs, and a display form for people. Both forms denote the same position, so
+// either can be stored and the other derived. A DisplayForm is safe for
+// concurrent use.
+type DisplayForm struct {
+	g          *Generator
+	separator  string
+	notes      tokenSet
+	characters tokenSet
+}
+
+// tokenSet is a set of display tokens, parsed by longest match
+type tokenSet struct {
+	tokens []string
+	index  map[string]int
+	min    int
+	max    int
+}
+
+// DisplayForm creates a display form for the generator's IDs.
+// Returns a *ConfigError if the alphabet does not have exactly one token per note
+// and character, or if tokens are duplicated or ambiguous prefixes of one
+// another. A token may be a prefix of a longer one whose rest cannot start the
+// next token, e.g. "C" and "C#".
+func (g *Generator) DisplayForm(alphabet DisplayAlphabet) (*DisplayForm, error) {
+	characters, err := newTokenSet("Characters", alphabet.Characters, g.equalTemperamentLen, nil)
+	if err != nil {
+		return nil, err
+	}
+	// The last note is followed by the separator or, without one, a character
+	following := characters.tokens
+	if alphabet.Separator != "" {
+		following = []string{alphabet.Separator}
+	}
+	notes, err := newTokenSet("Notes", alphabet.Notes, g.justIntonationLen, following)
+	if err != nil {
+		return nil, err
+	}
+	return &DisplayForm{g: g, separator: alphabet.Separator, notes: notes, characters: characters}, nil
+}
+
+// WithDisplayNames creates a display form rendering notes and characters under
+// brand-specific labels, e.g. {"do": "pi", "re": "ka", "mi": "chu"}, while
+// Canonical keeps parsing back to the stored IDs. Symbols without a label are
+// displayed as themselves, and the separator is kept.
+// Returns a *ConfigError if a key is not a note or character, a label contains
+// whitespace, or labels and kept symbols are duplicated or ambiguous prefixes of
+// one another.
+func (g *Generator) WithDisplayNames(names map[string]string) (*DisplayForm, error) {
+	notes := make([]string, g.justIntonationLen)
+	for i, note := range g.justIntonationBytes {
+		notes[i] = string(note)
+	}
+	characters := make([]string, g.equalTemperamentLen)
+	for i, char := range g.equalTemperamentBytes {
+		characters[i] = string(char)
+	}
+
+	for symbol, label := range names {
+		if label == "" || strings.ContainsFunc(label, unicode.IsSpace) {
+			return nil, &ConfigError{Field: "names", Reason: fmt.Sprintf("label %q for %q must be non-empty without whitespace", label, symbol)}
+		}
+		note, isNote := g.justIntonationMap[symbol]
+		char, isChar := -1, false
+		if len(symbol) == 1 {
+			char, isChar = g.equalTemperamentMap[symbol[0]]
+		}
+		switch {
+		case isNote && isChar:
+			return nil, &ConfigError{Field: "names", Reason: fmt.Sprintf("%q is both a note and a character", symbol)}
+		case isNote:
+			notes[note] = label
+		case isChar:
+			characters[char] = label
+		default:
+			return nil, &ConfigError{Field: "names", Reason: fmt.Sprintf("%q is not a note or character", symbol)}
+		}
+	}
+
+	return g.DisplayForm(DisplayAlphabet{
+		Notes:      strings.Join(notes, " "),
+		Characters: strings.Join(characters, " "),
+		Separator:  g.Separator,
+	})
+}
+
+// ChromaticCharacters names the twelve pitch classes of the chromatic scale from
+// C, for displaying the characters of a twelve-tone generator as notes
+const ChromaticCharacters = "C C# D D# E F F# G G# A A# B"
+
+// ChromaticForm creates a display form rendering characters as the chromatic
+// note names of ChromaticCharacters, 0 to b as C to B, e.g. "domi-1a2" as
+// "domi-C#A#D". Notes and the separator display as themselves.
+// Returns a *ConfigError unless the generator has 12 characters.
+func (g *Generator) ChromaticForm() (*DisplayForm, error) {
+	if g.equalTemperamentLen != 12 {
+		return nil, &ConfigError{Field: "Characters", Reason: fmt.Sprintf("chromatic note names need 12 characters, got %d", g.equalTemperamentLen)}
+	}
+	notes := make([]string, g.justIntonationLen)
+	for i, note := range g.justIntonationBytes {
+		notes[i] = string(note)
+	}
+	return g.DisplayForm(DisplayAlphabet{
+		Notes:      strings.Join(notes, " "),
+		Characters: ChromaticCharacters,
+		Separator:  g.Separator,
+	})
+}
+
+// Display converts a canonical ID to its display form.
+// Returns a *FormatError if id is not a valid canonical ID.
+func (d *DisplayForm) Display(id string) (string, error) {
+	if _, err := d.g.decode(id); err != nil {
+		return "", err
+	}
+	id = d.g.ungroup(d.g.unalias(id))
+
+	var b strings.Builder
+	b.WriteString(d.g.prefix)
+	offset := len(d.g.prefix)
+	for i := 0; i < d.g.JustIntonationDigits; i++ {
+		index, width, _ := d.g.nextNote(id, offset)
+		b.WriteString(d.notes.tokens[index])
+		offset += width
+	}
+	b.WriteString(d.separator)
+	for _, char := range []byte(id[offset+len(d.g.Separator):]) {
+		b.WriteString(d.characters.tokens[d.g.equalTemperamentMap[char]])
+	}
+	return b.String(), nil
+}
+
+// Canonical converts a display ID back to its canonical form.
+// Returns a *FormatError if display is not a valid display ID; offsets refer to
+// bytes of display.
+func (d *DisplayForm) Canonical(display string) (string, error) {
+	characters := d.g.EqualTemperamentDigits + d.g.checksumLen()
+	longest := len(d.g.prefix) + d.g.JustIntonationDigits*d.notes.max + len(d.separator) + characters*d.characters.max
+	if len(display) > longest {
+		return "", &FormatError{Input: truncate(display, longest), Offset: -1, Reason: fmt.Sprintf("display ID exceeds %d bytes", longest), Err: ErrInvalidFormat}
+	}
+
+	// The namespace prefix is displayed as is
+	if !strings.HasPrefix(display, d.g.prefix) {
+		return "", &FormatError{Input: display, Offset: 0, Symbol: symbolAt(display, 0, len(d.g.prefix)), Reason: fmt.Sprintf("missing prefix %q", d.g.prefix), Err: ErrInvalidFormat}
+	}
+	canonical := make([]byte, 0, len(display))
+	canonical = append(canonical, d.g.prefix...)
+	offset := len(d.g.prefix)
+	for i := 0; i < d.g.JustIntonationDigits; i++ {
+		index, width, found := d.notes.next(display, offset)
+		if !found {
+			return "", &FormatError{Input: display, Offset: offset, Symbol: symbolAt(display, offset, d.notes.max), Reason: "unknown display note", Err: ErrBadCharacter}
+		}
+		canonical = append(canonical, d.g.justIntonationBytes[index]...)
+		offset += width
+	}
+
+	if !strings.HasPrefix(display[offset:], d.separator) {
+		return "", &FormatError{Input: display, Offset: offset, Symbol: symbolAt(display, offset, len(d.separator)), Reason: "unexpected separator", Err: ErrInvalidFormat}
+	}
+	offset += len(d.separator)
+	canonical = append(canonical, d.g.Separator...)
+
+	for i := 0; i < characters; i++ {
+		index, width, found := d.characters.next(display, offset)
+		if !found {
+			return "", &FormatError{Input: display, Offset: offset, Symbol: symbolAt(display, offset, d.characters.max), Reason: "unknown display character", Err: ErrBadCharacter}
+		}
+		canonical = append(canonical, d.g.equalTemperamentBytes[index])
+		offset += width
+	}
+
+	if offset != len(display) {
+		return "", &FormatError{Input: display, Offset: offset, Symbol: display[offset:], Reason: "unexpected trailing input", Err: ErrInvalidFormat}
+	}
+
+	// Check the canonical form, e.g. its check character
+	if _, err := d.g.decode(string(canonical)); err != nil {
+		return "", err
+	}
+	return string(canonical), nil
+}
+
+// newTokenSet parses a space-separated token list that must hold exactly count
+// tokens, each followed by another token or one of following. A token may only
+// be a prefix of another if the rest of the longer token overlaps none of
+// these, so matching the longest token is always right.
+func newTokenSet(field, list string, count int, following []string) (tokenSet, error) {
+	tokens := strings.Fields(list)
+	if len(tokens) != count {
+		return tokenSet{}, &ConfigError{Field: field, Reason: fmt.Sprintf("must contain %d tokens, got %d", count, len(tokens))}
+	}
+
+	set := tokenSet{tokens: tokens, index: make(map[string]int, count), min: len(tokens[0]), max: len(tokens[0])}
+	for i, token := range tokens {
+		for j, other := range tokens {
+			if i != j && strings.HasPrefix(other, token) && overlaps(other[len(token):], tokens, following) {
+				return tokenSet{}, &ConfigError{Field: field, Reason: fmt.Sprintf("%q is a duplicate or ambiguous prefix of %q", token, other)}
+			}
+		}
+		set.index[token] = i
+		set.min = min(set.min, len(token))
+		set.max = max(set.max, len(token))
+	}
+	return set, nil
+}
+
+// overlaps reports whether rest is empty or could be the start of what follows
+// a token, i.e. a token or following string that rest starts or that starts rest
+func overlaps(rest string, tokens, following []string) bool {
+	for _, next := range slices.Concat(tokens, following) {
+		if strings.HasPrefix(next, rest) || strings.HasPrefix(rest, next) {
+			return true
+		}
+	}
+	return false
+}
+
+// next finds the longest token starting at offset in s
+func (t tokenSet) next(s string, offset int) (index, width int, found bool) {
+	for width := min(t.max, len(s)-offset); width >= t.min; width-- {
+		if index, found := t.index[s[offset:offset+width]]; found {
+			return index, width, true
+		}
+	}
+	return 0, 0, false
+}
@@ -0,0 +1,239 @@
+package doremid
+
+import (
+	"errors"
+	"testing"
+)
+
+var katakana = DisplayAlphabet{
+	Notes:      "ド レ ミ ファ ソ ラ シ",
+	Characters: "０ １ ２ ３ ４ ５ ６ ７ ８ ９ Ａ Ｂ",
+	Separator:  "・",
+}
+
+func TestDisplayForm(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 3,
+		Separator:              "-",
+	})
+	form, err := generator.DisplayForm(katakana)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		canonical string
+		display   string
+	}{
+		{"dodo-000", "ドド・０００"},
+		{"domi-1a2", "ドミ・１Ａ２"},
+		{"fati-bb0", "ファシ・ＢＢ０"},
+		{"fafa-9ab", "ファファ・９ＡＢ"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.canonical, func(t *testing.T) {
+			display, err := form.Display(tt.canonical)
+			if err != nil || display != tt.display {
+				t.Errorf("expected '%s', got '%s' (err: %v)", tt.display, display, err)
+			}
+			canonical, err := form.Canonical(tt.display)
+			if err != nil || canonical != tt.canonical {
+				t.Errorf("expected '%s', got '%s' (err: %v)", tt.canonical, canonical, err)
+			}
+		})
+	}
+
+	invalid := []struct {
+		display  string
+		expected error
+	}{
+		{"ドド０００", ErrInvalidFormat},
+		{"ドド・０００Ａ", ErrInvalidFormat},
+		{"ドヌ・０００", ErrBadCharacter},
+		{"ドド・０Ｃ０", ErrBadCharacter},
+		{"ドド・００", ErrBadCharacter},
+		{"ドドドドドドドドドドドド・０００", ErrInvalidFormat},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.display, func(t *testing.T) {
+			if _, err := form.Canonical(tt.display); !errors.Is(err, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+
+	if _, err := form.Display("domi_1a2"); !errors.Is(err, ErrInvalidFormat) {
+		t.Errorf("expected ErrInvalidFormat for an invalid canonical ID, got %v", err)
+	}
+}
+
+func TestDisplayFormChecksum(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 3,
+		Separator:              "-",
+		Checksum:               true,
+	})
+	form, _ := generator.DisplayForm(katakana)
+
+	display, err := form.Display("domi-1a26")
+	if err != nil || display != "ドミ・１Ａ２６" {
+		t.Fatalf("expected 'ドミ・１Ａ２６', got '%s' (err: %v)", display, err)
+	}
+	if _, err := form.Canonical("ドミ・１Ａ２７"); !errors.Is(err, ErrChecksum) {
+		t.Errorf("expected ErrChecksum, got %v", err)
+	}
+}
+
+func TestDisplayFormConfig(t *testing.T) {
+	generator := NewWithDefaults()
+
+	tests := []struct {
+		name     string
+		alphabet DisplayAlphabet
+		field    string
+	}{
+		{"too few notes", DisplayAlphabet{Notes: "ド レ ミ", Characters: katakana.Characters}, "Notes"},
+		{"too few characters", DisplayAlphabet{Notes: katakana.Notes, Characters: "０ １"}, "Characters"},
+		{"prefix", DisplayAlphabet{Notes: "ド レ ミ ファ ソ ラ ドレ", Characters: katakana.Characters}, "Notes"},
+		{"prefix of separator", DisplayAlphabet{Notes: "ド レ ミ ファ ソ ラ ド・", Characters: katakana.Characters, Separator: "・"}, "Notes"},
+		{"prefix of character", DisplayAlphabet{Notes: "ド レ ミ ファ ソ ラ ド０", Characters: katakana.Characters}, "Notes"},
+		{"duplicate", DisplayAlphabet{Notes: katakana.Notes, Characters: "０ １ ２ ３ ４ ５ ６ ７ ８ ９ Ａ Ａ"}, "Characters"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := generator.DisplayForm(tt.alphabet)
+			var configErr *ConfigError
+			if !errors.As(err, &configErr) || configErr.Field != tt.field {
+				t.Errorf("expected ConfigError for %s, got %v", tt.field, err)
+			}
+		})
+	}
+}
+
+func TestDisplayFormPrefixes(t *testing.T) {
+	generator := New(Config{JustIntonationDigits: 2, EqualTemperamentDigits: 2, Separator: "-"})
+
+	// ド starts ドー, but no token starts with ー, so the longest match is right
+	form, err := generator.DisplayForm(DisplayAlphabet{Notes: "ド レ ミ ファ ソ ラ ドー", Characters: katakana.Characters})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for canonical, expected := range map[string]string{"doti-00": "ドドー００", "tido-00": "ドード００", "dodo-00": "ドド００"} {
+		display, err := form.Display(canonical)
+		if err != nil || display != expected {
+			t.Errorf("expected '%s', got '%s' (err: %v)", expected, display, err)
+		}
+		if back, err := form.Canonical(display); err != nil || back != canonical {
+			t.Errorf("expected '%s', got '%s' (err: %v)", canonical, back, err)
+		}
+	}
+}
+
+func TestChromaticForm(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 3,
+		Separator:              "-",
+	})
+	form, err := generator.ChromaticForm()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		canonical string
+		display   string
+	}{
+		{"dodo-000", "dodo-CCC"},
+		{"domi-1a2", "domi-C#A#D"},
+		{"fati-bb0", "fati-BBC"},
+		{"sola-123", "sola-C#DD#"},
+		{"sola-6b7", "sola-F#BG"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.canonical, func(t *testing.T) {
+			display, err := form.Display(tt.canonical)
+			if err != nil || display != tt.display {
+				t.Errorf("expected '%s', got '%s' (err: %v)", tt.display, display, err)
+			}
+			canonical, err := form.Canonical(tt.display)
+			if err != nil || canonical != tt.canonical {
+				t.Errorf("expected '%s', got '%s' (err: %v)", tt.canonical, canonical, err)
+			}
+		})
+	}
+
+	for _, display := range []string{"domi-C#A#", "domi-C#A#DD", "domi-H#AD", "domi-#CAD", "domi_CCC"} {
+		if _, err := form.Canonical(display); !errors.Is(err, ErrInvalidID) {
+			t.Errorf("expected '%s' to be rejected, got %v", display, err)
+		}
+	}
+
+	// Check characters are note names too
+	checked, err := New(Config{JustIntonationDigits: 2, EqualTemperamentDigits: 3, Separator: "-", Checksum: true}).ChromaticForm()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if display, err := checked.Display("domi-1a26"); err != nil || display != "domi-C#A#DF#" {
+		t.Errorf("expected 'domi-C#A#DF#', got '%s' (err: %v)", display, err)
+	}
+	if _, err := checked.Canonical("domi-C#A#DG"); !errors.Is(err, ErrChecksum) {
+		t.Errorf("expected ErrChecksum, got %v", err)
+	}
+
+	var configErr *ConfigError
+	if _, err := New(Config{EqualTemperamentBase: 16}).ChromaticForm(); !errors.As(err, &configErr) {
+		t.Errorf("expected ConfigError for 16 characters, got %v", err)
+	}
+}
+
+func TestWithDisplayNames(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   3,
+		EqualTemperamentDigits: 3,
+		Separator:              "-",
+	})
+	form, err := generator.WithDisplayNames(map[string]string{"do": "pi", "re": "ka", "mi": "chu", "a": "X"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		canonical string
+		display   string
+	}{
+		{"doremi-1a2", "pikachu-1X2"},
+		{"fasoti-000", "fasoti-000"},
+		{"mimido-aaa", "chuchupi-XXX"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.canonical, func(t *testing.T) {
+			display, err := form.Display(tt.canonical)
+			if err != nil || display != tt.display {
+				t.Errorf("expected '%s', got '%s' (err: %v)", tt.display, display, err)
+			}
+			if canonical, err := form.Canonical(display); err != nil || canonical != tt.canonical {
+				t.Errorf("expected '%s', got '%s' (err: %v)", tt.canonical, canonical, err)
+			}
+		})
+	}
+
+	invalid := []map[string]string{
+		{"sol": "x"},               // unknown note
+		{"do": "pi ka"},            // whitespace
+		{"do": "fa"},               // clashes with the kept note "fa"
+		{"do": "p", "re": "pmi"},   // ambiguous prefix of another label
+		{"0": "zero", "1": "zero"}, // duplicate label
+	}
+	for _, names := range invalid {
+		if _, err := generator.WithDisplayNames(names); !errors.Is(err, ErrInvalidConfig) {
+			t.Errorf("expected ErrInvalidConfig for %v, got %v", names, err)
+		}
+	}
+}
@@ -3,17 +3,26 @@
 package doremid
 
 import (
+	"context"
+	"crypto/cipher"
+	"fmt"
 	"math/rand"
+	"slices"
 	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 )
 
-// Generator holds the configuration and lookup tables for efficient ID generation
+// Generator holds the configuration and lookup tables for efficient ID generation.
+// Random generation is not safe for concurrent use unless Config.Concurrent,
+// Config.SecureRandom or Config.Rand is set; parsing and conversion always are.
 type Generator struct {
 	// ID generation parameters
 	JustIntonationDigits   int    // Number of musical note pairs in the first part
 	EqualTemperamentDigits int    // Number of characters in the second part
 	Separator              string // String used to separate the two parts of the ID
+	MaxParseLength         int    // Maximum input length accepted by parsing methods, negative for no limit
 
 	// Musical note names as byte slices for better performance
 	justIntonationBytes [][]byte
@@ -22,11 +31,52 @@ type Generator struct {
 	// Cached lengths
 	justIntonationLen   int
 	equalTemperamentLen int
+	// Shortest and longest note in bytes, equal for the default notes
+	minNoteLen int
+	maxNoteLen int
 	// Lookup maps for O(1) reverse conversion
 	justIntonationMap   map[string]int
 	equalTemperamentMap map[byte]int
 	// Random number generator with proper seeding
 	rand *rand.Rand
+	// Whether rand draws from crypto/rand
+	secureRandom bool
+	// Source rand draws from, nil for the built-in sources
+	randSource RandSource
+	// Whether randSource is keyed by a known seed, see NewSeeded
+	seeded bool
+	// Whether IDs end with a check character
+	checksum bool
+	// Per-goroutine random sources for concurrent generators, nil otherwise
+	randPool *sync.Pool
+	// Position range new IDs are restricted to, nil if unrestricted
+	restriction *Range
+	// Keyed permutation used by ObfuscatedPositionToID, nil without a PermutationKey
+	permutation cipher.Block
+	// Retry policy for store calls, nil to call stores once
+	retry *RetryPolicy
+	// Namespace prefix including its separator, e.g. "usr_", empty without a Prefix
+	prefix    string
+	namespace string
+	// Version marked in every ID, see Config.Version; the marker ends prefix
+	version int
+	// Cumulative weights of the notes and characters, nil for uniform draws
+	justWeights  weightTable
+	equalWeights weightTable
+	// Positions generation skips, see Config.ReservedIDs
+	reserved reservedSet
+	// Filter for note sequences generation skips, nil if none
+	wordFilter WordFilter
+	// Alternate note spellings accepted on input, see Config.InputAliases
+	aliases []noteAlias
+	// Rewrites input before parsing, see Config.UnicodeNormalize
+	normalize func(string) string
+	// Whether parsing accepts notes and characters in any case, see Config.LenientCase
+	lenientCase bool
+	// Symbols per group and the separators between groups, see Config.GroupSize
+	groupSize               int
+	noteGroupSeparator      string
+	characterGroupSeparator string
 }
 
 // Config defines the configuration for ID generation
@@ -37,10 +87,177 @@ type Config struct {
 	// EqualTemperamentDigits specifies the number of characters in the second part
 	EqualTemperamentDigits int
 
-	// Separator is the string used to separate the two parts of the ID
+	// MinCombinations, when positive, replaces both digit counts above with the
+	// fewest symbols whose ID space holds at least this many IDs, e.g. 1000000
+	// gives 2 notes and 4 characters. NewForCapacity is a shorthand.
+	MinCombinations int64
+
+	// CapacityBias chooses how MinCombinations splits symbols between the two
+	// parts when several splits need the same number. Zero is BalancedParts.
+	CapacityBias CapacityBias
+
+	// Separator is the string used to separate the two parts of the ID, which
+	// may be multi-byte, e.g. "·"
 	Separator string
+
+	// MaxParseLength limits the length of inputs accepted by parsing methods such as
+	// IDToPosition. Longer inputs are rejected in O(1) before any splitting or lookup
+	// work, protecting handlers that pass user input straight to the parser.
+	// Zero uses DefaultMaxParseLength; a negative value disables the limit.
+	MaxParseLength int
+
+	// SecureRandom makes NewID and BatchGenerateRandomIDs draw from crypto/rand
+	// instead of a time-seeded math/rand source, so random IDs are unpredictable
+	// enough to be used as tokens. It is slower than the default source.
+	// A secure generator is safe for concurrent use.
+	SecureRandom bool
+
+	// Concurrent makes the generator safe for concurrent use. Each call borrows a
+	// random source from a pool instead of sharing one, so concurrent callers are
+	// not serialized behind a mutex.
+	Concurrent bool
+
+	// Rand replaces the built-in random sources with src, e.g. a hardware RNG,
+	// or a seeded math/rand/v2.ChaCha8 so tests see the same IDs on every run.
+	// SecureRandom and Concurrent have no effect when it is set. Reads are
+	// serialized, so the generator is safe for concurrent use.
+	Rand RandSource
+
+	// Notes overrides the just intonation syllables as a space-separated list,
+	// e.g. "do re mi fa sol la ti" or "ut re mi fa sol la si". Notes may differ in
+	// length but no note may be a prefix of another (or a duplicate), so IDs parse
+	// unambiguously. Empty uses DefaultNotes.
+	Notes string
+
+	// InputAliases maps alternate note spellings to the notes they stand for,
+	// e.g. {"sol": "so", "si": "ti"}, so IDs written with regional solfège
+	// variants parse: "solla-1a2" is read as "sola-1a2". Generated IDs and the
+	// results of Canonicalize and Compact use the notes. An alias must not be a
+	// note, and notes and aliases together must split unambiguously.
+	InputAliases map[string]string
+
+	// Characters overrides the equal temperament character set, e.g.
+	// "0123456789ABCDEF" for uppercase hex. Characters must be distinct ASCII.
+	// Empty uses DefaultCharacters, or BaseCharacters with EqualTemperamentBase.
+	Characters string
+
+	// EqualTemperamentBase is the number of equal temperament characters, the
+	// radix of the second part, e.g. 16 for hex, 24 for quarter tones or 31 for
+	// 31-EDO. Without Characters the first EqualTemperamentBase of BaseCharacters
+	// are used, so at most 36; with Characters it must match their number. Zero
+	// takes the base from Characters, 12 by default.
+	EqualTemperamentBase int
+
+	// Case writes the notes and characters of IDs in LowerCase, e.g. "domi-1a2",
+	// UpperCase, e.g. "DOMI-1A2", or TitleCase, e.g. "DoMi-1a2", rewriting Notes,
+	// Characters and InputAliases. Parsing then expects that case unless
+	// LenientCase is set. The prefix keeps its own case. Zero is AsConfigured,
+	// which uses the notes and characters as listed.
+	Case Case
+
+	// LenientCase makes parsing accept notes, characters and the prefix in any
+	// case, so "DOMI-1A2", "DoMi-1a2" and "domi-1a2" are the same ID. Canonicalize
+	// and Compact return the configured case. Notes, aliases and characters must
+	// then differ other than by case.
+	LenientCase bool
+
+	// UnicodeNormalize rewrites input before parsing, e.g. FoldWidth to accept
+	// fullwidth IDs typed with an East Asian input method, FoldHomoglyphs to also
+	// fold look-alike letters, or norm.NFKC.String from golang.org/x/text for
+	// full compatibility normalization. Errors then
+	// report offsets in the rewritten input. Nil parses input as given. Not
+	// serialized, so configs stay JSON-encodable.
+	UnicodeNormalize func(string) string `json:"-"`
+
+	// JustWeights and EqualWeights bias NewID toward some notes and characters,
+	// e.g. to match the distribution of existing IDs: each symbol is drawn with
+	// a chance proportional to its weight. They hold one non-negative weight per
+	// note of Notes and character of Characters, in the order listed there, and
+	// need a positive total. Positions map to IDs as before, and other random
+	// methods such as BatchGenerateRandomIDs and restricted generators still draw
+	// uniformly. Weighted IDs are easier to guess, which BruteForceCost ignores.
+	// Nil draws uniformly.
+	JustWeights  []float64
+	EqualWeights []float64
+
+	// LexSortable orders the notes and characters by their bytes, so that IDs
+	// sort as strings in the same order as their positions, e.g. for database
+	// keys and time-ordered IDs. The default notes become "do fa la mi re so ti",
+	// which changes the ID of every position compared to a generator without
+	// it; Sortable then reports true.
+	LexSortable bool
+
+	// Checksum appends a Luhn mod N check character, drawn from the equal
+	// temperament characters, to every ID, e.g. "domi-1a2" becomes "domi-1a26".
+	// It detects every single-symbol typo and most adjacent transpositions, and
+	// parsing rejects IDs whose check character does not match. It requires at
+	// least as many characters as notes.
+	Checksum bool
+
+	// Prefix is a namespace such as "usr" or "ord" placed before every ID, e.g.
+	// "usr_domi-1a2". Parsing requires and strips it. It must not contain
+	// PrefixSeparator; ParsePrefix reads it back from IDs of any generator.
+	Prefix string
+
+	// PrefixSeparator follows Prefix. Empty uses DefaultPrefixSeparator.
+	PrefixSeparator string
+
+	// Version marks every ID with the equal temperament character at index
+	// Version, placed after any Prefix, e.g. "domi-1a2" becomes "2domi-1a2" with
+	// a Version of 2. Parsing rejects IDs marked with another version, so a
+	// system that grows its digit counts can tell old IDs from new ones and
+	// Migrate them. It must be less than the number of characters; zero
+	// disables the marker.
+	Version int
+
+	// GroupSize chunks long IDs for readability by placing a group separator
+	// between every GroupSize notes and every GroupSize characters, e.g.
+	// "dore-mifa_0a3b" with a GroupSize of 2, a NoteGroupSeparator of "-" and a
+	// Separator of "_". Any check character ends the last group. Generated IDs
+	// are grouped; parsing accepts the grouped and the compact form, and
+	// Canonicalize and Compact convert between them. Zero disables grouping.
+	GroupSize int
+
+	// NoteGroupSeparator separates groups of notes. It must not overlap any
+	// note. Empty leaves the notes ungrouped.
+	NoteGroupSeparator string
+
+	// CharacterGroupSeparator separates groups of characters. It must not
+	// start with a character. Empty leaves the characters ungrouped.
+	CharacterGroupSeparator string
+
+	// ReservedIDs and ReservedPositions are never generated, e.g. IDs spelling
+	// unfortunate words or sentinel values such as the first and last ID.
+	// Random generation draws from the remaining positions, and sequential
+	// methods leave reserved positions out of the ranges they cover, returning
+	// fewer IDs. Parsing still accepts them, and Mint refuses them with
+	// ErrReserved. EffectiveCombinations reports the capacity left. Reserved
+	// IDs must be valid, and reserved positions within the ID space, which
+	// must fit an int64.
+	ReservedIDs       []string
+	ReservedPositions []int64
+
+	// WordFilter skips IDs whose notes run together into a banned word, e.g.
+	// "dotiti" with DefaultWordFilter. Random generation re-rolls such IDs, and
+	// sequential methods skip every position of their note sequence, returning
+	// fewer IDs as they do for reserved positions. Parsing still accepts them,
+	// and Mint refuses them with ErrFiltered. EffectiveCombinations does not
+	// count them. Use DefaultWordFilter for a short English list, NewWordFilter
+	// for your own words or WordFilterFunc for any rule; nil filters nothing.
+	WordFilter WordFilter
+
+	// PermutationKey keys the Feistel permutation used by ObfuscatedPositionToID
+	// and IDToObfuscatedPosition, so sequential counters produce scattered-looking
+	// but reversible IDs. Keep it secret; anyone holding it can order the IDs.
+	PermutationKey string
 }
 
+// DefaultMaxParseLength is the input length limit used when Config.MaxParseLength is zero
+const DefaultMaxParseLength = 256
+
+// DefaultPrefixSeparator follows Config.Prefix when Config.PrefixSeparator is empty
+const DefaultPrefixSeparator = "_"
+
 // DefaultConfig returns a default configuration
 func DefaultConfig() Config {
 	return Config{
@@ -50,18 +267,104 @@ func DefaultConfig() Config {
 	}
 }
 
-// New creates a new ID generator with optimized lookup tables
+// New creates a new ID generator with optimized lookup tables.
+// It panics if custom Notes or Characters are invalid or do not support Checksum,
+// if MinCombinations cannot be met or a reserved ID or position is invalid; use
+// NewE to get an error instead.
 func New(config Config) *Generator {
+	g, err := newGenerator(config)
+	if err != nil {
+		panic(err)
+	}
+	return g
+}
+
+// newGenerator validates config and builds its generator
+func newGenerator(config Config) (*Generator, error) {
+	var err error
+	if config.Characters, err = baseCharacters(config); err != nil {
+		return nil, err
+	}
+	if config, err = applyCase(config); err != nil {
+		return nil, err
+	}
+	if err := validateAlphabet(config); err != nil {
+		return nil, err
+	}
+	if config.MinCombinations != 0 {
+		var err error
+		config.JustIntonationDigits, config.EqualTemperamentDigits, err = capacityDigits(config)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	notes := strings.Fields(config.Notes)
+	if len(notes) == 0 {
+		notes = strings.Fields(DefaultNotes)
+	}
+	characters := config.Characters
+	if characters == "" {
+		characters = DefaultCharacters
+	}
+	listedNotes, listedCharacters := notes, characters
+	if config.LexSortable {
+		notes = slices.Sorted(slices.Values(notes))
+		sorted := []byte(characters)
+		slices.Sort(sorted)
+		characters = string(sorted)
+	}
+
 	g := &Generator{
-		JustIntonationDigits:   config.JustIntonationDigits,
-		EqualTemperamentDigits: config.EqualTemperamentDigits,
-		Separator:              config.Separator,
-		justIntonationBytes: [][]byte{
-			[]byte("do"), []byte("re"), []byte("mi"), []byte("fa"),
-			[]byte("so"), []byte("la"), []byte("ti"),
-		},
-		equalTemperamentBytes: []byte("0123456789ab"),
-		rand:                  rand.New(rand.NewSource(time.Now().UnixNano())),
+		JustIntonationDigits:    config.JustIntonationDigits,
+		EqualTemperamentDigits:  config.EqualTemperamentDigits,
+		Separator:               config.Separator,
+		MaxParseLength:          config.MaxParseLength,
+		justIntonationBytes:     make([][]byte, len(notes)),
+		equalTemperamentBytes:   []byte(characters),
+		secureRandom:            config.SecureRandom,
+		checksum:                config.Checksum,
+		groupSize:               config.GroupSize,
+		noteGroupSeparator:      config.NoteGroupSeparator,
+		characterGroupSeparator: config.CharacterGroupSeparator,
+		wordFilter:              config.WordFilter,
+		normalize:               config.UnicodeNormalize,
+	}
+
+	if config.PermutationKey != "" {
+		g.permutation = newPermutation(config.PermutationKey)
+	}
+	if config.Prefix != "" {
+		g.prefix = config.Prefix + prefixSeparator(config)
+		g.namespace = config.Prefix
+	}
+	if config.Version > 0 {
+		g.version = config.Version
+		g.prefix += string(g.equalTemperamentBytes[config.Version])
+	}
+
+	g.minNoteLen, g.maxNoteLen = len(notes[0]), len(notes[0])
+	for i, note := range notes {
+		g.justIntonationBytes[i] = []byte(note)
+		g.minNoteLen = min(g.minNoteLen, len(note))
+		g.maxNoteLen = max(g.maxNoteLen, len(note))
+	}
+
+	switch {
+	case config.Rand != nil:
+		g.randSource = config.Rand
+		g.rand = rand.New(&readerSource{r: config.Rand})
+	case g.secureRandom:
+		g.rand = rand.New(cryptoSource{})
+	default:
+		g.rand = rand.New(rand.NewSource(time.Now().UnixNano()))
+		if config.Concurrent {
+			g.randPool = newRandPool()
+		}
+	}
+
+	if g.MaxParseLength == 0 {
+		g.MaxParseLength = DefaultMaxParseLength
 	}
 
 	// Cache lengths
@@ -79,7 +382,19 @@ func New(config Config) *Generator {
 		g.equalTemperamentMap[char] = i
 	}
 
-	return g
+	if err := g.alias(config); err != nil {
+		return nil, err
+	}
+	if err := g.foldCase(config); err != nil {
+		return nil, err
+	}
+	if err := g.weigh(config, listedNotes, listedCharacters); err != nil {
+		return nil, err
+	}
+	if err := g.reserve(config); err != nil {
+		return nil, err
+	}
+	return g, nil
 }
 
 // NewWithDefaults creates a new generator with default configuration
@@ -91,24 +406,82 @@ func NewWithDefaults() *Generator {
 // It creates an ID with two parts: a musical note part and an alphanumeric part,
 // separated by the configured separator.
 func (g *Generator) NewID() string {
-	// Pre-estimate capacity: just part longest element is 2 bytes, equal part is 1 byte
-	capacity := g.JustIntonationDigits*2 + len(g.Separator) + g.EqualTemperamentDigits
-	result := make([]byte, 0, capacity)
+	_, capacity := g.idLengths()
+	return string(g.AppendID(make([]byte, 0, capacity)))
+}
 
-	// Generate musical note part using optimized byte arrays
-	for i := 0; i < g.JustIntonationDigits; i++ {
-		result = append(result, g.justIntonationBytes[g.rand.Intn(g.justIntonationLen)]...)
+// AppendID appends a random ID to dst and returns the extended buffer, like
+// NewID but without allocating when dst has enough capacity. Appends nothing
+// if Config.WordFilter rejects a thousand IDs in a row.
+func (g *Generator) AppendID(dst []byte) []byte {
+	if g.restriction != nil || (g.reserved != nil && !g.weighted()) {
+		return g.appendRestrictedID(dst)
+	}
+
+	rng := g.acquireRand()
+	defer g.releaseRand(rng)
+	if g.reserved == nil {
+		return g.appendRandomID(dst, rng)
+	}
+
+	// Weighted draws cannot be mapped onto the unreserved positions, so
+	// reserved ones are re-rolled instead
+	for attempt := 0; attempt <= maxRerolls; attempt++ {
+		id := g.appendRandomID(dst, rng)
+		if len(id) == len(dst) || !g.reserved.contains(g.IDToPosition(string(id[len(dst):]))) {
+			return id
+		}
+	}
+	return dst
+}
+
+// appendRandomID appends an ID drawn symbol by symbol from rng to dst, or
+// nothing if Config.WordFilter rejects too many
+func (g *Generator) appendRandomID(dst []byte, rng *rand.Rand) []byte {
+	dst = append(dst, g.prefix...)
+
+	// Generate musical note part using optimized byte arrays, re-rolling notes
+	// that spell a filtered word
+	start, sum := len(dst), 0
+	var notes []byte
+	for attempt := 0; ; attempt++ {
+		dst, sum, notes = dst[:start], 0, notes[:0]
+		for i := 0; i < g.JustIntonationDigits; i++ {
+			index := g.justWeights.pick(rng, g.justIntonationLen)
+			dst = append(dst, g.noteGroupBreak(i)...)
+			dst = append(dst, g.justIntonationBytes[index]...)
+			if g.checksum {
+				sum = g.checksumAdd(sum, i, index)
+			}
+			if g.wordFilter != nil {
+				notes = append(notes, g.justIntonationBytes[index]...)
+			}
+		}
+		if g.wordFilter == nil || !g.wordFilter.Match(string(notes)) {
+			break
+		}
+		if attempt == maxRerolls {
+			return dst[:start-len(g.prefix)]
+		}
 	}
 
 	// Add separator
-	result = append(result, g.Separator...)
+	dst = append(dst, g.Separator...)
 
 	// Generate alphanumeric part using direct byte indexing
 	for i := 0; i < g.EqualTemperamentDigits; i++ {
-		result = append(result, g.equalTemperamentBytes[g.rand.Intn(g.equalTemperamentLen)])
+		index := g.equalWeights.pick(rng, g.equalTemperamentLen)
+		dst = append(dst, g.characterGroupBreak(i)...)
+		dst = append(dst, g.equalTemperamentBytes[index])
+		if g.checksum {
+			sum = g.checksumAdd(sum, g.JustIntonationDigits+i, index)
+		}
 	}
 
-	return string(result)
+	if g.checksum {
+		dst = append(dst, g.checkCharacter(sum))
+	}
+	return dst
 }
 
 // BatchGenerateRandomIDs generates a batch of unique random IDs.
@@ -120,44 +493,35 @@ func (g *Generator) NewID() string {
 // or count exceeds maximum possible combinations.
 // Uses random sampling from all possible positions to ensure uniqueness without collision checking.
 func (g *Generator) BatchGenerateRandomIDs(count int64) []string {
-	if count <= 0 {
-		return []string{}
-	}
-
-	maxCombinations := g.MaxCombinations()
-
-	// Check if count exceeds maximum possible combinations
-	if count > maxCombinations {
-		return []string{}
-	}
-
-	// Generate random sample of positions without replacement
-	positions := g.randomSample(int(maxCombinations), int(count))
-
-	// Convert positions to IDs
-	ids := make([]string, count)
-	for i, pos := range positions {
-		ids[i] = g.PositionToID(int64(pos))
-	}
-
+	ids, _ := g.BatchGenerateRandomIDsCtx(context.Background(), count)
 	return ids
 }
 
-// randomSample generates count unique random numbers from range [0, max).
+// randomSample generates count unique random numbers from range [0, max),
+// leaving out those skip reports, if not nil. It returns fewer numbers if too
+// few are left.
 // Uses reservoir sampling algorithm for efficient sampling without replacement.
-func (g *Generator) randomSample(max, count int) []int {
+// If ctx is done, it returns the numbers sampled so far and ctx.Err().
+func (g *Generator) randomSample(ctx context.Context, max, count int, skip func(int) bool) ([]int, error) {
+	rng := g.acquireRand()
+	defer g.releaseRand(rng)
+
 	if count >= max {
 		// Return all positions shuffled if count equals or exceeds max
 		positions := make([]int, max)
 		for i := 0; i < max; i++ {
 			positions[i] = i
 		}
-		// Shuffle the entire array using Fisher-Yates
+		// Shuffle the entire array using Fisher-Yates; the tail is final
 		for i := max - 1; i > 0; i-- {
-			j := g.rand.Intn(i + 1)
+			if (max-1-i)%batchCheckInterval == 0 && ctx.Err() != nil {
+				return skipSampled(positions[i+1:], skip), ctx.Err()
+			}
+			j := rng.Intn(i + 1)
 			positions[i], positions[j] = positions[j], positions[i]
 		}
-		return positions[:count]
+		positions = skipSampled(positions, skip)
+		return positions[:min(count, len(positions))], nil
 	}
 
 	// For smaller samples, use a more straightforward approach
@@ -165,20 +529,36 @@ func (g *Generator) randomSample(max, count int) []int {
 	used := make(map[int]bool)
 	positions := make([]int, 0, count)
 
-	// Generate unique random positions
-	for len(positions) < count {
-		pos := g.rand.Intn(max)
+	// Generate unique random positions; skipped ones count as used, so the
+	// loop ends once every position has been drawn
+	for attempt := 0; len(positions) < count && len(used) < max; attempt++ {
+		if attempt%batchCheckInterval == 0 && ctx.Err() != nil {
+			return positions, ctx.Err()
+		}
+		pos := rng.Intn(max)
 		if !used[pos] {
 			used[pos] = true
-			positions = append(positions, pos)
+			if skip == nil || !skip(pos) {
+				positions = append(positions, pos)
+			}
 		}
 	}
 
-	return positions
+	return positions, nil
+}
+
+// skipSampled removes the numbers skip reports from positions in place
+func skipSampled(positions []int, skip func(int) bool) []int {
+	if skip == nil {
+		return positions
+	}
+	return slices.DeleteFunc(positions, skip)
 }
 
 // MaxCombinations returns the maximum number of unique IDs that can be generated
 // with the current configuration.
+// It overflows for configurations exceeding int64, e.g. JustIntonationDigits=20;
+// use MaxCombinationsBig and the *Big conversions for those.
 func (g *Generator) MaxCombinations() int64 {
 	justMax := int64(g.intPow(g.justIntonationLen, g.JustIntonationDigits))
 	equalMax := int64(g.intPow(g.equalTemperamentLen, g.EqualTemperamentDigits))
@@ -193,32 +573,9 @@ func (g *Generator) MaxCombinations() int64 {
 //
 // Returns a slice of sequential IDs. The actual count may be less than requested
 // if it would exceed the maximum possible combinations or go beyond valid positions.
+// For restricted generators, positions outside the allowed range are never generated.
 func (g *Generator) BatchGenerateIDs(count int64, startPosition int64) []string {
-	if count <= 0 || startPosition < 0 {
-		return []string{}
-	}
-
-	maxCombinations := g.MaxCombinations()
-
-	// Check if startPosition is beyond maximum
-	if startPosition >= maxCombinations {
-		return []string{}
-	}
-
-	// Limit count to not exceed maximum combinations
-	if startPosition+count > maxCombinations {
-		count = maxCombinations - startPosition
-	}
-
-	// Additional safety check
-	if count <= 0 {
-		return []string{}
-	}
-
-	ids := make([]string, count)
-	for i := int64(0); i < count; i++ {
-		ids[i] = g.PositionToID(startPosition + i)
-	}
+	ids, _ := g.BatchGenerateIDsCtx(context.Background(), count, startPosition)
 	return ids
 }
 
@@ -231,46 +588,121 @@ func (g *Generator) BatchGenerateIDs(count int64, startPosition int64) []string
 //   - position in the sequence (0-based)
 //   - -1 if the ID format is invalid
 func (g *Generator) IDToPosition(id string) int64 {
-	// Split ID by separator
-	parts := strings.Split(id, g.Separator)
-	if len(parts) != 2 {
+	pos, err := g.decode(id)
+	if err != nil {
 		return -1
 	}
+	return pos
+}
 
-	justPart := parts[0]
-	equalPart := parts[1]
+// IDToPositionE is like IDToPosition but reports why an ID is invalid.
+//
+// Returns a *FormatError matching ErrInvalidFormat for structural problems such as
+// a wrong length or separator, or ErrBadCharacter for an unknown note or character.
+func (g *Generator) IDToPositionE(id string) (int64, error) {
+	return g.decode(id)
+}
 
-	// Validate part lengths
-	if len(justPart) != g.JustIntonationDigits*2 || len(equalPart) != g.EqualTemperamentDigits {
-		return -1
+// decode parses an ID into its position, reporting the first problem found as a *FormatError.
+// The parts are located by the widths of the notes read, not by searching for the
+// separator, so separators that also occur inside the note or character sets (or
+// an empty separator) are handled correctly.
+func (g *Generator) decode(id string) (int64, error) {
+	// Reject oversized input in O(1) before doing any other work
+	if g.MaxParseLength >= 0 && len(id) > g.MaxParseLength {
+		return -1, &FormatError{Input: truncate(id, g.MaxParseLength), Offset: -1, Reason: fmt.Sprintf("input exceeds %d bytes", g.MaxParseLength), Err: ErrInvalidFormat}
+	}
+	if g.JustIntonationDigits < 0 || g.EqualTemperamentDigits < 0 {
+		return -1, &ConfigError{Field: "JustIntonationDigits/EqualTemperamentDigits", Reason: "must not be negative"}
+	}
+
+	// Parse the compact form of grouped IDs, with aliases spelled as notes
+	id = g.ungroup(g.unalias(id))
+
+	// Validate total length before looking at any content. Lengths are counted
+	// in symbols, so an ID of the right length with a multi-byte symbol where an
+	// ASCII one belongs is reported where the symbol is rather than as too long.
+	if shortestBytes, longestBytes := g.compactLengths(); len(id) < shortestBytes || len(id) > longestBytes {
+		shortest, longest := g.compactRuneLengths()
+		length := utf8.RuneCountInString(id)
+		if shortest == longest && length != shortest {
+			return -1, &FormatError{Input: truncate(id, longestBytes), Offset: -1, Reason: fmt.Sprintf("length must be %d, got %d", shortest, length), Err: ErrInvalidFormat}
+		}
+		if length < shortest || length > longest {
+			return -1, &FormatError{Input: truncate(id, longestBytes), Offset: -1, Reason: fmt.Sprintf("length must be between %d and %d, got %d", shortest, longest, length), Err: ErrInvalidFormat}
+		}
 	}
 
-	// Parse musical note part using O(1) map lookup
+	// Require and skip the namespace prefix and version marker
+	if g.version > 0 && strings.HasPrefix(id, g.prefix[:len(g.prefix)-1]) && len(id) >= len(g.prefix) && id[len(g.prefix)-1] != g.prefix[len(g.prefix)-1] {
+		return -1, &FormatError{Input: id, Offset: len(g.prefix) - 1, Symbol: symbolAt(id, len(g.prefix)-1, 1), Reason: fmt.Sprintf("expected version %d, got marker", g.version), Err: ErrVersionMismatch}
+	}
+	if !strings.HasPrefix(id, g.prefix) {
+		return -1, &FormatError{Input: id, Offset: 0, Symbol: symbolAt(id, 0, len(g.prefix)), Reason: fmt.Sprintf("missing prefix %q", g.prefix), Err: ErrInvalidFormat}
+	}
+
+	// Parse musical note part using O(1) map lookup; justLen is the offset past it
 	justValue := int64(0)
-	for i := 0; i < len(justPart); i += 2 {
-		if i+1 >= len(justPart) {
-			return -1 // Length is not a multiple of 2
+	justLen := len(g.prefix)
+	sum := 0
+	for i := 0; i < g.JustIntonationDigits; i++ {
+		index, width, found := g.nextNote(id, justLen)
+		if !found {
+			return -1, &FormatError{Input: id, Offset: justLen, Symbol: symbolAt(id, justLen, g.maxNoteLen), Reason: "unknown note", Err: ErrBadCharacter}
 		}
-		twoChar := justPart[i : i+2]
-		if index, found := g.justIntonationMap[twoChar]; found {
-			justValue = justValue*int64(g.justIntonationLen) + int64(index)
-		} else {
-			return -1
+		justValue = justValue*int64(g.justIntonationLen) + int64(index)
+		justLen += width
+		if g.checksum {
+			sum = g.checksumAdd(sum, i, index)
+		}
+	}
+
+	equalStart := justLen + len(g.Separator)
+	equalEnd := equalStart + g.EqualTemperamentDigits
+	if expected := equalEnd + g.checksumLen(); len(id) != expected {
+		// As above, only a differing number of symbols is a length problem
+		expected := utf8.RuneCountInString(id[:justLen]+g.Separator) + g.EqualTemperamentDigits + g.checksumLen()
+		if length := utf8.RuneCountInString(id); length != expected {
+			return -1, &FormatError{Input: id, Offset: -1, Reason: fmt.Sprintf("length must be %d for these notes, got %d", expected, length), Err: ErrInvalidFormat}
 		}
 	}
+	if !strings.HasPrefix(id[justLen:], g.Separator) {
+		return -1, &FormatError{Input: id, Offset: justLen, Symbol: symbolAt(id, justLen, len(g.Separator)), Reason: "unexpected separator", Err: ErrInvalidFormat}
+	}
 
 	// Parse alphanumeric part using O(1) map lookup
 	equalValue := int64(0)
-	for _, char := range []byte(equalPart) {
-		if index, found := g.equalTemperamentMap[char]; found {
+	for i := equalStart; i < equalEnd; i++ {
+		if index, found := g.equalTemperamentMap[id[i]]; found {
 			equalValue = equalValue*int64(g.equalTemperamentLen) + int64(index)
+			if g.checksum {
+				sum = g.checksumAdd(sum, g.JustIntonationDigits+i-equalStart, index)
+			}
 		} else {
-			return -1
+			return -1, &FormatError{Input: id, Offset: i, Symbol: symbolAt(id, i, 1), Reason: "unknown character", Err: ErrBadCharacter}
+		}
+	}
+
+	if g.checksum {
+		if _, found := g.equalTemperamentMap[id[equalEnd]]; !found {
+			return -1, &FormatError{Input: id, Offset: equalEnd, Symbol: symbolAt(id, equalEnd, 1), Reason: "unknown character", Err: ErrBadCharacter}
+		}
+		if id[equalEnd] != g.checkCharacter(sum) {
+			return -1, &FormatError{Input: id, Offset: equalEnd, Symbol: id[equalEnd:], Reason: "check character mismatch", Err: ErrChecksum}
 		}
 	}
 
 	// Calculate total position
-	return justValue*int64(g.intPow(g.equalTemperamentLen, g.EqualTemperamentDigits)) + equalValue
+	return justValue*int64(g.intPow(g.equalTemperamentLen, g.EqualTemperamentDigits)) + equalValue, nil
+}
+
+// PositionToIDE is like PositionToID but returns a *RangeError for positions
+// outside [0, MaxCombinations()) instead of an empty or wrapped-around ID
+func (g *Generator) PositionToIDE(position int64) (string, error) {
+	if err := checkRange(position, Range{Start: 0, End: g.MaxCombinations()}); err != nil {
+		return "", err
+	}
+	return g.PositionToID(position), nil
 }
 
 // PositionToID generates an ID based on its position in the sequential order.
@@ -285,6 +717,17 @@ func (g *Generator) PositionToID(position int64) string {
 	if position < 0 {
 		return ""
 	}
+	_, capacity := g.idLengths()
+	return string(g.AppendPositionID(make([]byte, 0, capacity), position))
+}
+
+// AppendPositionID appends the ID at position to dst and returns the extended
+// buffer, like PositionToID but without allocating when dst has enough
+// capacity. dst is returned unchanged if position is negative.
+func (g *Generator) AppendPositionID(dst []byte, position int64) []byte {
+	if position < 0 {
+		return dst
+	}
 
 	// Calculate maximum value for alphanumeric part
 	equalMax := int64(g.intPow(g.equalTemperamentLen, g.EqualTemperamentDigits))
@@ -293,38 +736,43 @@ func (g *Generator) PositionToID(position int64) string {
 	justValue := position / equalMax
 	equalValue := position % equalMax
 
-	// Pre-estimate capacity for efficiency
-	capacity := g.JustIntonationDigits*2 + len(g.Separator) + g.EqualTemperamentDigits
-	result := make([]byte, 0, capacity)
+	dst = append(dst, g.prefix...)
 
-	// Generate musical note part
-	justDigits := make([]int, g.JustIntonationDigits)
-	temp := justValue
-	for i := g.JustIntonationDigits - 1; i >= 0; i-- {
-		justDigits[i] = int(temp % int64(g.justIntonationLen))
-		temp /= int64(g.justIntonationLen)
-	}
-
-	for _, digit := range justDigits {
-		result = append(result, g.justIntonationBytes[digit]...)
+	// Generate musical note part, most significant digit first. Values beyond
+	// the note part wrap around.
+	sum := 0
+	justLen := int64(g.justIntonationLen)
+	divisor := int64(g.intPow(g.justIntonationLen, g.JustIntonationDigits-1))
+	for i := 0; i < g.JustIntonationDigits; i++ {
+		digit := int(justValue / divisor % justLen)
+		dst = append(dst, g.noteGroupBreak(i)...)
+		dst = append(dst, g.justIntonationBytes[digit]...)
+		if g.checksum {
+			sum = g.checksumAdd(sum, i, digit)
+		}
+		divisor /= justLen
 	}
 
 	// Add separator
-	result = append(result, g.Separator...)
+	dst = append(dst, g.Separator...)
 
 	// Generate alphanumeric part using direct byte indexing
-	equalDigits := make([]int, g.EqualTemperamentDigits)
-	temp = equalValue
-	for i := g.EqualTemperamentDigits - 1; i >= 0; i-- {
-		equalDigits[i] = int(temp % int64(g.equalTemperamentLen))
-		temp /= int64(g.equalTemperamentLen)
+	equalLen := int64(g.equalTemperamentLen)
+	divisor = equalMax / equalLen
+	for i := 0; i < g.EqualTemperamentDigits; i++ {
+		digit := int(equalValue / divisor % equalLen)
+		dst = append(dst, g.characterGroupBreak(i)...)
+		dst = append(dst, g.equalTemperamentBytes[digit])
+		if g.checksum {
+			sum = g.checksumAdd(sum, g.JustIntonationDigits+i, digit)
+		}
+		divisor /= equalLen
 	}
 
-	for _, digit := range equalDigits {
-		result = append(result, g.equalTemperamentBytes[digit])
+	if g.checksum {
+		dst = append(dst, g.checkCharacter(sum))
 	}
-
-	return string(result)
+	return dst
 }
 
 // intPow calculates integer power using binary exponentiation.
@@ -1,6 +1,8 @@
 package doremid
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
@@ -229,6 +231,33 @@ func TestPositionToID(t *testing.T) {
 	}
 }
 
+func TestConversionErrors(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 3,
+		Separator:              "-",
+	})
+
+	if pos, err := generator.IDToPositionE("domi-1a2"); err != nil || pos != 3722 {
+		t.Errorf("expected 3722, got %d (err: %v)", pos, err)
+	}
+	if _, err := generator.IDToPositionE("domi_1a2"); !errors.Is(err, ErrInvalidFormat) || errors.Is(err, ErrBadCharacter) {
+		t.Errorf("expected ErrInvalidFormat for a malformed separator, got %v", err)
+	}
+	if _, err := generator.IDToPositionE("doxx-1a2"); !errors.Is(err, ErrBadCharacter) || errors.Is(err, ErrInvalidFormat) {
+		t.Errorf("expected ErrBadCharacter for an unknown note pair, got %v", err)
+	}
+
+	if id, err := generator.PositionToIDE(3722); err != nil || id != "domi-1a2" {
+		t.Errorf("expected 'domi-1a2', got '%s' (err: %v)", id, err)
+	}
+	for _, pos := range []int64{-1, generator.MaxCombinations()} {
+		if _, err := generator.PositionToIDE(pos); !errors.Is(err, ErrOutOfRange) {
+			t.Errorf("expected ErrOutOfRange for %d, got %v", pos, err)
+		}
+	}
+}
+
 func TestPositionToIDAndIDToPosition(t *testing.T) {
 	generator := New(Config{
 		JustIntonationDigits:   2,
@@ -359,6 +388,111 @@ func TestEdgeCases(t *testing.T) {
 	})
 }
 
+func TestMaxParseLength(t *testing.T) {
+	huge := strings.Repeat("do", 1<<20)
+
+	tests := []struct {
+		name   string
+		limit  int
+		input  string
+		reject bool
+	}{
+		{"default rejects huge input", 0, huge, true},
+		{"custom limit rejects longer input", 5, "domi-1a2", true},
+		{"valid ID within limit", 8, "domi-1a2", false},
+		{"negative disables limit", -1, huge, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			generator := New(Config{
+				JustIntonationDigits:   2,
+				EqualTemperamentDigits: 3,
+				Separator:              "-",
+				MaxParseLength:         tt.limit,
+			})
+
+			_, err := generator.decode(tt.input)
+			if (err != nil) != tt.reject {
+				t.Fatalf("expected rejection %v, got %v", tt.reject, err)
+			}
+
+			var formatErr *FormatError
+			if errors.As(err, &formatErr) && len(formatErr.Input) > DefaultMaxParseLength {
+				t.Errorf("expected error input to be truncated, got %d bytes", len(formatErr.Input))
+			}
+		})
+	}
+}
+
+func TestMaxParseLengthEntryPoints(t *testing.T) {
+	entryPoints := []struct {
+		name  string
+		input string
+		parse func(g *Generator, input string) error
+	}{
+		{"Parse", "solla-1a2", func(g *Generator, input string) error {
+			_, err := g.Parse(input)
+			return err
+		}},
+		{"Canonicalize", "solla-1a2", func(g *Generator, input string) error {
+			_, err := g.Canonicalize(input)
+			return err
+		}},
+		{"Nearest", "solla-1a2", func(g *Generator, input string) error {
+			if g.Nearest(input, 1) == nil {
+				return ErrInvalidFormat
+			}
+			return nil
+		}},
+		{"ParseSpoken", "sole lah dash one alpha two", func(g *Generator, input string) error {
+			_, err := g.ParseSpoken(input)
+			return err
+		}},
+	}
+
+	for _, tt := range entryPoints {
+		t.Run(tt.name, func(t *testing.T) {
+			// Inputs at the limit parse, one byte over is rejected
+			for _, limit := range []int{len(tt.input), len(tt.input) - 1, -1} {
+				generator := New(Config{
+					JustIntonationDigits:   2,
+					EqualTemperamentDigits: 3,
+					Separator:              "-",
+					MaxParseLength:         limit,
+					InputAliases:           map[string]string{"sol": "so"},
+				})
+				err := tt.parse(generator, tt.input)
+				if reject := limit == len(tt.input)-1; (err != nil) != reject {
+					t.Errorf("expected rejection %v with a limit of %d, got %v", reject, limit, err)
+				}
+			}
+		})
+	}
+}
+
+func FuzzIDToPosition(f *testing.F) {
+	for _, seed := range []string{"", "domi-1a2", "domi-1a", "dom\xffi-1a2", "----", strings.Repeat("x", 300)} {
+		f.Add(seed)
+	}
+
+	generator := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 3,
+		Separator:              "-",
+	})
+
+	f.Fuzz(func(t *testing.T, id string) {
+		pos := generator.IDToPosition(id)
+		if pos < 0 {
+			return
+		}
+		if back := generator.PositionToID(pos); back != id {
+			t.Errorf("round-trip of '%s' returned '%s'", id, back)
+		}
+	})
+}
+
 func TestMaxCombinations(t *testing.T) {
 	tests := []struct {
 		name                   string
@@ -660,7 +794,10 @@ func TestRandomSample(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			sample := generator.randomSample(tt.max, tt.count)
+			sample, err := generator.randomSample(context.Background(), tt.max, tt.count, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
 
 			// Check count
 			if len(sample) != tt.count {
@@ -688,3 +825,105 @@ func TestRandomSample(t *testing.T) {
 		})
 	}
 }
+
+func TestAppendPositionID(t *testing.T) {
+	generators := []*Generator{
+		New(Config{JustIntonationDigits: 2, EqualTemperamentDigits: 3, Separator: "-"}),
+		New(Config{JustIntonationDigits: 4, EqualTemperamentDigits: 5, Separator: "-", Checksum: true, Prefix: "usr"}),
+		New(Config{JustIntonationDigits: 1, EqualTemperamentDigits: 0}),
+	}
+
+	for _, generator := range generators {
+		prefix := []byte("id=")
+		for _, pos := range []int64{0, 1, 3722, generator.MaxCombinations() - 1} {
+			got := generator.AppendPositionID(prefix, pos)
+			if expected := "id=" + generator.PositionToID(pos); string(got) != expected {
+				t.Errorf("expected %q, got %q", expected, got)
+			}
+			if generator.IDToPosition(string(got[3:])) != pos%generator.MaxCombinations() {
+				t.Errorf("expected '%s' to decode to %d", got[3:], pos)
+			}
+		}
+
+		if got := generator.AppendPositionID(prefix, -1); string(got) != "id=" {
+			t.Errorf("expected a negative position to append nothing, got %q", got)
+		}
+	}
+}
+
+func TestAppendID(t *testing.T) {
+	generator := New(Config{JustIntonationDigits: 4, EqualTemperamentDigits: 5, Separator: "-", Checksum: true})
+	buf := make([]byte, 0, 64)
+	for i := 0; i < 100; i++ {
+		buf = generator.AppendID(buf[:0])
+		if err := generator.Validate(string(buf)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	restricted := generator.Restrict(Range{Start: 10, End: 12})
+	if pos := restricted.IDToPosition(string(restricted.AppendID(nil))); pos != 10 && pos != 11 {
+		t.Errorf("expected a position in the restricted range, got %d", pos)
+	}
+	if got := generator.Restrict(Range{}).AppendID([]byte("x")); string(got) != "x" {
+		t.Errorf("expected an empty range to append nothing, got %q", got)
+	}
+
+	// Appending into a buffer with enough capacity does not allocate
+	if allocs := testing.AllocsPerRun(100, func() { buf = generator.AppendPositionID(buf[:0], 123456) }); allocs != 0 {
+		t.Errorf("expected AppendPositionID not to allocate, got %v allocations", allocs)
+	}
+	if allocs := testing.AllocsPerRun(100, func() { buf = generator.AppendID(buf[:0]) }); allocs != 0 {
+		t.Errorf("expected AppendID not to allocate, got %v allocations", allocs)
+	}
+}
+
+func BenchmarkPositionToID(b *testing.B) {
+	generator := NewWithDefaults()
+	b.ReportAllocs()
+	for i := 0; b.Loop(); i++ {
+		_ = generator.PositionToID(int64(i))
+	}
+}
+
+func BenchmarkAppendPositionID(b *testing.B) {
+	generator := NewWithDefaults()
+	buf := make([]byte, 0, 64)
+	b.ReportAllocs()
+	for i := 0; b.Loop(); i++ {
+		buf = generator.AppendPositionID(buf[:0], int64(i))
+	}
+}
+
+func BenchmarkNewID(b *testing.B) {
+	generator := NewWithDefaults()
+	b.ReportAllocs()
+	for b.Loop() {
+		_ = generator.NewID()
+	}
+}
+
+func BenchmarkAppendID(b *testing.B) {
+	generator := NewWithDefaults()
+	buf := make([]byte, 0, 64)
+	b.ReportAllocs()
+	for b.Loop() {
+		buf = generator.AppendID(buf[:0])
+	}
+}
+
+func BenchmarkBatchGenerateIDs(b *testing.B) {
+	generator := NewWithDefaults()
+	b.ReportAllocs()
+	for b.Loop() {
+		_ = generator.BatchGenerateIDs(10000, 0)
+	}
+}
+
+func BenchmarkBatchGenerateRandomIDs(b *testing.B) {
+	generator := NewWithDefaults()
+	b.ReportAllocs()
+	for b.Loop() {
+		_ = generator.BatchGenerateRandomIDs(10000)
+	}
+}
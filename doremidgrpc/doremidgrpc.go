@@ -0,0 +1,141 @@
+// Package doremidgrpc serves a generator over gRPC, so services in any language
+// mint and decode IDs consistently. The service is defined in
+// doremidv1/doremid.proto; clients are generated from it with protoc.
+//
+// Errors map to gRPC status codes: invalid IDs and counts to InvalidArgument,
+// positions outside the range to OutOfRange and an exhausted space to
+// ResourceExhausted.
+//
+// It lives in its own module so that the core doremid package stays free of
+// third-party dependencies.
+package doremidgrpc
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative doremidv1/doremid.proto
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/doremi-id/doremid"
+	"github.com/doremi-id/doremid/doremidgrpc/doremidv1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultMaxBatch is used when Server.MaxBatch is zero
+const DefaultMaxBatch = 1000
+
+// Server implements doremidv1.DoremidServiceServer for one generator. Fields
+// must not be changed after the server is registered. It is safe for concurrent
+// use.
+type Server struct {
+	doremidv1.UnimplementedDoremidServiceServer
+
+	// Generator mints and decodes the IDs. Any restriction applies to minting.
+	Generator *doremid.Generator
+
+	// MaxBatch bounds the count of GenerateBatch
+	MaxBatch int64
+}
+
+// NewServer creates a server for g
+func NewServer(g *doremid.Generator) *Server {
+	return &Server{Generator: g}
+}
+
+// Register registers s with registrar, e.g. a *grpc.Server
+func (s *Server) Register(registrar grpc.ServiceRegistrar) {
+	doremidv1.RegisterDoremidServiceServer(registrar, s)
+}
+
+// GenerateID returns a random ID
+func (s *Server) GenerateID(context.Context, *doremidv1.GenerateIDRequest) (*doremidv1.GenerateIDResponse, error) {
+	id := s.Generator.NewID()
+	if id == "" {
+		return nil, statusError(doremid.ErrSpaceExhausted)
+	}
+	return &doremidv1.GenerateIDResponse{Record: s.record(id)}, nil
+}
+
+// GenerateBatch returns unique random IDs, or sequential IDs from start
+func (s *Server) GenerateBatch(_ context.Context, req *doremidv1.GenerateBatchRequest) (*doremidv1.GenerateBatchResponse, error) {
+	if req.Count <= 0 || req.Count > s.maxBatch() {
+		return nil, statusError(fmt.Errorf("%w: count must be between 1 and %d", doremid.ErrInvalidCount, s.maxBatch()))
+	}
+
+	var ids []string
+	if req.Start != nil {
+		mintRange := s.Generator.MintRange()
+		if !mintRange.Contains(req.GetStart()) {
+			return nil, statusError(&doremid.RangeError{Position: req.GetStart(), Min: mintRange.Start, Max: mintRange.End})
+		}
+		ids = s.Generator.BatchGenerateIDs(req.Count, req.GetStart())
+	} else {
+		ids = s.Generator.BatchGenerateRandomIDs(req.Count)
+		if len(ids) == 0 {
+			return nil, statusError(doremid.ErrSpaceExhausted)
+		}
+	}
+
+	resp := &doremidv1.GenerateBatchResponse{Records: make([]*doremidv1.Record, len(ids))}
+	for i, id := range ids {
+		resp.Records[i] = s.record(id)
+	}
+	return resp, nil
+}
+
+// Decode returns the position and parts of a valid ID
+func (s *Server) Decode(_ context.Context, req *doremidv1.DecodeRequest) (*doremidv1.DecodeResponse, error) {
+	parsed, err := s.Generator.Parse(req.Id)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &doremidv1.DecodeResponse{
+		Position: parsed.Position,
+		Prefix:   parsed.Prefix,
+		Just:     parsed.Just,
+		Equal:    parsed.Equal,
+		Check:    parsed.Check,
+	}, nil
+}
+
+// Validate reports whether an ID is valid and, if not, why
+func (s *Server) Validate(_ context.Context, req *doremidv1.ValidateRequest) (*doremidv1.ValidateResponse, error) {
+	err := s.Generator.Validate(req.Id)
+	if err == nil {
+		return &doremidv1.ValidateResponse{Valid: true, Offset: -1}, nil
+	}
+	var formatErr *doremid.FormatError
+	if !errors.As(err, &formatErr) {
+		return nil, statusError(err)
+	}
+	return &doremidv1.ValidateResponse{Reason: formatErr.Reason, Offset: int32(formatErr.Offset)}, nil
+}
+
+// record returns the Record of id
+func (s *Server) record(id string) *doremidv1.Record {
+	return &doremidv1.Record{Id: id, Position: s.Generator.IDToPosition(id)}
+}
+
+func (s *Server) maxBatch() int64 {
+	if s.MaxBatch <= 0 {
+		return DefaultMaxBatch
+	}
+	return s.MaxBatch
+}
+
+// statusError converts a doremid error to a gRPC status
+func statusError(err error) error {
+	code := codes.Internal
+	switch {
+	case errors.Is(err, doremid.ErrInvalidID), errors.Is(err, doremid.ErrInvalidCount):
+		code = codes.InvalidArgument
+	case errors.Is(err, doremid.ErrOutOfRange):
+		code = codes.OutOfRange
+	case errors.Is(err, doremid.ErrSpaceExhausted):
+		code = codes.ResourceExhausted
+	}
+	return status.Error(code, err.Error())
+}
@@ -0,0 +1,135 @@
+package doremidgrpc
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/doremi-id/doremid"
+	"github.com/doremi-id/doremid/doremidgrpc/doremidv1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+)
+
+// newTestClient serves s over an in-memory connection
+func newTestClient(t *testing.T, s *Server) doremidv1.DoremidServiceClient {
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	s.Register(server)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return doremidv1.NewDoremidServiceClient(conn)
+}
+
+func newTestGenerator() *doremid.Generator {
+	return doremid.New(doremid.Config{JustIntonationDigits: 2, EqualTemperamentDigits: 3, Separator: "-", Checksum: true})
+}
+
+func TestGenerate(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t, NewServer(newTestGenerator()))
+
+	resp, err := client.GenerateID(ctx, &doremidv1.GenerateIDRequest{})
+	if err != nil || newTestGenerator().IDToPosition(resp.Record.Id) != resp.Record.Position {
+		t.Errorf("expected a new ID, got %v (err: %v)", resp, err)
+	}
+
+	batch, err := client.GenerateBatch(ctx, &doremidv1.GenerateBatchRequest{Count: 2, Start: proto.Int64(3722)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(batch.Records) != 2 {
+		t.Fatalf("expected 2 records, got %v", batch.Records)
+	}
+	for i, record := range batch.Records {
+		expected := newTestGenerator().PositionToID(3722 + int64(i))
+		if record.Position != 3722+int64(i) || record.Id != expected {
+			t.Errorf("expected %s at %d, got %v", expected, 3722+i, record)
+		}
+	}
+
+	random, err := client.GenerateBatch(ctx, &doremidv1.GenerateBatchRequest{Count: 100})
+	seen := map[string]bool{}
+	for _, record := range random.GetRecords() {
+		seen[record.Id] = true
+	}
+	if err != nil || len(seen) != 100 {
+		t.Errorf("expected 100 unique IDs, got %d (err: %v)", len(seen), err)
+	}
+}
+
+func TestDecodeAndValidate(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t, NewServer(newTestGenerator()))
+
+	decoded, err := client.Decode(ctx, &doremidv1.DecodeRequest{Id: "domi-1a26"})
+	if err != nil || decoded.Position != 3722 || decoded.Just != "domi" || decoded.Equal != "1a2" || decoded.Check != "6" {
+		t.Errorf("expected domi-1a26 at 3722, got %v (err: %v)", decoded, err)
+	}
+
+	tests := []struct {
+		id       string
+		expected *doremidv1.ValidateResponse
+	}{
+		{"domi-1a26", &doremidv1.ValidateResponse{Valid: true, Offset: -1}},
+		{"domi-1a27", &doremidv1.ValidateResponse{Reason: "check character mismatch", Offset: 8}},
+		{"doxx-1a26", &doremidv1.ValidateResponse{Reason: "unknown note", Offset: 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			resp, err := client.Validate(ctx, &doremidv1.ValidateRequest{Id: tt.id})
+			if err != nil || resp.Valid != tt.expected.Valid || resp.Offset != tt.expected.Offset || (!resp.Valid && resp.Reason == "") {
+				t.Errorf("expected %v, got %v (err: %v)", tt.expected, resp, err)
+			}
+		})
+	}
+}
+
+func TestStatusCodes(t *testing.T) {
+	ctx := context.Background()
+	server := NewServer(newTestGenerator().Restrict(doremid.Range{Start: 0, End: 10}))
+	server.MaxBatch = 50
+	client := newTestClient(t, server)
+
+	tests := []struct {
+		name     string
+		call     func() error
+		expected codes.Code
+	}{
+		{"invalid ID", func() error {
+			_, err := client.Decode(ctx, &doremidv1.DecodeRequest{Id: "domi-1a27"})
+			return err
+		}, codes.InvalidArgument},
+		{"count above limit", func() error {
+			_, err := client.GenerateBatch(ctx, &doremidv1.GenerateBatchRequest{Count: 51})
+			return err
+		}, codes.InvalidArgument},
+		{"start outside range", func() error {
+			_, err := client.GenerateBatch(ctx, &doremidv1.GenerateBatchRequest{Count: 1, Start: proto.Int64(10)})
+			return err
+		}, codes.OutOfRange},
+		{"exhausted", func() error {
+			_, err := client.GenerateBatch(ctx, &doremidv1.GenerateBatchRequest{Count: 11})
+			return err
+		}, codes.ResourceExhausted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code := status.Code(tt.call()); code != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, code)
+			}
+		})
+	}
+}
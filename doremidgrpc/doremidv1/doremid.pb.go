@@ -0,0 +1,589 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v5.29.3
+// source: doremidv1/doremid.proto
+
+package doremidv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Record is an ID with its position.
+type Record struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Position      int64                  `protobuf:"varint,2,opt,name=position,proto3" json:"position,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Record) Reset() {
+	*x = Record{}
+	mi := &file_doremidv1_doremid_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Record) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Record) ProtoMessage() {}
+
+func (x *Record) ProtoReflect() protoreflect.Message {
+	mi := &file_doremidv1_doremid_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Record.ProtoReflect.Descriptor instead.
+func (*Record) Descriptor() ([]byte, []int) {
+	return file_doremidv1_doremid_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Record) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Record) GetPosition() int64 {
+	if x != nil {
+		return x.Position
+	}
+	return 0
+}
+
+type GenerateIDRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GenerateIDRequest) Reset() {
+	*x = GenerateIDRequest{}
+	mi := &file_doremidv1_doremid_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GenerateIDRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenerateIDRequest) ProtoMessage() {}
+
+func (x *GenerateIDRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_doremidv1_doremid_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenerateIDRequest.ProtoReflect.Descriptor instead.
+func (*GenerateIDRequest) Descriptor() ([]byte, []int) {
+	return file_doremidv1_doremid_proto_rawDescGZIP(), []int{1}
+}
+
+type GenerateIDResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Record        *Record                `protobuf:"bytes,1,opt,name=record,proto3" json:"record,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GenerateIDResponse) Reset() {
+	*x = GenerateIDResponse{}
+	mi := &file_doremidv1_doremid_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GenerateIDResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenerateIDResponse) ProtoMessage() {}
+
+func (x *GenerateIDResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_doremidv1_doremid_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenerateIDResponse.ProtoReflect.Descriptor instead.
+func (*GenerateIDResponse) Descriptor() ([]byte, []int) {
+	return file_doremidv1_doremid_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GenerateIDResponse) GetRecord() *Record {
+	if x != nil {
+		return x.Record
+	}
+	return nil
+}
+
+type GenerateBatchRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Number of IDs, at most the server's batch limit.
+	Count int64 `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	// First position of sequential IDs; unset for random IDs.
+	Start         *int64 `protobuf:"varint,2,opt,name=start,proto3,oneof" json:"start,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GenerateBatchRequest) Reset() {
+	*x = GenerateBatchRequest{}
+	mi := &file_doremidv1_doremid_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GenerateBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenerateBatchRequest) ProtoMessage() {}
+
+func (x *GenerateBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_doremidv1_doremid_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenerateBatchRequest.ProtoReflect.Descriptor instead.
+func (*GenerateBatchRequest) Descriptor() ([]byte, []int) {
+	return file_doremidv1_doremid_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GenerateBatchRequest) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *GenerateBatchRequest) GetStart() int64 {
+	if x != nil && x.Start != nil {
+		return *x.Start
+	}
+	return 0
+}
+
+type GenerateBatchResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Records       []*Record              `protobuf:"bytes,1,rep,name=records,proto3" json:"records,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GenerateBatchResponse) Reset() {
+	*x = GenerateBatchResponse{}
+	mi := &file_doremidv1_doremid_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GenerateBatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenerateBatchResponse) ProtoMessage() {}
+
+func (x *GenerateBatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_doremidv1_doremid_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenerateBatchResponse.ProtoReflect.Descriptor instead.
+func (*GenerateBatchResponse) Descriptor() ([]byte, []int) {
+	return file_doremidv1_doremid_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GenerateBatchResponse) GetRecords() []*Record {
+	if x != nil {
+		return x.Records
+	}
+	return nil
+}
+
+type DecodeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DecodeRequest) Reset() {
+	*x = DecodeRequest{}
+	mi := &file_doremidv1_doremid_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DecodeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DecodeRequest) ProtoMessage() {}
+
+func (x *DecodeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_doremidv1_doremid_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DecodeRequest.ProtoReflect.Descriptor instead.
+func (*DecodeRequest) Descriptor() ([]byte, []int) {
+	return file_doremidv1_doremid_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *DecodeRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type DecodeResponse struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Position int64                  `protobuf:"varint,1,opt,name=position,proto3" json:"position,omitempty"`
+	// Namespace prefix without its separator, empty if none is configured.
+	Prefix string `protobuf:"bytes,2,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	// Musical note part, e.g. "domi".
+	Just string `protobuf:"bytes,3,opt,name=just,proto3" json:"just,omitempty"`
+	// Character part, e.g. "1a2".
+	Equal string `protobuf:"bytes,4,opt,name=equal,proto3" json:"equal,omitempty"`
+	// Check character, empty unless checksums are configured.
+	Check         string `protobuf:"bytes,5,opt,name=check,proto3" json:"check,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DecodeResponse) Reset() {
+	*x = DecodeResponse{}
+	mi := &file_doremidv1_doremid_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DecodeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DecodeResponse) ProtoMessage() {}
+
+func (x *DecodeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_doremidv1_doremid_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DecodeResponse.ProtoReflect.Descriptor instead.
+func (*DecodeResponse) Descriptor() ([]byte, []int) {
+	return file_doremidv1_doremid_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *DecodeResponse) GetPosition() int64 {
+	if x != nil {
+		return x.Position
+	}
+	return 0
+}
+
+func (x *DecodeResponse) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+func (x *DecodeResponse) GetJust() string {
+	if x != nil {
+		return x.Just
+	}
+	return ""
+}
+
+func (x *DecodeResponse) GetEqual() string {
+	if x != nil {
+		return x.Equal
+	}
+	return ""
+}
+
+func (x *DecodeResponse) GetCheck() string {
+	if x != nil {
+		return x.Check
+	}
+	return ""
+}
+
+type ValidateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateRequest) Reset() {
+	*x = ValidateRequest{}
+	mi := &file_doremidv1_doremid_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateRequest) ProtoMessage() {}
+
+func (x *ValidateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_doremidv1_doremid_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateRequest.ProtoReflect.Descriptor instead.
+func (*ValidateRequest) Descriptor() ([]byte, []int) {
+	return file_doremidv1_doremid_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ValidateRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ValidateResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Valid bool                   `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`
+	// Human readable description of the problem, empty if valid.
+	Reason string `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	// Byte offset of the problem, -1 if it concerns the whole input or the ID is valid.
+	Offset        int32 `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateResponse) Reset() {
+	*x = ValidateResponse{}
+	mi := &file_doremidv1_doremid_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateResponse) ProtoMessage() {}
+
+func (x *ValidateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_doremidv1_doremid_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateResponse.ProtoReflect.Descriptor instead.
+func (*ValidateResponse) Descriptor() ([]byte, []int) {
+	return file_doremidv1_doremid_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ValidateResponse) GetValid() bool {
+	if x != nil {
+		return x.Valid
+	}
+	return false
+}
+
+func (x *ValidateResponse) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *ValidateResponse) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+var File_doremidv1_doremid_proto protoreflect.FileDescriptor
+
+const file_doremidv1_doremid_proto_rawDesc = "" +
+	"\n" +
+	"\x17doremidv1/doremid.proto\x12\n" +
+	"doremid.v1\"4\n" +
+	"\x06Record\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1a\n" +
+	"\bposition\x18\x02 \x01(\x03R\bposition\"\x13\n" +
+	"\x11GenerateIDRequest\"@\n" +
+	"\x12GenerateIDResponse\x12*\n" +
+	"\x06record\x18\x01 \x01(\v2\x12.doremid.v1.RecordR\x06record\"Q\n" +
+	"\x14GenerateBatchRequest\x12\x14\n" +
+	"\x05count\x18\x01 \x01(\x03R\x05count\x12\x19\n" +
+	"\x05start\x18\x02 \x01(\x03H\x00R\x05start\x88\x01\x01B\b\n" +
+	"\x06_start\"E\n" +
+	"\x15GenerateBatchResponse\x12,\n" +
+	"\arecords\x18\x01 \x03(\v2\x12.doremid.v1.RecordR\arecords\"\x1f\n" +
+	"\rDecodeRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x84\x01\n" +
+	"\x0eDecodeResponse\x12\x1a\n" +
+	"\bposition\x18\x01 \x01(\x03R\bposition\x12\x16\n" +
+	"\x06prefix\x18\x02 \x01(\tR\x06prefix\x12\x12\n" +
+	"\x04just\x18\x03 \x01(\tR\x04just\x12\x14\n" +
+	"\x05equal\x18\x04 \x01(\tR\x05equal\x12\x14\n" +
+	"\x05check\x18\x05 \x01(\tR\x05check\"!\n" +
+	"\x0fValidateRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"X\n" +
+	"\x10ValidateResponse\x12\x14\n" +
+	"\x05valid\x18\x01 \x01(\bR\x05valid\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\x12\x16\n" +
+	"\x06offset\x18\x03 \x01(\x05R\x06offset2\xbb\x02\n" +
+	"\x0eDoremidService\x12K\n" +
+	"\n" +
+	"GenerateID\x12\x1d.doremid.v1.GenerateIDRequest\x1a\x1e.doremid.v1.GenerateIDResponse\x12T\n" +
+	"\rGenerateBatch\x12 .doremid.v1.GenerateBatchRequest\x1a!.doremid.v1.GenerateBatchResponse\x12?\n" +
+	"\x06Decode\x12\x19.doremid.v1.DecodeRequest\x1a\x1a.doremid.v1.DecodeResponse\x12E\n" +
+	"\bValidate\x12\x1b.doremid.v1.ValidateRequest\x1a\x1c.doremid.v1.ValidateResponseB>Z<github.com/doremi-id/doremid/doremidgrpc/doremidv1;doremidv1b\x06proto3"
+
+var (
+	file_doremidv1_doremid_proto_rawDescOnce sync.Once
+	file_doremidv1_doremid_proto_rawDescData []byte
+)
+
+func file_doremidv1_doremid_proto_rawDescGZIP() []byte {
+	file_doremidv1_doremid_proto_rawDescOnce.Do(func() {
+		file_doremidv1_doremid_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_doremidv1_doremid_proto_rawDesc), len(file_doremidv1_doremid_proto_rawDesc)))
+	})
+	return file_doremidv1_doremid_proto_rawDescData
+}
+
+var file_doremidv1_doremid_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_doremidv1_doremid_proto_goTypes = []any{
+	(*Record)(nil),                // 0: doremid.v1.Record
+	(*GenerateIDRequest)(nil),     // 1: doremid.v1.GenerateIDRequest
+	(*GenerateIDResponse)(nil),    // 2: doremid.v1.GenerateIDResponse
+	(*GenerateBatchRequest)(nil),  // 3: doremid.v1.GenerateBatchRequest
+	(*GenerateBatchResponse)(nil), // 4: doremid.v1.GenerateBatchResponse
+	(*DecodeRequest)(nil),         // 5: doremid.v1.DecodeRequest
+	(*DecodeResponse)(nil),        // 6: doremid.v1.DecodeResponse
+	(*ValidateRequest)(nil),       // 7: doremid.v1.ValidateRequest
+	(*ValidateResponse)(nil),      // 8: doremid.v1.ValidateResponse
+}
+var file_doremidv1_doremid_proto_depIdxs = []int32{
+	0, // 0: doremid.v1.GenerateIDResponse.record:type_name -> doremid.v1.Record
+	0, // 1: doremid.v1.GenerateBatchResponse.records:type_name -> doremid.v1.Record
+	1, // 2: doremid.v1.DoremidService.GenerateID:input_type -> doremid.v1.GenerateIDRequest
+	3, // 3: doremid.v1.DoremidService.GenerateBatch:input_type -> doremid.v1.GenerateBatchRequest
+	5, // 4: doremid.v1.DoremidService.Decode:input_type -> doremid.v1.DecodeRequest
+	7, // 5: doremid.v1.DoremidService.Validate:input_type -> doremid.v1.ValidateRequest
+	2, // 6: doremid.v1.DoremidService.GenerateID:output_type -> doremid.v1.GenerateIDResponse
+	4, // 7: doremid.v1.DoremidService.GenerateBatch:output_type -> doremid.v1.GenerateBatchResponse
+	6, // 8: doremid.v1.DoremidService.Decode:output_type -> doremid.v1.DecodeResponse
+	8, // 9: doremid.v1.DoremidService.Validate:output_type -> doremid.v1.ValidateResponse
+	6, // [6:10] is the sub-list for method output_type
+	2, // [2:6] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_doremidv1_doremid_proto_init() }
+func file_doremidv1_doremid_proto_init() {
+	if File_doremidv1_doremid_proto != nil {
+		return
+	}
+	file_doremidv1_doremid_proto_msgTypes[3].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_doremidv1_doremid_proto_rawDesc), len(file_doremidv1_doremid_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   9,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_doremidv1_doremid_proto_goTypes,
+		DependencyIndexes: file_doremidv1_doremid_proto_depIdxs,
+		MessageInfos:      file_doremidv1_doremid_proto_msgTypes,
+	}.Build()
+	File_doremidv1_doremid_proto = out.File
+	file_doremidv1_doremid_proto_goTypes = nil
+	file_doremidv1_doremid_proto_depIdxs = nil
+}
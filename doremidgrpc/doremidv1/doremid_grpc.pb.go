@@ -0,0 +1,247 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: doremidv1/doremid.proto
+
+package doremidv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	DoremidService_GenerateID_FullMethodName    = "/doremid.v1.DoremidService/GenerateID"
+	DoremidService_GenerateBatch_FullMethodName = "/doremid.v1.DoremidService/GenerateBatch"
+	DoremidService_Decode_FullMethodName        = "/doremid.v1.DoremidService/Decode"
+	DoremidService_Validate_FullMethodName      = "/doremid.v1.DoremidService/Validate"
+)
+
+// DoremidServiceClient is the client API for DoremidService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// DoremidService mints and decodes the IDs of one generator configuration.
+type DoremidServiceClient interface {
+	// GenerateID returns a random ID.
+	GenerateID(ctx context.Context, in *GenerateIDRequest, opts ...grpc.CallOption) (*GenerateIDResponse, error)
+	// GenerateBatch returns unique random IDs, or sequential IDs from start.
+	GenerateBatch(ctx context.Context, in *GenerateBatchRequest, opts ...grpc.CallOption) (*GenerateBatchResponse, error)
+	// Decode returns the position and parts of a valid ID.
+	Decode(ctx context.Context, in *DecodeRequest, opts ...grpc.CallOption) (*DecodeResponse, error)
+	// Validate reports whether an ID is valid and, if not, why.
+	Validate(ctx context.Context, in *ValidateRequest, opts ...grpc.CallOption) (*ValidateResponse, error)
+}
+
+type doremidServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewDoremidServiceClient(cc grpc.ClientConnInterface) DoremidServiceClient {
+	return &doremidServiceClient{cc}
+}
+
+func (c *doremidServiceClient) GenerateID(ctx context.Context, in *GenerateIDRequest, opts ...grpc.CallOption) (*GenerateIDResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GenerateIDResponse)
+	err := c.cc.Invoke(ctx, DoremidService_GenerateID_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *doremidServiceClient) GenerateBatch(ctx context.Context, in *GenerateBatchRequest, opts ...grpc.CallOption) (*GenerateBatchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GenerateBatchResponse)
+	err := c.cc.Invoke(ctx, DoremidService_GenerateBatch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *doremidServiceClient) Decode(ctx context.Context, in *DecodeRequest, opts ...grpc.CallOption) (*DecodeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DecodeResponse)
+	err := c.cc.Invoke(ctx, DoremidService_Decode_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *doremidServiceClient) Validate(ctx context.Context, in *ValidateRequest, opts ...grpc.CallOption) (*ValidateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ValidateResponse)
+	err := c.cc.Invoke(ctx, DoremidService_Validate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DoremidServiceServer is the server API for DoremidService service.
+// All implementations must embed UnimplementedDoremidServiceServer
+// for forward compatibility.
+//
+// DoremidService mints and decodes the IDs of one generator configuration.
+type DoremidServiceServer interface {
+	// GenerateID returns a random ID.
+	GenerateID(context.Context, *GenerateIDRequest) (*GenerateIDResponse, error)
+	// GenerateBatch returns unique random IDs, or sequential IDs from start.
+	GenerateBatch(context.Context, *GenerateBatchRequest) (*GenerateBatchResponse, error)
+	// Decode returns the position and parts of a valid ID.
+	Decode(context.Context, *DecodeRequest) (*DecodeResponse, error)
+	// Validate reports whether an ID is valid and, if not, why.
+	Validate(context.Context, *ValidateRequest) (*ValidateResponse, error)
+	mustEmbedUnimplementedDoremidServiceServer()
+}
+
+// UnimplementedDoremidServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedDoremidServiceServer struct{}
+
+func (UnimplementedDoremidServiceServer) GenerateID(context.Context, *GenerateIDRequest) (*GenerateIDResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GenerateID not implemented")
+}
+func (UnimplementedDoremidServiceServer) GenerateBatch(context.Context, *GenerateBatchRequest) (*GenerateBatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GenerateBatch not implemented")
+}
+func (UnimplementedDoremidServiceServer) Decode(context.Context, *DecodeRequest) (*DecodeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Decode not implemented")
+}
+func (UnimplementedDoremidServiceServer) Validate(context.Context, *ValidateRequest) (*ValidateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Validate not implemented")
+}
+func (UnimplementedDoremidServiceServer) mustEmbedUnimplementedDoremidServiceServer() {}
+func (UnimplementedDoremidServiceServer) testEmbeddedByValue()                        {}
+
+// UnsafeDoremidServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to DoremidServiceServer will
+// result in compilation errors.
+type UnsafeDoremidServiceServer interface {
+	mustEmbedUnimplementedDoremidServiceServer()
+}
+
+func RegisterDoremidServiceServer(s grpc.ServiceRegistrar, srv DoremidServiceServer) {
+	// If the following call pancis, it indicates UnimplementedDoremidServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&DoremidService_ServiceDesc, srv)
+}
+
+func _DoremidService_GenerateID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GenerateIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DoremidServiceServer).GenerateID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DoremidService_GenerateID_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DoremidServiceServer).GenerateID(ctx, req.(*GenerateIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DoremidService_GenerateBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GenerateBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DoremidServiceServer).GenerateBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DoremidService_GenerateBatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DoremidServiceServer).GenerateBatch(ctx, req.(*GenerateBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DoremidService_Decode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DecodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DoremidServiceServer).Decode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DoremidService_Decode_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DoremidServiceServer).Decode(ctx, req.(*DecodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DoremidService_Validate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ValidateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DoremidServiceServer).Validate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DoremidService_Validate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DoremidServiceServer).Validate(ctx, req.(*ValidateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// DoremidService_ServiceDesc is the grpc.ServiceDesc for DoremidService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var DoremidService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "doremid.v1.DoremidService",
+	HandlerType: (*DoremidServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GenerateID",
+			Handler:    _DoremidService_GenerateID_Handler,
+		},
+		{
+			MethodName: "GenerateBatch",
+			Handler:    _DoremidService_GenerateBatch_Handler,
+		},
+		{
+			MethodName: "Decode",
+			Handler:    _DoremidService_Decode_Handler,
+		},
+		{
+			MethodName: "Validate",
+			Handler:    _DoremidService_Validate_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "doremidv1/doremid.proto",
+}
@@ -0,0 +1,108 @@
+package doremidhttp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// StatusError is returned by Client when the service answers with an error
+type StatusError struct {
+	Code    int    // HTTP status code
+	Message string // Error from the response body
+}
+
+// Error implements the error interface
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("doremidhttp: %d %s: %s", e.Code, http.StatusText(e.Code), e.Message)
+}
+
+// Client calls the endpoints of a Handler. It is safe for concurrent use.
+type Client struct {
+	// BaseURL is where the handler is mounted, e.g. "http://ids.internal/v1"
+	BaseURL string
+
+	// HTTPClient sends the requests. Nil uses http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+// NewClient creates a client for the handler mounted at baseURL
+func NewClient(baseURL string) *Client {
+	return &Client{BaseURL: strings.TrimSuffix(baseURL, "/")}
+}
+
+// NewID mints a random ID
+func (c *Client) NewID(ctx context.Context) (Record, error) {
+	var record Record
+	err := c.do(ctx, http.MethodPost, "/ids", nil, &record)
+	return record, err
+}
+
+// Batch mints a batch of IDs, see BatchRequest
+func (c *Client) Batch(ctx context.Context, req BatchRequest) ([]Record, error) {
+	var resp BatchResponse
+	if err := c.do(ctx, http.MethodPost, "/ids/batch", req, &resp); err != nil {
+		return nil, err
+	}
+	return resp.IDs, nil
+}
+
+// Decode returns the position of id
+func (c *Client) Decode(ctx context.Context, id string) (Record, error) {
+	var record Record
+	err := c.do(ctx, http.MethodGet, "/ids/"+url.PathEscape(id), nil, &record)
+	return record, err
+}
+
+// Encode returns the ID at position
+func (c *Client) Encode(ctx context.Context, position int64) (Record, error) {
+	var record Record
+	err := c.do(ctx, http.MethodGet, "/positions/"+strconv.FormatInt(position, 10), nil, &record)
+	return record, err
+}
+
+// do sends body as JSON, if not nil, and decodes the response into v.
+// Returns a *StatusError if the service answers with an error status.
+func (c *Client) do(ctx context.Context, method, path string, body, v any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.BaseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		var errResp ErrorResponse
+		if json.NewDecoder(resp.Body).Decode(&errResp) != nil || errResp.Error == "" {
+			errResp.Error = http.StatusText(resp.StatusCode)
+		}
+		return &StatusError{Code: resp.StatusCode, Message: errResp.Error}
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
@@ -0,0 +1,60 @@
+package doremidhttp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient(t *testing.T) {
+	server := httptest.NewServer(http.StripPrefix("/v1", New(newTestGenerator())))
+	defer server.Close()
+	client := NewClient(server.URL + "/v1/")
+	ctx := context.Background()
+
+	record, err := client.NewID(ctx)
+	if err != nil || newTestGenerator().IDToPosition(record.ID) != record.Position {
+		t.Errorf("expected a new ID, got %+v (err: %v)", record, err)
+	}
+
+	start := int64(5)
+	records, err := client.Batch(ctx, BatchRequest{Count: 3, Start: &start})
+	if err != nil || len(records) != 3 || records[0].ID != "dodo-005" || records[2].Position != 7 {
+		t.Errorf("expected 3 IDs from dodo-005, got %+v (err: %v)", records, err)
+	}
+
+	if record, err := client.Decode(ctx, "domi-1a2"); err != nil || record.Position != 3722 {
+		t.Errorf("expected position 3722, got %+v (err: %v)", record, err)
+	}
+	if record, err := client.Encode(ctx, 3722); err != nil || record.ID != "domi-1a2" {
+		t.Errorf("expected 'domi-1a2', got %+v (err: %v)", record, err)
+	}
+}
+
+func TestClientErrors(t *testing.T) {
+	server := httptest.NewServer(New(newTestGenerator()))
+	defer server.Close()
+	client := NewClient(server.URL)
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		call     func() error
+		expected int
+	}{
+		{"invalid ID", func() error { _, err := client.Decode(ctx, "invalid"); return err }, http.StatusBadRequest},
+		{"position out of range", func() error { _, err := client.Encode(ctx, 84672); return err }, http.StatusBadRequest},
+		{"count too large", func() error { _, err := client.Batch(ctx, BatchRequest{Count: 1001}); return err }, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var statusErr *StatusError
+			if err := tt.call(); !errors.As(err, &statusErr) || statusErr.Code != tt.expected || statusErr.Message == "" {
+				t.Errorf("expected StatusError %d, got %v", tt.expected, err)
+			}
+		})
+	}
+}
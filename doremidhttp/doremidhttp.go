@@ -0,0 +1,230 @@
+// Package doremidhttp serves a generator over HTTP, for running a central ID
+// service. All endpoints speak JSON:
+//
+//	POST /ids                    {"id": "...", "position": 3722}
+//	POST /ids/batch              {"count": 10, "start": 5} -> {"ids": [{"id": ..., "position": ...}, ...]}
+//	GET  /ids/{id}               {"id": "...", "position": 3722}
+//	GET  /positions/{position}   {"id": "...", "position": 3722}
+//
+// A batch without "start" holds unique random IDs; with it, sequential IDs from
+// that position. Failed requests get a 4xx or 5xx status and a body such as
+// {"error": "..."}. A Limiter can reject requests before any work is done.
+// Client calls the endpoints from Go.
+package doremidhttp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/doremi-id/doremid"
+)
+
+// Defaults used when the corresponding Handler field is zero
+const (
+	DefaultMaxBatch     = 1000
+	DefaultMaxBodyBytes = 1 << 10
+)
+
+// Limiter decides whether a request may proceed, e.g. with a per-client token
+// bucket. cost is the number of IDs the request mints or decodes.
+type Limiter interface {
+	Allow(r *http.Request, cost int64) bool
+}
+
+// LimiterFunc adapts a function to a Limiter
+type LimiterFunc func(r *http.Request, cost int64) bool
+
+// Allow calls f(r, cost)
+func (f LimiterFunc) Allow(r *http.Request, cost int64) bool {
+	return f(r, cost)
+}
+
+// Record is an ID with its position
+type Record struct {
+	ID       string `json:"id"`
+	Position int64  `json:"position"`
+}
+
+// BatchRequest is the body of POST /ids/batch
+type BatchRequest struct {
+	Count int64  `json:"count"`
+	Start *int64 `json:"start,omitempty"` // Nil for random IDs
+}
+
+// BatchResponse is the response to POST /ids/batch
+type BatchResponse struct {
+	IDs []Record `json:"ids"`
+}
+
+// ErrorResponse is the body of a failed request
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// Handler serves the endpoints of one generator. Fields must not be changed
+// after the first request. It is safe for concurrent use.
+type Handler struct {
+	// Generator mints and decodes the IDs. Any restriction applies to minting.
+	Generator *doremid.Generator
+
+	// MaxBatch bounds the count of a batch request
+	MaxBatch int64
+
+	// MaxBodyBytes bounds the size of request bodies
+	MaxBodyBytes int64
+
+	// Limiter is consulted before every request; rejected requests get 429 Too
+	// Many Requests. Nil allows every request.
+	Limiter Limiter
+
+	once sync.Once
+	mux  *http.ServeMux
+}
+
+// New creates a handler serving g
+func New(g *doremid.Generator) *Handler {
+	return &Handler{Generator: g}
+}
+
+// ServeHTTP implements http.Handler
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.once.Do(func() {
+		h.mux = http.NewServeMux()
+		h.mux.HandleFunc("POST /ids", h.newID)
+		h.mux.HandleFunc("POST /ids/batch", h.batch)
+		h.mux.HandleFunc("GET /ids/{id}", h.decode)
+		h.mux.HandleFunc("GET /positions/{position}", h.encode)
+	})
+	h.mux.ServeHTTP(w, r)
+}
+
+// newID serves POST /ids
+func (h *Handler) newID(w http.ResponseWriter, r *http.Request) {
+	if !h.allow(w, r, 1) {
+		return
+	}
+	id := h.Generator.NewID()
+	if id == "" {
+		writeError(w, doremid.ErrSpaceExhausted)
+		return
+	}
+	writeJSON(w, http.StatusOK, Record{ID: id, Position: h.Generator.IDToPosition(id)})
+}
+
+// batch serves POST /ids/batch
+func (h *Handler) batch(w http.ResponseWriter, r *http.Request) {
+	var req BatchRequest
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, h.maxBodyBytes()))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "invalid request body: " + err.Error()})
+		return
+	}
+	if req.Count <= 0 || req.Count > h.maxBatch() {
+		writeError(w, fmt.Errorf("%w: count must be between 1 and %d", doremid.ErrInvalidCount, h.maxBatch()))
+		return
+	}
+	if !h.allow(w, r, req.Count) {
+		return
+	}
+
+	var ids []string
+	if req.Start != nil {
+		mintRange := h.Generator.MintRange()
+		if !mintRange.Contains(*req.Start) {
+			writeError(w, &doremid.RangeError{Position: *req.Start, Min: mintRange.Start, Max: mintRange.End})
+			return
+		}
+		ids = h.Generator.BatchGenerateIDs(req.Count, *req.Start)
+	} else {
+		ids = h.Generator.BatchGenerateRandomIDs(req.Count)
+		if len(ids) == 0 {
+			writeError(w, doremid.ErrSpaceExhausted)
+			return
+		}
+	}
+
+	resp := BatchResponse{IDs: make([]Record, len(ids))}
+	for i, id := range ids {
+		resp.IDs[i] = Record{ID: id, Position: h.Generator.IDToPosition(id)}
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// decode serves GET /ids/{id}
+func (h *Handler) decode(w http.ResponseWriter, r *http.Request) {
+	if !h.allow(w, r, 1) {
+		return
+	}
+	id := r.PathValue("id")
+	pos, err := h.Generator.IDToPositionE(id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, Record{ID: id, Position: pos})
+}
+
+// encode serves GET /positions/{position}
+func (h *Handler) encode(w http.ResponseWriter, r *http.Request) {
+	if !h.allow(w, r, 1) {
+		return
+	}
+	pos, err := strconv.ParseInt(r.PathValue("position"), 10, 64)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: fmt.Sprintf("invalid position %q", r.PathValue("position"))})
+		return
+	}
+	id, err := h.Generator.PositionToIDE(pos)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, Record{ID: id, Position: pos})
+}
+
+// allow consults the Limiter, answering 429 if it rejects the request
+func (h *Handler) allow(w http.ResponseWriter, r *http.Request, cost int64) bool {
+	if h.Limiter == nil || h.Limiter.Allow(r, cost) {
+		return true
+	}
+	writeJSON(w, http.StatusTooManyRequests, ErrorResponse{Error: "rate limit exceeded"})
+	return false
+}
+
+func (h *Handler) maxBatch() int64 {
+	if h.MaxBatch <= 0 {
+		return DefaultMaxBatch
+	}
+	return h.MaxBatch
+}
+
+func (h *Handler) maxBodyBytes() int64 {
+	if h.MaxBodyBytes <= 0 {
+		return DefaultMaxBodyBytes
+	}
+	return h.MaxBodyBytes
+}
+
+// writeError answers with the status matching a doremid error
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, doremid.ErrInvalidID), errors.Is(err, doremid.ErrOutOfRange), errors.Is(err, doremid.ErrInvalidCount):
+		status = http.StatusBadRequest
+	case errors.Is(err, doremid.ErrSpaceExhausted):
+		status = http.StatusConflict
+	}
+	writeJSON(w, status, ErrorResponse{Error: err.Error()})
+}
+
+// writeJSON answers with status and v as the body
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,152 @@
+package doremidhttp
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/doremi-id/doremid"
+)
+
+func newTestGenerator() *doremid.Generator {
+	return doremid.New(doremid.Config{JustIntonationDigits: 2, EqualTemperamentDigits: 3, Separator: "-"})
+}
+
+// serve sends a request to h and decodes the JSON response into v
+func serve(t *testing.T, h http.Handler, method, path, body string, v any) int {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(method, path, strings.NewReader(body)))
+	if rec.Code < 300 && rec.Header().Get("Content-Type") != "application/json" {
+		t.Errorf("expected JSON response, got %q", rec.Header().Get("Content-Type"))
+	}
+	if v != nil {
+		if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+			t.Fatalf("expected JSON body, got %v: %s", err, rec.Body.String())
+		}
+	}
+	return rec.Code
+}
+
+func TestEndpoints(t *testing.T) {
+	h := New(newTestGenerator())
+
+	var record Record
+	if code := serve(t, h, "POST", "/ids", "", &record); code != http.StatusOK || newTestGenerator().IDToPosition(record.ID) != record.Position {
+		t.Errorf("expected a new ID, got %d %+v", code, record)
+	}
+
+	tests := []struct {
+		path     string
+		expected Record
+	}{
+		{"/ids/domi-1a2", Record{ID: "domi-1a2", Position: 3722}},
+		{"/positions/3722", Record{ID: "domi-1a2", Position: 3722}},
+		{"/positions/0", Record{ID: "dodo-000", Position: 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			var record Record
+			if code := serve(t, h, "GET", tt.path, "", &record); code != http.StatusOK || record != tt.expected {
+				t.Errorf("expected %+v, got %d %+v", tt.expected, code, record)
+			}
+		})
+	}
+}
+
+func TestBatch(t *testing.T) {
+	h := New(newTestGenerator())
+
+	var sequential BatchResponse
+	if code := serve(t, h, "POST", "/ids/batch", `{"count": 3, "start": 5}`, &sequential); code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", code)
+	}
+	expected := []Record{{"dodo-005", 5}, {"dodo-006", 6}, {"dodo-007", 7}}
+	if len(sequential.IDs) != 3 {
+		t.Fatalf("expected %v, got %v", expected, sequential.IDs)
+	}
+	for i := range expected {
+		if sequential.IDs[i] != expected[i] {
+			t.Errorf("expected %v, got %v", expected[i], sequential.IDs[i])
+		}
+	}
+
+	var random BatchResponse
+	if code := serve(t, h, "POST", "/ids/batch", `{"count": 50}`, &random); code != http.StatusOK || len(random.IDs) != 50 {
+		t.Fatalf("expected 50 IDs, got %d %v", code, random.IDs)
+	}
+	seen := map[string]bool{}
+	for _, r := range random.IDs {
+		seen[r.ID] = true
+	}
+	if len(seen) != 50 {
+		t.Errorf("expected unique IDs, got %d distinct", len(seen))
+	}
+}
+
+func TestErrors(t *testing.T) {
+	h := New(newTestGenerator().Restrict(doremid.Range{Start: 0, End: 10}))
+	h.MaxBatch = 100
+
+	tests := []struct {
+		method   string
+		path     string
+		body     string
+		expected int
+	}{
+		{"GET", "/ids/domi-1a", "", http.StatusBadRequest},
+		{"GET", "/positions/-1", "", http.StatusBadRequest},
+		{"GET", "/positions/x", "", http.StatusBadRequest},
+		{"POST", "/ids/batch", `{"count": 0}`, http.StatusBadRequest},
+		{"POST", "/ids/batch", `{"count": 101}`, http.StatusBadRequest},
+		{"POST", "/ids/batch", `{"count": 1, "start": 10}`, http.StatusBadRequest},
+		{"POST", "/ids/batch", `{"count": 11}`, http.StatusConflict},
+		{"POST", "/ids/batch", `{"count": 1, "extra": true}`, http.StatusBadRequest},
+		{"POST", "/ids/batch", `{"count": ` + strings.Repeat("1", 2000) + `}`, http.StatusBadRequest},
+		{"DELETE", "/ids/domi-1a2", "", http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path+" "+tt.body, func(t *testing.T) {
+			// The mux answers unrouted requests in plain text
+			var resp ErrorResponse
+			var target any = &resp
+			if tt.expected == http.StatusMethodNotAllowed {
+				target = nil
+			}
+			code := serve(t, h, tt.method, tt.path, tt.body, target)
+			if code != tt.expected {
+				t.Errorf("expected %d, got %d", tt.expected, code)
+			}
+			if target != nil && resp.Error == "" {
+				t.Error("expected an error message")
+			}
+		})
+	}
+}
+
+func TestLimiter(t *testing.T) {
+	var costs []int64
+	h := New(newTestGenerator())
+	h.Limiter = LimiterFunc(func(r *http.Request, cost int64) bool {
+		costs = append(costs, cost)
+		return r.Header.Get("X-Client") != "blocked"
+	})
+
+	if code := serve(t, h, "POST", "/ids/batch", `{"count": 7}`, nil); code != http.StatusOK {
+		t.Errorf("expected 200, got %d", code)
+	}
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/ids/domi-1a2", nil)
+	req.Header.Set("X-Client", "blocked")
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusTooManyRequests {
+		t.Errorf("expected 429, got %d", rec.Code)
+	}
+
+	if len(costs) != 2 || costs[0] != 7 || costs[1] != 1 {
+		t.Errorf("expected costs [7 1], got %v", costs)
+	}
+}
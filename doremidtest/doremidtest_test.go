@@ -0,0 +1,61 @@
+package doremidtest
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/doremi-id/doremid"
+)
+
+func TestFakeClock(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+
+	if got := clock.Now(); !got.Equal(start) {
+		t.Errorf("expected %v, got %v", start, got)
+	}
+	if got := clock.Advance(time.Minute); !got.Equal(start.Add(time.Minute)) || !clock.Now().Equal(got) {
+		t.Errorf("expected %v after advancing, got %v", start.Add(time.Minute), got)
+	}
+	clock.Set(start)
+	if got := clock.Now(); !got.Equal(start) {
+		t.Errorf("expected %v after setting, got %v", start, got)
+	}
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			clock.Advance(time.Second)
+		}()
+	}
+	wg.Wait()
+	if got := clock.Now(); !got.Equal(start.Add(10 * time.Second)) {
+		t.Errorf("expected %v after concurrent advances, got %v", start.Add(10*time.Second), got)
+	}
+}
+
+func TestFakeClockDrivesGenerator(t *testing.T) {
+	epoch := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(epoch.Add(time.Hour))
+	generator := doremid.New(doremid.Config{JustIntonationDigits: 3, EqualTemperamentDigits: 6})
+	to, err := generator.TimeOrdered(doremid.TimeOrderedConfig{Epoch: epoch, Resolution: time.Second, Lifetime: 24 * time.Hour, Clock: clock})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id, err := to.NewID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ts, err := to.ExtractTimestamp(id); err != nil || !ts.Equal(clock.Now()) {
+		t.Errorf("expected timestamp %v, got %v (err: %v)", clock.Now(), ts, err)
+	}
+
+	clock.Set(epoch.Add(-time.Second))
+	if _, err := to.NewID(); err == nil {
+		t.Error("expected an error before the epoch")
+	}
+}
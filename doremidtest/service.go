@@ -0,0 +1,49 @@
+package doremidtest
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/doremi-id/doremid/doremidhttp"
+)
+
+// StartHTTP serves handler on a loopback address until the test ends and
+// returns its base URL, e.g. to run a service built on doremidhttp in-process
+func StartHTTP(tb testing.TB, handler http.Handler) string {
+	tb.Helper()
+	server := httptest.NewServer(handler)
+	tb.Cleanup(server.Close)
+	return server.URL
+}
+
+// StartClient is like StartHTTP but returns a client for the doremidhttp
+// handler mounted at path, e.g. "/v1"
+func StartClient(tb testing.TB, handler http.Handler, path string) *doremidhttp.Client {
+	tb.Helper()
+	return doremidhttp.NewClient(StartHTTP(tb, handler) + path)
+}
+
+// Start runs serve on a loopback listener until the test ends, then calls
+// stop, and returns the listener's address. It suits servers that are not
+// HTTP handlers, e.g. Start(t, server.Serve, server.Stop) for a grpc.Server.
+// The test fails if serve returns an error other than the listener closing.
+func Start(tb testing.TB, serve func(net.Listener) error, stop func()) string {
+	tb.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		tb.Fatalf("doremidtest: listen: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- serve(listener) }()
+	tb.Cleanup(func() {
+		stop()
+		if err := <-done; err != nil && !errors.Is(err, net.ErrClosed) && !errors.Is(err, http.ErrServerClosed) {
+			tb.Errorf("doremidtest: serve: %v", err)
+		}
+	})
+	return listener.Addr().String()
+}
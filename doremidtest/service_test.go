@@ -0,0 +1,33 @@
+package doremidtest
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/doremi-id/doremid"
+	"github.com/doremi-id/doremid/doremidhttp"
+)
+
+func newTestGenerator() *doremid.Generator {
+	return doremid.New(doremid.Config{JustIntonationDigits: 2, EqualTemperamentDigits: 3, Separator: "-"})
+}
+
+func TestStartClient(t *testing.T) {
+	client := StartClient(t, http.StripPrefix("/v1", doremidhttp.New(newTestGenerator())), "/v1")
+
+	record, err := client.Encode(context.Background(), 3722)
+	if err != nil || record.ID != "domi-1a2" {
+		t.Errorf("expected 'domi-1a2', got %+v (err: %v)", record, err)
+	}
+}
+
+func TestStart(t *testing.T) {
+	server := &http.Server{Handler: doremidhttp.New(newTestGenerator())}
+	addr := Start(t, server.Serve, func() { server.Close() })
+
+	record, err := doremidhttp.NewClient("http://"+addr).Decode(context.Background(), "domi-1a2")
+	if err != nil || record.Position != 3722 {
+		t.Errorf("expected position 3722, got %+v (err: %v)", record, err)
+	}
+}
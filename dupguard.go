@@ -0,0 +1,164 @@
+package doremid
+
+import (
+	"context"
+	"sync"
+)
+
+// Defaults used when the corresponding DupGuardConfig field is zero
+const (
+	DefaultDupGuardFalsePositiveRate = 0.001
+	DefaultDupGuardMaxAttempts       = 100
+	DefaultDupGuardReconcileEvery    = 1000
+)
+
+// DupGuardConfig configures a DupGuard
+type DupGuardConfig struct {
+	// Filter continues from an existing filter, e.g. one restored with
+	// UnmarshalBinary from a Snapshot taken before a restart. Nil creates a new
+	// filter sized for Expected IDs at FalsePositiveRate.
+	Filter *BloomFilter
+
+	// Expected is the number of IDs the guard is sized for. Required if Filter is nil.
+	Expected int64
+
+	// FalsePositiveRate is roughly the fraction of fresh IDs rejected as
+	// possible duplicates. Zero uses DefaultDupGuardFalsePositiveRate.
+	FalsePositiveRate float64
+
+	// MaxAttempts bounds the candidates tried per ID.
+	// Zero uses DefaultDupGuardMaxAttempts.
+	MaxAttempts int
+
+	// Reconcile receives the IDs issued since the previous call and a snapshot of
+	// the filter including them, every ReconcileEvery IDs and on Flush, e.g. to
+	// check the IDs against an authoritative store and persist the snapshot. It
+	// is called with the guard locked and must not call its methods.
+	// Nil disables reconciliation.
+	Reconcile func(ctx context.Context, ids []string, snapshot []byte) error
+
+	// ReconcileEvery is the number of IDs issued between reconciliations.
+	// Zero uses DefaultDupGuardReconcileEvery.
+	ReconcileEvery int
+}
+
+// DupGuard issues random IDs that are never repeated while its bloom filter
+// remembers them, rejecting any candidate the filter may have seen. Rarely a
+// fresh ID is rejected as a false positive, but an issued ID is never reissued.
+// Restoring the filter from a Snapshot extends the guarantee across restarts.
+// It is safe for concurrent use.
+type DupGuard struct {
+	g      *Generator
+	config DupGuardConfig
+
+	mu         sync.Mutex
+	filter     *BloomFilter
+	pending    []string
+	rejections int64
+}
+
+// NewDupGuard creates a duplicate guard for random IDs of g.
+// Returns a *ConfigError if neither Filter nor a positive Expected is set.
+func (g *Generator) NewDupGuard(config DupGuardConfig) (*DupGuard, error) {
+	if config.FalsePositiveRate == 0 {
+		config.FalsePositiveRate = DefaultDupGuardFalsePositiveRate
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = DefaultDupGuardMaxAttempts
+	}
+	if config.ReconcileEvery <= 0 {
+		config.ReconcileEvery = DefaultDupGuardReconcileEvery
+	}
+
+	filter := config.Filter
+	if filter == nil {
+		if config.Expected <= 0 {
+			return nil, &ConfigError{Field: "Expected", Reason: "must be positive when Filter is nil"}
+		}
+		var err error
+		if filter, err = g.NewBloomFilter(config.Expected, config.FalsePositiveRate); err != nil {
+			return nil, err
+		}
+	}
+
+	return &DupGuard{g: g, config: config, filter: filter}, nil
+}
+
+// NewID returns a random ID the filter has not seen and records it.
+// Reconcile is called before returning once ReconcileEvery IDs are pending; if it
+// fails, its error is returned and the ID is withheld but stays recorded.
+// Returns ErrSpaceExhausted if MaxAttempts candidates were all rejected or the
+// generator has no IDs left to draw.
+func (d *DupGuard) NewID(ctx context.Context) (string, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for attempt := 0; attempt < d.config.MaxAttempts; attempt++ {
+		id := d.g.NewID()
+		if id == "" {
+			return "", ErrSpaceExhausted
+		}
+		seen, err := d.filter.Contains(id)
+		if err != nil {
+			return "", err
+		}
+		if seen {
+			d.rejections++
+			continue
+		}
+		if err := d.filter.Add(id); err != nil {
+			return "", err
+		}
+
+		d.pending = append(d.pending, id)
+		if len(d.pending) >= d.config.ReconcileEvery {
+			if err := d.reconcile(ctx); err != nil {
+				return "", err
+			}
+		}
+		return id, nil
+	}
+	return "", ErrSpaceExhausted
+}
+
+// Flush reconciles the IDs issued since the last reconciliation, e.g. before shutdown
+func (d *DupGuard) Flush(ctx context.Context) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.reconcile(ctx)
+}
+
+// Snapshot encodes the filter, to be restored with BloomFilter.UnmarshalBinary
+// and passed as DupGuardConfig.Filter after a restart
+func (d *DupGuard) Snapshot() ([]byte, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.filter.MarshalBinary()
+}
+
+// Rejections returns the number of candidates rejected as possible duplicates
+func (d *DupGuard) Rejections() int64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.rejections
+}
+
+// reconcile passes pending IDs to the callback, keeping them pending if it fails
+func (d *DupGuard) reconcile(ctx context.Context) error {
+	if d.config.Reconcile == nil {
+		d.pending = d.pending[:0]
+		return nil
+	}
+	if len(d.pending) == 0 {
+		return nil
+	}
+	snapshot, err := d.filter.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	if err := d.config.Reconcile(ctx, d.pending, snapshot); err != nil {
+		return err
+	}
+	d.pending = nil
+	return nil
+}
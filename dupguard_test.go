@@ -0,0 +1,134 @@
+package doremid
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestDupGuard(t *testing.T) {
+	ctx := context.Background()
+	generator := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 2,
+		Separator:              "-",
+	})
+
+	var reconciled []string
+	var snapshot []byte
+	guard, err := generator.NewDupGuard(DupGuardConfig{
+		Expected:          1000,
+		FalsePositiveRate: 0.01,
+		ReconcileEvery:    100,
+		Reconcile: func(_ context.Context, ids []string, s []byte) error {
+			reconciled = append(reconciled, ids...)
+			snapshot = s
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issued := make(map[string]bool)
+	for i := 0; i < 1000; i++ {
+		id, err := guard.NewID(ctx)
+		if err != nil {
+			t.Fatalf("unexpected error after %d IDs: %v", i, err)
+		}
+		if issued[id] {
+			t.Fatalf("duplicate ID '%s'", id)
+		}
+		issued[id] = true
+	}
+	if len(reconciled) != 1000 {
+		t.Errorf("expected 1000 reconciled IDs, got %d", len(reconciled))
+	}
+
+	// A restarted guard restores the filter and keeps avoiding issued IDs
+	filter, _ := generator.NewBloomFilter(1, 0.5)
+	if err := filter.UnmarshalBinary(snapshot); err != nil {
+		t.Fatal(err)
+	}
+	restarted, _ := generator.NewDupGuard(DupGuardConfig{Filter: filter})
+	for i := 0; i < 500; i++ {
+		id, err := restarted.NewID(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if issued[id] {
+			t.Fatalf("ID '%s' reissued after restart", id)
+		}
+		issued[id] = true
+	}
+	if restarted.Rejections() == 0 {
+		t.Error("expected candidates to be rejected after restart")
+	}
+}
+
+func TestDupGuardErrors(t *testing.T) {
+	ctx := context.Background()
+	generator := New(Config{
+		JustIntonationDigits:   1,
+		EqualTemperamentDigits: 1,
+		Separator:              "-",
+	})
+
+	if _, err := generator.NewDupGuard(DupGuardConfig{}); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig, got %v", err)
+	}
+
+	failure := errors.New("store unavailable")
+	calls := 0
+	guard, _ := generator.NewDupGuard(DupGuardConfig{
+		Expected:       84,
+		ReconcileEvery: 2,
+		Reconcile: func(_ context.Context, ids []string, _ []byte) error {
+			calls++
+			if calls == 1 {
+				return failure
+			}
+			return nil
+		},
+	})
+
+	guard.NewID(ctx)
+	if _, err := guard.NewID(ctx); !errors.Is(err, failure) {
+		t.Errorf("expected reconcile error, got %v", err)
+	}
+	if err := guard.Flush(ctx); err != nil || calls != 2 {
+		t.Errorf("expected pending IDs to be retried on Flush, got %v after %d calls", err, calls)
+	}
+
+	// The exact filter covers all 84 IDs; once they are issued the guard gives up
+	issued := 2 // Recorded above, including the one withheld by the failed reconcile
+	for i := 0; i < 1000; i++ {
+		if _, err := guard.NewID(ctx); err != nil {
+			continue
+		}
+		issued++
+	}
+	if issued != 84 {
+		t.Errorf("expected exactly 84 IDs to be issued, got %d", issued)
+	}
+	if _, err := guard.NewID(ctx); !errors.Is(err, ErrSpaceExhausted) {
+		t.Errorf("expected ErrSpaceExhausted, got %v", err)
+	}
+}
+
+func TestDupGuardExhaustedGenerator(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   1,
+		EqualTemperamentDigits: 1,
+		Separator:              "-",
+		ReservedPositions:      []int64{0, 1, 2},
+	}).Restrict(Range{Start: 0, End: 3})
+
+	guard, _ := generator.NewDupGuard(DupGuardConfig{Expected: 84})
+	if id, err := guard.NewID(context.Background()); id != "" || !errors.Is(err, ErrSpaceExhausted) {
+		t.Errorf("expected ErrSpaceExhausted, got '%s' and %v", id, err)
+	}
+	if guard.rejections != 0 || len(guard.pending) != 0 {
+		t.Errorf("expected nothing recorded, got %d rejections and %d pending", guard.rejections, len(guard.pending))
+	}
+}
@@ -0,0 +1,164 @@
+package doremid
+
+import (
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
+
+// Sentinel errors. Error-returning APIs return these directly or wrap them in one
+// of the structured error types below, so callers can branch with errors.Is and
+// inspect details with errors.As.
+var (
+	// ErrInvalidID is matched by every FormatError
+	ErrInvalidID = errors.New("doremid: invalid ID")
+
+	// ErrInvalidFormat is matched by FormatErrors about the structure of the input,
+	// such as its length or separator
+	ErrInvalidFormat = errors.New("doremid: malformed ID")
+
+	// ErrBadCharacter is matched by FormatErrors about an unknown note or character
+	ErrBadCharacter = errors.New("doremid: unknown symbol in ID")
+
+	// ErrChecksum is matched by FormatErrors about a check character that does
+	// not match the rest of the ID
+	ErrChecksum = errors.New("doremid: checksum mismatch")
+
+	// ErrVersionMismatch is matched by FormatErrors about an ID marked with
+	// another Config.Version
+	ErrVersionMismatch = errors.New("doremid: ID version mismatch")
+
+	// ErrReserved is returned when asked to mint a reserved position
+	ErrReserved = errors.New("doremid: position is reserved")
+
+	// ErrFiltered is returned when asked to mint a position whose notes spell a
+	// word banned by Config.WordFilter
+	ErrFiltered = errors.New("doremid: notes spell a filtered word")
+
+	// ErrOutOfRange is matched by every RangeError
+	ErrOutOfRange = errors.New("doremid: position out of range")
+
+	// ErrInvalidConfig is matched by every ConfigError
+	ErrInvalidConfig = errors.New("doremid: invalid configuration")
+
+	// ErrInvalidCount is returned when a non-positive count is requested
+	ErrInvalidCount = errors.New("doremid: count must be positive")
+
+	// ErrSpaceExhausted is returned when no positions remain to be issued
+	ErrSpaceExhausted = errors.New("doremid: ID space exhausted")
+
+	// ErrPoolClosed is returned when taking an ID from a closed, drained IDPool
+	ErrPoolClosed = errors.New("doremid: ID pool closed")
+
+	// ErrInvalidTransition is matched by every TransitionError
+	ErrInvalidTransition = errors.New("doremid: invalid lifecycle transition")
+
+	// ErrInvalidCode is returned when a one-time code is wrong or has expired
+	ErrInvalidCode = errors.New("doremid: invalid or expired code")
+
+	// ErrCodeReused is returned when a one-time code has already been verified
+	ErrCodeReused = errors.New("doremid: code already used")
+
+	// ErrNotRecorded is returned when an ID is missing from a ledger
+	ErrNotRecorded = errors.New("doremid: ID not recorded in ledger")
+
+	// ErrNotOrdered is returned when asked to prove an order the ledger contradicts
+	ErrNotOrdered = errors.New("doremid: IDs not issued in the stated order")
+
+	// ErrInvalidProof is returned when an order proof fails verification
+	ErrInvalidProof = errors.New("doremid: invalid order proof")
+)
+
+// FormatError reports why an input could not be parsed as an ID
+type FormatError struct {
+	Input  string // The rejected input
+	Offset int    // Byte offset of the problem, -1 if it concerns the whole input
+	Symbol string // Offending symbol, empty if not applicable
+	Reason string // Human readable description
+	Err    error  // Cause, ErrInvalidFormat, ErrBadCharacter, ErrChecksum or ErrVersionMismatch
+}
+
+// Error implements the error interface
+func (e *FormatError) Error() string {
+	if e.Offset < 0 {
+		return fmt.Sprintf("doremid: invalid ID %q: %s", e.Input, e.Reason)
+	}
+	if e.Symbol != "" {
+		return fmt.Sprintf("doremid: invalid ID %q: %s %q at offset %d", e.Input, e.Reason, e.Symbol, e.Offset)
+	}
+	return fmt.Sprintf("doremid: invalid ID %q: %s at offset %d", e.Input, e.Reason, e.Offset)
+}
+
+// Is reports whether target is ErrInvalidID
+func (e *FormatError) Is(target error) bool {
+	return target == ErrInvalidID
+}
+
+// Unwrap returns the cause, so errors.Is also matches ErrInvalidFormat,
+// ErrBadCharacter, ErrChecksum or ErrVersionMismatch
+func (e *FormatError) Unwrap() error {
+	return e.Err
+}
+
+// RangeError reports a position outside the allowed range [Min, Max)
+type RangeError struct {
+	Position int64
+	Min      int64
+	Max      int64
+}
+
+// Error implements the error interface
+func (e *RangeError) Error() string {
+	return fmt.Sprintf("doremid: position %d out of range [%d, %d)", e.Position, e.Min, e.Max)
+}
+
+// Is reports whether target is ErrOutOfRange
+func (e *RangeError) Is(target error) bool {
+	return target == ErrOutOfRange
+}
+
+// ConfigError reports an invalid configuration field
+type ConfigError struct {
+	Field  string // Name of the Config field, e.g. "JustIntonationDigits"
+	Reason string // Human readable description
+}
+
+// Error implements the error interface
+func (e *ConfigError) Error() string {
+	return fmt.Sprintf("doremid: invalid configuration: %s %s", e.Field, e.Reason)
+}
+
+// Is reports whether target is ErrInvalidConfig
+func (e *ConfigError) Is(target error) bool {
+	return target == ErrInvalidConfig
+}
+
+// truncate shortens s to at most n bytes so oversized inputs are not copied into
+// errors, without splitting a multi-byte rune. A negative n keeps s whole.
+func truncate(s string, n int) string {
+	if n < 0 || len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+// symbolAt returns the n bytes of s from offset for the Symbol of a FormatError,
+// fewer if s ends first, and more if needed to end on a whole rune
+func symbolAt(s string, offset, n int) string {
+	end := min(offset+n, len(s))
+	for end < len(s) && !utf8.RuneStart(s[end]) {
+		end++
+	}
+	return s[offset:end]
+}
+
+// checkRange returns a RangeError if position lies outside r
+func checkRange(position int64, r Range) error {
+	if !r.Contains(position) {
+		return &RangeError{Position: position, Min: r.Start, Max: r.End}
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+package doremid
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFormatError(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 2,
+		Separator:              "-",
+	})
+
+	tests := []struct {
+		name   string
+		id     string
+		offset int
+		symbol string
+		cause  error
+	}{
+		{"wrong length", "dore-0", -1, "", ErrInvalidFormat},
+		{"wrong separator", "dore_01", 4, "_", ErrInvalidFormat},
+		{"unknown note", "doxx-01", 2, "xx", ErrBadCharacter},
+		{"unknown character", "dore-0z", 6, "z", ErrBadCharacter},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := generator.decode(tt.id)
+			if !errors.Is(err, ErrInvalidID) {
+				t.Fatalf("expected ErrInvalidID, got %v", err)
+			}
+			if !errors.Is(err, tt.cause) {
+				t.Errorf("expected %v, got %v", tt.cause, err)
+			}
+
+			var formatErr *FormatError
+			if !errors.As(err, &formatErr) {
+				t.Fatalf("expected *FormatError, got %T", err)
+			}
+			if formatErr.Offset != tt.offset || formatErr.Symbol != tt.symbol || formatErr.Input != tt.id {
+				t.Errorf("expected offset %d and symbol %q, got %+v", tt.offset, tt.symbol, formatErr)
+			}
+		})
+	}
+}
+
+func TestFormatErrorMultiByte(t *testing.T) {
+	separator := New(Config{JustIntonationDigits: 2, EqualTemperamentDigits: 3, Separator: "·"})
+	japanese := New(Config{JustIntonationDigits: 2, EqualTemperamentDigits: 3, Separator: "・", Notes: "ド レ ミ ファ ソ ラ シ"})
+
+	tests := []struct {
+		name      string
+		generator *Generator
+		id        string
+		offset    int
+		symbol    string
+		reason    string
+	}{
+		{"ASCII separator", separator, "domi.1a2", 4, ".1", "unexpected separator"},
+		{"multi-byte character", separator, "domi·1á2", 7, "á", "unknown character"},
+		{"too short", separator, "domi·1a", -1, "", "length must be 8, got 7"},
+		{"too long", separator, "domi·1a23", -1, "", "length must be 8, got 9"},
+		{"foreign note", separator, "doмі·1a2", 2, "м", "unknown note"},
+		{"wrong separator", japanese, "ドミ·1a2", 6, "·1", "unexpected separator"},
+		{"unknown note", japanese, "ドx・1a2", 3, "x・1a", "unknown note"},
+		{"too long for these notes", japanese, "ドミ・1a2b", -1, "", "length must be 6 for these notes, got 7"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.generator.decode(tt.id)
+			var formatErr *FormatError
+			if !errors.As(err, &formatErr) {
+				t.Fatalf("expected *FormatError, got %v", err)
+			}
+			if formatErr.Offset != tt.offset || formatErr.Symbol != tt.symbol || formatErr.Reason != tt.reason {
+				t.Errorf("expected offset %d, symbol %q and reason %q, got %+v", tt.offset, tt.symbol, tt.reason, formatErr)
+			}
+		})
+	}
+}
+
+func TestTruncateAndSymbolAt(t *testing.T) {
+	tests := []struct {
+		s                string
+		n                int
+		truncated, after string
+	}{
+		{"domi-1a2", 4, "domi", "domi"},
+		{"do·mi", 3, "do", "do·"},
+		{"do·mi", 4, "do·", "do·"},
+		{"ドミ", 1, "", "ド"},
+		{"ドミ", -1, "ドミ", ""},
+		{"do", 8, "do", "do"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			if truncated := truncate(tt.s, tt.n); truncated != tt.truncated {
+				t.Errorf("expected truncate to give %q, got %q", tt.truncated, truncated)
+			}
+			if tt.n >= 0 {
+				if symbol := symbolAt(tt.s, 0, tt.n); symbol != tt.after {
+					t.Errorf("expected symbolAt to give %q, got %q", tt.after, symbol)
+				}
+			}
+		})
+	}
+}
+
+func TestErrorTaxonomy(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   1,
+		EqualTemperamentDigits: 1,
+		Separator:              "-",
+	})
+
+	t.Run("range errors", func(t *testing.T) {
+		_, err := generator.Restrict(Range{Start: 10, End: 20}).Mint(25)
+
+		var rangeErr *RangeError
+		if !errors.As(err, &rangeErr) || !errors.Is(err, ErrOutOfRange) {
+			t.Fatalf("expected *RangeError, got %v", err)
+		}
+		if rangeErr.Position != 25 || rangeErr.Min != 10 || rangeErr.Max != 20 {
+			t.Errorf("unexpected range error %+v", rangeErr)
+		}
+
+		if _, err := generator.FromKey(generator.PositionToKey(84)); !errors.Is(err, ErrOutOfRange) {
+			t.Errorf("expected ErrOutOfRange from FromKey, got %v", err)
+		}
+	})
+
+	t.Run("format errors are wrapped with context", func(t *testing.T) {
+		composite := Compose(ComponentSpec{Name: "a", Generator: generator}, ComponentSpec{Name: "b", Generator: generator, Separator: "."})
+		_, err := composite.Parse("do-0.dx-0")
+
+		var formatErr *FormatError
+		if !errors.As(err, &formatErr) || formatErr.Symbol != "dx" {
+			t.Errorf("expected wrapped *FormatError for 'dx', got %v", err)
+		}
+
+		dir := t.TempDir()
+		in := filepath.Join(dir, "in.txt")
+		os.WriteFile(in, []byte("do-0\ndo-z\n"), 0o644)
+		err = generator.DedupeLargeFile(in, filepath.Join(dir, "out.txt"), dir)
+		if !errors.Is(err, ErrInvalidID) || !strings.Contains(err.Error(), "line 2") {
+			t.Errorf("expected wrapped ErrInvalidID mentioning line 2, got %v", err)
+		}
+	})
+
+	t.Run("config errors", func(t *testing.T) {
+		err := error(&ConfigError{Field: "Separator", Reason: "must not be empty"})
+
+		var configErr *ConfigError
+		if !errors.Is(err, ErrInvalidConfig) || !errors.As(err, &configErr) || configErr.Field != "Separator" {
+			t.Errorf("unexpected config error behaviour for %v", err)
+		}
+		if errors.Is(err, ErrInvalidID) {
+			t.Error("config error must not match ErrInvalidID")
+		}
+	})
+}
+
+func TestIDToPositionEmptySeparator(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 2,
+		Separator:              "",
+	})
+
+	for _, pos := range []int64{0, 1, 500, generator.MaxCombinations() - 1} {
+		id := generator.PositionToID(pos)
+		if back := generator.IDToPosition(id); back != pos {
+			t.Errorf("round-trip of %d via '%s' returned %d", pos, id, back)
+		}
+	}
+}
@@ -38,8 +38,7 @@ func main() {
 	fmt.Printf("Position %d to ID: %s\n", position, convertedID)
 
 	// Check maximum combinations
-	maxCombs := generator.MaxCombinations()
-	fmt.Printf("Max combinations: %s\n", formatNumber(int(maxCombs)))
+	fmt.Printf("Max combinations: %s\n", generator.FormatCombinations("en"))
 
 	// Use default configuration
 	defaultGen := doremid.NewWithDefaults()
@@ -60,22 +59,3 @@ func main() {
 	limitedIDs := smallGen.BatchGenerateIDs(int64(10), int64(80)) // Automatically limited
 	fmt.Printf("Limited batch generation: %d IDs\n", len(limitedIDs))
 }
-
-// formatNumber formats large numbers with thousand separators
-func formatNumber(n int) string {
-	if n < 1000 {
-		return fmt.Sprintf("%d", n)
-	}
-
-	str := fmt.Sprintf("%d", n)
-	result := ""
-
-	for i, char := range str {
-		if i > 0 && (len(str)-i)%3 == 0 {
-			result += ","
-		}
-		result += string(char)
-	}
-
-	return result
-}
@@ -0,0 +1,22 @@
+module github.com/doremi-id/doremid/examples/service
+
+go 1.24
+
+require (
+	github.com/doremi-id/doremid v0.0.0
+	github.com/doremi-id/doremid/doremidgrpc v0.0.0
+	google.golang.org/grpc v1.71.0
+	google.golang.org/protobuf v1.36.6
+)
+
+require (
+	golang.org/x/net v0.34.0 // indirect
+	golang.org/x/sys v0.29.0 // indirect
+	golang.org/x/text v0.21.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20250115164207-1a7da9e5054f // indirect
+)
+
+replace (
+	github.com/doremi-id/doremid => ../..
+	github.com/doremi-id/doremid/doremidgrpc => ../../doremidgrpc
+)
@@ -0,0 +1,169 @@
+// Command service is a production-shaped ID service: random IDs are registered
+// so they are never issued twice, batches are allocated sequentially from a
+// separate part of the ID space, and every request is counted for scraping.
+// It serves the doremidhttp API and the doremid.v1.DoremidService side by side,
+// and doubles as a command line client for the HTTP API.
+//
+// Usage:
+//
+//	service serve [-http :8080] [-grpc :9090] [-just 4] [-equal 5] [-checksum]
+//	service client [-url http://localhost:8080/v1] new
+//	service client [-url http://localhost:8080/v1] batch <count>
+//	service client [-url http://localhost:8080/v1] decode <id>
+//	service client [-url http://localhost:8080/v1] encode <position>
+//
+// The in-memory registry and allocator lose their state on restart; swap in
+// redisstore or badgerstore when deploying.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+
+	"github.com/doremi-id/doremid"
+	"github.com/doremi-id/doremid/doremidhttp"
+	"google.golang.org/grpc"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := run(ctx, os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "service:", err)
+		os.Exit(1)
+	}
+}
+
+// run executes the command in args, writing its output to w
+func run(ctx context.Context, args []string, w io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: service serve|client [flags]")
+	}
+	switch args[0] {
+	case "serve":
+		return serve(ctx, args[1:], w)
+	case "client":
+		return client(ctx, args[1:], w)
+	default:
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+}
+
+// serve runs the service until ctx is done
+func serve(ctx context.Context, args []string, w io.Writer) error {
+	flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	httpAddr := flags.String("http", ":8080", "HTTP listen address")
+	grpcAddr := flags.String("grpc", ":9090", "gRPC listen address")
+	config := Config{Generator: doremid.DefaultConfig()}
+	flags.IntVar(&config.Generator.JustIntonationDigits, "just", config.Generator.JustIntonationDigits, "number of musical note pairs")
+	flags.IntVar(&config.Generator.EqualTemperamentDigits, "equal", config.Generator.EqualTemperamentDigits, "number of twelve-tone characters")
+	flags.BoolVar(&config.Generator.Checksum, "checksum", false, "append a check character")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	s, err := NewService(config)
+	if err != nil {
+		return err
+	}
+	httpListener, err := net.Listen("tcp", *httpAddr)
+	if err != nil {
+		return err
+	}
+	grpcListener, err := net.Listen("tcp", *grpcAddr)
+	if err != nil {
+		httpListener.Close()
+		return err
+	}
+
+	httpServer := &http.Server{Handler: s}
+	grpcServer := grpc.NewServer(grpc.UnaryInterceptor(s.UnaryInterceptor))
+	s.RegisterGRPC(grpcServer)
+
+	errs := make(chan error, 2)
+	go func() { errs <- httpServer.Serve(httpListener) }()
+	go func() { errs <- grpcServer.Serve(grpcListener) }()
+	fmt.Fprintf(w, "serving HTTP on %s and gRPC on %s\n", httpListener.Addr(), grpcListener.Addr())
+
+	select {
+	case <-ctx.Done():
+	case err = <-errs:
+	}
+	grpcServer.GracefulStop()
+	if shutdownErr := httpServer.Shutdown(context.Background()); err == nil {
+		err = shutdownErr
+	}
+	if errors.Is(err, http.ErrServerClosed) {
+		err = nil
+	}
+	return err
+}
+
+// client calls the HTTP API of a running service and prints the records as JSON
+func client(ctx context.Context, args []string, w io.Writer) error {
+	flags := flag.NewFlagSet("client", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	url := flags.String("url", "http://localhost:8080/v1", "base URL of the HTTP API")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		return fmt.Errorf("usage: service client [-url url] new|batch|decode|encode [arg]")
+	}
+	c := doremidhttp.NewClient(*url)
+
+	var records []doremidhttp.Record
+	switch command, arg := flags.Arg(0), flags.Arg(1); {
+	case command == "new":
+		record, err := c.NewID(ctx)
+		if err != nil {
+			return err
+		}
+		records = append(records, record)
+	case command == "batch":
+		count, err := strconv.ParseInt(arg, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid count %q", arg)
+		}
+		if records, err = c.Batch(ctx, doremidhttp.BatchRequest{Count: count}); err != nil {
+			return err
+		}
+	case command == "decode":
+		record, err := c.Decode(ctx, arg)
+		if err != nil {
+			return err
+		}
+		records = append(records, record)
+	case command == "encode":
+		position, err := strconv.ParseInt(arg, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid position %q", arg)
+		}
+		record, err := c.Encode(ctx, position)
+		if err != nil {
+			return err
+		}
+		records = append(records, record)
+	default:
+		return fmt.Errorf("unknown client command %q", command)
+	}
+
+	encoder := json.NewEncoder(w)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
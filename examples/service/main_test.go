@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/doremi-id/doremid/doremidhttp"
+	"github.com/doremi-id/doremid/doremidtest"
+)
+
+func TestClientCommands(t *testing.T) {
+	url := doremidtest.StartHTTP(t, newTestService(t)) + "/v1"
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected int // Number of records printed
+	}{
+		{"new", []string{"new"}, 1},
+		{"batch", []string{"batch", "3"}, 3},
+		{"decode", []string{"decode", "domi-1a2"}, 1},
+		{"encode", []string{"encode", "3722"}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := run(context.Background(), append([]string{"client", "-url", url}, tt.args...), &out); err != nil {
+				t.Fatal(err)
+			}
+			decoder := json.NewDecoder(&out)
+			count := 0
+			for decoder.More() {
+				var record doremidhttp.Record
+				if err := decoder.Decode(&record); err != nil || record.ID == "" {
+					t.Fatalf("expected a record, got %+v (err: %v)", record, err)
+				}
+				count++
+			}
+			if count != tt.expected {
+				t.Errorf("expected %d records, got %d", tt.expected, count)
+			}
+		})
+	}
+}
+
+func TestClientCommandErrors(t *testing.T) {
+	url := doremidtest.StartHTTP(t, newTestService(t)) + "/v1"
+
+	for _, args := range [][]string{
+		{}, {"unknown"}, {"client"}, {"client", "-url", url, "unknown"}, {"client", "-url", url, "batch", "x"},
+		{"client", "-url", url, "decode", "invalid"}, {"client", "-url", url, "encode", "84672"},
+	} {
+		if err := run(context.Background(), args, &bytes.Buffer{}); err == nil {
+			t.Errorf("expected an error for %q", args)
+		}
+	}
+}
+
+func TestServe(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var out bytes.Buffer
+	if err := run(ctx, []string{"serve", "-http", "127.0.0.1:0", "-grpc", "127.0.0.1:0"}, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(out.Bytes(), []byte("serving HTTP on 127.0.0.1:")) {
+		t.Errorf("expected the listen addresses, got %q", out.String())
+	}
+}
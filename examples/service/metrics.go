@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+)
+
+// Metrics counts requests by transport, route and status, and serves them in
+// the Prometheus text format. It is safe for concurrent use.
+type Metrics struct {
+	mu       sync.Mutex
+	requests map[requestKey]int64
+}
+
+type requestKey struct {
+	transport, route, status string
+}
+
+// NewMetrics creates empty metrics
+func NewMetrics() *Metrics {
+	return &Metrics{requests: make(map[requestKey]int64)}
+}
+
+// Count records one request
+func (m *Metrics) Count(transport, route, status string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests[requestKey{transport, route, status}]++
+}
+
+// Requests returns the number of requests recorded for transport, route and status
+func (m *Metrics) Requests(transport, route, status string) int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.requests[requestKey{transport, route, status}]
+}
+
+// ServeHTTP serves the metrics for scraping
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	m.mu.Lock()
+	lines := make([]string, 0, len(m.requests))
+	for key, count := range m.requests {
+		lines = append(lines, fmt.Sprintf("doremid_requests_total{transport=%q,route=%q,status=%q} %d\n", key.transport, key.route, key.status, count))
+	}
+	m.mu.Unlock()
+	slices.Sort(lines)
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	fmt.Fprintln(w, "# HELP doremid_requests_total Requests served, by transport, route and status.")
+	fmt.Fprintln(w, "# TYPE doremid_requests_total counter")
+	for _, line := range lines {
+		fmt.Fprint(w, line)
+	}
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMetrics(t *testing.T) {
+	m := NewMetrics()
+	m.Count("http", "POST /v1/ids", "200")
+	m.Count("http", "POST /v1/ids", "200")
+	m.Count("grpc", "/doremid.v1.DoremidService/Decode", "InvalidArgument")
+
+	rec := httptest.NewRecorder()
+	m.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	expected := []string{
+		"# TYPE doremid_requests_total counter",
+		`doremid_requests_total{transport="grpc",route="/doremid.v1.DoremidService/Decode",status="InvalidArgument"} 1`,
+		`doremid_requests_total{transport="http",route="POST /v1/ids",status="200"} 2`,
+	}
+	for _, line := range expected {
+		if !strings.Contains(rec.Body.String(), line+"\n") {
+			t.Errorf("expected %q in metrics, got:\n%s", line, rec.Body.String())
+		}
+	}
+	if n := m.Requests("http", "GET /healthz", "200"); n != 0 {
+		t.Errorf("expected 0 requests, got %d", n)
+	}
+}
@@ -0,0 +1,265 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/doremi-id/doremid"
+	"github.com/doremi-id/doremid/doremidgrpc"
+	"github.com/doremi-id/doremid/doremidgrpc/doremidv1"
+	"github.com/doremi-id/doremid/doremidhttp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultMaxBatch bounds batch requests when Config.MaxBatch is zero
+const DefaultMaxBatch = 1000
+
+// Config configures a Service
+type Config struct {
+	// Generator configures the IDs. Concurrent is always set.
+	Generator doremid.Config
+
+	// Split divides the ID space: batches are allocated sequentially below it
+	// and single IDs drawn at random above it, so the two never collide. Zero
+	// splits the space in half.
+	Split int64
+
+	// Registry remembers random IDs so they are never issued twice. Nil keeps
+	// them in memory; use a shared store such as redisstore across replicas.
+	Registry doremid.Registry
+
+	// Allocator hands out blocks of sequential positions below Split. Nil
+	// allocates in memory; use a shared store such as badgerstore across restarts.
+	Allocator doremid.Allocator
+
+	// MaxBatch bounds the count of a batch request
+	MaxBatch int64
+}
+
+// Service issues IDs over HTTP and gRPC. Single IDs are random and registered,
+// batches are allocated sequentially, and any ID can be decoded or encoded.
+// Every request is counted in Metrics.
+type Service struct {
+	Metrics *Metrics
+
+	generator  *doremid.Generator
+	random     *doremid.Generator // Restricted to positions above the split
+	sequential *doremid.Generator // Restricted to positions below the split
+	registry   doremid.Registry
+	allocator  doremid.Allocator
+	maxBatch   int64
+	mux        *http.ServeMux
+}
+
+// NewService creates a service with config
+func NewService(config Config) (*Service, error) {
+	config.Generator.Concurrent = true
+	generator, err := doremid.NewE(config.Generator)
+	if err != nil {
+		return nil, err
+	}
+
+	space := generator.MaxCombinations()
+	if config.Split == 0 {
+		config.Split = space / 2
+	}
+	if config.Split <= 0 || config.Split >= space {
+		return nil, &doremid.ConfigError{Field: "Split", Reason: fmt.Sprintf("must be between 1 and %d", space-1)}
+	}
+
+	s := &Service{
+		Metrics:    NewMetrics(),
+		generator:  generator,
+		random:     generator.Restrict(doremid.Range{Start: config.Split, End: space}),
+		sequential: generator.Restrict(doremid.Range{Start: 0, End: config.Split}),
+		registry:   config.Registry,
+		allocator:  config.Allocator,
+		maxBatch:   config.MaxBatch,
+	}
+	if s.registry == nil {
+		s.registry = doremid.NewMemoryRegistry()
+	}
+	if s.allocator == nil {
+		s.allocator = doremid.NewMemoryAllocator(config.Split)
+	}
+	if s.maxBatch <= 0 {
+		s.maxBatch = DefaultMaxBatch
+	}
+
+	// Decoding and encoding are stateless, so doremidhttp serves them as is
+	codec := http.StripPrefix("/v1", doremidhttp.New(generator))
+	s.mux = http.NewServeMux()
+	s.mux.HandleFunc("POST /v1/ids", s.newID)
+	s.mux.HandleFunc("POST /v1/ids/batch", s.batch)
+	s.mux.Handle("GET /v1/ids/{id}", codec)
+	s.mux.Handle("GET /v1/positions/{position}", codec)
+	s.mux.Handle("GET /metrics", s.Metrics)
+	s.mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintln(w, "ok")
+	})
+	return s, nil
+}
+
+// ServeHTTP implements http.Handler, serving the doremidhttp API under /v1
+// along with /metrics and /healthz
+func (s *Service) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+	s.mux.ServeHTTP(recorder, r)
+
+	// The mux sets the pattern it matched on r
+	route := r.Pattern
+	if route == "" {
+		route = "unmatched"
+	}
+	s.Metrics.Count("http", route, fmt.Sprint(recorder.status))
+}
+
+// NewID issues a random ID that was never issued before
+func (s *Service) NewID(ctx context.Context) (string, error) {
+	return s.random.NewRegisteredID(ctx, s.registry)
+}
+
+// Batch allocates count sequential IDs
+func (s *Service) Batch(ctx context.Context, count int64) ([]string, error) {
+	if count <= 0 || count > s.maxBatch {
+		return nil, fmt.Errorf("%w: count must be between 1 and %d", doremid.ErrInvalidCount, s.maxBatch)
+	}
+	return s.sequential.AllocateIDs(ctx, s.allocator, count)
+}
+
+// newID serves POST /v1/ids
+func (s *Service) newID(w http.ResponseWriter, r *http.Request) {
+	id, err := s.NewID(r.Context())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, s.record(id))
+}
+
+// batch serves POST /v1/ids/batch
+func (s *Service) batch(w http.ResponseWriter, r *http.Request) {
+	var req doremidhttp.BatchRequest
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, doremidhttp.DefaultMaxBodyBytes))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, doremidhttp.ErrorResponse{Error: "invalid request body: " + err.Error()})
+		return
+	}
+	if req.Start != nil {
+		writeJSON(w, http.StatusBadRequest, doremidhttp.ErrorResponse{Error: "start is chosen by the allocator"})
+		return
+	}
+
+	ids, err := s.Batch(r.Context(), req.Count)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	resp := doremidhttp.BatchResponse{IDs: make([]doremidhttp.Record, len(ids))}
+	for i, id := range ids {
+		resp.IDs[i] = s.record(id)
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Service) record(id string) doremidhttp.Record {
+	return doremidhttp.Record{ID: id, Position: s.generator.IDToPosition(id)}
+}
+
+// RegisterGRPC registers the doremid.v1.DoremidService with server, minting
+// like the HTTP API
+func (s *Service) RegisterGRPC(server *grpc.Server) {
+	doremidv1.RegisterDoremidServiceServer(server, &grpcServer{Server: doremidgrpc.NewServer(s.generator), s: s})
+}
+
+// UnaryInterceptor counts gRPC requests in Metrics; pass it to grpc.NewServer
+// with grpc.UnaryInterceptor
+func (s *Service) UnaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	resp, err := handler(ctx, req)
+	s.Metrics.Count("grpc", info.FullMethod, status.Code(err).String())
+	return resp, err
+}
+
+// grpcServer mints through the registry and allocator, leaving decoding and
+// validation to doremidgrpc
+type grpcServer struct {
+	*doremidgrpc.Server
+	s *Service
+}
+
+// GenerateID returns a random registered ID
+func (g *grpcServer) GenerateID(ctx context.Context, _ *doremidv1.GenerateIDRequest) (*doremidv1.GenerateIDResponse, error) {
+	id, err := g.s.NewID(ctx)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	record := g.s.record(id)
+	return &doremidv1.GenerateIDResponse{Record: &doremidv1.Record{Id: record.ID, Position: record.Position}}, nil
+}
+
+// GenerateBatch returns allocated sequential IDs
+func (g *grpcServer) GenerateBatch(ctx context.Context, req *doremidv1.GenerateBatchRequest) (*doremidv1.GenerateBatchResponse, error) {
+	if req.Start != nil {
+		return nil, status.Error(codes.InvalidArgument, "start is chosen by the allocator")
+	}
+	ids, err := g.s.Batch(ctx, req.Count)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	resp := &doremidv1.GenerateBatchResponse{Records: make([]*doremidv1.Record, len(ids))}
+	for i, id := range ids {
+		record := g.s.record(id)
+		resp.Records[i] = &doremidv1.Record{Id: record.ID, Position: record.Position}
+	}
+	return resp, nil
+}
+
+// statusError converts a doremid error to a gRPC status, like doremidgrpc
+func statusError(err error) error {
+	code := codes.Internal
+	switch {
+	case errors.Is(err, doremid.ErrInvalidCount):
+		code = codes.InvalidArgument
+	case errors.Is(err, doremid.ErrSpaceExhausted):
+		code = codes.ResourceExhausted
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		code = status.FromContextError(err).Code()
+	}
+	return status.Error(code, err.Error())
+}
+
+// writeError answers with the status matching a doremid error, like doremidhttp
+func writeError(w http.ResponseWriter, err error) {
+	code := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, doremid.ErrInvalidCount):
+		code = http.StatusBadRequest
+	case errors.Is(err, doremid.ErrSpaceExhausted):
+		code = http.StatusConflict
+	}
+	writeJSON(w, code, doremidhttp.ErrorResponse{Error: err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
+
+// statusRecorder remembers the status code written through it
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	r.status = code
+	r.ResponseWriter.WriteHeader(code)
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/doremi-id/doremid"
+	"github.com/doremi-id/doremid/doremidgrpc/doremidv1"
+	"github.com/doremi-id/doremid/doremidhttp"
+	"github.com/doremi-id/doremid/doremidtest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// startService runs s over HTTP and gRPC in-process and returns clients for both
+func startService(t *testing.T, s *Service) (*doremidhttp.Client, doremidv1.DoremidServiceClient) {
+	t.Helper()
+	httpClient := doremidtest.StartClient(t, s, "/v1")
+
+	server := grpc.NewServer(grpc.UnaryInterceptor(s.UnaryInterceptor))
+	s.RegisterGRPC(server)
+	conn, err := grpc.NewClient(doremidtest.Start(t, server.Serve, server.Stop), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return httpClient, doremidv1.NewDoremidServiceClient(conn)
+}
+
+func newTestService(t *testing.T) *Service {
+	t.Helper()
+	s, err := NewService(Config{Generator: doremid.Config{JustIntonationDigits: 2, EqualTemperamentDigits: 3, Separator: "-"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestService(t *testing.T) {
+	ctx := context.Background()
+	s := newTestService(t)
+	httpClient, grpcClient := startService(t, s)
+	split := s.generator.MaxCombinations() / 2
+
+	// Random IDs come from the registry above the split, over either transport
+	seen := make(map[int64]bool)
+	for range 50 {
+		record, err := httpClient.NewID(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := grpcClient.GenerateID(ctx, &doremidv1.GenerateIDRequest{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, pos := range []int64{record.Position, resp.Record.Position} {
+			if pos < split || seen[pos] {
+				t.Fatalf("expected a fresh position above %d, got %d", split, pos)
+			}
+			seen[pos] = true
+		}
+	}
+
+	// Batches are allocated below the split, continuing across transports
+	records, err := httpClient.Batch(ctx, doremidhttp.BatchRequest{Count: 3})
+	if err != nil || len(records) != 3 || records[0].Position != 0 || records[2].Position != 2 {
+		t.Errorf("expected positions 0 to 2, got %+v (err: %v)", records, err)
+	}
+	batch, err := grpcClient.GenerateBatch(ctx, &doremidv1.GenerateBatchRequest{Count: 2})
+	if err != nil || len(batch.Records) != 2 || batch.Records[0].Position != 3 {
+		t.Errorf("expected positions 3 and 4, got %v (err: %v)", batch, err)
+	}
+
+	// Decoding is stateless
+	if record, err := httpClient.Decode(ctx, "domi-1a2"); err != nil || record.Position != 3722 {
+		t.Errorf("expected position 3722, got %+v (err: %v)", record, err)
+	}
+	if resp, err := grpcClient.Decode(ctx, &doremidv1.DecodeRequest{Id: "domi-1a2"}); err != nil || resp.Position != 3722 {
+		t.Errorf("expected position 3722, got %v (err: %v)", resp, err)
+	}
+
+	if n := s.Metrics.Requests("http", "POST /v1/ids", "200"); n != 50 {
+		t.Errorf("expected 50 HTTP requests for new IDs, got %d", n)
+	}
+	if n := s.Metrics.Requests("grpc", doremidv1.DoremidService_GenerateID_FullMethodName, "OK"); n != 50 {
+		t.Errorf("expected 50 gRPC requests for new IDs, got %d", n)
+	}
+}
+
+func TestServiceErrors(t *testing.T) {
+	ctx := context.Background()
+	s := newTestService(t)
+	httpClient, grpcClient := startService(t, s)
+
+	var statusErr *doremidhttp.StatusError
+	start := int64(5)
+	if _, err := httpClient.Batch(ctx, doremidhttp.BatchRequest{Count: 1, Start: &start}); !errors.As(err, &statusErr) || statusErr.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for a batch with a start, got %v", err)
+	}
+	if _, err := httpClient.Batch(ctx, doremidhttp.BatchRequest{Count: DefaultMaxBatch + 1}); !errors.As(err, &statusErr) || statusErr.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for an oversized batch, got %v", err)
+	}
+	if _, err := grpcClient.GenerateBatch(ctx, &doremidv1.GenerateBatchRequest{Count: 1, Start: proto.Int64(5)}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument for a batch with a start, got %v", err)
+	}
+
+	// Exhaust the sequential half
+	for {
+		if _, err := s.Batch(ctx, DefaultMaxBatch); err != nil {
+			break
+		}
+	}
+	if _, err := httpClient.Batch(ctx, doremidhttp.BatchRequest{Count: DefaultMaxBatch}); !errors.As(err, &statusErr) || statusErr.Code != http.StatusConflict {
+		t.Errorf("expected 409 once the allocator is exhausted, got %v", err)
+	}
+	if _, err := grpcClient.GenerateBatch(ctx, &doremidv1.GenerateBatchRequest{Count: DefaultMaxBatch}); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("expected ResourceExhausted once the allocator is exhausted, got %v", err)
+	}
+
+	if n := s.Metrics.Requests("http", "POST /v1/ids/batch", "409"); n != 1 {
+		t.Errorf("expected 1 failed batch request, got %d", n)
+	}
+}
+
+func TestNewServiceErrors(t *testing.T) {
+	generator := doremid.Config{JustIntonationDigits: 2, EqualTemperamentDigits: 3}
+	for _, split := range []int64{-1, 84672} {
+		_, err := NewService(Config{Generator: generator, Split: split})
+		var configErr *doremid.ConfigError
+		if !errors.As(err, &configErr) || configErr.Field != "Split" {
+			t.Errorf("expected ConfigError for Split %d, got %v", split, err)
+		}
+	}
+}
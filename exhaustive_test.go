@@ -0,0 +1,177 @@
+package doremid
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestExhaustiveRandomGenerator(t *testing.T) {
+	ctx := context.Background()
+	generator := New(Config{
+		JustIntonationDigits:   1,
+		EqualTemperamentDigits: 1,
+		Separator:              "-",
+	})
+	key := []byte("vouchers")
+
+	e, err := generator.NewExhaustiveRandomGenerator(ctx, ExhaustiveConfig{Key: key})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for range 84 {
+		id, err := e.Next(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, id)
+	}
+	if _, err := e.Next(ctx); !errors.Is(err, ErrSpaceExhausted) {
+		t.Errorf("expected ErrSpaceExhausted, got %v", err)
+	}
+	if !e.Done() {
+		t.Error("expected Done after every draw")
+	}
+
+	// Every ID once, in the order ShufflePositions gives
+	var expected []string
+	for pos := range ShufflePositions(Range{Start: 0, End: 84}, key) {
+		expected = append(expected, generator.PositionToID(pos))
+	}
+	if !slices.Equal(ids, expected) {
+		t.Errorf("expected %v, got %v", expected, ids)
+	}
+	if slices.IsSorted(ids) {
+		t.Error("expected a shuffled order")
+	}
+	sorted := slices.Sorted(slices.Values(ids))
+	if len(slices.Compact(sorted)) != 84 {
+		t.Errorf("expected 84 distinct IDs, got %v", ids)
+	}
+}
+
+func TestExhaustiveRandomGeneratorRestart(t *testing.T) {
+	ctx := context.Background()
+	generator := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 1,
+		Separator:              "-",
+		ReservedIDs:            []string{"domi-1"},
+	})
+	counters := NewMemoryCounterStore()
+	config := ExhaustiveConfig{Key: []byte("codes"), Store: CounterState(counters, "codes"), ReserveSize: 10}
+
+	seen := make(map[string]bool)
+	issue := func(e *ExhaustiveRandomGenerator, count int) {
+		for range count {
+			id, err := e.Next(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if seen[id] {
+				t.Fatalf("duplicate ID '%s'", id)
+			}
+			seen[id] = true
+		}
+	}
+
+	e, _ := generator.NewExhaustiveRandomGenerator(ctx, config)
+	issue(e, 3)
+	if saved, _, _ := counters.Load(ctx, "codes"); saved != 9 {
+		t.Errorf("expected reservation up to draw 9, got %d", saved)
+	}
+
+	// After a restart the unused reservation is skipped, never reissued
+	restarted, err := generator.NewExhaustiveRandomGenerator(ctx, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restarted.Cursor() != 10 {
+		t.Errorf("expected cursor 10 after restart, got %d", restarted.Cursor())
+	}
+	for {
+		id, err := restarted.Next(ctx)
+		if errors.Is(err, ErrSpaceExhausted) {
+			break
+		}
+		if err != nil || seen[id] {
+			t.Fatalf("expected a new ID, got '%s' (err: %v)", id, err)
+		}
+		seen[id] = true
+	}
+	if seen["domi-1"] {
+		t.Error("expected the reserved ID to be skipped")
+	}
+	if len(seen) != 49*12-1-7 {
+		t.Errorf("expected every ID but the reserved one and the skipped reservation, got %d", len(seen))
+	}
+}
+
+func TestExhaustiveRandomGeneratorConcurrent(t *testing.T) {
+	ctx := context.Background()
+	generator := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 3,
+		Separator:              "-",
+	}).Restrict(Range{Start: 100, End: 1100})
+
+	e, _ := generator.NewExhaustiveRandomGenerator(ctx, ExhaustiveConfig{Key: []byte("k"), Store: CounterState(NewMemoryCounterStore(), "c"), ReserveSize: 7})
+
+	var mu sync.Mutex
+	seen := make(map[int64]bool)
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				id, err := e.Next(ctx)
+				if errors.Is(err, ErrSpaceExhausted) {
+					return
+				}
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				pos := generator.IDToPosition(id)
+				mu.Lock()
+				if seen[pos] || pos < 100 || pos >= 1100 {
+					t.Errorf("unexpected ID '%s' at position %d", id, pos)
+				}
+				seen[pos] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(seen) != 1000 {
+		t.Errorf("expected every position in the range issued once, got %d IDs", len(seen))
+	}
+}
+
+func TestExhaustiveRandomGeneratorErrors(t *testing.T) {
+	ctx := context.Background()
+	generator := New(Config{JustIntonationDigits: 1, EqualTemperamentDigits: 1, Separator: "-"})
+
+	if _, err := generator.NewExhaustiveRandomGenerator(ctx, ExhaustiveConfig{}); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig for a missing key, got %v", err)
+	}
+
+	counters := NewMemoryCounterStore()
+	counters.Save(ctx, "codes", 84)
+	if _, err := generator.NewExhaustiveRandomGenerator(ctx, ExhaustiveConfig{Key: []byte("k"), Store: CounterState(counters, "codes")}); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("expected ErrOutOfRange for a saved draw past the end, got %v", err)
+	}
+
+	failing, _ := generator.NewExhaustiveRandomGenerator(ctx, ExhaustiveConfig{Key: []byte("k"), Store: failingState{}})
+	if id, err := failing.Next(ctx); err == nil {
+		t.Errorf("expected a store error, got '%s'", id)
+	}
+	if failing.Cursor() != 0 {
+		t.Errorf("expected the failed draw to be retried, got cursor %d", failing.Cursor())
+	}
+}
@@ -0,0 +1,171 @@
+package doremid
+
+import (
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+)
+
+// DefaultExportPartSize is the uncompressed size bound of an export part used
+// when ExportOptions.MaxPartBytes is zero
+const DefaultExportPartSize = 64 << 20
+
+// ObjectStore creates objects in a bucket, e.g. an adapter around an S3 or GCS client.
+// The object is committed when the returned writer is closed without error.
+type ObjectStore interface {
+	Create(ctx context.Context, name string) (io.WriteCloser, error)
+}
+
+// ExportOptions configures ExportToObjectStore
+type ExportOptions struct {
+	// Prefix is prepended to every object name, e.g. "vouchers/2025-01/"
+	Prefix string
+
+	// StartPosition and Count select the sequential positions to export
+	StartPosition int64
+	Count         int64
+
+	// MaxPartBytes bounds the uncompressed size of each part.
+	// Zero uses DefaultExportPartSize.
+	MaxPartBytes int64
+}
+
+// ExportManifest describes a completed export. It is written as JSON to
+// "<prefix>manifest.json" after every part has been committed, so its presence
+// marks the export as complete.
+type ExportManifest struct {
+	JustIntonationDigits   int          `json:"just_intonation_digits"`
+	EqualTemperamentDigits int          `json:"equal_temperament_digits"`
+	Separator              string       `json:"separator"`
+	StartPosition          int64        `json:"start_position"`
+	Count                  int64        `json:"count"`
+	Parts                  []ExportPart `json:"parts"`
+}
+
+// ExportPart describes one gzip-compressed, newline-delimited part of an export
+type ExportPart struct {
+	Name          string `json:"name"`
+	FirstPosition int64  `json:"first_position"`
+	Count         int64  `json:"count"`
+	Bytes         int64  `json:"bytes"`  // Compressed size
+	SHA256        string `json:"sha256"` // Hex digest of the compressed object
+}
+
+// ExportToObjectStore writes the IDs selected by opts to store as size-bounded,
+// gzip-compressed parts named "<prefix>part-00000.txt.gz", followed by a manifest.
+//
+// Returns ErrInvalidCount if opts.Count is not positive, a *RangeError if the
+// positions lie outside those the generator may mint, or any error from store.
+// Parts written before a failure are left in place but no manifest is written.
+func (g *Generator) ExportToObjectStore(ctx context.Context, store ObjectStore, opts ExportOptions) (*ExportManifest, error) {
+	if opts.Count <= 0 {
+		return nil, ErrInvalidCount
+	}
+	if err := checkRange(opts.StartPosition, g.mintRange()); err != nil {
+		return nil, err
+	}
+	if err := checkRange(opts.StartPosition+opts.Count-1, g.mintRange()); err != nil {
+		return nil, err
+	}
+
+	maxPartBytes := opts.MaxPartBytes
+	if maxPartBytes <= 0 {
+		maxPartBytes = DefaultExportPartSize
+	}
+	_, longest := g.idLengths()
+	lineLen := int64(longest + 1)
+	perPart := max(maxPartBytes/lineLen, 1)
+
+	manifest := &ExportManifest{
+		JustIntonationDigits:   g.JustIntonationDigits,
+		EqualTemperamentDigits: g.EqualTemperamentDigits,
+		Separator:              g.Separator,
+		StartPosition:          opts.StartPosition,
+		Count:                  opts.Count,
+	}
+
+	end := opts.StartPosition + opts.Count
+	for first := opts.StartPosition; first < end; first += perPart {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		part := ExportPart{
+			Name:          fmt.Sprintf("%spart-%05d.txt.gz", opts.Prefix, len(manifest.Parts)),
+			FirstPosition: first,
+			Count:         min(perPart, end-first),
+		}
+		if err := g.writeExportPart(ctx, store, &part); err != nil {
+			return nil, fmt.Errorf("%s: %w", part.Name, err)
+		}
+		manifest.Parts = append(manifest.Parts, part)
+	}
+
+	if err := writeManifest(ctx, store, opts.Prefix+"manifest.json", manifest); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// writeExportPart writes the IDs of part as one compressed object, filling in
+// its size and digest
+func (g *Generator) writeExportPart(ctx context.Context, store ObjectStore, part *ExportPart) error {
+	object, err := store.Create(ctx, part.Name)
+	if err != nil {
+		return err
+	}
+
+	counter := &countingWriter{w: object, hash: sha256.New()}
+	compressor := gzip.NewWriter(counter)
+
+	if _, err := g.WriteBatch(compressor, part.Count, part.FirstPosition, '\n'); err != nil {
+		object.Close()
+		return err
+	}
+	if err := compressor.Close(); err != nil {
+		object.Close()
+		return err
+	}
+	if err := object.Close(); err != nil {
+		return err
+	}
+
+	part.Bytes = counter.n
+	part.SHA256 = hex.EncodeToString(counter.hash.Sum(nil))
+	return nil
+}
+
+// writeManifest stores manifest as indented JSON under name
+func writeManifest(ctx context.Context, store ObjectStore, name string, manifest *ExportManifest) error {
+	object, err := store.Create(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(object)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(manifest); err != nil {
+		object.Close()
+		return err
+	}
+	return object.Close()
+}
+
+// countingWriter counts and hashes the bytes written through it
+type countingWriter struct {
+	w    io.Writer
+	hash hash.Hash
+	n    int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.hash.Write(p[:n])
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,205 @@
+// Package export writes pools of pre-generated IDs as CSV or JSON Lines, e.g.
+// for seeding a database table that hands IDs out later. Every row holds an ID,
+// its position and any metadata columns:
+//
+//	f, err := os.Create("pool.csv.gz")
+//	progress, err := export.ExportCSV(ctx, f, generator, export.Options{
+//		Count:   10_000_000,
+//		Columns: []export.Column{{Name: "batch", Value: func(string, int64) string { return "2025-01" }}},
+//		Gzip:    true,
+//	})
+//
+// Output is written in chunks that are complete on their own, so an export
+// interrupted by a failing writer or a cancelled context can be resumed: truncate
+// the output to progress.Bytes and call again with Options.Resume set to progress.
+package export
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/doremi-id/doremid"
+)
+
+// chunkSize is the number of positions exported between checkpoints
+const chunkSize = 4096
+
+// Column is a metadata column written after the ID and position of every row
+type Column struct {
+	// Name is the CSV header and the JSON key, which must not be "id" or "position"
+	Name string
+
+	// Value returns the value of the column for the ID at position
+	Value func(id string, position int64) string
+}
+
+// Options configures an export
+type Options struct {
+	// StartPosition and Count select the sequential positions to export. They
+	// must lie within the positions the generator may mint; reserved positions
+	// are left out.
+	StartPosition int64
+	Count         int64
+
+	// Columns are written after the ID and position, in order
+	Columns []Column
+
+	// Gzip compresses the output. Each chunk is a complete gzip member, so the
+	// output of a resumed export is a valid multi-member gzip file.
+	Gzip bool
+
+	// Resume continues an interrupted export from the Progress it returned,
+	// zero to start afresh. The CSV header is only written when starting afresh.
+	Resume Progress
+}
+
+// Progress records how much of an export has reached the writer. Both counts
+// include the Resume progress the export started from.
+type Progress struct {
+	Positions int64 // Positions exported in full, including reserved ones left out
+	Bytes     int64 // Bytes written up to the last complete chunk
+}
+
+// ExportCSV writes a header row "id,position" followed by the column names,
+// then one row per ID. Returns the progress made, together with
+// doremid.ErrInvalidCount if Count is not positive, a *doremid.RangeError if
+// the positions lie outside those the generator may mint, a *doremid.ConfigError
+// for an invalid column, ctx.Err() or the first error returned by w.
+func ExportCSV(ctx context.Context, w io.Writer, g *doremid.Generator, opts Options) (Progress, error) {
+	return export(ctx, w, g, opts, func(buf *bytes.Buffer, header bool, positions []int64, ids []string) error {
+		cw := csv.NewWriter(buf)
+		record := make([]string, 2+len(opts.Columns))
+		if header {
+			record[0], record[1] = "id", "position"
+			for i, column := range opts.Columns {
+				record[2+i] = column.Name
+			}
+			cw.Write(record)
+		}
+		for i, id := range ids {
+			position := positions[i]
+			record[0], record[1] = id, strconv.FormatInt(position, 10)
+			for j, column := range opts.Columns {
+				record[2+j] = column.Value(id, position)
+			}
+			cw.Write(record)
+		}
+		cw.Flush()
+		return cw.Error()
+	})
+}
+
+// ExportJSONL writes one JSON object per line with the keys "id", "position"
+// and the column names, e.g. {"id":"domi-1a2","position":3722,"batch":"2025-01"}.
+// Returns the same errors as ExportCSV.
+func ExportJSONL(ctx context.Context, w io.Writer, g *doremid.Generator, opts Options) (Progress, error) {
+	return export(ctx, w, g, opts, func(buf *bytes.Buffer, _ bool, positions []int64, ids []string) error {
+		for i, id := range ids {
+			position := positions[i]
+			buf.WriteString(`{"id":`)
+			writeJSONString(buf, id)
+			buf.WriteString(`,"position":`)
+			buf.WriteString(strconv.FormatInt(position, 10))
+			for _, column := range opts.Columns {
+				buf.WriteByte(',')
+				writeJSONString(buf, column.Name)
+				buf.WriteByte(':')
+				writeJSONString(buf, column.Value(id, position))
+			}
+			buf.WriteString("}\n")
+		}
+		return nil
+	})
+}
+
+// encodeFunc appends the rows of ids at positions to buf, preceded by a header
+// if header is set
+type encodeFunc func(buf *bytes.Buffer, header bool, positions []int64, ids []string) error
+
+// export validates opts and writes the selected IDs in checkpointed chunks
+func export(ctx context.Context, w io.Writer, g *doremid.Generator, opts Options, encode encodeFunc) (Progress, error) {
+	progress := opts.Resume
+	if err := validate(g, opts); err != nil {
+		return progress, err
+	}
+
+	var buf, compressed bytes.Buffer
+	var compressor *gzip.Writer
+	if opts.Gzip {
+		compressor = gzip.NewWriter(&compressed)
+	}
+	positions := make([]int64, 0, chunkSize)
+	ids := make([]string, 0, chunkSize)
+
+	for progress.Positions < opts.Count {
+		if err := ctx.Err(); err != nil {
+			return progress, err
+		}
+
+		count := min(chunkSize, opts.Count-progress.Positions)
+		positions, ids = positions[:0], ids[:0]
+		for position, id := range g.GenerateSeq2(opts.StartPosition+progress.Positions, count) {
+			positions = append(positions, position)
+			ids = append(ids, id)
+		}
+		buf.Reset()
+		if err := encode(&buf, progress.Positions == 0, positions, ids); err != nil {
+			return progress, err
+		}
+
+		chunk := buf.Bytes()
+		if compressor != nil {
+			compressed.Reset()
+			compressor.Reset(&compressed)
+			compressor.Write(chunk)
+			if err := compressor.Close(); err != nil {
+				return progress, err
+			}
+			chunk = compressed.Bytes()
+		}
+		if _, err := w.Write(chunk); err != nil {
+			return progress, err
+		}
+		progress.Positions += count
+		progress.Bytes += int64(len(chunk))
+	}
+	return progress, nil
+}
+
+// validate checks the options before anything is written
+func validate(g *doremid.Generator, opts Options) error {
+	if opts.Count <= 0 {
+		return doremid.ErrInvalidCount
+	}
+	mintRange := g.MintRange()
+	for _, position := range []int64{opts.StartPosition, opts.StartPosition + opts.Count - 1} {
+		if !mintRange.Contains(position) {
+			return &doremid.RangeError{Position: position, Min: mintRange.Start, Max: mintRange.End}
+		}
+	}
+	if opts.Resume.Positions < 0 || opts.Resume.Positions > opts.Count || opts.Resume.Bytes < 0 {
+		return &doremid.ConfigError{Field: "Resume", Reason: fmt.Sprintf("must lie within the %d positions exported", opts.Count)}
+	}
+
+	for i, column := range opts.Columns {
+		switch {
+		case column.Name == "" || column.Name == "id" || column.Name == "position":
+			return &doremid.ConfigError{Field: "Columns", Reason: fmt.Sprintf("column %d needs a name other than \"id\" and \"position\"", i)}
+		case column.Value == nil:
+			return &doremid.ConfigError{Field: "Columns", Reason: fmt.Sprintf("column %q has no Value", column.Name)}
+		}
+	}
+	return nil
+}
+
+// writeJSONString appends s to buf as a JSON string
+func writeJSONString(buf *bytes.Buffer, s string) {
+	quoted, _ := json.Marshal(s)
+	buf.Write(quoted)
+}
@@ -0,0 +1,191 @@
+package export
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/doremi-id/doremid"
+)
+
+var columns = []Column{
+	{Name: "batch", Value: func(string, int64) string { return "2025-01" }},
+	{Name: "note", Value: func(id string, position int64) string { return fmt.Sprintf("%s, \"%d\"", id, position) }},
+}
+
+func newGenerator() *doremid.Generator {
+	return doremid.New(doremid.Config{JustIntonationDigits: 2, EqualTemperamentDigits: 3, Separator: "-"})
+}
+
+func TestExportCSV(t *testing.T) {
+	generator := newGenerator()
+	var buf bytes.Buffer
+	progress, err := ExportCSV(context.Background(), &buf, generator, Options{StartPosition: 100, Count: 10000, Columns: columns})
+	if err != nil || progress.Positions != 10000 || progress.Bytes != int64(buf.Len()) {
+		t.Fatalf("expected 10000 IDs in %d bytes, got %+v (err: %v)", buf.Len(), progress, err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if header := strings.Join(records[0], ","); header != "id,position,batch,note" {
+		t.Errorf("expected header id,position,batch,note, got %s", header)
+	}
+	if len(records) != 10001 {
+		t.Fatalf("expected 10000 rows, got %d", len(records)-1)
+	}
+	for i, record := range records[1:] {
+		position := int64(100 + i)
+		id := generator.PositionToID(position)
+		expected := []string{id, strconv.FormatInt(position, 10), "2025-01", fmt.Sprintf("%s, \"%d\"", id, position)}
+		if strings.Join(record, "|") != strings.Join(expected, "|") {
+			t.Fatalf("expected row %v, got %v", expected, record)
+		}
+	}
+}
+
+func TestExportJSONL(t *testing.T) {
+	generator := newGenerator()
+	var buf bytes.Buffer
+	progress, err := ExportJSONL(context.Background(), &buf, generator, Options{StartPosition: 5, Count: 5000, Columns: columns, Gzip: true})
+	if err != nil || progress.Positions != 5000 || progress.Bytes != int64(buf.Len()) {
+		t.Fatalf("expected 5000 IDs in %d bytes, got %+v (err: %v)", buf.Len(), progress, err)
+	}
+
+	reader, err := gzip.NewReader(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoder := json.NewDecoder(reader)
+	for i := int64(0); i < 5000; i++ {
+		var row struct {
+			ID       string `json:"id"`
+			Position int64  `json:"position"`
+			Batch    string `json:"batch"`
+			Note     string `json:"note"`
+		}
+		if err := decoder.Decode(&row); err != nil {
+			t.Fatalf("row %d: %v", i, err)
+		}
+		if row.Position != 5+i || row.ID != generator.PositionToID(row.Position) || row.Batch != "2025-01" || row.Note != fmt.Sprintf("%s, \"%d\"", row.ID, row.Position) {
+			t.Fatalf("unexpected row %+v", row)
+		}
+	}
+	if decoder.More() {
+		t.Error("expected no more rows")
+	}
+}
+
+// flakyWriter writes to buf until limit bytes have been written, then writes
+// part of the next chunk and fails
+type flakyWriter struct {
+	buf   *bytes.Buffer
+	limit int
+}
+
+func (f *flakyWriter) Write(p []byte) (int, error) {
+	if f.buf.Len()+len(p) > f.limit {
+		n := len(p) / 2
+		f.buf.Write(p[:n])
+		return n, io.ErrShortWrite
+	}
+	return f.buf.Write(p)
+}
+
+func TestExportResume(t *testing.T) {
+	generator := newGenerator()
+	for _, compressed := range []bool{false, true} {
+		for name, exportFunc := range map[string]func(context.Context, io.Writer, *doremid.Generator, Options) (Progress, error){"csv": ExportCSV, "jsonl": ExportJSONL} {
+			t.Run(fmt.Sprintf("%s gzip=%v", name, compressed), func(t *testing.T) {
+				opts := Options{StartPosition: 1000, Count: 20000, Columns: columns, Gzip: compressed}
+				var expected bytes.Buffer
+				if _, err := exportFunc(context.Background(), &expected, generator, opts); err != nil {
+					t.Fatal(err)
+				}
+
+				// Fail twice mid-export, truncating and resuming each time
+				var buf bytes.Buffer
+				for _, limit := range []int{expected.Len() / 3, 2 * expected.Len() / 3} {
+					progress, err := exportFunc(context.Background(), &flakyWriter{buf: &buf, limit: limit}, generator, opts)
+					if !errors.Is(err, io.ErrShortWrite) || progress.Positions%chunkSize != 0 || progress.Positions >= opts.Count {
+						t.Fatalf("expected a partial export, got %+v (err: %v)", progress, err)
+					}
+					buf.Truncate(int(progress.Bytes))
+					opts.Resume = progress
+				}
+				progress, err := exportFunc(context.Background(), &buf, generator, opts)
+				if err != nil || progress.Positions != opts.Count || progress.Bytes != int64(buf.Len()) {
+					t.Fatalf("expected a complete export, got %+v (err: %v)", progress, err)
+				}
+				if !bytes.Equal(buf.Bytes(), expected.Bytes()) {
+					t.Error("expected the resumed export to equal an uninterrupted one")
+				}
+			})
+		}
+	}
+}
+
+func TestExportReserved(t *testing.T) {
+	generator := doremid.New(doremid.Config{JustIntonationDigits: 2, EqualTemperamentDigits: 3, Separator: "-", ReservedPositions: []int64{1, 4096, 5000}})
+	var buf bytes.Buffer
+	progress, err := ExportJSONL(context.Background(), &buf, generator, Options{Count: 6000})
+	if err != nil || progress.Positions != 6000 {
+		t.Fatalf("expected 6000 positions, got %+v (err: %v)", progress, err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 5997 {
+		t.Fatalf("expected 5997 rows, got %d", len(lines))
+	}
+	for _, line := range lines {
+		var row struct{ Position int64 }
+		json.Unmarshal([]byte(line), &row)
+		if generator.Reserved(row.Position) {
+			t.Fatalf("expected reserved position %d to be left out", row.Position)
+		}
+	}
+}
+
+func TestExportCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var buf bytes.Buffer
+	progress, err := ExportCSV(ctx, &buf, newGenerator(), Options{Count: 10})
+	if !errors.Is(err, context.Canceled) || progress != (Progress{}) || buf.Len() != 0 {
+		t.Errorf("expected nothing written and context.Canceled, got %+v (err: %v)", progress, err)
+	}
+}
+
+func TestExportOptions(t *testing.T) {
+	generator := newGenerator().Restrict(doremid.Range{Start: 0, End: 100})
+	tests := []struct {
+		name     string
+		opts     Options
+		expected error
+	}{
+		{"zero count", Options{}, doremid.ErrInvalidCount},
+		{"beyond the range", Options{StartPosition: 90, Count: 20}, doremid.ErrOutOfRange},
+		{"negative start", Options{StartPosition: -1, Count: 20}, doremid.ErrOutOfRange},
+		{"resume beyond count", Options{Count: 10, Resume: Progress{Positions: 11}}, doremid.ErrInvalidConfig},
+		{"reserved column", Options{Count: 10, Columns: []Column{{Name: "id", Value: columns[0].Value}}}, doremid.ErrInvalidConfig},
+		{"column without value", Options{Count: 10, Columns: []Column{{Name: "batch"}}}, doremid.ErrInvalidConfig},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if _, err := ExportJSONL(context.Background(), &buf, generator, tt.opts); !errors.Is(err, tt.expected) || buf.Len() != 0 {
+				t.Errorf("expected %v and no output, got %v", tt.expected, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package doremid
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+// memoryBucket is an ObjectStore keeping committed objects in memory
+type memoryBucket struct {
+	objects map[string][]byte
+}
+
+type memoryObject struct {
+	bytes.Buffer
+	bucket *memoryBucket
+	name   string
+}
+
+func (o *memoryObject) Close() error {
+	o.bucket.objects[o.name] = o.Bytes()
+	return nil
+}
+
+func (b *memoryBucket) Create(_ context.Context, name string) (io.WriteCloser, error) {
+	return &memoryObject{bucket: b, name: name}, nil
+}
+
+func TestExportToObjectStore(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   1,
+		EqualTemperamentDigits: 2,
+		Separator:              "-",
+	})
+	bucket := &memoryBucket{objects: make(map[string][]byte)}
+
+	// Each line is 6 bytes, so parts hold 10 IDs
+	manifest, err := generator.ExportToObjectStore(context.Background(), bucket, ExportOptions{
+		Prefix:        "vouchers/",
+		StartPosition: 100,
+		Count:         25,
+		MaxPartBytes:  64,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(manifest.Parts) != 3 || manifest.Parts[2].Count != 5 || manifest.Parts[2].Name != "vouchers/part-00002.txt.gz" {
+		t.Fatalf("unexpected parts %+v", manifest.Parts)
+	}
+
+	var exported []string
+	for _, part := range manifest.Parts {
+		data := bucket.objects[part.Name]
+		digest := sha256.Sum256(data)
+		if int64(len(data)) != part.Bytes || hex.EncodeToString(digest[:]) != part.SHA256 {
+			t.Errorf("size or digest mismatch for %s", part.Name)
+		}
+
+		r, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		text, _ := io.ReadAll(r)
+		exported = append(exported, strings.Fields(string(text))...)
+	}
+
+	expected := generator.BatchGenerateIDs(25, 100)
+	if strings.Join(exported, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected %v, got %v", expected, exported)
+	}
+
+	var stored ExportManifest
+	if err := json.Unmarshal(bucket.objects["vouchers/manifest.json"], &stored); err != nil || stored.Count != 25 || len(stored.Parts) != 3 {
+		t.Errorf("unexpected stored manifest %+v (err: %v)", stored, err)
+	}
+}
+
+func TestExportToObjectStoreErrors(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   1,
+		EqualTemperamentDigits: 1,
+		Separator:              "-",
+	})
+	bucket := &memoryBucket{objects: make(map[string][]byte)}
+	ctx := context.Background()
+
+	if _, err := generator.ExportToObjectStore(ctx, bucket, ExportOptions{}); !errors.Is(err, ErrInvalidCount) {
+		t.Errorf("expected ErrInvalidCount, got %v", err)
+	}
+	if _, err := generator.ExportToObjectStore(ctx, bucket, ExportOptions{StartPosition: 80, Count: 10}); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("expected ErrOutOfRange, got %v", err)
+	}
+	if len(bucket.objects) != 0 {
+		t.Errorf("expected no objects to be written, got %d", len(bucket.objects))
+	}
+}
@@ -0,0 +1,163 @@
+package doremid
+
+import (
+	"context"
+	crand "crypto/rand"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// FailoverConfig configures a FailoverGenerator
+type FailoverConfig struct {
+	// Allocator is the shared allocator IDs are normally drawn from. It must be set.
+	Allocator Allocator
+
+	// BlockSize is the number of positions allocated per call to Allocator.
+	// Zero or negative allocates one position at a time.
+	BlockSize int64
+
+	// Emergency is a region reserved for minting while Allocator is unreachable.
+	// The allocator must never hand out its positions. It must not be empty and
+	// must lie within the positions the generator may mint.
+	Emergency Range
+
+	// EmergencyKey orders the emergency IDs, which are issued like those of an
+	// ExhaustiveRandomGenerator so they never repeat. Empty draws a random key,
+	// so emergency IDs do not repeat within the process but may repeat those
+	// of an earlier run.
+	EmergencyKey []byte
+
+	// EmergencyStore persists the emergency cursor so emergency IDs do not
+	// repeat across restarts either. It requires EmergencyKey and should not
+	// depend on the allocator's datastore, e.g. a local file. Nil keeps the
+	// cursor in memory only.
+	EmergencyStore StateStore
+
+	// OnFailover is called for every ID minted from Emergency with the error that
+	// caused it, e.g. to log it or queue the ID for reconciliation. Nil disables it.
+	OnFailover func(ctx context.Context, id string, cause error)
+}
+
+// FailoverGenerator issues IDs from a shared allocator and degrades to random IDs
+// from a pre-reserved emergency region when the allocator fails, so issuance never
+// hard-depends on one datastore. Emergency IDs never collide with allocated ones
+// and are drawn without repeats in an order set by EmergencyKey; size the region
+// for the IDs all outages together may need. It is safe for concurrent use.
+type FailoverGenerator struct {
+	g         *Generator
+	config    FailoverConfig
+	emergency *Generator
+
+	mu       sync.Mutex
+	drawn    *ExhaustiveRandomGenerator // Emergency IDs, created on the first failover
+	next     int64                      // Next position of the current block
+	end      int64                      // End of the current block
+	degraded int64
+}
+
+// NewFailover creates a failover generator with the configuration of g.
+// Returns a *ConfigError if Allocator is nil, Emergency is empty or outside the
+// positions g may mint, or EmergencyStore is set without EmergencyKey.
+func (g *Generator) NewFailover(config FailoverConfig) (*FailoverGenerator, error) {
+	if config.Allocator == nil {
+		return nil, &ConfigError{Field: "Allocator", Reason: "must not be nil"}
+	}
+	mintRange := g.mintRange()
+	if config.Emergency.Len() == 0 || config.Emergency.intersect(mintRange) != config.Emergency {
+		return nil, &ConfigError{Field: "Emergency", Reason: "must be a non-empty range the generator may mint"}
+	}
+	if config.EmergencyStore != nil && len(config.EmergencyKey) == 0 {
+		return nil, &ConfigError{Field: "EmergencyKey", Reason: "must be set with EmergencyStore"}
+	}
+	if len(config.EmergencyKey) == 0 {
+		config.EmergencyKey = make([]byte, 32)
+		crand.Read(config.EmergencyKey)
+	}
+	config.BlockSize = max(config.BlockSize, 1)
+
+	return &FailoverGenerator{g: g, config: config, emergency: g.Restrict(config.Emergency)}, nil
+}
+
+// NewID issues the next allocated ID, or an emergency ID if the allocator fails.
+// Reserved and filtered positions of allocated blocks are skipped.
+// Returns ErrSpaceExhausted if the allocator is exhausted, a *RangeError if it
+// allocates positions the generator may not mint, or an error if it allocates
+// positions of the emergency region; none of these is treated as an outage, nor
+// is the caller's context ending. During an outage, returns ErrSpaceExhausted
+// once every emergency ID has been issued, or any error of EmergencyStore.
+func (f *FailoverGenerator) NewID(ctx context.Context) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	// Reserved and filtered positions are skipped, allocating another block if
+	// the rest of this one is blocked
+	for {
+		if pos := f.g.nextAllowed(f.next); pos < f.end {
+			f.next = pos + 1
+			return f.g.PositionToID(pos), nil
+		}
+
+		var start int64
+		err := f.g.withRetry(ctx, func() (err error) {
+			start, err = f.config.Allocator.Allocate(ctx, f.config.BlockSize)
+			return err
+		})
+		if errors.Is(err, ErrSpaceExhausted) || ctx.Err() != nil {
+			return "", err
+		}
+		if err != nil {
+			return f.failover(ctx, err)
+		}
+
+		block := Range{Start: start, End: start + f.config.BlockSize}
+		if err := checkRange(start, f.g.mintRange()); err != nil {
+			return "", err
+		}
+		if err := checkRange(block.End-1, f.g.mintRange()); err != nil {
+			return "", err
+		}
+		if block.intersect(f.config.Emergency).Len() > 0 {
+			return "", fmt.Errorf("doremid: allocated block [%d, %d) overlaps the emergency region", block.Start, block.End)
+		}
+		f.next, f.end = block.Start, block.End
+	}
+}
+
+// Degraded returns the number of IDs minted from the emergency region
+func (f *FailoverGenerator) Degraded() int64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.degraded
+}
+
+// Emergency reports whether id was minted from the emergency region.
+// Returns a *FormatError if id is invalid.
+func (f *FailoverGenerator) Emergency(id string) (bool, error) {
+	pos, err := f.g.decode(id)
+	if err != nil {
+		return false, err
+	}
+	return f.config.Emergency.Contains(pos), nil
+}
+
+// failover mints the next emergency ID and records it
+func (f *FailoverGenerator) failover(ctx context.Context, cause error) (string, error) {
+	if f.drawn == nil {
+		drawn, err := f.emergency.NewExhaustiveRandomGenerator(ctx, ExhaustiveConfig{Key: f.config.EmergencyKey, Store: f.config.EmergencyStore})
+		if err != nil {
+			return "", err
+		}
+		f.drawn = drawn
+	}
+
+	id, err := f.drawn.Next(ctx)
+	if err != nil {
+		return "", err
+	}
+	f.degraded++
+	if f.config.OnFailover != nil {
+		f.config.OnFailover(ctx, id, cause)
+	}
+	return id, nil
+}
@@ -0,0 +1,173 @@
+package doremid
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestFailoverGenerator(t *testing.T) {
+	ctx := context.Background()
+	generator := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 3,
+		Separator:              "-",
+	})
+	emergency := Range{Start: 80000, End: generator.MaxCombinations()}
+	allocator := &flakyAllocator{next: NewMemoryAllocator(emergency.Start)}
+
+	var recorded []string
+	f, err := generator.NewFailover(FailoverConfig{
+		Allocator: allocator,
+		BlockSize: 2,
+		Emergency: emergency,
+		OnFailover: func(_ context.Context, id string, cause error) {
+			if !errors.Is(cause, errUnavailable) {
+				t.Errorf("expected the allocator error as cause, got %v", cause)
+			}
+			recorded = append(recorded, id)
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Healthy allocator: sequential IDs in blocks of two
+	for i := int64(0); i < 3; i++ {
+		id, err := f.NewID(ctx)
+		if err != nil || id != generator.PositionToID(i) {
+			t.Fatalf("expected '%s', got '%s' (err: %v)", generator.PositionToID(i), id, err)
+		}
+	}
+
+	// The allocator goes down after the current block is used up
+	allocator.failures, allocator.calls, allocator.err = 3, 0, errUnavailable
+	id, _ := f.NewID(ctx)
+	if id != generator.PositionToID(3) {
+		t.Errorf("expected the rest of the block ('%s'), got '%s'", generator.PositionToID(3), id)
+	}
+	for i := 0; i < 3; i++ {
+		id, err := f.NewID(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if isEmergency, _ := f.Emergency(id); !isEmergency {
+			t.Errorf("expected an emergency ID, got '%s'", id)
+		}
+	}
+	if f.Degraded() != 3 || len(recorded) != 3 {
+		t.Errorf("expected 3 degraded IDs recorded, got %d (%d recorded)", f.Degraded(), len(recorded))
+	}
+
+	// Recovered allocator resumes where it stopped
+	if id, _ := f.NewID(ctx); id != generator.PositionToID(4) {
+		t.Errorf("expected '%s' after recovery, got '%s'", generator.PositionToID(4), id)
+	}
+}
+
+func TestFailoverSkipsReserved(t *testing.T) {
+	ctx := context.Background()
+	generator := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 3,
+		Separator:              "-",
+		ReservedPositions:      []int64{0, 1, 2},
+	})
+	emergency := Range{Start: 80000, End: generator.MaxCombinations()}
+	f, _ := generator.NewFailover(FailoverConfig{Allocator: NewMemoryAllocator(emergency.Start), BlockSize: 2, Emergency: emergency})
+
+	// The first block is fully reserved, so another is allocated
+	for _, expected := range []int64{3, 4, 5} {
+		if id, err := f.NewID(ctx); err != nil || generator.IDToPosition(id) != expected {
+			t.Errorf("expected position %d, got '%s' (err: %v)", expected, id, err)
+		}
+	}
+}
+
+func TestFailoverEmergencyUnique(t *testing.T) {
+	ctx := context.Background()
+	generator := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 3,
+		Separator:              "-",
+	})
+	emergency := Range{Start: 80000, End: 80050}
+	down := &flakyAllocator{failures: math.MaxInt, err: errUnavailable}
+	config := FailoverConfig{
+		Allocator:      down,
+		Emergency:      emergency,
+		EmergencyKey:   []byte("outage"),
+		EmergencyStore: CounterState(NewMemoryCounterStore(), "emergency"),
+	}
+
+	// Every emergency ID is issued once, also across a restart
+	seen := make(map[string]bool)
+	f, _ := generator.NewFailover(config)
+	for i := 0; i < int(emergency.Len()); i++ {
+		if i == 20 {
+			f, _ = generator.NewFailover(config)
+		}
+		id, err := f.NewID(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if isEmergency, _ := f.Emergency(id); !isEmergency || seen[id] {
+			t.Fatalf("expected a new emergency ID, got '%s'", id)
+		}
+		seen[id] = true
+	}
+	if id, err := f.NewID(ctx); !errors.Is(err, ErrSpaceExhausted) {
+		t.Errorf("expected ErrSpaceExhausted for an exhausted emergency region, got '%s' (err: %v)", id, err)
+	}
+
+	// Without a store emergency IDs still do not repeat within the process
+	f, _ = generator.NewFailover(FailoverConfig{Allocator: down, Emergency: emergency})
+	clear(seen)
+	for range emergency.Len() {
+		id, err := f.NewID(ctx)
+		if err != nil || seen[id] {
+			t.Fatalf("expected a new emergency ID, got '%s' (err: %v)", id, err)
+		}
+		seen[id] = true
+	}
+
+	failing, _ := generator.NewFailover(FailoverConfig{Allocator: down, Emergency: emergency, EmergencyKey: []byte("k"), EmergencyStore: failingState{}})
+	if id, err := failing.NewID(ctx); err == nil || failing.Degraded() != 0 {
+		t.Errorf("expected a store error, got '%s' (%d degraded)", id, failing.Degraded())
+	}
+}
+
+func TestFailoverErrors(t *testing.T) {
+	ctx := context.Background()
+	generator := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 3,
+		Separator:              "-",
+	})
+	emergency := Range{Start: 80000, End: generator.MaxCombinations()}
+
+	// An exhausted allocator is not an outage
+	f, _ := generator.NewFailover(FailoverConfig{Allocator: NewMemoryAllocator(0), Emergency: emergency})
+	if _, err := f.NewID(ctx); !errors.Is(err, ErrSpaceExhausted) {
+		t.Errorf("expected ErrSpaceExhausted, got %v", err)
+	}
+
+	// Nor is an allocator handing out emergency positions
+	f, _ = generator.NewFailover(FailoverConfig{Allocator: NewMemoryAllocator(emergency.End), Emergency: Range{Start: 0, End: 10}})
+	if id, err := f.NewID(ctx); err == nil {
+		t.Errorf("expected an overlap error, got '%s'", id)
+	}
+
+	configs := []FailoverConfig{
+		{Emergency: emergency},
+		{Allocator: NewMemoryAllocator(10)},
+		{Allocator: NewMemoryAllocator(10), Emergency: Range{Start: 80000, End: 90000}},
+		{Allocator: NewMemoryAllocator(10), Emergency: emergency, EmergencyStore: CounterState(NewMemoryCounterStore(), "emergency")},
+	}
+	for _, config := range configs {
+		if _, err := generator.NewFailover(config); !errors.Is(err, ErrInvalidConfig) {
+			t.Errorf("expected ErrInvalidConfig for %+v, got %v", config, err)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package doremid
+
+import "fmt"
+
+// FrozenGenerator is a read-only view of a Generator for code that handles untrusted
+// input and must never mint IDs. It can parse, validate and convert IDs, but has no
+// generation methods, so the restriction is enforced by the type system.
+//
+// Inputs are checked against the exact ID length before any other work, once
+// aliases are resolved and group separators removed, and conversions reject
+// out-of-range positions. A FrozenGenerator holds no random
+// state and is safe for concurrent use.
+type FrozenGenerator struct {
+	g         *Generator
+	minLength int // Shortest compact ID
+	maxLength int // Longest compact ID
+	maxInput  int // Longest ID, compact or grouped
+	maxValue  int64
+}
+
+// Freeze returns a read-only FrozenGenerator with the generator's current configuration.
+// Later changes to the generator's exported fields do not affect the frozen copy.
+func (g *Generator) Freeze() *FrozenGenerator {
+	clone := *g
+	clone.rand = nil
+
+	minLength, maxLength := g.compactLengths()
+	_, maxInput := g.idLengths()
+	return &FrozenGenerator{
+		g:         &clone,
+		minLength: minLength,
+		maxLength: maxLength,
+		maxInput:  maxInput,
+		maxValue:  g.MaxCombinations(),
+	}
+}
+
+// MaxInputLength returns the length of the longest valid ID as generated, which
+// is the length of every valid ID unless custom notes differ in length. Compact
+// IDs of another length are rejected without being decoded.
+func (f *FrozenGenerator) MaxInputLength() int {
+	return f.maxInput
+}
+
+// MaxCombinations returns the maximum number of unique IDs
+func (f *FrozenGenerator) MaxCombinations() int64 {
+	return f.maxValue
+}
+
+// Validate returns a *FormatError if id is not a valid ID
+func (f *FrozenGenerator) Validate(id string) error {
+	_, err := f.parse(id)
+	return err
+}
+
+// IDToPosition converts an ID to its position.
+// Returns -1 if the ID is invalid.
+func (f *FrozenGenerator) IDToPosition(id string) int64 {
+	pos, err := f.parse(id)
+	if err != nil {
+		return -1
+	}
+	return pos
+}
+
+// PositionToID converts a position to its ID.
+// Returns an empty string if the position is negative or beyond the maximum.
+func (f *FrozenGenerator) PositionToID(position int64) string {
+	if position < 0 || position >= f.maxValue {
+		return ""
+	}
+	return f.g.PositionToID(position)
+}
+
+// ToKey converts an ID to its fixed-width binary key
+func (f *FrozenGenerator) ToKey(id string) ([]byte, error) {
+	pos, err := f.parse(id)
+	if err != nil {
+		return nil, err
+	}
+	return f.g.PositionToKey(pos), nil
+}
+
+// parse rejects inputs of the wrong length before decoding them. Aliases and
+// group separators are removed first, as decode does, so their spellings are
+// measured in compact form.
+func (f *FrozenGenerator) parse(id string) (int64, error) {
+	if f.g.MaxParseLength >= 0 && len(id) > f.g.MaxParseLength {
+		return f.g.decode(id) // Reports the oversized input without inspecting it
+	}
+	id = f.g.ungroup(f.g.unalias(id))
+	if f.minLength == f.maxLength && len(id) != f.maxLength {
+		return -1, &FormatError{Input: truncate(id, f.maxLength+1), Offset: -1, Reason: fmt.Sprintf("length must be %d, got %d", f.maxLength, len(id)), Err: ErrInvalidFormat}
+	}
+	if len(id) < f.minLength || len(id) > f.maxLength {
+		return -1, &FormatError{Input: truncate(id, f.maxLength+1), Offset: -1, Reason: fmt.Sprintf("length must be between %d and %d, got %d", f.minLength, f.maxLength, len(id)), Err: ErrInvalidFormat}
+	}
+	return f.g.decode(id)
+}
+
+// Debug returns a structured description of the frozen configuration
+func (f *FrozenGenerator) Debug() DebugInfo {
+	return f.g.Debug()
+}
@@ -0,0 +1,116 @@
+package doremid
+
+import (
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestFreeze(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   1,
+		EqualTemperamentDigits: 2,
+		Separator:              "-",
+	})
+	frozen := generator.Freeze()
+
+	if frozen.MaxInputLength() != 5 {
+		t.Errorf("expected max input length 5, got %d", frozen.MaxInputLength())
+	}
+	if frozen.MaxCombinations() != generator.MaxCombinations() {
+		t.Errorf("expected max combinations %d, got %d", generator.MaxCombinations(), frozen.MaxCombinations())
+	}
+
+	if pos := frozen.IDToPosition("re-00"); pos != 144 {
+		t.Errorf("expected position 144, got %d", pos)
+	}
+	if id := frozen.PositionToID(144); id != "re-00" {
+		t.Errorf("expected 're-00', got '%s'", id)
+	}
+	if key, err := frozen.ToKey("do-01"); err != nil || key[KeySize-1] != 1 {
+		t.Errorf("unexpected key %v (err: %v)", key, err)
+	}
+
+	// Changes to the original generator do not leak into the frozen copy
+	generator.Separator = "_"
+	if err := frozen.Validate("do-00"); err != nil {
+		t.Errorf("frozen generator should keep its configuration: %v", err)
+	}
+}
+
+func TestFreezeRejectsUntrustedInput(t *testing.T) {
+	frozen := NewWithDefaults().Freeze()
+
+	tests := []struct {
+		name string
+		id   string
+	}{
+		{"empty", ""},
+		{"oversized", strings.Repeat("do", 1<<20)},
+		{"one byte too long", "dodododo-000000"},
+		{"illegal character", "dodododo-0000x"},
+		{"wrong separator", "dodododo_00000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := frozen.Validate(tt.id); err == nil {
+				t.Error("expected validation error")
+			}
+			if pos := frozen.IDToPosition(tt.id); pos != -1 {
+				t.Errorf("expected -1, got %d", pos)
+			}
+		})
+	}
+
+	if id := frozen.PositionToID(frozen.MaxCombinations()); id != "" {
+		t.Errorf("expected empty ID for out-of-range position, got '%s'", id)
+	}
+	if id := frozen.PositionToID(-1); id != "" {
+		t.Errorf("expected empty ID for negative position, got '%s'", id)
+	}
+}
+
+func TestFreezeConcurrentUse(t *testing.T) {
+	frozen := NewWithDefaults().Freeze()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(offset int64) {
+			defer wg.Done()
+			for pos := offset; pos < 1000; pos += 8 {
+				if frozen.IDToPosition(frozen.PositionToID(pos)) != pos {
+					t.Errorf("round-trip failed for position %d", pos)
+				}
+			}
+		}(int64(i))
+	}
+	wg.Wait()
+}
+
+func TestFreezeAliasesAndGroups(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+		id     string
+	}{
+		{"alias", Config{InputAliases: map[string]string{"sol": "so"}}, "solsol-1a2"},
+		{"grouped", Config{GroupSize: 1, NoteGroupSeparator: ".", CharacterGroupSeparator: "."}, "so.so-1.a.2"},
+		{"compact form of grouped", Config{GroupSize: 1, NoteGroupSeparator: ".", CharacterGroupSeparator: "."}, "soso-1a2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.JustIntonationDigits, tt.config.EqualTemperamentDigits, tt.config.Separator = 2, 3, "-"
+			generator := New(tt.config)
+			expected := generator.IDToPosition(tt.id)
+			if expected < 0 {
+				t.Fatalf("expected '%s' to parse", tt.id)
+			}
+			if pos := generator.Freeze().IDToPosition(tt.id); pos != expected {
+				t.Errorf("expected position %d, got %d", expected, pos)
+			}
+		})
+	}
+}
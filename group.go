@@ -0,0 +1,148 @@
+package doremid
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Canonicalize returns id in the form the generator produces, grouped if
+// Config.GroupSize is set and compact otherwise, e.g. "doremifa_0a3b" becomes
+// "dore-mifa_0a3b" with a GroupSize of 2 and a NoteGroupSeparator of "-".
+// Returns a *FormatError if id is invalid.
+func (g *Generator) Canonicalize(id string) (string, error) {
+	compact, err := g.Compact(id)
+	if err != nil || !g.grouped() {
+		return compact, err
+	}
+	return g.group(compact), nil
+}
+
+// Compact returns id without group separators, the form a generator without
+// Config.GroupSize produces, with any Config.InputAliases spelled as the
+// notes they stand for and, with Config.LenientCase, in the configured case.
+// Both forms parse to the same position.
+// Returns a *FormatError if id is invalid.
+func (g *Generator) Compact(id string) (string, error) {
+	if _, err := g.decode(id); err != nil {
+		return "", err
+	}
+	return g.ungroup(g.unalias(id)), nil
+}
+
+// grouped reports whether IDs are chunked into groups
+func (g *Generator) grouped() bool {
+	return g.groupSize > 0 && (g.noteGroupSeparator != "" || g.characterGroupSeparator != "")
+}
+
+// groupOverhead returns the bytes group separators add to the note and the
+// character part of an ID
+func (g *Generator) groupOverhead() (notes, characters int) {
+	if g.groupSize <= 0 {
+		return 0, 0
+	}
+	if g.JustIntonationDigits > 0 {
+		notes = (g.JustIntonationDigits - 1) / g.groupSize * len(g.noteGroupSeparator)
+	}
+	if g.EqualTemperamentDigits > 0 {
+		characters = (g.EqualTemperamentDigits - 1) / g.groupSize * len(g.characterGroupSeparator)
+	}
+	return notes, characters
+}
+
+// noteGroupBreak returns the separator to place before note i, if any
+func (g *Generator) noteGroupBreak(i int) string {
+	if g.groupSize > 0 && i > 0 && i%g.groupSize == 0 {
+		return g.noteGroupSeparator
+	}
+	return ""
+}
+
+// characterGroupBreak returns the separator to place before character i, if any
+func (g *Generator) characterGroupBreak(i int) string {
+	if g.groupSize > 0 && i > 0 && i%g.groupSize == 0 {
+		return g.characterGroupSeparator
+	}
+	return ""
+}
+
+// ungroup removes the group separators from id, accepting each one where the
+// grouped form has it. It returns id unchanged if it has none or is malformed,
+// leaving decode to report the problem.
+func (g *Generator) ungroup(id string) string {
+	if !g.grouped() || !strings.HasPrefix(id, g.prefix) {
+		return id
+	}
+
+	var compact []byte
+	copied, offset := 0, len(g.prefix)
+	skip := func(sep string) {
+		if sep != "" && strings.HasPrefix(id[offset:], sep) {
+			compact = append(compact, id[copied:offset]...)
+			offset += len(sep)
+			copied = offset
+		}
+	}
+
+	for i := 0; i < g.JustIntonationDigits; i++ {
+		skip(g.noteGroupBreak(i))
+		_, width, found := g.nextNote(id, offset)
+		if !found {
+			return id
+		}
+		offset += width
+	}
+	if !strings.HasPrefix(id[offset:], g.Separator) {
+		return id
+	}
+	offset += len(g.Separator)
+	for i := 0; i < g.EqualTemperamentDigits && offset < len(id); i++ {
+		skip(g.characterGroupBreak(i))
+		offset++
+	}
+
+	if compact == nil {
+		return id
+	}
+	return string(append(compact, id[copied:]...))
+}
+
+// group inserts the group separators into a valid compact ID
+func (g *Generator) group(id string) string {
+	notes, characters := g.groupOverhead()
+	grouped := make([]byte, 0, len(id)+notes+characters)
+	grouped = append(grouped, g.prefix...)
+	offset := len(g.prefix)
+	for i := 0; i < g.JustIntonationDigits; i++ {
+		_, width, _ := g.nextNote(id, offset)
+		grouped = append(grouped, g.noteGroupBreak(i)...)
+		grouped = append(grouped, id[offset:offset+width]...)
+		offset += width
+	}
+	grouped = append(grouped, g.Separator...)
+	offset += len(g.Separator)
+	for i := 0; i < g.EqualTemperamentDigits; i++ {
+		grouped = append(grouped, g.characterGroupBreak(i)...)
+		grouped = append(grouped, id[offset])
+		offset++
+	}
+	return string(append(grouped, id[offset:]...))
+}
+
+// validateGrouping checks that group separators cannot be mistaken for notes
+// or characters
+func validateGrouping(config Config, notes []string, characters string) error {
+	if config.GroupSize < 0 {
+		return &ConfigError{Field: "GroupSize", Reason: "must not be negative"}
+	}
+	if sep := config.NoteGroupSeparator; sep != "" {
+		for _, note := range notes {
+			if strings.HasPrefix(note, sep) || strings.HasPrefix(sep, note) {
+				return &ConfigError{Field: "NoteGroupSeparator", Reason: fmt.Sprintf("must not overlap the note %q", note)}
+			}
+		}
+	}
+	if sep := config.CharacterGroupSeparator; sep != "" && strings.IndexByte(characters, sep[0]) >= 0 {
+		return &ConfigError{Field: "CharacterGroupSeparator", Reason: fmt.Sprintf("must not start with the character %q", sep[0])}
+	}
+	return nil
+}
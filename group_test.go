@@ -0,0 +1,121 @@
+package doremid
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGrouping(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:    4,
+		EqualTemperamentDigits:  5,
+		Separator:               "_",
+		GroupSize:               2,
+		NoteGroupSeparator:      "-",
+		CharacterGroupSeparator: ".",
+	})
+
+	tests := []struct {
+		name     string
+		position int64
+		grouped  string
+		compact  string
+	}{
+		{"first", 0, "dodo-dodo_00.00.0", "dodododo_00000"},
+		{"last", 597445631, "titi-titi_bb.bb.b", "titititi_bbbbb"},
+		{"middle", 2*248832 + 3722, "dodo-domi_02.1a.2", "dododomi_021a2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if id := generator.PositionToID(tt.position); id != tt.grouped {
+				t.Errorf("expected '%s', got '%s'", tt.grouped, id)
+			}
+			for _, id := range []string{tt.grouped, tt.compact} {
+				if pos := generator.IDToPosition(id); pos != tt.position {
+					t.Errorf("expected position %d for '%s', got %d", tt.position, id, pos)
+				}
+				if pos, err := generator.IDToPositionBig(id); err != nil || pos.Int64() != tt.position {
+					t.Errorf("expected big position %d for '%s', got %v (err: %v)", tt.position, id, pos, err)
+				}
+			}
+			if canonical, err := generator.Canonicalize(tt.compact); err != nil || canonical != tt.grouped {
+				t.Errorf("expected '%s', got '%s' (err: %v)", tt.grouped, canonical, err)
+			}
+			if compact, err := generator.Compact(tt.grouped); err != nil || compact != tt.compact {
+				t.Errorf("expected '%s', got '%s' (err: %v)", tt.compact, compact, err)
+			}
+		})
+	}
+
+	id := generator.NewID()
+	if len(id) != len("dodo-dodo_00.00.0") || generator.Validate(id) != nil {
+		t.Errorf("expected a grouped random ID, got '%s'", id)
+	}
+	if parsed, err := generator.Parse("doremifa_0a.3b.1"); err != nil || parsed.Just != "doremifa" || parsed.Equal != "0a3b1" {
+		t.Errorf("expected the compact parts of a partly grouped ID, got %+v (err: %v)", parsed, err)
+	}
+	for _, invalid := range []string{"do-re-mifa_0a.3b.1", "dore-mifa_0a.3b.1.", "dore--mifa_0a.3b.1", "dore-mifa_0a-3b.1"} {
+		if err := generator.Validate(invalid); !errors.Is(err, ErrInvalidID) {
+			t.Errorf("expected '%s' to be invalid, got %v", invalid, err)
+		}
+	}
+	if _, err := generator.Canonicalize("invalid"); !errors.Is(err, ErrInvalidID) {
+		t.Errorf("expected ErrInvalidID, got %v", err)
+	}
+}
+
+func TestGroupingWithChecksumAndPrefix(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   3,
+		EqualTemperamentDigits: 4,
+		Separator:              "-",
+		GroupSize:              2,
+		NoteGroupSeparator:     " ",
+		Checksum:               true,
+		Prefix:                 "ord",
+	})
+	plain := New(Config{JustIntonationDigits: 3, EqualTemperamentDigits: 4, Separator: "-", Checksum: true, Prefix: "ord"})
+
+	for _, pos := range []int64{0, 12345, 1234567} {
+		id := generator.PositionToID(pos)
+		compact, err := generator.Compact(id)
+		if err != nil || compact != plain.PositionToID(pos) {
+			t.Errorf("expected '%s', got '%s' (err: %v)", plain.PositionToID(pos), compact, err)
+		}
+		if generator.IDToPosition(id) != pos || generator.IDToPosition(compact) != pos {
+			t.Errorf("expected '%s' and '%s' at position %d", id, compact, pos)
+		}
+	}
+	if id := generator.PositionToID(12345); id[:9] != "ord_dodo " {
+		t.Errorf("expected the notes grouped after the prefix, got '%s'", id)
+	}
+
+	// Without a separator a part stays compact
+	if id := plain.PositionToID(12345); generator.Debug().Layout[1].Length != plain.Debug().Layout[1].Length+1 {
+		t.Errorf("expected the grouped note part of '%s' to be one byte longer", id)
+	}
+}
+
+func TestGroupingConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+		field  string
+	}{
+		{"negative size", Config{GroupSize: -1}, "GroupSize"},
+		{"separator starts a note", Config{GroupSize: 2, NoteGroupSeparator: "d"}, "NoteGroupSeparator"},
+		{"note starts the separator", Config{GroupSize: 2, NoteGroupSeparator: "dot"}, "NoteGroupSeparator"},
+		{"separator starts with a character", Config{GroupSize: 2, CharacterGroupSeparator: "a-"}, "CharacterGroupSeparator"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewE(tt.config)
+			var configErr *ConfigError
+			if !errors.As(err, &configErr) || configErr.Field != tt.field {
+				t.Errorf("expected ConfigError for %s, got %v", tt.field, err)
+			}
+		})
+	}
+}
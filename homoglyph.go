@@ -0,0 +1,144 @@
+package doremid
+
+import (
+	"bytes"
+	"strings"
+	"unicode/utf8"
+)
+
+// homoglyphs maps non-ASCII characters to the ASCII character they imitate,
+// covering Cyrillic, Greek and Armenian look-alikes of Latin letters and dash
+// variants of the hyphen. Fullwidth forms, mathematical alphanumerics and
+// invisible characters are handled by foldRune.
+var homoglyphs = map[rune]rune{
+	// Cyrillic
+	'а': 'a', 'е': 'e', 'һ': 'h', 'і': 'i', 'ј': 'j', 'ӏ': 'l', 'о': 'o', 'р': 'p',
+	'с': 'c', 'ѕ': 's', 'у': 'y', 'х': 'x', 'ԁ': 'd', 'ԛ': 'q', 'ԝ': 'w',
+	'А': 'A', 'В': 'B', 'Е': 'E', 'І': 'I', 'Ј': 'J', 'К': 'K', 'М': 'M', 'Н': 'H',
+	'О': 'O', 'Р': 'P', 'С': 'C', 'Ѕ': 'S', 'Т': 'T', 'У': 'Y', 'Х': 'X',
+
+	// Greek
+	'α': 'a', 'ι': 'i', 'κ': 'k', 'ν': 'v', 'ο': 'o', 'ρ': 'p', 'υ': 'u', 'ϲ': 'c', 'ϳ': 'j',
+	'Α': 'A', 'Β': 'B', 'Ε': 'E', 'Ζ': 'Z', 'Η': 'H', 'Ι': 'I', 'Κ': 'K', 'Μ': 'M',
+	'Ν': 'N', 'Ο': 'O', 'Ρ': 'P', 'Τ': 'T', 'Υ': 'Y', 'Χ': 'X',
+
+	// Armenian and Latin extensions
+	'հ': 'h', 'ո': 'n', 'ս': 'u', 'օ': 'o', 'ı': 'i', 'ȷ': 'j', 'ɑ': 'a', 'ɡ': 'g', 'ℎ': 'h',
+
+	// Dashes and minus signs
+	'‐': '-', '‑': '-', '‒': '-', '–': '-', '—': '-', '―': '-', '−': '-', '﹘': '-', '﹣': '-',
+}
+
+// ContainsHomoglyphs reports whether s contains characters that look like ASCII
+// letters, digits or punctuation but are not, such as a Cyrillic а or a
+// fullwidth digit, or invisible characters such as zero-width spaces. Pasted IDs
+// containing any are likely spoofed or mangled by a word processor.
+func ContainsHomoglyphs(s string) bool {
+	return containsHomoglyphs(s, nil)
+}
+
+// FoldHomoglyphs replaces every homoglyph in s with the ASCII character it
+// imitates and removes invisible characters, so that a pasted ID can be parsed.
+// Characters it does not recognize are kept, so parsing still rejects them.
+// Folding is for input typed or pasted by people; use ContainsHomoglyphs to
+// reject such input instead where a spoofed ID must not resolve.
+func FoldHomoglyphs(s string) string {
+	return foldHomoglyphs(s, nil)
+}
+
+// ContainsHomoglyphs is like the package function but ignores characters of
+// the generator's own notes, aliases, separators and prefix
+func (g *Generator) ContainsHomoglyphs(s string) bool {
+	return containsHomoglyphs(s, g.ownsRune)
+}
+
+// FoldHomoglyphs is like the package function but keeps characters of the
+// generator's own notes, aliases, separators and prefix wherever they appear,
+// so non-ASCII notes such as "до ре" survive folding
+func (g *Generator) FoldHomoglyphs(s string) string {
+	return foldHomoglyphs(s, g.ownsRune)
+}
+
+// containsHomoglyphs reports whether s contains a homoglyph not kept by keep,
+// which may be nil
+func containsHomoglyphs(s string, keep func(rune) bool) bool {
+	for _, r := range s {
+		if foldRune(r) != r && (keep == nil || !keep(r)) {
+			return true
+		}
+	}
+	return false
+}
+
+// foldHomoglyphs folds the homoglyphs of s not kept by keep, which may be nil
+func foldHomoglyphs(s string, keep func(rune) bool) string {
+	if !containsHomoglyphs(s, keep) {
+		return s
+	}
+
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range s {
+		if keep != nil && keep(r) {
+			b.WriteRune(r)
+		} else if folded := foldRune(r); folded >= 0 {
+			b.WriteRune(folded)
+		}
+	}
+	return b.String()
+}
+
+// ownsRune reports whether r is a non-ASCII character of the generator's
+// notes, aliases, separators or prefix
+func (g *Generator) ownsRune(r rune) bool {
+	if r < utf8.RuneSelf {
+		return false
+	}
+	for _, symbol := range [...]string{g.prefix, g.Separator, g.noteGroupSeparator, g.characterGroupSeparator} {
+		if strings.ContainsRune(symbol, r) {
+			return true
+		}
+	}
+	for _, note := range g.justIntonationBytes {
+		if bytes.ContainsRune(note, r) {
+			return true
+		}
+	}
+	for _, alias := range g.aliases {
+		if strings.ContainsRune(alias.spelling, r) {
+			return true
+		}
+	}
+	return false
+}
+
+// foldRune returns the ASCII character r imitates, -1 if r is invisible, or r
+// itself if it is not a homoglyph
+func foldRune(r rune) rune {
+	if r < 0x80 {
+		return r
+	}
+	if folded, ok := homoglyphs[r]; ok {
+		return folded
+	}
+
+	switch {
+	case r == '\u00ad', r == '\u200b', r == '\u200c', r == '\u200d', r == '\u2060', r == '\ufeff':
+		// Soft hyphen, zero-width spaces and joiners, word joiner and byte order mark
+		return -1
+	case r >= 0xff01 && r <= 0xff5e:
+		// Fullwidth forms of ASCII
+		return r - 0xff01 + '!'
+	case r >= 0x1d400 && r <= 0x1d6a3:
+		// Mathematical letters: runs of A-Z then a-z in each style
+		i := (r - 0x1d400) % 52
+		if i < 26 {
+			return 'A' + i
+		}
+		return 'a' + i - 26
+	case r >= 0x1d7ce && r <= 0x1d7ff:
+		// Mathematical digits: runs of 0-9 in each style
+		return '0' + (r-0x1d7ce)%10
+	}
+	return r
+}
@@ -0,0 +1,86 @@
+package doremid
+
+import "testing"
+
+func TestFoldHomoglyphs(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"ascii", "domi-1a2", "domi-1a2"},
+		{"cyrillic o and i", "dоmі-1a2", "domi-1a2"},
+		{"greek omicron", "dοmi-1a2", "domi-1a2"},
+		{"armenian o", "dօmi-1a2", "domi-1a2"},
+		{"en dash", "domi–1a2", "domi-1a2"},
+		{"minus sign", "domi−1a2", "domi-1a2"},
+		{"fullwidth", "ｄｏmi-１a２", "domi-1a2"},
+		{"mathematical bold", "domi-\U0001d7cf\U0001d41a2", "domi-1a2"},
+		{"mathematical monospace", "\U0001d68d\U0001d698mi-1a2", "domi-1a2"},
+		{"zero-width space", "do\u200bmi-1a2", "domi-1a2"},
+		{"byte order mark", "\ufeffdomi-1a2", "domi-1a2"},
+		{"uppercase cyrillic", "ВОХ", "BOX"},
+		{"unrelated letters kept", "domï-1a2", "domï-1a2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FoldHomoglyphs(tt.input); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+			if contains := ContainsHomoglyphs(tt.input); contains != (tt.input != tt.expected) {
+				t.Errorf("expected ContainsHomoglyphs to be %v", !contains)
+			}
+		})
+	}
+}
+
+func TestFoldHomoglyphsBeforeParsing(t *testing.T) {
+	generator := New(Config{JustIntonationDigits: 2, EqualTemperamentDigits: 3, Separator: "-"})
+	spoofed := "dоmі–1a2"
+
+	if err := generator.Validate(spoofed); err == nil {
+		t.Error("expected a spoofed ID to be invalid")
+	}
+	if !ContainsHomoglyphs(spoofed) {
+		t.Error("expected a spoofed ID to contain homoglyphs")
+	}
+	if pos, err := generator.IDToPositionE(FoldHomoglyphs(spoofed)); err != nil || pos != 3722 {
+		t.Errorf("expected position 3722 after folding, got %d (err: %v)", pos, err)
+	}
+}
+
+func TestGeneratorFoldHomoglyphs(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 3,
+		Separator:              "-",
+		Notes:                  "до ре ми фа со ля си",
+	})
+	pasted := "доми–1a2\u200b" // Cyrillic notes, en dash and zero-width space
+
+	if !generator.ContainsHomoglyphs(pasted) || generator.ContainsHomoglyphs("доми-1a2") {
+		t.Error("expected only characters outside the alphabet to count as homoglyphs")
+	}
+	folded := generator.FoldHomoglyphs(pasted)
+	if folded != "доми-1a2" {
+		t.Errorf("expected the notes kept and the rest folded, got %q", folded)
+	}
+	if pos, err := generator.IDToPositionE(folded); err != nil || pos != 3722 {
+		t.Errorf("expected position 3722 after folding, got %d (err: %v)", pos, err)
+	}
+	if FoldHomoglyphs(pasted) == folded {
+		t.Error("expected the package function to fold the notes too")
+	}
+
+	// Characters of the alphabet are kept wherever they appear
+	if kept := generator.FoldHomoglyphs("доми-1а2"); kept != "доми-1а2" {
+		t.Errorf("expected the Cyrillic а of фа to be kept, got %q", kept)
+	}
+
+	// ASCII alphabets fold like the package function
+	ascii := New(Config{JustIntonationDigits: 2, EqualTemperamentDigits: 3, Separator: "-"})
+	if spoofed := "dоmі–1a2"; ascii.FoldHomoglyphs(spoofed) != FoldHomoglyphs(spoofed) {
+		t.Errorf("expected %q, got %q", FoldHomoglyphs(spoofed), ascii.FoldHomoglyphs(spoofed))
+	}
+}
@@ -0,0 +1,179 @@
+package doremid
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// HybridConfig configures a HybridGenerator
+type HybridConfig struct {
+	// Store persists the next unreserved position so uniqueness survives restarts
+	Store CounterStore
+
+	// Name is the counter name used in Store
+	Name string
+
+	// Epoch is the start of the first time bucket. It must be set.
+	Epoch time.Time
+
+	// BucketSize is the duration of one time bucket. Zero uses one hour.
+	BucketSize time.Duration
+
+	// ReserveSize is the number of positions reserved per write to Store.
+	// Larger values mean fewer writes; unused reserved positions are skipped
+	// after a restart. Zero or negative reserves one position at a time.
+	ReserveSize int64
+
+	// Clock tells the time. Nil uses the system clock.
+	Clock Clock
+}
+
+// HybridGenerator issues roughly time-sortable IDs whose musical note part is a
+// coarse time bucket and whose character part is a counter within that bucket.
+//
+// The counter is persisted before IDs are handed out, so uniqueness survives
+// crashes and does not depend on clock precision: if the clock goes backwards
+// or a bucket's counter runs out, issuance continues from the last position, so
+// IDs stay strictly increasing. Reserved and filtered positions are skipped the
+// same way. Each bucket holds characters^EqualTemperamentDigits IDs and the
+// generator covers notes^JustIntonationDigits buckets from the epoch, counting
+// the characters and notes of its alphabet.
+//
+// A HybridGenerator uses the full ID space of its generator and ignores any
+// restriction. It is safe for concurrent use.
+type HybridGenerator struct {
+	g           *Generator
+	store       CounterStore
+	name        string
+	epoch       time.Time
+	bucketSize  time.Duration
+	reserveSize int64
+	perBucket   int64
+	clock       Clock
+
+	mu     sync.Mutex
+	loaded bool
+	next   int64 // Next position to issue
+	limit  int64 // First position not covered by the persisted reservation
+}
+
+// Hybrid returns a HybridGenerator issuing IDs with the configuration of g.
+// Returns a *ConfigError if Store, Name or Epoch is missing or BucketSize is negative.
+func (g *Generator) Hybrid(config HybridConfig) (*HybridGenerator, error) {
+	switch {
+	case config.Store == nil:
+		return nil, &ConfigError{Field: "Store", Reason: "must not be nil"}
+	case config.Name == "":
+		return nil, &ConfigError{Field: "Name", Reason: "must not be empty"}
+	case config.Epoch.IsZero():
+		return nil, &ConfigError{Field: "Epoch", Reason: "must be set"}
+	case config.BucketSize < 0:
+		return nil, &ConfigError{Field: "BucketSize", Reason: "must not be negative"}
+	}
+
+	bucketSize := config.BucketSize
+	if bucketSize == 0 {
+		bucketSize = time.Hour
+	}
+
+	return &HybridGenerator{
+		g:           g,
+		store:       config.Store,
+		name:        config.Name,
+		epoch:       config.Epoch,
+		bucketSize:  bucketSize,
+		reserveSize: max(config.ReserveSize, 1),
+		perBucket:   int64(g.intPow(g.equalTemperamentLen, g.EqualTemperamentDigits)),
+		clock:       clockOrWall(config.Clock),
+	}, nil
+}
+
+// NewID issues the next ID for the current time bucket.
+// Returns ErrSpaceExhausted once the clock passes the last bucket or the ID space
+// is used up, a *RangeError if the clock is before the epoch, or any error
+// returned by the store.
+func (h *HybridGenerator) NewID(ctx context.Context) (string, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !h.loaded {
+		var next int64
+		err := h.g.withRetry(ctx, func() (err error) {
+			next, _, err = h.store.Load(ctx, h.name)
+			return err
+		})
+		if err != nil {
+			return "", err
+		}
+		h.next, h.limit, h.loaded = next, next, true
+	}
+
+	now := h.clock.Now()
+	if now.Before(h.epoch) {
+		return "", &RangeError{Position: -1, Min: 0, Max: h.buckets()}
+	}
+	bucket := int64(now.Sub(h.epoch) / h.bucketSize)
+	if bucket >= h.buckets() {
+		return "", ErrSpaceExhausted
+	}
+
+	// Reserved and filtered positions are skipped, into the next bucket if
+	// the rest of this one is blocked
+	space := h.g.MaxCombinations()
+	pos := h.g.nextAllowed(max(h.next, bucket*h.perBucket))
+	if pos >= space {
+		return "", ErrSpaceExhausted
+	}
+
+	if pos >= h.limit {
+		limit := min(pos+h.reserveSize, space)
+		if err := h.g.withRetry(ctx, func() error { return h.store.Save(ctx, h.name, limit) }); err != nil {
+			return "", err
+		}
+		h.limit = limit
+	}
+
+	h.next = pos + 1
+	return h.g.PositionToID(pos), nil
+}
+
+// Bucket returns the start of the time bucket id was issued in.
+// Returns a *FormatError if id is invalid.
+func (h *HybridGenerator) Bucket(id string) (time.Time, error) {
+	pos, err := h.g.decode(id)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return h.epoch.Add(time.Duration(pos/h.perBucket) * h.bucketSize), nil
+}
+
+// Counter returns the counter part of id, its index within its time bucket.
+// Returns a *FormatError if id is invalid.
+func (h *HybridGenerator) Counter(id string) (int64, error) {
+	pos, err := h.g.decode(id)
+	if err != nil {
+		return -1, err
+	}
+	return pos % h.perBucket, nil
+}
+
+// BucketRange returns the positions of the time bucket containing t, e.g. for
+// range scans over binary keys. The range is empty if t is outside the covered buckets.
+func (h *HybridGenerator) BucketRange(t time.Time) Range {
+	bucket := int64(t.Sub(h.epoch) / h.bucketSize)
+	if t.Before(h.epoch) || bucket >= h.buckets() {
+		return Range{}
+	}
+	return Range{Start: bucket * h.perBucket, End: (bucket + 1) * h.perBucket}
+}
+
+// Until returns the end of the last time bucket the generator covers
+func (h *HybridGenerator) Until() time.Time {
+	return h.epoch.Add(time.Duration(h.buckets()) * h.bucketSize)
+}
+
+// buckets returns the number of time buckets the ID space covers
+func (h *HybridGenerator) buckets() int64 {
+	return h.g.MaxCombinations() / h.perBucket
+}
@@ -0,0 +1,129 @@
+package doremid
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestHybridGenerator(t *testing.T) {
+	ctx := context.Background()
+	epoch := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	generator := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 2,
+		Separator:              "-",
+	})
+	store := NewMemoryCounterStore()
+
+	clock := epoch.Add(90 * time.Minute)
+	newHybrid := func() *HybridGenerator {
+		h, err := generator.Hybrid(HybridConfig{Store: store, Name: "hybrid", Epoch: epoch, ReserveSize: 10})
+		if err != nil {
+			t.Fatal(err)
+		}
+		h.clock = ClockFunc(func() time.Time { return clock })
+		return h
+	}
+
+	h := newHybrid()
+	first, _ := h.NewID(ctx)
+	second, _ := h.NewID(ctx)
+	if first != "dore-00" || second != "dore-01" {
+		t.Errorf("expected 'dore-00' and 'dore-01' in bucket 1, got '%s' and '%s'", first, second)
+	}
+
+	bucket, err := h.Bucket(second)
+	if err != nil || !bucket.Equal(epoch.Add(time.Hour)) {
+		t.Errorf("expected bucket %v, got %v (err: %v)", epoch.Add(time.Hour), bucket, err)
+	}
+	if counter, _ := h.Counter(second); counter != 1 {
+		t.Errorf("expected counter 1, got %d", counter)
+	}
+	if r := h.BucketRange(clock); !r.Contains(generator.IDToPosition(first)) || r.Len() != 144 {
+		t.Errorf("unexpected bucket range %+v", r)
+	}
+
+	// After a crash the unused reservation is skipped, never reissued
+	restarted := newHybrid()
+	if id, _ := restarted.NewID(ctx); generator.IDToPosition(id) != 144+10 {
+		t.Errorf("expected position 154 after restart, got '%s'", id)
+	}
+
+	// A clock going backwards keeps IDs increasing
+	clock = epoch
+	if id, _ := restarted.NewID(ctx); generator.IDToPosition(id) != 155 {
+		t.Errorf("expected position 155 after clock moved back, got '%s'", id)
+	}
+
+	// A later bucket jumps ahead
+	clock = epoch.Add(3 * time.Hour)
+	if id, _ := restarted.NewID(ctx); id != "dofa-00" {
+		t.Errorf("expected 'dofa-00', got '%s'", id)
+	}
+}
+
+func TestHybridGeneratorSkipsReserved(t *testing.T) {
+	ctx := context.Background()
+	epoch := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	reserved := []int64{0, 1, 2}
+	for pos := int64(12); pos < 24; pos++ {
+		reserved = append(reserved, pos) // All of bucket 1
+	}
+	generator := New(Config{
+		JustIntonationDigits:   1,
+		EqualTemperamentDigits: 1,
+		Separator:              "-",
+		ReservedPositions:      reserved,
+	})
+	h, _ := generator.Hybrid(HybridConfig{Store: NewMemoryCounterStore(), Name: "hybrid", Epoch: epoch})
+	clock := epoch
+	h.clock = ClockFunc(func() time.Time { return clock })
+
+	for _, expected := range []int64{3, 4} {
+		if id, err := h.NewID(ctx); err != nil || generator.IDToPosition(id) != expected {
+			t.Errorf("expected position %d, got '%s' (err: %v)", expected, id, err)
+		}
+	}
+	clock = epoch.Add(time.Hour)
+	if id, err := h.NewID(ctx); err != nil || generator.IDToPosition(id) != 24 {
+		t.Errorf("expected a reserved bucket to spill into the next, got '%s' (err: %v)", id, err)
+	}
+}
+
+func TestHybridGeneratorLimits(t *testing.T) {
+	ctx := context.Background()
+	epoch := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	generator := New(Config{
+		JustIntonationDigits:   1,
+		EqualTemperamentDigits: 1,
+		Separator:              "-",
+	})
+
+	h, _ := generator.Hybrid(HybridConfig{Store: NewMemoryCounterStore(), Name: "hybrid", Epoch: epoch})
+
+	h.clock = ClockFunc(func() time.Time { return epoch.Add(-time.Minute) })
+	if _, err := h.NewID(ctx); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("expected ErrOutOfRange before the epoch, got %v", err)
+	}
+
+	h.clock = ClockFunc(func() time.Time { return epoch.Add(7 * time.Hour) })
+	if _, err := h.NewID(ctx); !errors.Is(err, ErrSpaceExhausted) {
+		t.Errorf("expected ErrSpaceExhausted after the last bucket, got %v", err)
+	}
+
+	h.clock = ClockFunc(func() time.Time { return epoch.Add(6 * time.Hour) })
+	for i := 0; i < 12; i++ {
+		if _, err := h.NewID(ctx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if _, err := h.NewID(ctx); !errors.Is(err, ErrSpaceExhausted) {
+		t.Errorf("expected ErrSpaceExhausted once the space is used up, got %v", err)
+	}
+
+	if _, err := generator.Hybrid(HybridConfig{Store: NewMemoryCounterStore(), Name: "hybrid"}); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig without an epoch, got %v", err)
+	}
+}
@@ -0,0 +1,173 @@
+package doremid
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// ID is a validated identifier together with its position.
+// The zero value is an empty ID with no position.
+//
+// ID implements fmt.Formatter so log lines and debuggers show useful detail:
+//
+//	%s   canonical form                domi-1a2
+//	%v   canonical form and position   domi-1a2 (3722)
+//	%q   quoted canonical form         "domi-1a2"
+//	%x   position in hexadecimal       e8a (%X for upper case, %d for decimal)
+//	%#v  Go syntax                     doremid.ID{Value:"domi-1a2", Position:3722}
+//
+// ID implements encoding.TextMarshaler and json.Marshaler, so it can be embedded
+// in API structs. Decoding validates against DefaultConfig; use IDFor to validate
+// against another configuration. The zero ID encodes as an empty string.
+type ID struct {
+	value    string
+	position int64
+}
+
+// ParseID validates s and returns it as an ID in canonical form, so aliases,
+// group separators and case accepted by the generator do not carry over.
+// Returns a *FormatError if s is invalid.
+func (g *Generator) ParseID(s string) (ID, error) {
+	pos, err := g.decode(s)
+	if err != nil {
+		return ID{}, err
+	}
+	return ID{value: g.PositionToID(pos), position: pos}, nil
+}
+
+// IDAt returns the ID at position, or a *RangeError if the position is outside the ID space
+func (g *Generator) IDAt(position int64) (ID, error) {
+	value, err := g.PositionToIDE(position)
+	if err != nil {
+		return ID{}, err
+	}
+	return ID{value: value, position: position}, nil
+}
+
+// String returns the canonical form of the ID
+func (id ID) String() string {
+	return id.value
+}
+
+// Position returns the position of the ID, -1 for the zero ID
+func (id ID) Position() int64 {
+	if id.IsZero() {
+		return -1
+	}
+	return id.position
+}
+
+// IsZero reports whether id is the zero ID
+func (id ID) IsZero() bool {
+	return id.value == ""
+}
+
+// GoString returns a Go syntax representation of the ID for debugging
+func (id ID) GoString() string {
+	return fmt.Sprintf("doremid.ID{Value:%q, Position:%d}", id.value, id.Position())
+}
+
+// Format implements fmt.Formatter. Width and flags are honoured for every verb.
+func (id ID) Format(f fmt.State, verb rune) {
+	switch verb {
+	case 's', 'q':
+		fmt.Fprintf(f, fmt.FormatString(f, verb), id.value)
+	case 'v':
+		if f.Flag('#') {
+			fmt.Fprint(f, id.GoString())
+			return
+		}
+		s := id.value
+		if !id.IsZero() {
+			s += " (" + strconv.FormatInt(id.position, 10) + ")"
+		}
+		fmt.Fprintf(f, fmt.FormatString(f, 's'), s)
+	case 'x', 'X', 'd':
+		fmt.Fprintf(f, fmt.FormatString(f, verb), id.Position())
+	default:
+		fmt.Fprintf(f, "%%!%c(doremid.ID=%s)", verb, id.value)
+	}
+}
+
+// defaultGenerator validates IDs decoded into an ID
+var defaultGenerator = New(DefaultConfig())
+
+// MarshalText implements encoding.TextMarshaler
+func (id ID) MarshalText() ([]byte, error) {
+	return []byte(id.value), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, validating text against
+// DefaultConfig. Empty text decodes to the zero ID.
+func (id *ID) UnmarshalText(text []byte) error {
+	return id.unmarshalText(defaultGenerator, text)
+}
+
+// MarshalJSON implements json.Marshaler
+func (id ID) MarshalJSON() ([]byte, error) {
+	return json.Marshal(id.value)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, validating the string against
+// DefaultConfig. null leaves the ID unchanged and "" decodes to the zero ID.
+func (id *ID) UnmarshalJSON(data []byte) error {
+	return id.unmarshalJSON(defaultGenerator, data)
+}
+
+func (id *ID) unmarshalText(g *Generator, text []byte) error {
+	if len(text) == 0 {
+		*id = ID{}
+		return nil
+	}
+	parsed, err := g.ParseID(string(text))
+	if err != nil {
+		return err
+	}
+	*id = parsed
+	return nil
+}
+
+func (id *ID) unmarshalJSON(g *Generator, data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("doremid: ID must be a JSON string: %w", err)
+	}
+	return id.unmarshalText(g, []byte(s))
+}
+
+// Scheme selects the generator an IDFor is validated against. Implementations
+// are usually empty structs returning a package-level generator:
+//
+//	type orderIDs struct{}
+//
+//	func (orderIDs) Generator() *doremid.Generator { return orderGenerator }
+type Scheme interface {
+	Generator() *Generator
+}
+
+// IDFor is an ID that validates against the generator of scheme S when decoded,
+// so API structs get validation for their own configuration:
+//
+//	type Order struct {
+//		ID doremid.IDFor[orderIDs] `json:"id"`
+//	}
+type IDFor[S Scheme] struct {
+	ID
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, validating text against S
+func (id *IDFor[S]) UnmarshalText(text []byte) error {
+	var scheme S
+	return id.ID.unmarshalText(scheme.Generator(), text)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, validating the string against S
+func (id *IDFor[S]) UnmarshalJSON(data []byte) error {
+	var scheme S
+	return id.ID.unmarshalJSON(scheme.Generator(), data)
+}
@@ -0,0 +1,184 @@
+package doremid
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestParseID(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 3,
+		Separator:              "-",
+	})
+
+	id, err := generator.ParseID("domi-1a2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if id.String() != "domi-1a2" || id.Position() != generator.IDToPosition("domi-1a2") {
+		t.Errorf("unexpected ID %#v", id)
+	}
+
+	if _, err := generator.ParseID("domi-1z2"); !errors.Is(err, ErrInvalidID) {
+		t.Errorf("expected ErrInvalidID, got %v", err)
+	}
+
+	at, err := generator.IDAt(id.Position())
+	if err != nil || at != id {
+		t.Errorf("expected IDAt to return %#v, got %#v (%v)", id, at, err)
+	}
+	if _, err := generator.IDAt(generator.MaxCombinations()); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("expected ErrOutOfRange, got %v", err)
+	}
+
+	// Accepted spellings parse to the canonical form and compare equal
+	lenient := New(Config{
+		JustIntonationDigits:   4,
+		EqualTemperamentDigits: 3,
+		Separator:              "-",
+		GroupSize:              2,
+		NoteGroupSeparator:     ".",
+		LenientCase:            true,
+		InputAliases:           map[string]string{"sol": "so"},
+	})
+	canonical, err := lenient.ParseID("dodo.sodo-1a2")
+	if err != nil || canonical.String() != "dodo.sodo-1a2" {
+		t.Fatalf("expected 'dodo.sodo-1a2', got %#v (err: %v)", canonical, err)
+	}
+	for _, s := range []string{"dodosodo-1a2", "DODO.SOLDO-1A2"} {
+		parsed, err := lenient.ParseID(s)
+		if err != nil || parsed != canonical {
+			t.Errorf("expected %#v for '%s', got %#v (err: %v)", canonical, s, parsed, err)
+		}
+	}
+
+	var zero ID
+	if !zero.IsZero() || zero.Position() != -1 {
+		t.Errorf("unexpected zero ID %#v", zero)
+	}
+}
+
+func TestIDFormat(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 3,
+		Separator:              "-",
+	})
+	id, _ := generator.IDAt(1234)
+
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{"%s", "dodo-86a"},
+		{"%v", "dodo-86a (1234)"},
+		{"%q", `"dodo-86a"`},
+		{"%x", "4d2"},
+		{"%X", "4D2"},
+		{"%#x", "0x4d2"},
+		{"%d", "1234"},
+		{"%10s", "  dodo-86a"},
+		{"%-10s|", "dodo-86a  |"},
+		{"%#v", `doremid.ID{Value:"dodo-86a", Position:1234}`},
+		{"%z", "%!z(doremid.ID=dodo-86a)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			if got := fmt.Sprintf(tt.format, id); got != tt.expected {
+				t.Errorf("expected '%s', got '%s'", tt.expected, got)
+			}
+		})
+	}
+
+	if got := fmt.Sprintf("%v", ID{}); got != "" {
+		t.Errorf("expected empty string for zero ID, got '%s'", got)
+	}
+}
+
+func TestIDMarshaling(t *testing.T) {
+	id, _ := NewWithDefaults().ParseID("domifaso-1a2b3")
+
+	text, err := id.MarshalText()
+	if err != nil || string(text) != "domifaso-1a2b3" {
+		t.Errorf("expected 'domifaso-1a2b3', got '%s' (err: %v)", text, err)
+	}
+
+	type payload struct {
+		ID       ID            `json:"id"`
+		Optional ID            `json:"optional"`
+		Keyed    map[ID]string `json:"keyed"`
+	}
+	data, err := json.Marshal(payload{ID: id, Keyed: map[ID]string{id: "x"}})
+	if err != nil || string(data) != `{"id":"domifaso-1a2b3","optional":"","keyed":{"domifaso-1a2b3":"x"}}` {
+		t.Errorf("unexpected JSON %s (err: %v)", data, err)
+	}
+
+	var decoded payload
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.ID != id || !decoded.Optional.IsZero() || decoded.Keyed[id] != "x" {
+		t.Errorf("unexpected round trip %+v", decoded)
+	}
+
+	tests := []struct {
+		name     string
+		json     string
+		expected error
+	}{
+		{"null", `{"id":null}`, nil},
+		{"invalid ID", `{"id":"domi-1a2"}`, ErrInvalidID},
+		{"bad character", `{"id":"domifaso-1a2bz"}`, ErrBadCharacter},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p payload
+			err := json.Unmarshal([]byte(tt.json), &p)
+			if tt.expected == nil && err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+			if tt.expected != nil && !errors.Is(err, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+
+	if err := json.Unmarshal([]byte(`{"id":3722}`), &decoded); err == nil {
+		t.Error("expected error for a non-string ID")
+	}
+}
+
+type shortIDs struct{}
+
+var shortGenerator = New(Config{JustIntonationDigits: 2, EqualTemperamentDigits: 3, Separator: "-"})
+
+func (shortIDs) Generator() *Generator { return shortGenerator }
+
+func TestIDForMarshaling(t *testing.T) {
+	type order struct {
+		ID IDFor[shortIDs] `json:"id"`
+	}
+
+	var o order
+	if err := json.Unmarshal([]byte(`{"id":"domi-1a2"}`), &o); err != nil {
+		t.Fatal(err)
+	}
+	if o.ID.Position() != 3722 {
+		t.Errorf("expected position 3722, got %d", o.ID.Position())
+	}
+	if data, _ := json.Marshal(o); string(data) != `{"id":"domi-1a2"}` {
+		t.Errorf("unexpected JSON %s", data)
+	}
+
+	if err := json.Unmarshal([]byte(`{"id":"domifaso-1a2b3"}`), &o); !errors.Is(err, ErrInvalidID) {
+		t.Errorf("expected ErrInvalidID for another configuration, got %v", err)
+	}
+	if err := o.ID.UnmarshalText([]byte("fati-bb0")); err != nil || o.ID.String() != "fati-bb0" {
+		t.Errorf("expected 'fati-bb0', got '%s' (err: %v)", o.ID, err)
+	}
+}
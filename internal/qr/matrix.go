@@ -0,0 +1,262 @@
+package qr
+
+// matrix is a symbol under construction
+type matrix struct {
+	size     int
+	version  int
+	dark     [][]bool
+	reserved [][]bool // Function patterns and format areas, never masked
+}
+
+// newMatrix returns a symbol of version v with its function patterns drawn
+func newMatrix(v int) *matrix {
+	size := 17 + 4*v
+	m := &matrix{size: size, version: v, dark: make([][]bool, size), reserved: make([][]bool, size)}
+	for y := range size {
+		m.dark[y] = make([]bool, size)
+		m.reserved[y] = make([]bool, size)
+	}
+
+	// Finder patterns with their separators
+	for _, corner := range [][2]int{{0, 0}, {size - 7, 0}, {0, size - 7}} {
+		for dy := -1; dy <= 7; dy++ {
+			for dx := -1; dx <= 7; dx++ {
+				x, y := corner[0]+dx, corner[1]+dy
+				if x < 0 || y < 0 || x >= size || y >= size {
+					continue
+				}
+				ring := max(abs(dx-3), abs(dy-3))
+				m.set(x, y, ring != 2 && ring != 4)
+			}
+		}
+	}
+
+	// Alignment patterns, except where they would overlap a finder
+	centres := versions[v].alignment
+	for _, cy := range centres {
+		for _, cx := range centres {
+			if m.reserved[cy][cx] {
+				continue
+			}
+			for dy := -2; dy <= 2; dy++ {
+				for dx := -2; dx <= 2; dx++ {
+					m.set(cx+dx, cy+dy, max(abs(dx), abs(dy)) != 1)
+				}
+			}
+		}
+	}
+
+	// Timing patterns
+	for i := 8; i < size-8; i++ {
+		m.set(i, 6, i%2 == 0)
+		m.set(6, i, i%2 == 0)
+	}
+
+	// Reserve the format areas and draw the dark module
+	for i := 0; i < 9; i++ {
+		m.reserved[8][i], m.reserved[i][8] = true, true
+		if i < 8 {
+			m.reserved[8][size-1-i], m.reserved[size-1-i][8] = true, true
+		}
+	}
+	m.set(8, size-8, true)
+
+	// Version information from version 7
+	if v >= 7 {
+		bits := v<<12 | bch(v, 0x1f25, 12)
+		for i := range 18 {
+			dark := bits>>i&1 == 1
+			m.set(size-11+i%3, i/3, dark)
+			m.set(i/3, size-11+i%3, dark)
+		}
+	}
+	return m
+}
+
+// set draws a function module
+func (m *matrix) set(x, y int, dark bool) {
+	m.dark[y][x] = dark
+	m.reserved[y][x] = true
+}
+
+// placeData fills the data area in zigzag order. Remainder modules stay light.
+func (m *matrix) placeData(codewords []byte) {
+	i := 0
+	upward := true
+	for right := m.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5 // Skip the vertical timing pattern
+		}
+		for step := range m.size {
+			y := step
+			if upward {
+				y = m.size - 1 - step
+			}
+			for _, x := range []int{right, right - 1} {
+				if m.reserved[y][x] || i >= 8*len(codewords) {
+					continue
+				}
+				m.dark[y][x] = codewords[i/8]>>(7-i%8)&1 == 1
+				i++
+			}
+		}
+		upward = !upward
+	}
+}
+
+// masks are the eight data mask conditions, inverting modules where true
+var masks = []func(x, y int) bool{
+	func(x, y int) bool { return (x+y)%2 == 0 },
+	func(x, y int) bool { return y%2 == 0 },
+	func(x, y int) bool { return x%3 == 0 },
+	func(x, y int) bool { return (x+y)%3 == 0 },
+	func(x, y int) bool { return (y/2+x/3)%2 == 0 },
+	func(x, y int) bool { return x*y%2+x*y%3 == 0 },
+	func(x, y int) bool { return (x*y%2+x*y%3)%2 == 0 },
+	func(x, y int) bool { return ((x+y)%2+x*y%3)%2 == 0 },
+}
+
+func (m *matrix) applyMask(mask int) {
+	for y := range m.size {
+		for x := range m.size {
+			if !m.reserved[y][x] && masks[mask](x, y) {
+				m.dark[y][x] = !m.dark[y][x]
+			}
+		}
+	}
+}
+
+// placeFormat draws the format information for level M and mask twice
+func (m *matrix) placeFormat(mask int) {
+	data := 0b00<<3 | mask // Level M
+	bits := (data<<10 | bch(data, 0x537, 10)) ^ 0x5412
+
+	for i := range 15 {
+		dark := bits>>i&1 == 1
+
+		// Around the top left finder
+		switch {
+		case i < 6:
+			m.dark[i][8] = dark
+		case i < 8:
+			m.dark[i+1][8] = dark
+		case i == 8:
+			m.dark[8][7] = dark
+		default:
+			m.dark[8][14-i] = dark
+		}
+
+		// Split between the other two finders
+		if i < 8 {
+			m.dark[8][m.size-1-i] = dark
+		} else {
+			m.dark[m.size-15+i][8] = dark
+		}
+	}
+}
+
+// bch returns the remainder of value shifted by degree divided by generator
+func bch(value, generator, degree int) int {
+	r := value << degree
+	for bit := 31; bit >= degree; bit-- {
+		if r>>bit&1 == 1 {
+			r ^= generator << (bit - degree)
+		}
+	}
+	return r
+}
+
+func (m *matrix) clone() *matrix {
+	c := &matrix{size: m.size, version: m.version, dark: make([][]bool, m.size), reserved: m.reserved}
+	for y := range m.size {
+		c.dark[y] = append([]bool(nil), m.dark[y]...)
+	}
+	return c
+}
+
+// penalty scores the symbol by the four rules of ISO/IEC 18004, lower is better
+func (m *matrix) penalty() int {
+	score := 0
+	at := func(x, y int, vertical bool) bool {
+		if vertical {
+			return m.dark[x][y]
+		}
+		return m.dark[y][x]
+	}
+
+	for _, vertical := range []bool{false, true} {
+		for y := range m.size {
+			// Rule 1: runs of five or more modules of the same colour
+			run := 1
+			for x := 1; x < m.size; x++ {
+				if at(x, y, vertical) == at(x-1, y, vertical) {
+					run++
+					continue
+				}
+				if run >= 5 {
+					score += run - 2
+				}
+				run = 1
+			}
+			if run >= 5 {
+				score += run - 2
+			}
+
+			// Rule 3: finder-like 1:1:3:1:1 patterns next to four light modules
+			for x := 0; x+10 < m.size; x++ {
+				pattern := true
+				for i, dark := range []bool{true, false, true, true, true, false, true} {
+					if at(x+i, y, vertical) != dark {
+						pattern = false
+						break
+					}
+				}
+				if !pattern {
+					continue
+				}
+				lightAfter, lightBefore := true, true
+				for i := 7; i < 11; i++ {
+					if at(x+i, y, vertical) {
+						lightAfter = false
+					}
+				}
+				for i := 1; i <= 4; i++ {
+					if x-i >= 0 && at(x-i, y, vertical) {
+						lightBefore = false
+					}
+				}
+				if lightAfter || lightBefore {
+					score += 40
+				}
+			}
+		}
+	}
+
+	// Rule 2: 2x2 blocks of the same colour
+	dark := 0
+	for y := range m.size {
+		for x := range m.size {
+			if m.dark[y][x] {
+				dark++
+			}
+			if x+1 < m.size && y+1 < m.size {
+				c := m.dark[y][x]
+				if m.dark[y][x+1] == c && m.dark[y+1][x] == c && m.dark[y+1][x+1] == c {
+					score += 3
+				}
+			}
+		}
+	}
+
+	// Rule 4: deviation of the dark proportion from 50%
+	percent := dark * 100 / (m.size * m.size)
+	score += abs(percent-50) / 5 * 10
+	return score
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
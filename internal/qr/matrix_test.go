@@ -0,0 +1,75 @@
+package qr
+
+import "testing"
+
+func TestFormatBits(t *testing.T) {
+	// Level M format strings from ISO/IEC 18004 table C.1
+	expected := []int{0x5412, 0x5125, 0x5e7c, 0x5b4b, 0x45f9, 0x40ce, 0x4f97, 0x4aa0}
+
+	for mask, bits := range expected {
+		data := mask
+		if got := (data<<10 | bch(data, 0x537, 10)) ^ 0x5412; got != bits {
+			t.Errorf("mask %d: expected %015b, got %015b", mask, bits, got)
+		}
+
+		// Both copies carry the string, least significant bit nearest the finders' edge
+		m := newMatrix(1)
+		m.placeFormat(mask)
+		if m.dark[0][8] != (bits&1 == 1) || m.dark[8][m.size-1] != (bits&1 == 1) {
+			t.Errorf("mask %d: expected bit 0 in both copies", mask)
+		}
+		if m.dark[8][0] != (bits>>14&1 == 1) || m.dark[m.size-1][8] != (bits>>14&1 == 1) {
+			t.Errorf("mask %d: expected bit 14 in both copies", mask)
+		}
+	}
+}
+
+func TestVersionBits(t *testing.T) {
+	tests := []struct {
+		version int
+		bits    int
+	}{
+		{7, 0x07c94},
+		{8, 0x085bc},
+		{10, 0x0a4d3},
+	}
+
+	for _, tt := range tests {
+		if got := tt.version<<12 | bch(tt.version, 0x1f25, 12); got != tt.bits {
+			t.Errorf("version %d: expected %018b, got %018b", tt.version, tt.bits, got)
+		}
+	}
+}
+
+func TestPlaceData(t *testing.T) {
+	m := newMatrix(1)
+	m.placeData([]byte{0b10110000})
+
+	// The first codeword fills the bottom right corner upwards, right column first
+	expected := [][3]int{{20, 20, 1}, {19, 20, 0}, {20, 19, 1}, {19, 19, 1}, {20, 18, 0}}
+	for _, e := range expected {
+		if m.dark[e[1]][e[0]] != (e[2] == 1) {
+			t.Errorf("expected module (%d, %d) to be dark=%v", e[0], e[1], e[2] == 1)
+		}
+	}
+}
+
+func TestPenalty(t *testing.T) {
+	m := newMatrix(1)
+	light := m.clone()
+	for y := range light.size {
+		for x := range light.size {
+			light.dark[y][x] = false
+		}
+	}
+	checker := light.clone()
+	for y := range checker.size {
+		for x := range checker.size {
+			checker.dark[y][x] = (x+y)%2 == 0
+		}
+	}
+
+	if light.penalty() <= checker.penalty() {
+		t.Errorf("expected a blank symbol to score worse than a checkerboard, got %d and %d", light.penalty(), checker.penalty())
+	}
+}
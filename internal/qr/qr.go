@@ -0,0 +1,228 @@
+// Package qr encodes short byte strings as QR codes (ISO/IEC 18004), enough to
+// print IDs on labels without a third-party dependency.
+//
+// Only byte mode, error correction level M and versions 1 to 10 (up to 213
+// bytes) are supported.
+package qr
+
+import (
+	"errors"
+	"image"
+	"image/color"
+)
+
+// ErrTooLong is returned by Encode for data that does not fit in version 10
+var ErrTooLong = errors.New("qr: data too long")
+
+// version describes the layout of one symbol version at level M
+type version struct {
+	ecPerBlock int      // Error correction codewords per block
+	blocks     [][2]int // Groups of {block count, data codewords per block}
+	alignment  []int    // Alignment pattern centres
+}
+
+var versions = []version{
+	1:  {10, [][2]int{{1, 16}}, nil},
+	2:  {16, [][2]int{{1, 28}}, []int{6, 18}},
+	3:  {26, [][2]int{{1, 44}}, []int{6, 22}},
+	4:  {18, [][2]int{{2, 32}}, []int{6, 26}},
+	5:  {24, [][2]int{{2, 43}}, []int{6, 30}},
+	6:  {16, [][2]int{{4, 27}}, []int{6, 34}},
+	7:  {18, [][2]int{{4, 31}}, []int{6, 22, 38}},
+	8:  {22, [][2]int{{2, 38}, {2, 39}}, []int{6, 24, 42}},
+	9:  {22, [][2]int{{3, 36}, {2, 37}}, []int{6, 26, 46}},
+	10: {26, [][2]int{{4, 43}, {1, 44}}, []int{6, 28, 50}},
+}
+
+// dataCodewords returns the number of data codewords of v
+func (v version) dataCodewords() int {
+	n := 0
+	for _, group := range v.blocks {
+		n += group[0] * group[1]
+	}
+	return n
+}
+
+// Code is an encoded QR symbol
+type Code struct {
+	Size    int      // Modules per side, without the quiet zone
+	modules [][]bool // True for dark modules
+}
+
+// Dark reports whether the module at column x and row y is dark
+func (c *Code) Dark(x, y int) bool {
+	return c.modules[y][x]
+}
+
+// Encode returns the smallest QR code holding data
+func Encode(data []byte) (*Code, error) {
+	for v := 1; v < len(versions); v++ {
+		countBits := 8
+		if v >= 10 {
+			countBits = 16
+		}
+		if 4+countBits+8*len(data) <= 8*versions[v].dataCodewords() {
+			return encode(data, v, countBits), nil
+		}
+	}
+	return nil, ErrTooLong
+}
+
+// encode builds the symbol of data in version v
+func encode(data []byte, v, countBits int) *Code {
+	info := versions[v]
+
+	// Mode indicator, character count, data, terminator and padding
+	var bits bitBuffer
+	bits.append(0b0100, 4)
+	bits.append(len(data), countBits)
+	for _, b := range data {
+		bits.append(int(b), 8)
+	}
+	capacity := 8 * info.dataCodewords()
+	bits.append(0, min(4, capacity-bits.len()))
+	bits.append(0, (8-bits.len()%8)%8)
+	for pad := 0; bits.len() < capacity; pad++ {
+		bits.append([]int{0xec, 0x11}[pad%2], 8)
+	}
+	codewords := bits.bytes()
+
+	// Split into blocks, add error correction and interleave
+	var dataBlocks, ecBlocks [][]byte
+	for _, group := range info.blocks {
+		for range group[0] {
+			block := codewords[:group[1]]
+			codewords = codewords[group[1]:]
+			dataBlocks = append(dataBlocks, block)
+			ecBlocks = append(ecBlocks, reedSolomon(block, info.ecPerBlock))
+		}
+	}
+	var final []byte
+	for i := 0; i < info.blocks[len(info.blocks)-1][1]; i++ {
+		for _, block := range dataBlocks {
+			if i < len(block) {
+				final = append(final, block[i])
+			}
+		}
+	}
+	for i := 0; i < info.ecPerBlock; i++ {
+		for _, block := range ecBlocks {
+			final = append(final, block[i])
+		}
+	}
+
+	m := newMatrix(v)
+	m.placeData(final)
+
+	// Choose the mask with the lowest penalty
+	var best *matrix
+	bestPenalty := -1
+	for mask := range 8 {
+		candidate := m.clone()
+		candidate.applyMask(mask)
+		candidate.placeFormat(mask)
+		if p := candidate.penalty(); bestPenalty < 0 || p < bestPenalty {
+			best, bestPenalty = candidate, p
+		}
+	}
+	return &Code{Size: best.size, modules: best.dark}
+}
+
+// Image returns the code as a black and white image with scale pixels per
+// module and the standard four-module quiet zone
+func (c *Code) Image(scale int) *image.Gray {
+	side := (c.Size + 8) * scale
+	img := image.NewGray(image.Rect(0, 0, side, side))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+	for y := range c.Size {
+		for x := range c.Size {
+			if !c.modules[y][x] {
+				continue
+			}
+			for dy := range scale {
+				for dx := range scale {
+					img.SetGray((x+4)*scale+dx, (y+4)*scale+dy, color.Gray{})
+				}
+			}
+		}
+	}
+	return img
+}
+
+// bitBuffer accumulates bits most significant first
+type bitBuffer struct {
+	bits []bool
+}
+
+func (b *bitBuffer) append(value, n int) {
+	for i := n - 1; i >= 0; i-- {
+		b.bits = append(b.bits, value>>i&1 == 1)
+	}
+}
+
+func (b *bitBuffer) len() int {
+	return len(b.bits)
+}
+
+func (b *bitBuffer) bytes() []byte {
+	out := make([]byte, len(b.bits)/8)
+	for i, bit := range b.bits {
+		if bit {
+			out[i/8] |= 0x80 >> (i % 8)
+		}
+	}
+	return out
+}
+
+// GF(256) tables with the QR polynomial x^8 + x^4 + x^3 + x^2 + 1
+var gfExp, gfLog = func() ([512]byte, [256]byte) {
+	var exp [512]byte
+	var log [256]byte
+	x := 1
+	for i := range 255 {
+		exp[i] = byte(x)
+		log[x] = byte(i)
+		x <<= 1
+		if x&0x100 != 0 {
+			x ^= 0x11d
+		}
+	}
+	for i := 255; i < 512; i++ {
+		exp[i] = exp[i-255]
+	}
+	return exp, log
+}()
+
+func gfMul(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return gfExp[int(gfLog[a])+int(gfLog[b])]
+}
+
+// reedSolomon returns n error correction codewords for data
+func reedSolomon(data []byte, n int) []byte {
+	// Generator polynomial (x - a^0)(x - a^1)...(x - a^(n-1)), highest degree first
+	generator := []byte{1}
+	for i := range n {
+		next := make([]byte, len(generator)+1)
+		for j, c := range generator {
+			next[j] ^= c
+			next[j+1] ^= gfMul(c, gfExp[i])
+		}
+		generator = next
+	}
+
+	remainder := make([]byte, n)
+	for _, b := range data {
+		factor := b ^ remainder[0]
+		copy(remainder, remainder[1:])
+		remainder[n-1] = 0
+		for j := range n {
+			remainder[j] ^= gfMul(generator[j+1], factor)
+		}
+	}
+	return remainder
+}
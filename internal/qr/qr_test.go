@@ -0,0 +1,94 @@
+package qr
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestEncodeVersion(t *testing.T) {
+	tests := []struct {
+		length int
+		size   int
+	}{
+		{1, 21},
+		{14, 21},
+		{15, 25},
+		{26, 25},
+		{27, 29},
+		{122, 45},
+		{123, 49},
+		{213, 57},
+	}
+
+	for _, tt := range tests {
+		code, err := Encode(bytes.Repeat([]byte("a"), tt.length))
+		if err != nil {
+			t.Fatalf("%d bytes: %v", tt.length, err)
+		}
+		if code.Size != tt.size {
+			t.Errorf("%d bytes: expected size %d, got %d", tt.length, tt.size, code.Size)
+		}
+	}
+
+	if _, err := Encode(bytes.Repeat([]byte("a"), 214)); !errors.Is(err, ErrTooLong) {
+		t.Errorf("expected ErrTooLong, got %v", err)
+	}
+}
+
+func TestEncodeFunctionPatterns(t *testing.T) {
+	code, err := Encode([]byte("dorefa-1a2b3"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Finder patterns are a dark ring, a light ring and a dark 3x3 centre
+	for _, corner := range [][2]int{{0, 0}, {code.Size - 7, 0}, {0, code.Size - 7}} {
+		for _, offset := range [][3]int{{0, 0, 1}, {6, 6, 1}, {1, 1, 0}, {5, 3, 0}, {3, 3, 1}, {2, 4, 1}} {
+			x, y := corner[0]+offset[0], corner[1]+offset[1]
+			if code.Dark(x, y) != (offset[2] == 1) {
+				t.Errorf("expected module (%d, %d) of the finder to be dark=%v", x, y, offset[2] == 1)
+			}
+		}
+	}
+
+	// Timing patterns alternate between the finders
+	for i := 8; i < code.Size-8; i++ {
+		if code.Dark(i, 6) != (i%2 == 0) || code.Dark(6, i) != (i%2 == 0) {
+			t.Errorf("expected timing module %d to be dark=%v", i, i%2 == 0)
+		}
+	}
+
+	// The dark module above the bottom left format area
+	if !code.Dark(8, code.Size-8) {
+		t.Error("expected the dark module to be dark")
+	}
+}
+
+func TestImage(t *testing.T) {
+	code, err := Encode([]byte("1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	img := code.Image(2)
+	if side := img.Bounds().Dx(); side != (21+8)*2 {
+		t.Errorf("expected %d pixels, got %d", (21+8)*2, side)
+	}
+	if img.GrayAt(0, 0).Y != 0xff {
+		t.Error("expected a light quiet zone")
+	}
+	if img.GrayAt(8, 8).Y != 0 || img.GrayAt(9, 9).Y != 0 {
+		t.Error("expected the top left module to be dark")
+	}
+}
+
+func TestReedSolomon(t *testing.T) {
+	// HELLO WORLD in alphanumeric mode at 1-M
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	expected := []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}
+
+	if got := reedSolomon(data, 10); !bytes.Equal(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
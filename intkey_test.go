@@ -0,0 +1,115 @@
+package doremid
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestEncodeInt64(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 3,
+		Separator:              "-",
+	})
+
+	tests := []struct {
+		key      int64
+		expected int64 // Position without a salt
+	}{
+		{0, 0},
+		{-1, 1},
+		{1, 2},
+		{-2, 3},
+		{1861, 3722},
+		{42335, 84670},
+		{-42336, 84671},
+	}
+
+	for _, tt := range tests {
+		id, err := generator.EncodeInt64(tt.key, 0)
+		if err != nil || id != generator.PositionToID(tt.expected) {
+			t.Errorf("expected '%s' for key %d, got '%s' (err: %v)", generator.PositionToID(tt.expected), tt.key, id, err)
+			continue
+		}
+		if key, err := generator.DecodeInt64(id, 0); err != nil || key != tt.key {
+			t.Errorf("expected key %d for '%s', got %d (err: %v)", tt.key, id, key, err)
+		}
+	}
+
+	for _, key := range []int64{42336, -42337, math.MaxInt64, math.MinInt64} {
+		_, err := generator.EncodeInt64(key, 0)
+		var rangeErr *RangeError
+		if !errors.As(err, &rangeErr) || rangeErr.Min != -42336 || rangeErr.Max != 42336 {
+			t.Errorf("expected RangeError [-42336, 42336) for key %d, got %v", key, err)
+		}
+	}
+	if _, err := generator.DecodeInt64("invalid", 0); !errors.Is(err, ErrInvalidID) {
+		t.Errorf("expected ErrInvalidID, got %v", err)
+	}
+}
+
+func TestEncodeInt64Salted(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 3,
+		Separator:              "-",
+	})
+	const salt = 0x5eed
+
+	// Every key in range maps to a distinct ID and back
+	seen := make(map[string]bool)
+	adjacent := 0
+	previous := int64(-1)
+	for key := int64(-42336); key < 42336; key++ {
+		id, err := generator.EncodeInt64(key, salt)
+		if err != nil || seen[id] {
+			t.Fatalf("expected a fresh ID for key %d, got '%s' (err: %v)", key, id, err)
+		}
+		seen[id] = true
+		if decoded, err := generator.DecodeInt64(id, salt); err != nil || decoded != key {
+			t.Fatalf("expected key %d for '%s', got %d (err: %v)", key, id, decoded, err)
+		}
+
+		pos := generator.IDToPosition(id)
+		if pos == previous+1 {
+			adjacent++
+		}
+		previous = pos
+	}
+	if adjacent > 100 {
+		t.Errorf("expected consecutive keys to scatter, got %d adjacent positions", adjacent)
+	}
+
+	id, _ := generator.EncodeInt64(1000, salt)
+	if key, _ := generator.DecodeInt64(id, salt+1); key == 1000 {
+		t.Error("expected a different salt to decode a different key")
+	}
+}
+
+func TestEncodeInt64FullRange(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   4,
+		EqualTemperamentDigits: 15,
+		Separator:              "-",
+	})
+
+	for _, salt := range []uint64{0, 42} {
+		for _, key := range []int64{0, -1, 1, math.MaxInt64, math.MinInt64, 1 << 62} {
+			id, err := generator.EncodeInt64(key, salt)
+			if err != nil {
+				t.Fatalf("expected key %d to fit, got %v", key, err)
+			}
+			if decoded, err := generator.DecodeInt64(id, salt); err != nil || decoded != key {
+				t.Errorf("expected key %d for '%s' with salt %d, got %d (err: %v)", key, id, salt, decoded, err)
+			}
+		}
+	}
+
+	// Positions beyond 2^64 are not keys
+	last, _ := generator.PositionToIDBig(new(big.Int).Sub(generator.MaxCombinationsBig(), big.NewInt(1)))
+	if _, err := generator.DecodeInt64(last, 0); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("expected ErrOutOfRange, got %v", err)
+	}
+}
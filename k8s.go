@@ -0,0 +1,45 @@
+package doremid
+
+import "fmt"
+
+// K8sNameMaxLength is the maximum length of a DNS-1123 label
+const K8sNameMaxLength = 63
+
+// NewK8sNameGenerator creates a generator for Kubernetes object names such as
+// "domire-1a2b3". Its IDs are valid DNS-1123 labels: lowercase, starting with a
+// letter, at most 63 characters, with enough room left for a prefix like
+// "worker-". Capacity is 7^3 * 12^5, about 85 million names.
+func NewK8sNameGenerator() *Generator {
+	return New(Config{
+		JustIntonationDigits:   3,
+		EqualTemperamentDigits: 5,
+		Separator:              "-",
+	})
+}
+
+// ValidateK8sName checks that name is a DNS-1123 label that starts with a letter:
+// 1 to 63 characters from a-z, 0-9 and '-', beginning with a letter and ending
+// with a letter or digit. Returns a *FormatError describing the first violation.
+func ValidateK8sName(name string) error {
+	if len(name) == 0 || len(name) > K8sNameMaxLength {
+		return &FormatError{Input: truncate(name, K8sNameMaxLength), Offset: -1, Reason: fmt.Sprintf("length must be between 1 and %d, got %d", K8sNameMaxLength, len(name)), Err: ErrInvalidFormat}
+	}
+
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		switch {
+		case c >= 'a' && c <= 'z':
+		case c >= '0' && c <= '9':
+			if i == 0 {
+				return &FormatError{Input: name, Offset: i, Symbol: symbolAt(name, i, 1), Reason: "name must start with a letter", Err: ErrInvalidFormat}
+			}
+		case c == '-':
+			if i == 0 || i == len(name)-1 {
+				return &FormatError{Input: name, Offset: i, Symbol: "-", Reason: "name must start and end with an alphanumeric character", Err: ErrInvalidFormat}
+			}
+		default:
+			return &FormatError{Input: name, Offset: i, Symbol: symbolAt(name, i, 1), Reason: "unknown character", Err: ErrBadCharacter}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package doremid
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestK8sNameGenerator(t *testing.T) {
+	generator := NewK8sNameGenerator()
+
+	for i := 0; i < 100; i++ {
+		name := "worker-" + generator.NewID()
+		if err := ValidateK8sName(name); err != nil {
+			t.Fatalf("generated invalid name '%s': %v", name, err)
+		}
+	}
+	for _, pos := range []int64{0, generator.MaxCombinations() - 1} {
+		if err := ValidateK8sName(generator.PositionToID(pos)); err != nil {
+			t.Errorf("position %d produced an invalid name: %v", pos, err)
+		}
+	}
+}
+
+func TestValidateK8sName(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		cause error
+	}{
+		{"valid", "domire-1a2b3", nil},
+		{"empty", "", ErrInvalidFormat},
+		{"too long", strings.Repeat("a", 64), ErrInvalidFormat},
+		{"leading digit", "1domire", ErrInvalidFormat},
+		{"leading hyphen", "-domire", ErrInvalidFormat},
+		{"trailing hyphen", "domire-", ErrInvalidFormat},
+		{"uppercase", "DoMiRe-1a2b3", ErrBadCharacter},
+		{"underscore", "domire_1a2b3", ErrBadCharacter},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateK8sName(tt.input)
+			if tt.cause == nil {
+				if err != nil {
+					t.Errorf("expected valid name, got %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrInvalidID) || !errors.Is(err, tt.cause) {
+				t.Errorf("expected %v, got %v", tt.cause, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package doremid
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// KeySize is the width in bytes of the binary keys produced by ToKey
+const KeySize = 8
+
+// ToKey converts an ID into a fixed-width big-endian encoding of its position.
+// Byte-wise ordering of keys matches position ordering, which makes them suitable
+// as keys in ordered key-value stores such as LevelDB, Badger or Pebble.
+//
+// Returns a *FormatError if the ID is invalid.
+func (g *Generator) ToKey(id string) ([]byte, error) {
+	pos, err := g.decode(id)
+	if err != nil {
+		return nil, err
+	}
+	return g.PositionToKey(pos), nil
+}
+
+// FromKey converts a key produced by ToKey back into its ID.
+//
+// Returns a *FormatError if the key has the wrong width, or a *RangeError if it
+// encodes a position beyond the maximum.
+func (g *Generator) FromKey(key []byte) (string, error) {
+	if len(key) != KeySize {
+		return "", &FormatError{Input: string(key), Offset: -1, Reason: fmt.Sprintf("key must be %d bytes, got %d", KeySize, len(key)), Err: ErrInvalidFormat}
+	}
+	pos := binary.BigEndian.Uint64(key)
+	if maxCombinations := g.MaxCombinations(); pos >= uint64(maxCombinations) {
+		return "", &RangeError{Position: int64(pos), Max: maxCombinations}
+	}
+	return g.PositionToID(int64(pos)), nil
+}
+
+// PositionToKey returns the fixed-width key for a position.
+// Negative positions are encoded as the zero key.
+func (g *Generator) PositionToKey(position int64) []byte {
+	key := make([]byte, KeySize)
+	if position > 0 {
+		binary.BigEndian.PutUint64(key, uint64(position))
+	}
+	return key
+}
+
+// KeyRange returns the half-open key range [lower, upper) covering positions
+// [start, end), for use as iterator bounds in a range scan.
+func (g *Generator) KeyRange(start, end int64) (lower, upper []byte) {
+	return g.PositionToKey(start), g.PositionToKey(end)
+}
+
+// PrefixKeyRange returns the half-open key range [lower, upper) covering every ID
+// whose musical note part starts with prefix.
+//
+// Parameters:
+//   - prefix: whole note pairs, e.g. "domi"; an empty prefix covers the whole space
+//
+// Because the note part holds the most significant digits of the position, all IDs
+// sharing a note prefix occupy one contiguous key range.
+// Returns a *FormatError if prefix is not a sequence of valid notes or is too long.
+func (g *Generator) PrefixKeyRange(prefix string) (lower, upper []byte, err error) {
+	if len(prefix) > g.JustIntonationDigits*g.maxNoteLen {
+		return nil, nil, &FormatError{Input: prefix, Offset: -1, Reason: "note prefix must be at most JustIntonationDigits whole notes", Err: ErrInvalidFormat}
+	}
+
+	value := int64(0)
+	notes := 0
+	for i := 0; i < len(prefix); notes++ {
+		index, width, found := g.nextNote(prefix, i)
+		if !found {
+			return nil, nil, &FormatError{Input: prefix, Offset: i, Symbol: symbolAt(prefix, i, g.maxNoteLen), Reason: "unknown note", Err: ErrBadCharacter}
+		}
+		value = value*int64(g.justIntonationLen) + int64(index)
+		i += width
+	}
+	if notes > g.JustIntonationDigits {
+		return nil, nil, &FormatError{Input: prefix, Offset: -1, Reason: "note prefix must be at most JustIntonationDigits whole notes", Err: ErrInvalidFormat}
+	}
+
+	// Each prefix value spans the remaining note digits and the whole equal temperament part
+	remaining := g.JustIntonationDigits - notes
+	span := int64(g.intPow(g.justIntonationLen, remaining)) * int64(g.intPow(g.equalTemperamentLen, g.EqualTemperamentDigits))
+
+	lower, upper = g.KeyRange(value*span, (value+1)*span)
+	return lower, upper, nil
+}
@@ -0,0 +1,92 @@
+package doremid
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestToKeyAndFromKey(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 2,
+		Separator:              "-",
+	})
+
+	var previous []byte
+	for pos := int64(0); pos < generator.MaxCombinations(); pos += 97 {
+		id := generator.PositionToID(pos)
+
+		key, err := generator.ToKey(id)
+		if err != nil {
+			t.Fatalf("ToKey('%s') returned error: %v", id, err)
+		}
+		if len(key) != KeySize {
+			t.Errorf("expected key width %d, got %d", KeySize, len(key))
+		}
+
+		// Lexical key ordering must follow position ordering
+		if previous != nil && bytes.Compare(previous, key) >= 0 {
+			t.Errorf("key for position %d does not sort after its predecessor", pos)
+		}
+		previous = key
+
+		back, err := generator.FromKey(key)
+		if err != nil || back != id {
+			t.Errorf("FromKey: expected '%s', got '%s' (err: %v)", id, back, err)
+		}
+	}
+}
+
+func TestKeyErrors(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   1,
+		EqualTemperamentDigits: 1,
+		Separator:              "-",
+	})
+
+	if _, err := generator.ToKey("dx-0"); err == nil {
+		t.Error("expected error for invalid ID")
+	}
+	if _, err := generator.FromKey([]byte{1, 2, 3}); err == nil {
+		t.Error("expected error for short key")
+	}
+	if _, err := generator.FromKey(generator.PositionToKey(84)); err == nil {
+		t.Error("expected error for key beyond maximum")
+	}
+}
+
+func TestPrefixKeyRange(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   3,
+		EqualTemperamentDigits: 2,
+		Separator:              "-",
+	})
+
+	lower, upper, err := generator.PrefixKeyRange("remi")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for pos := int64(0); pos < generator.MaxCombinations(); pos++ {
+		id := generator.PositionToID(pos)
+		key := generator.PositionToKey(pos)
+		inRange := bytes.Compare(key, lower) >= 0 && bytes.Compare(key, upper) < 0
+		if hasPrefix := id[:4] == "remi"; inRange != hasPrefix {
+			t.Fatalf("ID '%s': in range %v, has prefix %v", id, inRange, hasPrefix)
+		}
+	}
+
+	lower, upper, err = generator.PrefixKeyRange("")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(lower, generator.PositionToKey(0)) || !bytes.Equal(upper, generator.PositionToKey(generator.MaxCombinations())) {
+		t.Error("empty prefix should cover the whole space")
+	}
+
+	for _, prefix := range []string{"r", "rx", "domifaso"} {
+		if _, _, err := generator.PrefixKeyRange(prefix); err == nil {
+			t.Errorf("expected error for prefix %q", prefix)
+		}
+	}
+}
@@ -0,0 +1,251 @@
+package doremid
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// State is the lifecycle state of an ID
+type State int
+
+const (
+	// StateUnknown means the ID has never been recorded
+	StateUnknown State = iota
+	// StateReserved means the ID is set aside but not yet handed out
+	StateReserved
+	// StateIssued means the ID has been handed out
+	StateIssued
+	// StateActive means the ID is in use by a live record
+	StateActive
+	// StateRevoked means the ID was withdrawn and must no longer resolve
+	StateRevoked
+	// StateRecycled means a revoked or abandoned ID may be reserved again
+	StateRecycled
+)
+
+// String returns the name of the state
+func (s State) String() string {
+	switch s {
+	case StateUnknown:
+		return "unknown"
+	case StateReserved:
+		return "reserved"
+	case StateIssued:
+		return "issued"
+	case StateActive:
+		return "active"
+	case StateRevoked:
+		return "revoked"
+	case StateRecycled:
+		return "recycled"
+	default:
+		return fmt.Sprintf("State(%d)", int(s))
+	}
+}
+
+// transitions lists the states each state may move to
+var transitions = map[State][]State{
+	StateUnknown:  {StateReserved, StateIssued},
+	StateReserved: {StateIssued, StateRecycled},
+	StateIssued:   {StateActive, StateRevoked},
+	StateActive:   {StateRevoked},
+	StateRevoked:  {StateRecycled},
+	StateRecycled: {StateReserved, StateIssued},
+}
+
+// CanTransition reports whether an ID may move from one state to another
+func CanTransition(from, to State) bool {
+	for _, allowed := range transitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// LifecycleStore persists lifecycle states per position.
+type LifecycleStore interface {
+	// State returns the state of position, StateUnknown if it was never recorded
+	State(ctx context.Context, position int64) (State, error)
+
+	// CompareAndSwapState sets the state of position to to if it is currently from.
+	// Returns false without changing anything if the current state differs.
+	CompareAndSwapState(ctx context.Context, position int64, from, to State) (bool, error)
+}
+
+// Transition is a committed change of an ID's lifecycle state
+type Transition struct {
+	ID       string
+	Position int64
+	From     State
+	To       State
+}
+
+// TransitionFunc receives committed transitions. It is called synchronously and
+// must not call back into the notifying Lifecycle.
+type TransitionFunc func(Transition)
+
+// TransitionError reports a state change the lifecycle does not allow
+type TransitionError struct {
+	ID   string
+	From State
+	To   State
+}
+
+// Error implements the error interface
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("doremid: cannot move %q from %s to %s", e.ID, e.From, e.To)
+}
+
+// Is reports whether target is ErrInvalidTransition
+func (e *TransitionError) Is(target error) bool {
+	return target == ErrInvalidTransition
+}
+
+// Lifecycle tracks the state of each ID through
+// reserved → issued → active → revoked → recycled, persisting states in a
+// LifecycleStore and notifying hooks after every committed transition.
+// It is safe for concurrent use if the store is.
+type Lifecycle struct {
+	g     *Generator
+	store LifecycleStore
+
+	mu        sync.RWMutex
+	nextHook  int
+	listeners map[int]TransitionFunc
+}
+
+// NewLifecycle creates a lifecycle for IDs of g persisted in store
+func (g *Generator) NewLifecycle(store LifecycleStore) *Lifecycle {
+	return &Lifecycle{g: g, store: store, listeners: make(map[int]TransitionFunc)}
+}
+
+// OnTransition registers fn to be called after every committed transition.
+// The returned function removes the hook.
+func (l *Lifecycle) OnTransition(fn TransitionFunc) (unsubscribe func()) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	id := l.nextHook
+	l.nextHook++
+	l.listeners[id] = fn
+
+	return func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		delete(l.listeners, id)
+	}
+}
+
+// State returns the current state of id.
+// Returns a *FormatError if id is invalid.
+func (l *Lifecycle) State(ctx context.Context, id string) (State, error) {
+	pos, err := l.g.decode(id)
+	if err != nil {
+		return StateUnknown, err
+	}
+	return l.store.State(ctx, pos)
+}
+
+// Reserve moves id to StateReserved
+func (l *Lifecycle) Reserve(ctx context.Context, id string) error {
+	return l.Transition(ctx, id, StateReserved)
+}
+
+// Issue moves id to StateIssued
+func (l *Lifecycle) Issue(ctx context.Context, id string) error {
+	return l.Transition(ctx, id, StateIssued)
+}
+
+// Activate moves id to StateActive
+func (l *Lifecycle) Activate(ctx context.Context, id string) error {
+	return l.Transition(ctx, id, StateActive)
+}
+
+// Revoke moves id to StateRevoked
+func (l *Lifecycle) Revoke(ctx context.Context, id string) error {
+	return l.Transition(ctx, id, StateRevoked)
+}
+
+// Recycle moves id to StateRecycled
+func (l *Lifecycle) Recycle(ctx context.Context, id string) error {
+	return l.Transition(ctx, id, StateRecycled)
+}
+
+// Transition moves id to state to.
+// Returns a *TransitionError if the move is not allowed from the current state,
+// a *FormatError if id is invalid, or any error returned by the store.
+func (l *Lifecycle) Transition(ctx context.Context, id string, to State) error {
+	pos, err := l.g.decode(id)
+	if err != nil {
+		return err
+	}
+
+	for {
+		from, err := l.store.State(ctx, pos)
+		if err != nil {
+			return err
+		}
+		if !CanTransition(from, to) {
+			return &TransitionError{ID: id, From: from, To: to}
+		}
+
+		swapped, err := l.store.CompareAndSwapState(ctx, pos, from, to)
+		if err != nil {
+			return err
+		}
+		if swapped {
+			l.notify(Transition{ID: id, Position: pos, From: from, To: to})
+			return nil
+		}
+		// The state changed concurrently; re-validate against the new state
+	}
+}
+
+// notify delivers t to every hook
+func (l *Lifecycle) notify(t Transition) {
+	l.mu.RLock()
+	listeners := make([]TransitionFunc, 0, len(l.listeners))
+	for _, fn := range l.listeners {
+		listeners = append(listeners, fn)
+	}
+	l.mu.RUnlock()
+
+	for _, fn := range listeners {
+		fn(t)
+	}
+}
+
+// MemoryLifecycleStore is an in-memory LifecycleStore. It is safe for concurrent use.
+type MemoryLifecycleStore struct {
+	mu     sync.RWMutex
+	states map[int64]State
+}
+
+// NewMemoryLifecycleStore creates an empty in-memory lifecycle store
+func NewMemoryLifecycleStore() *MemoryLifecycleStore {
+	return &MemoryLifecycleStore{states: make(map[int64]State)}
+}
+
+// State returns the state of position, StateUnknown if it was never recorded
+func (s *MemoryLifecycleStore) State(_ context.Context, position int64) (State, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.states[position], nil
+}
+
+// CompareAndSwapState sets the state of position to to if it is currently from
+func (s *MemoryLifecycleStore) CompareAndSwapState(_ context.Context, position int64, from, to State) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.states[position] != from {
+		return false, nil
+	}
+	s.states[position] = to
+	return true, nil
+}
+
+// Compile-time interface check
+var _ LifecycleStore = (*MemoryLifecycleStore)(nil)
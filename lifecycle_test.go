@@ -0,0 +1,103 @@
+package doremid
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestLifecycle(t *testing.T) {
+	ctx := context.Background()
+	generator := New(Config{
+		JustIntonationDigits:   1,
+		EqualTemperamentDigits: 2,
+		Separator:              "-",
+	})
+	lifecycle := generator.NewLifecycle(NewMemoryLifecycleStore())
+
+	var transitions []Transition
+	unsubscribe := lifecycle.OnTransition(func(tr Transition) {
+		transitions = append(transitions, tr)
+	})
+
+	id := "re-1a"
+	steps := []func(context.Context, string) error{
+		lifecycle.Reserve, lifecycle.Issue, lifecycle.Activate, lifecycle.Revoke, lifecycle.Recycle,
+	}
+	for _, step := range steps {
+		if err := step(ctx, id); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if state, _ := lifecycle.State(ctx, id); state != StateRecycled {
+		t.Errorf("expected recycled, got %s", state)
+	}
+	if len(transitions) != 5 || transitions[0].From != StateUnknown || transitions[4].To != StateRecycled {
+		t.Errorf("unexpected transitions %+v", transitions)
+	}
+	if transitions[2].Position != generator.IDToPosition(id) || transitions[2].ID != id {
+		t.Errorf("unexpected transition %+v", transitions[2])
+	}
+
+	unsubscribe()
+	lifecycle.Reserve(ctx, id)
+	if len(transitions) != 5 {
+		t.Error("hook called after unsubscribe")
+	}
+}
+
+func TestLifecycleInvalidTransitions(t *testing.T) {
+	ctx := context.Background()
+	generator := New(Config{
+		JustIntonationDigits:   1,
+		EqualTemperamentDigits: 2,
+		Separator:              "-",
+	})
+	lifecycle := generator.NewLifecycle(NewMemoryLifecycleStore())
+
+	err := lifecycle.Activate(ctx, "do-00")
+	var transitionErr *TransitionError
+	if !errors.Is(err, ErrInvalidTransition) || !errors.As(err, &transitionErr) {
+		t.Fatalf("expected *TransitionError, got %v", err)
+	}
+	if transitionErr.From != StateUnknown || transitionErr.To != StateActive {
+		t.Errorf("unexpected error %+v", transitionErr)
+	}
+
+	lifecycle.Issue(ctx, "do-00")
+	lifecycle.Revoke(ctx, "do-00")
+	if err := lifecycle.Activate(ctx, "do-00"); !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("expected revoked ID to stay revoked, got %v", err)
+	}
+
+	if err := lifecycle.Issue(ctx, "xx-00"); !errors.Is(err, ErrInvalidID) {
+		t.Errorf("expected ErrInvalidID, got %v", err)
+	}
+}
+
+func TestLifecycleConcurrentIssue(t *testing.T) {
+	ctx := context.Background()
+	lifecycle := NewWithDefaults().NewLifecycle(NewMemoryLifecycleStore())
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	succeeded := 0
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if lifecycle.Issue(ctx, "dodododo-00000") == nil {
+				mu.Lock()
+				succeeded++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if succeeded != 1 {
+		t.Errorf("expected exactly one successful issue, got %d", succeeded)
+	}
+}
@@ -0,0 +1,97 @@
+package doremid
+
+import (
+	"math/big"
+	"strings"
+)
+
+// digitGrouping describes how a locale groups the digits of an integer
+type digitGrouping struct {
+	separator string
+	indian    bool // Group the last three digits, then pairs (12,34,56,789)
+}
+
+// digitGroupings maps language tags and base languages to their grouping.
+// Full tags such as "de-CH" take precedence over their base language.
+var digitGroupings = map[string]digitGrouping{
+	"en":    {separator: ","},
+	"en-IN": {separator: ",", indian: true},
+	"hi":    {separator: ",", indian: true},
+	"ja":    {separator: ","},
+	"ko":    {separator: ","},
+	"zh":    {separator: ","},
+	"de":    {separator: "."},
+	"de-CH": {separator: "\u2019"},
+	"es":    {separator: "."},
+	"it":    {separator: "."},
+	"nl":    {separator: "."},
+	"pt":    {separator: "."},
+	"fr":    {separator: "\u202f"},
+	"pl":    {separator: "\u00a0"},
+	"ru":    {separator: "\u00a0"},
+	"sv":    {separator: "\u00a0"},
+}
+
+// FormatCombinations returns MaxCombinations formatted with the digit grouping of
+// lang, a BCP 47 language tag such as "en", "de-CH" or "hi-IN". The count is
+// computed exactly, so configurations whose capacity overflows int64 are
+// formatted correctly. Unknown languages use English grouping.
+func (g *Generator) FormatCombinations(lang string) string {
+	return GroupDigits(g.MaxCombinationsBig(), lang)
+}
+
+// MaxCombinationsBig returns the exact number of unique IDs, even when it
+// exceeds the range of int64
+func (g *Generator) MaxCombinationsBig() *big.Int {
+	just := new(big.Int).Exp(big.NewInt(int64(g.justIntonationLen)), big.NewInt(int64(g.JustIntonationDigits)), nil)
+	equal := new(big.Int).Exp(big.NewInt(int64(g.equalTemperamentLen)), big.NewInt(int64(g.EqualTemperamentDigits)), nil)
+	return just.Mul(just, equal)
+}
+
+// GroupDigits formats n with the digit grouping of lang, a BCP 47 language tag.
+// Unknown languages use English grouping.
+func GroupDigits(n *big.Int, lang string) string {
+	grouping := lookupGrouping(lang)
+
+	digits := new(big.Int).Abs(n).String()
+	var groups []string
+	size := 3
+	for len(digits) > size {
+		groups = append(groups, digits[len(digits)-size:])
+		digits = digits[:len(digits)-size]
+		if grouping.indian {
+			size = 2
+		}
+	}
+	groups = append(groups, digits)
+
+	var b strings.Builder
+	if n.Sign() < 0 {
+		b.WriteByte('-')
+	}
+	for i := len(groups) - 1; i >= 0; i-- {
+		b.WriteString(groups[i])
+		if i > 0 {
+			b.WriteString(grouping.separator)
+		}
+	}
+	return b.String()
+}
+
+// lookupGrouping finds the grouping for lang, falling back to its base language
+// and then to English
+func lookupGrouping(lang string) digitGrouping {
+	lang = strings.ReplaceAll(lang, "_", "-")
+	base, region, _ := strings.Cut(lang, "-")
+	base = strings.ToLower(base)
+
+	if region != "" {
+		if grouping, found := digitGroupings[base+"-"+strings.ToUpper(region)]; found {
+			return grouping
+		}
+	}
+	if grouping, found := digitGroupings[base]; found {
+		return grouping
+	}
+	return digitGroupings["en"]
+}
@@ -0,0 +1,55 @@
+package doremid
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestGroupDigits(t *testing.T) {
+	tests := []struct {
+		n        int64
+		lang     string
+		expected string
+	}{
+		{0, "en", "0"},
+		{999, "en", "999"},
+		{1000, "en", "1,000"},
+		{597445632, "en-US", "597,445,632"},
+		{597445632, "de", "597.445.632"},
+		{597445632, "de-CH", "597\u2019445\u2019632"},
+		{597445632, "fr_FR", "597\u202f445\u202f632"},
+		{597445632, "ru", "597\u00a0445\u00a0632"},
+		{597445632, "hi-IN", "59,74,45,632"},
+		{123456, "en-IN", "1,23,456"},
+		{-1234567, "en", "-1,234,567"},
+		{1234, "xx", "1,234"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.lang, func(t *testing.T) {
+			if got := GroupDigits(big.NewInt(tt.n), tt.lang); got != tt.expected {
+				t.Errorf("expected '%s', got '%s'", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestFormatCombinations(t *testing.T) {
+	if got := NewWithDefaults().FormatCombinations("en"); got != "597,445,632" {
+		t.Errorf("expected '597,445,632', got '%s'", got)
+	}
+
+	// 7^20 * 12^10 overflows int64
+	huge := New(Config{
+		JustIntonationDigits:   20,
+		EqualTemperamentDigits: 10,
+		Separator:              "-",
+	})
+	expected := new(big.Int).Mul(new(big.Int).Exp(big.NewInt(7), big.NewInt(20), nil), new(big.Int).Exp(big.NewInt(12), big.NewInt(10), nil))
+	if huge.MaxCombinationsBig().Cmp(expected) != 0 {
+		t.Errorf("expected %s, got %s", expected, huge.MaxCombinationsBig())
+	}
+	if got := huge.FormatCombinations("en"); got != GroupDigits(expected, "en") {
+		t.Errorf("unexpected formatting '%s'", got)
+	}
+}
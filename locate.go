@@ -0,0 +1,77 @@
+package doremid
+
+import "fmt"
+
+// Topology describes how the segments of composite IDs map to where the
+// identified resources live
+type Topology struct {
+	// RegionSegment names the segment whose position indexes Regions, e.g.
+	// "region". Empty leaves Placement.Region empty.
+	RegionSegment string
+
+	// Regions lists region names by the position of the region segment
+	Regions []string
+
+	// ShardSegment names the segment whose hash selects a shard within the region,
+	// e.g. a random tail. Empty places every resource on shard 0.
+	ShardSegment string
+
+	// Shards is the number of shards per region. Zero or negative means one shard.
+	Shards int
+}
+
+// Placement is where a resource identified by a composite ID lives
+type Placement struct {
+	Region string
+	Shard  int
+}
+
+// Locate computes the placement of the resource identified by id from its
+// embedded segments, so gateways can route by ID without a lookup service.
+// Shards are chosen by the Hash64 of the shard segment's position, spreading
+// sequential positions as evenly as random ones.
+//
+// Returns a *ConfigError if topology names a segment the composite lacks, an error
+// wrapping a *RangeError if the region segment has no entry in Regions, or an
+// error from Parse if id is invalid.
+func (c *Composite) Locate(id string, topology Topology) (Placement, error) {
+	regionIndex, err := c.segmentIndex("RegionSegment", topology.RegionSegment)
+	if err != nil {
+		return Placement{}, err
+	}
+	shardIndex, err := c.segmentIndex("ShardSegment", topology.ShardSegment)
+	if err != nil {
+		return Placement{}, err
+	}
+
+	positions, err := c.Parse(id)
+	if err != nil {
+		return Placement{}, err
+	}
+
+	var placement Placement
+	if regionIndex >= 0 {
+		pos := positions[regionIndex]
+		if err := checkRange(pos, Range{Start: 0, End: int64(len(topology.Regions))}); err != nil {
+			return Placement{}, fmt.Errorf("segment %q: %w", topology.RegionSegment, err)
+		}
+		placement.Region = topology.Regions[pos]
+	}
+	if shardIndex >= 0 && topology.Shards > 1 {
+		placement.Shard = int(splitmix64(uint64(positions[shardIndex])) % uint64(topology.Shards))
+	}
+	return placement, nil
+}
+
+// segmentIndex returns the index of the segment called name, -1 if name is empty
+func (c *Composite) segmentIndex(field, name string) (int, error) {
+	if name == "" {
+		return -1, nil
+	}
+	for i, part := range c.parts {
+		if part.Name == name {
+			return i, nil
+		}
+	}
+	return -1, &ConfigError{Field: field, Reason: fmt.Sprintf("names no segment %q", name)}
+}
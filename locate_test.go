@@ -0,0 +1,80 @@
+package doremid
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLocate(t *testing.T) {
+	composite := newTestComposite()
+	topology := Topology{
+		RegionSegment: "region",
+		Regions:       []string{"eu-west", "us-east", "ap-south"},
+		ShardSegment:  "tail",
+		Shards:        4,
+	}
+
+	tests := []struct {
+		name     string
+		id       string
+		expected Placement
+	}{
+		{"first region", "do-0.dodo-6b4.do_00", Placement{Region: "eu-west", Shard: int(splitmix64(0) % 4)}},
+		{"third region", "do-2.dodo-6b4.do_05", Placement{Region: "ap-south", Shard: int(splitmix64(5) % 4)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			placement, err := composite.Locate(tt.id, topology)
+			if err != nil || placement != tt.expected {
+				t.Errorf("expected %+v, got %+v (err: %v)", tt.expected, placement, err)
+			}
+		})
+	}
+
+	// Shards spread evenly over the random tail
+	counts := make([]int, topology.Shards)
+	for pos := int64(0); pos < 1000; pos++ {
+		id, _ := composite.Format(1, 0, pos)
+		placement, err := composite.Locate(id, topology)
+		if err != nil || placement.Region != "us-east" {
+			t.Fatalf("unexpected placement %+v (err: %v)", placement, err)
+		}
+		counts[placement.Shard]++
+	}
+	for shard, count := range counts {
+		if count < 200 || count > 300 {
+			t.Errorf("expected about 250 IDs on shard %d, got %d", shard, count)
+		}
+	}
+}
+
+func TestLocateErrors(t *testing.T) {
+	composite := newTestComposite()
+	regions := []string{"eu-west", "us-east"}
+
+	tests := []struct {
+		name     string
+		id       string
+		topology Topology
+		expected error
+	}{
+		{"unknown region segment", "do-0.dodo-6b4.do_00", Topology{RegionSegment: "zone"}, ErrInvalidConfig},
+		{"unknown shard segment", "do-0.dodo-6b4.do_00", Topology{ShardSegment: "zone", Shards: 2}, ErrInvalidConfig},
+		{"unlisted region", "re-1.dodo-6b4.do_00", Topology{RegionSegment: "region", Regions: regions}, ErrOutOfRange},
+		{"invalid ID", "invalid", Topology{RegionSegment: "region", Regions: regions}, ErrInvalidID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := composite.Locate(tt.id, tt.topology); !errors.Is(err, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+
+	// Without segments everything lives in one place
+	if placement, err := composite.Locate("do-0.dodo-6b4.do_00", Topology{}); err != nil || placement != (Placement{}) {
+		t.Errorf("expected the zero placement, got %+v (err: %v)", placement, err)
+	}
+}
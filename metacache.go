@@ -0,0 +1,132 @@
+package doremid
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// errLoadPanicked is returned to callers sharing a call to Load that panicked
+var errLoadPanicked = errors.New("doremid: MetaCache Load panicked")
+
+// MetaCacheConfig configures a MetaCache
+type MetaCacheConfig struct {
+	// Store caches the snapshots. It must be set.
+	Store MetaStore
+
+	// Load builds the snapshot for position on a cache miss, e.g. by reading and
+	// serializing the record the ID refers to. It must be set.
+	Load func(ctx context.Context, position int64) ([]byte, error)
+
+	// TTL is how long snapshots stay cached. Zero never expires them.
+	TTL time.Duration
+
+	// OnError is called when Store fails, e.g. to log it. Cache failures never fail
+	// Get, which falls back to Load. Nil ignores them.
+	OnError func(ctx context.Context, position int64, err error)
+}
+
+// MetaCache is a read-through cache of metadata snapshots keyed by ID position,
+// the "ID to record snapshot" wrapper most consumers otherwise write themselves.
+//
+// Concurrent misses for the same position share a single call to Load, so a hot
+// ID expiring does not send a stampede of requests to the backing database. The
+// shared call runs with the context of the first caller; if it panics, the
+// other callers get an error. It is safe for concurrent use.
+type MetaCache struct {
+	g      *Generator
+	config MetaCacheConfig
+
+	mu    sync.Mutex
+	loads map[int64]*metaLoad // Loads in flight
+}
+
+// metaLoad is a call to Load shared by concurrent misses
+type metaLoad struct {
+	done  chan struct{}
+	value []byte
+	err   error
+}
+
+// NewMetaCache creates a MetaCache for the IDs of g.
+// Returns a *ConfigError if Store or Load is nil or TTL is negative.
+func (g *Generator) NewMetaCache(config MetaCacheConfig) (*MetaCache, error) {
+	switch {
+	case config.Store == nil:
+		return nil, &ConfigError{Field: "Store", Reason: "must not be nil"}
+	case config.Load == nil:
+		return nil, &ConfigError{Field: "Load", Reason: "must not be nil"}
+	case config.TTL < 0:
+		return nil, &ConfigError{Field: "TTL", Reason: "must not be negative"}
+	}
+	return &MetaCache{g: g, config: config, loads: make(map[int64]*metaLoad)}, nil
+}
+
+// Get returns the snapshot for id, loading and caching it on a miss.
+// Returns a *FormatError if id is invalid, or any error returned by Load.
+func (c *MetaCache) Get(ctx context.Context, id string) ([]byte, error) {
+	pos, err := c.g.decode(id)
+	if err != nil {
+		return nil, err
+	}
+
+	value, found, err := c.config.Store.Get(ctx, pos)
+	if err != nil {
+		c.report(ctx, pos, err)
+	} else if found {
+		return value, nil
+	}
+
+	c.mu.Lock()
+	load, shared := c.loads[pos]
+	if !shared {
+		load = &metaLoad{done: make(chan struct{})}
+		c.loads[pos] = load
+	}
+	c.mu.Unlock()
+
+	if shared {
+		select {
+		case <-load.done:
+			return load.value, load.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	// Release the waiters even if Load panics, which they see as an error while
+	// the panic continues in this caller
+	load.err = errLoadPanicked
+	defer func() {
+		c.mu.Lock()
+		delete(c.loads, pos)
+		c.mu.Unlock()
+		close(load.done)
+	}()
+
+	load.value, load.err = c.config.Load(ctx, pos)
+	if load.err == nil {
+		if err := c.config.Store.Set(ctx, pos, load.value, c.config.TTL); err != nil {
+			c.report(ctx, pos, err)
+		}
+	}
+	return load.value, load.err
+}
+
+// Invalidate removes the snapshot for id, e.g. after its record changes.
+// Returns a *FormatError if id is invalid, or any error returned by the store.
+func (c *MetaCache) Invalidate(ctx context.Context, id string) error {
+	pos, err := c.g.decode(id)
+	if err != nil {
+		return err
+	}
+	return c.config.Store.Delete(ctx, pos)
+}
+
+// report passes a store failure to OnError
+func (c *MetaCache) report(ctx context.Context, position int64, err error) {
+	if c.config.OnError != nil {
+		c.config.OnError(ctx, position, err)
+	}
+}
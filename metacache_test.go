@@ -0,0 +1,213 @@
+package doremid
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// failingMeta is a MetaStore whose every call fails
+type failingMeta struct{}
+
+func (failingMeta) Get(context.Context, int64) ([]byte, bool, error) {
+	return nil, false, errUnavailable
+}
+
+func (failingMeta) Set(context.Context, int64, []byte, time.Duration) error {
+	return errUnavailable
+}
+
+func (failingMeta) Delete(context.Context, int64) error {
+	return errUnavailable
+}
+
+func TestMetaCache(t *testing.T) {
+	ctx := context.Background()
+	generator := New(Config{JustIntonationDigits: 2, EqualTemperamentDigits: 3, Separator: "-"})
+	store := NewMemoryMetaStore()
+
+	var loads atomic.Int64
+	cache, err := generator.NewMetaCache(MetaCacheConfig{
+		Store: store,
+		TTL:   time.Hour,
+		Load: func(_ context.Context, position int64) ([]byte, error) {
+			loads.Add(1)
+			return []byte(fmt.Sprintf("record %d v%d", position, loads.Load())), nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for range 3 {
+		if value, err := cache.Get(ctx, "domi-1a2"); err != nil || string(value) != "record 3722 v1" {
+			t.Errorf("expected record 3722 v1, got %q (err: %v)", value, err)
+		}
+	}
+	if loads.Load() != 1 {
+		t.Errorf("expected 1 load, got %d", loads.Load())
+	}
+
+	if err := cache.Invalidate(ctx, "domi-1a2"); err != nil {
+		t.Fatal(err)
+	}
+	if value, _ := cache.Get(ctx, "domi-1a2"); string(value) != "record 3722 v2" {
+		t.Errorf("expected record 3722 v2 after invalidation, got %q", value)
+	}
+
+	if _, err := cache.Get(ctx, "domi-1a"); !errors.Is(err, ErrInvalidID) {
+		t.Errorf("expected ErrInvalidID, got %v", err)
+	}
+	if err := cache.Invalidate(ctx, "domi-1a"); !errors.Is(err, ErrInvalidID) {
+		t.Errorf("expected ErrInvalidID, got %v", err)
+	}
+}
+
+func TestMetaCacheStampede(t *testing.T) {
+	ctx := context.Background()
+	release := make(chan struct{})
+	var loads atomic.Int64
+	cache, _ := NewWithDefaults().NewMetaCache(MetaCacheConfig{
+		Store: NewMemoryMetaStore(),
+		Load: func(context.Context, int64) ([]byte, error) {
+			loads.Add(1)
+			<-release
+			return []byte("record"), nil
+		},
+	})
+
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if value, err := cache.Get(ctx, "dodododo-00000"); err != nil || string(value) != "record" {
+				t.Errorf("expected record, got %q (err: %v)", value, err)
+			}
+		}()
+	}
+
+	// Let every goroutine reach the cache before the load completes
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	if loads.Load() != 1 {
+		t.Errorf("expected 1 load, got %d", loads.Load())
+	}
+}
+
+func TestMetaCachePanic(t *testing.T) {
+	ctx := context.Background()
+	started, release := make(chan struct{}), make(chan struct{})
+	var loads atomic.Int64
+	cache, _ := NewWithDefaults().NewMetaCache(MetaCacheConfig{
+		Store: NewMemoryMetaStore(),
+		Load: func(context.Context, int64) ([]byte, error) {
+			if loads.Add(1) == 1 {
+				close(started)
+				<-release
+				panic("database driver bug")
+			}
+			return []byte("record"), nil
+		},
+	})
+
+	panicked := make(chan any)
+	go func() {
+		defer func() { panicked <- recover() }()
+		cache.Get(ctx, "dodododo-00000")
+	}()
+	<-started
+
+	// A caller sharing the load is released with an error instead of blocking
+	waited := make(chan error)
+	go func() {
+		_, err := cache.Get(ctx, "dodododo-00000")
+		waited <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	if r := <-panicked; r != "database driver bug" {
+		t.Errorf("expected the panic to reach the loading caller, got %v", r)
+	}
+	if err := <-waited; err == nil {
+		t.Error("expected an error for the shared load")
+	}
+
+	// The failed load is forgotten, so the next miss loads again
+	if value, err := cache.Get(ctx, "dodododo-00000"); err != nil || string(value) != "record" {
+		t.Errorf("expected record, got %q (err: %v)", value, err)
+	}
+}
+
+func TestMetaCacheErrors(t *testing.T) {
+	ctx := context.Background()
+	errMissing := errors.New("record missing")
+	var reported []error
+	var loads int
+	cache, _ := NewWithDefaults().NewMetaCache(MetaCacheConfig{
+		Store: failingMeta{},
+		Load: func(_ context.Context, position int64) ([]byte, error) {
+			loads++
+			if position != 0 {
+				return nil, errMissing
+			}
+			return []byte("record"), nil
+		},
+		OnError: func(_ context.Context, _ int64, err error) { reported = append(reported, err) },
+	})
+
+	// Store failures fall back to Load
+	if value, err := cache.Get(ctx, "dodododo-00000"); err != nil || string(value) != "record" {
+		t.Errorf("expected record, got %q (err: %v)", value, err)
+	}
+	if len(reported) != 2 || !errors.Is(reported[0], errUnavailable) {
+		t.Errorf("expected Get and Set failures reported, got %v", reported)
+	}
+
+	if _, err := cache.Get(ctx, "dodododo-00001"); !errors.Is(err, errMissing) {
+		t.Errorf("expected errMissing, got %v", err)
+	}
+	if err := cache.Invalidate(ctx, "dodododo-00000"); !errors.Is(err, errUnavailable) {
+		t.Errorf("expected errUnavailable, got %v", err)
+	}
+
+	waiting, cancel := context.WithCancel(ctx)
+	cancel()
+	blocked, _ := NewWithDefaults().NewMetaCache(MetaCacheConfig{
+		Store: NewMemoryMetaStore(),
+		Load: func(ctx context.Context, _ int64) ([]byte, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	})
+	if _, err := blocked.Get(waiting, "dodododo-00000"); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestNewMetaCacheConfig(t *testing.T) {
+	load := func(context.Context, int64) ([]byte, error) { return nil, nil }
+	tests := []struct {
+		config MetaCacheConfig
+		field  string
+	}{
+		{MetaCacheConfig{Load: load}, "Store"},
+		{MetaCacheConfig{Store: NewMemoryMetaStore()}, "Load"},
+		{MetaCacheConfig{Store: NewMemoryMetaStore(), Load: load, TTL: -time.Second}, "TTL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			_, err := NewWithDefaults().NewMetaCache(tt.config)
+			var configErr *ConfigError
+			if !errors.As(err, &configErr) || configErr.Field != tt.field {
+				t.Errorf("expected ConfigError for %s, got %v", tt.field, err)
+			}
+		})
+	}
+}
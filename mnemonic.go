@@ -0,0 +1,144 @@
+package doremid
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// mnemonicBar separates the notes from the characters of a mnemonic
+const mnemonicBar = "|"
+
+// Mnemonic renders id as notation that can be sung or read out, e.g. over the
+// phone: the notes separated by spaces, a bar, then each character as a pitch
+// of the chromatic scale, "domi-1a2" as "do mi | C#4 A#4 D4". Characters 0 to b
+// are C4 to B4 and higher ones continue into the next octaves, so alphabets of
+// up to 36 characters end on B6. Any prefix comes first and any check character
+// last, and ParseMnemonic reads the mnemonic back.
+// Returns a *FormatError if id is invalid.
+func (g *Generator) Mnemonic(id string) (string, error) {
+	compact, err := g.Compact(id)
+	if err != nil {
+		return "", err
+	}
+
+	names := strings.Fields(ChromaticCharacters)
+	var b strings.Builder
+	if g.prefix != "" {
+		b.WriteString(g.prefix)
+		b.WriteByte(' ')
+	}
+	offset := len(g.prefix)
+	for i := 0; i < g.JustIntonationDigits; i++ {
+		_, width, _ := g.nextNote(compact, offset)
+		b.WriteString(compact[offset : offset+width])
+		b.WriteByte(' ')
+		offset += width
+	}
+	b.WriteString(mnemonicBar)
+	for _, char := range []byte(compact[offset+len(g.Separator):]) {
+		index := g.equalTemperamentMap[char]
+		b.WriteByte(' ')
+		b.WriteString(names[index%12])
+		b.WriteString(strconv.Itoa(4 + index/12))
+	}
+	return b.String(), nil
+}
+
+// ParseMnemonic reads a mnemonic written by Mnemonic back into the ID it stands
+// for, in canonical form. Words may be separated by any whitespace, notes may be
+// Config.InputAliases, and pitches may be written in either case with flats,
+// e.g. "bb4" for A#4, or the symbols ♯ and ♭.
+// Returns a *FormatError if mnemonic is invalid; offsets refer to its bytes.
+func (g *Generator) ParseMnemonic(mnemonic string) (string, error) {
+	// Leave room for twice the bytes of a mnemonic with pitches such as "C♯4"
+	characters := g.EqualTemperamentDigits + g.checksumLen()
+	longest := 2 * (len(g.prefix) + g.JustIntonationDigits*(g.maxNoteLen+1) + len(mnemonicBar) + characters*len(" C♯4") + 1)
+	if len(mnemonic) > longest {
+		return "", &FormatError{Input: truncate(mnemonic, longest), Offset: -1, Reason: fmt.Sprintf("mnemonic exceeds %d bytes", longest), Err: ErrInvalidFormat}
+	}
+
+	rest := strings.TrimLeftFunc(mnemonic, unicode.IsSpace)
+	if !strings.HasPrefix(rest, g.prefix) {
+		offset := len(mnemonic) - len(rest)
+		return "", &FormatError{Input: mnemonic, Offset: offset, Symbol: symbolAt(mnemonic, offset, len(g.prefix)), Reason: fmt.Sprintf("missing prefix %q", g.prefix), Err: ErrInvalidFormat}
+	}
+	words, offsets := splitWords(mnemonic, len(mnemonic)-len(rest)+len(g.prefix))
+
+	if len(words) != g.JustIntonationDigits+1+characters || words[g.JustIntonationDigits] != mnemonicBar {
+		return "", &FormatError{Input: mnemonic, Offset: -1, Reason: fmt.Sprintf("must hold %d notes, %q and %d pitches", g.JustIntonationDigits, mnemonicBar, characters), Err: ErrInvalidFormat}
+	}
+
+	id := []byte(g.prefix)
+	for i, word := range words[:g.JustIntonationDigits] {
+		note, found := g.justIntonationMap[word]
+		for _, alias := range g.aliases {
+			if !found && word == alias.spelling {
+				note, found = alias.note, true
+			}
+		}
+		if !found {
+			return "", &FormatError{Input: mnemonic, Offset: offsets[i], Symbol: word, Reason: "unknown note", Err: ErrBadCharacter}
+		}
+		id = append(id, g.justIntonationBytes[note]...)
+	}
+	id = append(id, g.Separator...)
+	for i, word := range words[g.JustIntonationDigits+1:] {
+		index, ok := parsePitch(word)
+		if !ok || index < 0 || index >= g.equalTemperamentLen {
+			return "", &FormatError{Input: mnemonic, Offset: offsets[g.JustIntonationDigits+1+i], Symbol: word, Reason: "unknown pitch", Err: ErrBadCharacter}
+		}
+		id = append(id, g.equalTemperamentBytes[index])
+	}
+
+	// Check the ID, e.g. its check character
+	return g.Canonicalize(string(id))
+}
+
+// splitWords splits s from offset into whitespace-separated words and their
+// byte offsets
+func splitWords(s string, offset int) (words []string, offsets []int) {
+	start := -1
+	for i, r := range s[offset:] {
+		switch {
+		case unicode.IsSpace(r) && start >= 0:
+			words, offsets = append(words, s[start:offset+i]), append(offsets, start)
+			start = -1
+		case !unicode.IsSpace(r) && start < 0:
+			start = offset + i
+		}
+	}
+	if start >= 0 {
+		words, offsets = append(words, s[start:]), append(offsets, start)
+	}
+	return words, offsets
+}
+
+// pitchSemitones places the natural pitches within the octave from C
+var pitchSemitones = map[rune]int{'c': 0, 'd': 2, 'e': 4, 'f': 5, 'g': 7, 'a': 9, 'b': 11}
+
+// parsePitch returns the character index of a pitch such as "C#4" or "Db4",
+// the number of semitones above C4
+func parsePitch(pitch string) (int, bool) {
+	runes := []rune(strings.ToLower(pitch))
+	if len(runes) < 2 {
+		return 0, false
+	}
+	semitone, found := pitchSemitones[runes[0]]
+	if !found {
+		return 0, false
+	}
+	octave := string(runes[1:])
+	switch runes[1] {
+	case '#', '♯':
+		semitone, octave = semitone+1, string(runes[2:])
+	case 'b', '♭':
+		semitone, octave = semitone-1, string(runes[2:])
+	}
+	n, err := strconv.Atoi(octave)
+	if err != nil || len(octave) > 2 || octave[0] < '0' || octave[0] > '9' {
+		return 0, false
+	}
+	return (n-4)*12 + semitone, true
+}
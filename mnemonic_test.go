@@ -0,0 +1,112 @@
+package doremid
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMnemonic(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   Config
+		id       string
+		mnemonic string
+	}{
+		{"default", Config{}, "domi-1a2", "do mi | C#4 A#4 D4"},
+		{"lowest", Config{}, "dodo-000", "do do | C4 C4 C4"},
+		{"highest", Config{}, "titi-bbb", "ti ti | B4 B4 B4"},
+		{"checksum", Config{Checksum: true}, "domi-1a26", "do mi | C#4 A#4 D4 F#4"},
+		{"prefix", Config{Prefix: "usr"}, "usr_domi-1a2", "usr_ do mi | C#4 A#4 D4"},
+		{"grouped", Config{GroupSize: 1, NoteGroupSeparator: ".", CharacterGroupSeparator: "."}, "do.mi-1.a.2", "do mi | C#4 A#4 D4"},
+		{"base 36", Config{EqualTemperamentBase: 36}, "domi-coz", "do mi | C5 C6 B6"},
+		{"custom notes", Config{Notes: "ut re mi fa sol la si"}, "solut-1a2", "sol ut | C#4 A#4 D4"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.JustIntonationDigits, tt.config.EqualTemperamentDigits, tt.config.Separator = 2, 3, "-"
+			generator := New(tt.config)
+
+			mnemonic, err := generator.Mnemonic(tt.id)
+			if err != nil || mnemonic != tt.mnemonic {
+				t.Errorf("expected '%s', got '%s' (err: %v)", tt.mnemonic, mnemonic, err)
+			}
+			id, err := generator.ParseMnemonic(tt.mnemonic)
+			if err != nil || id != tt.id {
+				t.Errorf("expected '%s', got '%s' (err: %v)", tt.id, id, err)
+			}
+		})
+	}
+}
+
+func TestParseMnemonic(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 3,
+		Separator:              "-",
+		InputAliases:           map[string]string{"sol": "so"},
+	})
+
+	valid := []struct {
+		mnemonic string
+		id       string
+	}{
+		{"do mi | C#4 A#4 D4", "domi-1a2"},
+		{"  do\tmi |\nc#4 a#4 d4 ", "domi-1a2"},
+		{"do mi | Db4 Bb4 D4", "domi-1a2"},
+		{"do mi | C♯4 B♭4 D4", "domi-1a2"},
+		{"do mi | B#3 A#4 D4", "domi-0a2"},
+		{"sol la | C4 C4 C4", "sola-000"},
+	}
+	for _, tt := range valid {
+		if id, err := generator.ParseMnemonic(tt.mnemonic); err != nil || id != tt.id {
+			t.Errorf("expected '%s' from '%s', got '%s' (err: %v)", tt.id, tt.mnemonic, id, err)
+		}
+	}
+
+	tests := []struct {
+		mnemonic string
+		offset   int
+		symbol   string
+		expected error
+	}{
+		{"do mi C#4 A#4 D4", -1, "", ErrInvalidFormat},
+		{"do mi | C#4 A#4", -1, "", ErrInvalidFormat},
+		{"do mi | C#4 A#4 D4 D4", -1, "", ErrInvalidFormat},
+		{"do xi | C#4 A#4 D4", 3, "xi", ErrBadCharacter},
+		{"do mi | C#4 H4 D4", 12, "H4", ErrBadCharacter},
+		{"do mi | C#4 C5 D4", 12, "C5", ErrBadCharacter},
+		{"do mi | C#4 Cb4 D4", 12, "Cb4", ErrBadCharacter},
+		{"do mi | C#4 C D4", 12, "C", ErrBadCharacter},
+		{"do mi | C#4 C+4 D4", 12, "C+4", ErrBadCharacter},
+		{"do mi | C#4 C400 D4", 12, "C400", ErrBadCharacter},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mnemonic, func(t *testing.T) {
+			_, err := generator.ParseMnemonic(tt.mnemonic)
+			if !errors.Is(err, tt.expected) {
+				t.Fatalf("expected %v, got %v", tt.expected, err)
+			}
+			var formatErr *FormatError
+			if !errors.As(err, &formatErr) || formatErr.Offset != tt.offset || formatErr.Symbol != tt.symbol {
+				t.Errorf("expected offset %d and symbol %q, got %v", tt.offset, tt.symbol, err)
+			}
+		})
+	}
+
+	checked := New(Config{JustIntonationDigits: 2, EqualTemperamentDigits: 3, Separator: "-", Checksum: true})
+	if _, err := checked.ParseMnemonic("do mi | C#4 A#4 D4 G4"); !errors.Is(err, ErrChecksum) {
+		t.Errorf("expected ErrChecksum, got %v", err)
+	}
+	prefixed := New(Config{JustIntonationDigits: 2, EqualTemperamentDigits: 3, Separator: "-", Prefix: "usr"})
+	if _, err := prefixed.ParseMnemonic("ord_ do mi | C#4 A#4 D4"); !errors.Is(err, ErrInvalidFormat) {
+		t.Errorf("expected ErrInvalidFormat for a missing prefix, got %v", err)
+	}
+	if _, err := generator.Mnemonic("domi_1a2"); !errors.Is(err, ErrInvalidID) {
+		t.Errorf("expected ErrInvalidID, got %v", err)
+	}
+	if _, err := generator.ParseMnemonic(string(make([]byte, 1000))); !errors.Is(err, ErrInvalidFormat) {
+		t.Errorf("expected ErrInvalidFormat for an oversized mnemonic, got %v", err)
+	}
+}
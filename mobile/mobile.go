@@ -0,0 +1,118 @@
+// Package mobile provides a gomobile-friendly binding surface for doremid.
+//
+// The exported API only uses types gomobile can bind (int64, string, error and
+// pointers to structs), so iOS and Android apps can generate and validate IDs
+// offline with exactly the same semantics as the Go backend:
+//
+//	gomobile bind -target=android github.com/doremi-id/doremid/mobile
+//	gomobile bind -target=ios github.com/doremi-id/doremid/mobile
+package mobile
+
+import (
+	"sync"
+
+	"github.com/doremi-id/doremid"
+)
+
+// Generator wraps doremid.Generator for mobile platforms.
+// It is safe for concurrent use from multiple threads.
+type Generator struct {
+	mu        sync.Mutex
+	generator *doremid.Generator
+}
+
+// NewGenerator creates a generator with the given configuration
+func NewGenerator(justIntonationDigits, equalTemperamentDigits int64, separator string) (*Generator, error) {
+	if justIntonationDigits <= 0 {
+		return nil, &doremid.ConfigError{Field: "JustIntonationDigits", Reason: "must be positive"}
+	}
+	if equalTemperamentDigits <= 0 {
+		return nil, &doremid.ConfigError{Field: "EqualTemperamentDigits", Reason: "must be positive"}
+	}
+
+	return &Generator{
+		generator: doremid.New(doremid.Config{
+			JustIntonationDigits:   int(justIntonationDigits),
+			EqualTemperamentDigits: int(equalTemperamentDigits),
+			Separator:              separator,
+		}),
+	}, nil
+}
+
+// NewDefaultGenerator creates a generator with the default configuration
+func NewDefaultGenerator() *Generator {
+	return &Generator{generator: doremid.NewWithDefaults()}
+}
+
+// NewID generates a random ID
+func (g *Generator) NewID() string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.generator.NewID()
+}
+
+// Validate returns an error if id is not a valid ID for this generator
+func (g *Generator) Validate(id string) error {
+	_, err := g.IDToPosition(id)
+	return err
+}
+
+// IDToPosition converts an ID to its position in the sequential order
+func (g *Generator) IDToPosition(id string) (int64, error) {
+	return g.generator.IDToPositionE(id)
+}
+
+// PositionToID converts a position to its ID
+func (g *Generator) PositionToID(position int64) (string, error) {
+	return g.generator.Mint(position)
+}
+
+// MaxCombinations returns the maximum number of unique IDs
+func (g *Generator) MaxCombinations() int64 {
+	return g.generator.MaxCombinations()
+}
+
+// BatchGenerateIDs generates count sequential IDs starting at startPosition.
+// The list may be shorter than count if it would exceed the maximum combinations.
+func (g *Generator) BatchGenerateIDs(count, startPosition int64) (*IDList, error) {
+	if count <= 0 {
+		return nil, doremid.ErrInvalidCount
+	}
+	if maxCombinations := g.generator.MaxCombinations(); startPosition < 0 || startPosition >= maxCombinations {
+		return nil, &doremid.RangeError{Position: startPosition, Max: maxCombinations}
+	}
+	return &IDList{ids: g.generator.BatchGenerateIDs(count, startPosition)}, nil
+}
+
+// BatchGenerateRandomIDs generates count unique random IDs
+func (g *Generator) BatchGenerateRandomIDs(count int64) (*IDList, error) {
+	if count <= 0 {
+		return nil, doremid.ErrInvalidCount
+	}
+	if count > g.generator.MaxCombinations() {
+		return nil, doremid.ErrSpaceExhausted
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return &IDList{ids: g.generator.BatchGenerateRandomIDs(count)}, nil
+}
+
+// IDList is an immutable list of IDs. gomobile cannot bind string slices,
+// so batches are returned through this accessor type.
+type IDList struct {
+	ids []string
+}
+
+// Len returns the number of IDs in the list
+func (l *IDList) Len() int64 {
+	return int64(len(l.ids))
+}
+
+// Get returns the ID at index
+func (l *IDList) Get(index int64) (string, error) {
+	if index < 0 || index >= int64(len(l.ids)) {
+		return "", &doremid.RangeError{Position: index, Max: int64(len(l.ids))}
+	}
+	return l.ids[index], nil
+}
@@ -0,0 +1,99 @@
+package mobile
+
+import (
+	"testing"
+
+	"github.com/doremi-id/doremid"
+)
+
+func TestNewGenerator(t *testing.T) {
+	if _, err := NewGenerator(0, 2, "-"); err == nil {
+		t.Error("expected error for zero just intonation digits")
+	}
+	if _, err := NewGenerator(1, -2, "-"); err == nil {
+		t.Error("expected error for negative equal temperament digits")
+	}
+
+	g, err := NewGenerator(1, 2, "-")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if g.MaxCombinations() != 7*144 {
+		t.Errorf("expected max combinations %d, got %d", 7*144, g.MaxCombinations())
+	}
+}
+
+func TestSameSemanticsAsBackend(t *testing.T) {
+	g := NewDefaultGenerator()
+	backend := doremid.NewWithDefaults()
+
+	for _, pos := range []int64{0, 1, 12345, backend.MaxCombinations() - 1} {
+		id, err := g.PositionToID(pos)
+		if err != nil {
+			t.Fatalf("PositionToID(%d) returned error: %v", pos, err)
+		}
+		if expected := backend.PositionToID(pos); id != expected {
+			t.Errorf("PositionToID(%d): expected '%s', got '%s'", pos, expected, id)
+		}
+
+		back, err := g.IDToPosition(id)
+		if err != nil || back != pos {
+			t.Errorf("IDToPosition('%s'): expected %d, got %d (err: %v)", id, pos, back, err)
+		}
+	}
+
+	if err := g.Validate(g.NewID()); err != nil {
+		t.Errorf("generated ID should validate: %v", err)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	g, _ := NewGenerator(1, 1, "-")
+
+	if err := g.Validate("dx-0"); err == nil {
+		t.Error("expected invalid ID error")
+	}
+	if _, err := g.PositionToID(-1); err == nil {
+		t.Error("expected error for negative position")
+	}
+	if _, err := g.PositionToID(84); err == nil {
+		t.Error("expected error for position beyond maximum")
+	}
+	if _, err := g.BatchGenerateIDs(0, 0); err == nil {
+		t.Error("expected error for zero count")
+	}
+	if _, err := g.BatchGenerateRandomIDs(85); err == nil {
+		t.Error("expected error for count beyond maximum")
+	}
+}
+
+func TestIDList(t *testing.T) {
+	g, _ := NewGenerator(1, 1, "-")
+
+	list, err := g.BatchGenerateIDs(10, 80)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if list.Len() != 4 {
+		t.Errorf("expected 4 IDs, got %d", list.Len())
+	}
+	if id, err := list.Get(0); err != nil || id != "ti-8" {
+		t.Errorf("expected 'ti-8', got '%s' (err: %v)", id, err)
+	}
+	if _, err := list.Get(4); err == nil {
+		t.Error("expected error for index out of range")
+	}
+
+	random, err := g.BatchGenerateRandomIDs(84)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	seen := make(map[string]bool)
+	for i := int64(0); i < random.Len(); i++ {
+		id, _ := random.Get(i)
+		if seen[id] {
+			t.Errorf("duplicate ID '%s'", id)
+		}
+		seen[id] = true
+	}
+}
@@ -0,0 +1,51 @@
+package doremid
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+)
+
+// ResourceNamer derives deterministic, friendly names for infrastructure resources,
+// e.g. from Terraform or deployment tooling. The same inputs always produce the
+// same name, across processes and library versions.
+type ResourceNamer struct {
+	// Prefix is prepended verbatim, e.g. "web-"
+	Prefix string
+
+	// Generator determines the suffix format and length. Nil uses a compact
+	// format with two notes and three characters, e.g. "domi-1a2".
+	Generator *Generator
+}
+
+// ResourceName returns the prefix followed by an ID derived from a hash of
+// seedInputs. Inputs are length-delimited before hashing, so ("ab", "c") and
+// ("a", "bc") produce different names. Returns just the prefix if the
+// generator may not mint any position.
+func (n ResourceNamer) ResourceName(seedInputs ...string) string {
+	g := n.Generator
+	if g == nil {
+		g = New(Config{JustIntonationDigits: 2, EqualTemperamentDigits: 3, Separator: "-"})
+	}
+
+	r := g.mintRange()
+	if r.Len() == 0 {
+		return n.Prefix
+	}
+
+	h := sha256.New()
+	var length [8]byte
+	for _, input := range seedInputs {
+		binary.BigEndian.PutUint64(length[:], uint64(len(input)))
+		h.Write(length[:])
+		h.Write([]byte(input))
+	}
+	sum := binary.BigEndian.Uint64(h.Sum(nil))
+
+	return n.Prefix + g.PositionToID(r.Start+int64(sum%uint64(r.Len())))
+}
+
+// ResourceName returns a deterministic ID derived from a hash of seedInputs,
+// without a prefix. See ResourceNamer.
+func (g *Generator) ResourceName(seedInputs ...string) string {
+	return ResourceNamer{Generator: g}.ResourceName(seedInputs...)
+}
@@ -0,0 +1,34 @@
+package doremid
+
+import "testing"
+
+func TestResourceName(t *testing.T) {
+	namer := ResourceNamer{Prefix: "web-"}
+
+	first := namer.ResourceName("prod", "eu-west-1", "frontend")
+	if first != namer.ResourceName("prod", "eu-west-1", "frontend") {
+		t.Error("expected identical inputs to produce identical names")
+	}
+	if first == namer.ResourceName("prod", "eu-west-1", "backend") {
+		t.Error("expected different inputs to produce different names")
+	}
+	if namer.ResourceName("ab", "c") == namer.ResourceName("a", "bc") {
+		t.Error("expected input boundaries to affect the name")
+	}
+
+	// Names must never change between versions
+	if first != "web-tiso-537" {
+		t.Errorf("expected stable name 'web-tiso-537', got '%s'", first)
+	}
+
+	generator := NewWithDefaults()
+	name := generator.ResourceName("prod", "eu-west-1", "frontend")
+	if generator.IDToPosition(name) < 0 {
+		t.Errorf("expected a valid ID, got '%s'", name)
+	}
+
+	restricted := generator.Restrict(Range{Start: 100, End: 110})
+	if pos := restricted.IDToPosition(restricted.ResourceName("x")); pos < 100 || pos >= 110 {
+		t.Errorf("expected position in [100, 110), got %d", pos)
+	}
+}
@@ -0,0 +1,144 @@
+package doremid
+
+import (
+	"slices"
+	"unicode/utf8"
+)
+
+// Nearest suggests the valid IDs closest to id, e.g. for a "did you mean"
+// prompt after a mistyped ID fails to parse. Distance counts the symbols to
+// insert, delete or replace, where a note is one symbol however it is spelled:
+// "dp" for "do" is one edit, as is "re" for "do". The prefix and separator
+// count as one symbol each, and a mismatched check character is one more edit.
+//
+// IDs within maxDistance are returned nearest first, then in position order,
+// in canonical form. Only IDs the generator may mint are suggested, so
+// reserved, filtered and restricted positions are left out. The number of
+// suggestions grows quickly with maxDistance; 1 or 2 suits typo recovery.
+// Returns nil if maxDistance is negative or id exceeds MaxParseLength.
+func (g *Generator) Nearest(id string, maxDistance int) []string {
+	if maxDistance < 0 || g.MaxParseLength >= 0 && len(id) > g.MaxParseLength {
+		return nil
+	}
+	id = g.ungroup(g.unalias(id))
+	s := &nearestSearch{g: g, input: id, max: maxDistance}
+	for offset := range id {
+		if utf8.RuneStart(id[offset]) {
+			s.bounds = append(s.bounds, offset)
+		}
+	}
+	s.bounds = append(s.bounds, len(id))
+
+	row := make([]int, len(s.bounds))
+	for k := range row {
+		row[k] = k
+	}
+	if g.prefix != "" {
+		row = s.step(row, g.prefix)
+	}
+	s.visit(0, row, []byte(g.prefix), 0)
+
+	slices.SortStableFunc(s.matches, func(a, b nearestMatch) int {
+		return a.distance - b.distance
+	})
+	ids := make([]string, len(s.matches))
+	for i, match := range s.matches {
+		ids[i] = match.id
+	}
+	return ids
+}
+
+// nearestSearch walks the IDs of a generator symbol by symbol, keeping a row of
+// edit distances to the input for the symbols walked so far
+type nearestSearch struct {
+	g       *Generator
+	input   string
+	bounds  []int // Byte offsets of the runes of input, then its length
+	max     int
+	matches []nearestMatch
+}
+
+// nearestMatch is a suggested ID and its distance to the input
+type nearestMatch struct {
+	id       string
+	distance int
+}
+
+// visit extends id, whose last symbol is number i counting notes and then
+// characters, while its distance to some prefix of the input stays in range.
+// sum is the Luhn sum of the symbols so far.
+func (s *nearestSearch) visit(i int, row []int, id []byte, sum int) {
+	g := s.g
+	if slices.Min(row) > s.max {
+		return
+	}
+
+	switch {
+	case i < g.JustIntonationDigits:
+		for note, token := range g.justIntonationBytes {
+			next := sum
+			if g.checksum {
+				next = g.checksumAdd(sum, i, note)
+			}
+			s.visit(i+1, s.step(row, string(token)), append(id, token...), next)
+		}
+		return
+	case i == g.JustIntonationDigits && g.Separator != "":
+		row = s.step(row, g.Separator)
+		id = append(id, g.Separator...)
+	}
+
+	if j := i - g.JustIntonationDigits; j < g.EqualTemperamentDigits {
+		for index, char := range g.equalTemperamentBytes {
+			next := sum
+			if g.checksum {
+				next = g.checksumAdd(sum, i, index)
+			}
+			s.visit(i+1, s.step(row, string(char)), append(id, char), next)
+		}
+		return
+	}
+
+	if g.checksum {
+		check := g.checkCharacter(sum)
+		row = s.step(row, string(check))
+		id = append(id, check)
+	}
+	if distance := row[len(row)-1]; distance <= s.max && s.mintable(string(id)) {
+		canonical := string(id)
+		if g.grouped() {
+			canonical = g.group(canonical)
+		}
+		s.matches = append(s.matches, nearestMatch{id: canonical, distance: distance})
+	}
+}
+
+// step returns the row of edit distances after appending token to the IDs
+// walked. Replacing a span of up to one rune more than token costs one edit,
+// so a misspelled note counts once.
+func (s *nearestSearch) step(row []int, token string) []int {
+	next := make([]int, len(row))
+	next[0] = row[0] + 1
+	span := utf8.RuneCountInString(token) + 1
+	for k := 1; k < len(row); k++ {
+		next[k] = min(row[k]+1, next[k-1]+1)
+		for j := max(k-span, 0); j < k; j++ {
+			cost := 1
+			if s.input[s.bounds[j]:s.bounds[k]] == token {
+				cost = 0
+			}
+			next[k] = min(next[k], row[j]+cost)
+		}
+	}
+	return next
+}
+
+// mintable reports whether the generator may mint the valid compact id
+func (s *nearestSearch) mintable(id string) bool {
+	g := s.g
+	if g.restriction == nil && g.reserved == nil && g.wordFilter == nil {
+		return true
+	}
+	position, err := g.IDToPositionE(id)
+	return err == nil && g.mintRange().Contains(position) && g.allowed(position)
+}
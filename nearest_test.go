@@ -0,0 +1,113 @@
+package doremid
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNearest(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 3,
+		Separator:              "-",
+	})
+
+	tests := []struct {
+		name     string
+		input    string
+		distance int
+		count    int
+		first    string
+		includes []string
+	}{
+		{"valid", "domi-1a2", 0, 1, "domi-1a2", nil},
+		{"misspelled note", "dpmi-1a2", 1, 7, "domi-1a2", []string{"remi-1a2", "timi-1a2"}},
+		{"unknown note", "doni-1a2", 1, 7, "dodo-1a2", []string{"domi-1a2", "doti-1a2"}},
+		{"unknown character", "domi-1c2", 1, 12, "domi-102", []string{"domi-1a2", "domi-1b2"}},
+		{"missing separator", "domi1a2", 1, 1, "domi-1a2", nil},
+		{"missing character", "domi-1a", 1, 34, "domi-01a", []string{"domi-1a2", "domi-1ab"}},
+		{"extra character", "domi-1a2x", 1, 12, "domi-1a0", []string{"domi-1a2"}},
+		{"too far", "dmoi-1a2", 1, 0, "", nil},
+		{"two edits", "dpmi-1c2", 2, 7 * 12, "domi-102", []string{"domi-1a2", "timi-1b2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ids := generator.Nearest(tt.input, tt.distance)
+			if len(ids) != tt.count {
+				t.Fatalf("expected %d suggestions, got %d: %v", tt.count, len(ids), ids)
+			}
+			if tt.count > 0 && ids[0] != tt.first {
+				t.Errorf("expected '%s' first, got %v", tt.first, ids)
+			}
+			for _, id := range tt.includes {
+				if !slices.Contains(ids, id) {
+					t.Errorf("expected '%s' among %v", id, ids)
+				}
+			}
+			for _, id := range ids {
+				if err := generator.Validate(id); err != nil {
+					t.Errorf("expected valid suggestion, got %v", err)
+				}
+			}
+		})
+	}
+
+	// Nearer IDs come first
+	if ids := generator.Nearest("domi-1a2", 1); ids[0] != "domi-1a2" || len(ids) != 1+2*6+3*11 {
+		t.Errorf("expected 'domi-1a2' and its %d neighbours, got %v", 2*6+3*11, ids)
+	}
+	if ids := generator.Nearest("domi-1a2", -1); ids != nil {
+		t.Errorf("expected nil for a negative distance, got %v", ids)
+	}
+	if ids := generator.Nearest(string(make([]byte, generator.MaxParseLength+1)), 1); ids != nil {
+		t.Errorf("expected nil for oversized input, got %v", ids)
+	}
+}
+
+func TestNearestConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   Config
+		input    string
+		expected []string
+	}{
+		{"checksum", Config{Checksum: true}, "domi-1a2", []string{"domi-1a26"}},
+		{"wrong check character", Config{Checksum: true}, "domi-1a27", []string{"domi-1a26"}},
+		{"prefix", Config{Prefix: "usr"}, "urs_domi-1a2", []string{"usr_domi-1a2"}},
+		{"prefix and character", Config{Prefix: "usr"}, "urs_domi-1a", nil},
+		{"grouped", Config{GroupSize: 1, NoteGroupSeparator: ".", CharacterGroupSeparator: "."}, "do.mi-1.a.c", []string{"do.mi-1.a.0"}},
+		{"aliases", Config{InputAliases: map[string]string{"sol": "so"}}, "solmi-1c2", []string{"somi-1a2"}},
+		{"multi-byte", Config{Notes: "ド レ ミ ファ ソ ラ シ", Separator: "・"}, "ドミ・1á2", []string{"ドミ・102"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.JustIntonationDigits, tt.config.EqualTemperamentDigits = 2, 3
+			if tt.config.Separator == "" {
+				tt.config.Separator = "-"
+			}
+			ids := New(tt.config).Nearest(tt.input, 1)
+			if len(ids) == 0 && tt.expected != nil || len(ids) > 0 && tt.expected == nil {
+				t.Fatalf("expected %v, got %v", tt.expected, ids)
+			}
+			for _, id := range tt.expected {
+				if !slices.Contains(ids, id) {
+					t.Errorf("expected '%s' among %v", id, ids)
+				}
+			}
+		})
+	}
+
+	// Only mintable IDs are suggested
+	reserved := New(Config{JustIntonationDigits: 2, EqualTemperamentDigits: 3, Separator: "-", ReservedIDs: []string{"domi-1a2"}})
+	if ids := reserved.Nearest("domi-1a2", 0); len(ids) != 0 {
+		t.Errorf("expected no reserved suggestion, got %v", ids)
+	}
+	restricted := New(Config{JustIntonationDigits: 2, EqualTemperamentDigits: 3, Separator: "-"}).Restrict(Range{Start: 0, End: 1728})
+	for _, id := range restricted.Nearest("domi-1a2", 1) {
+		if id[:2] != "do" || id[2:4] != "do" {
+			t.Errorf("expected only IDs in the restricted range, got '%s'", id)
+		}
+	}
+}
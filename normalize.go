@@ -0,0 +1,33 @@
+package doremid
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// FoldWidth rewrites the fullwidth forms of ASCII, U+FF01 to U+FF5E, and the
+// ideographic space in s to ASCII, so "ｄｏｍｉ－１ａ２" becomes "domi-1a2". Use it
+// as Config.UnicodeNormalize where IDs are typed with East Asian input methods.
+// Other bytes, including invalid UTF-8, are kept.
+func FoldWidth(s string) string {
+	// Every rune folded starts with one of these bytes in UTF-8
+	if strings.IndexByte(s, 0xef) < 0 && strings.IndexByte(s, 0xe3) < 0 {
+		return s
+	}
+
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r >= 0xff01 && r <= 0xff5e:
+			b.WriteByte(byte(r - 0xff01 + '!'))
+		case r == 0x3000:
+			b.WriteByte(' ')
+		default:
+			b.WriteString(s[i : i+size])
+		}
+		i += size
+	}
+	return b.String()
+}
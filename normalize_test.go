@@ -0,0 +1,62 @@
+package doremid
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestFoldWidth(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"domi-1a2", "domi-1a2"},
+		{"ｄｏｍｉ－１ａ２", "domi-1a2"},
+		{"ＤＯＭＩ＿１Ａ２", "DOMI_1A2"},
+		{"ｄｏ　ｍｉ", "do mi"},
+		{"音ｄｏ\xff～", "音do\xff~"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if folded := FoldWidth(tt.input); folded != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, folded)
+			}
+		})
+	}
+}
+
+func TestUnicodeNormalize(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 3,
+		Separator:              "-",
+		UnicodeNormalize:       FoldWidth,
+	})
+
+	for _, id := range []string{"domi-1a2", "ｄｏｍｉ－１ａ２", "ｄｏmi-１a２"} {
+		if pos, err := generator.IDToPositionE(id); err != nil || pos != 3722 {
+			t.Errorf("expected 3722 for '%s', got %d (err: %v)", id, pos, err)
+		}
+		if compact, err := generator.Compact(id); err != nil || compact != "domi-1a2" {
+			t.Errorf("expected 'domi-1a2' for '%s', got '%s' (err: %v)", id, compact, err)
+		}
+	}
+	if _, err := New(Config{JustIntonationDigits: 2, EqualTemperamentDigits: 3, Separator: "-"}).IDToPositionE("ｄｏｍｉ－１ａ２"); !errors.Is(err, ErrInvalidID) {
+		t.Errorf("expected fullwidth input to be rejected without normalization, got %v", err)
+	}
+
+	// Any normalization applies, e.g. composing accents for a non-ASCII alphabet
+	composed := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 3,
+		Separator:              "-",
+		Notes:                  "dó ré mí fá só lá tí",
+		UnicodeNormalize:       strings.NewReplacer("o\u0301", "ó", "i\u0301", "í").Replace,
+	})
+	if pos, err := composed.IDToPositionE("do\u0301mi\u0301-1a2"); err != nil || pos != 3722 {
+		t.Errorf("expected 3722 for decomposed notes, got %d (err: %v)", pos, err)
+	}
+}
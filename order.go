@@ -0,0 +1,153 @@
+package doremid
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Ledger records the order in which IDs were issued.
+type Ledger interface {
+	// Append records position as issued and returns its sequence number.
+	// Sequence numbers increase with every new position; appending a recorded
+	// position returns its original sequence number.
+	Append(ctx context.Context, position int64) (int64, error)
+
+	// Sequence returns the sequence number of position, false if it was never recorded
+	Sequence(ctx context.Context, position int64) (int64, bool, error)
+}
+
+// Proof is a signed statement that ID A was issued before ID B, for settling
+// disputes without access to the ledger
+type Proof struct {
+	A         string    `json:"a"`
+	B         string    `json:"b"`
+	SequenceA int64     `json:"sequence_a"`
+	SequenceB int64     `json:"sequence_b"`
+	Created   time.Time `json:"created"`
+	Signature []byte    `json:"signature"` // HMAC-SHA256 over the other fields
+}
+
+// Record validates id and appends it to ledger, returning its sequence number
+func (g *Generator) Record(ctx context.Context, ledger Ledger, id string) (int64, error) {
+	pos, err := g.decode(id)
+	if err != nil {
+		return 0, err
+	}
+	return ledger.Append(ctx, pos)
+}
+
+// ProveOrder returns a proof, signed with key, that a was issued before b
+// according to ledger.
+// Returns ErrNotRecorded if either ID is missing from the ledger, ErrNotOrdered
+// if a was not issued before b, a *FormatError if either ID is invalid, or any
+// error returned by the ledger.
+func (g *Generator) ProveOrder(ctx context.Context, ledger Ledger, a, b string, key []byte) (Proof, error) {
+	seqA, err := g.sequence(ctx, ledger, a)
+	if err != nil {
+		return Proof{}, err
+	}
+	seqB, err := g.sequence(ctx, ledger, b)
+	if err != nil {
+		return Proof{}, err
+	}
+	if seqA >= seqB {
+		return Proof{}, ErrNotOrdered
+	}
+
+	p := Proof{A: a, B: b, SequenceA: seqA, SequenceB: seqB, Created: time.Now().UTC().Truncate(time.Second)}
+	p.Signature = signProof(p, key)
+	return p, nil
+}
+
+// VerifyOrder checks that p was signed with key and states a valid order.
+// Returns ErrInvalidProof if it was not, or a *FormatError if either ID is invalid.
+func (g *Generator) VerifyOrder(p Proof, key []byte) error {
+	if _, err := g.decode(p.A); err != nil {
+		return err
+	}
+	if _, err := g.decode(p.B); err != nil {
+		return err
+	}
+	if p.SequenceA >= p.SequenceB || !hmac.Equal(p.Signature, signProof(p, key)) {
+		return ErrInvalidProof
+	}
+	return nil
+}
+
+// sequence looks up the sequence number of id
+func (g *Generator) sequence(ctx context.Context, ledger Ledger, id string) (int64, error) {
+	pos, err := g.decode(id)
+	if err != nil {
+		return 0, err
+	}
+	seq, found, err := ledger.Sequence(ctx, pos)
+	if err != nil {
+		return 0, err
+	}
+	if !found {
+		return 0, fmt.Errorf("%w: %q", ErrNotRecorded, id)
+	}
+	return seq, nil
+}
+
+// signProof computes the signature of p over length-delimited fields
+func signProof(p Proof, key []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	var buf [8]byte
+	writeInt := func(v int64) {
+		binary.BigEndian.PutUint64(buf[:], uint64(v))
+		mac.Write(buf[:])
+	}
+
+	mac.Write([]byte("doremid order proof v1"))
+	for _, id := range []string{p.A, p.B} {
+		writeInt(int64(len(id)))
+		mac.Write([]byte(id))
+	}
+	writeInt(p.SequenceA)
+	writeInt(p.SequenceB)
+	writeInt(p.Created.Unix())
+	return mac.Sum(nil)
+}
+
+// MemoryLedger is an in-memory Ledger. It is safe for concurrent use.
+type MemoryLedger struct {
+	mu        sync.RWMutex
+	sequences map[int64]int64
+	next      int64
+}
+
+// NewMemoryLedger creates an empty in-memory ledger
+func NewMemoryLedger() *MemoryLedger {
+	return &MemoryLedger{sequences: make(map[int64]int64)}
+}
+
+// Append records position as issued and returns its sequence number
+func (l *MemoryLedger) Append(_ context.Context, position int64) (int64, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if seq, found := l.sequences[position]; found {
+		return seq, nil
+	}
+	seq := l.next
+	l.sequences[position] = seq
+	l.next++
+	return seq, nil
+}
+
+// Sequence returns the sequence number of position, false if it was never recorded
+func (l *MemoryLedger) Sequence(_ context.Context, position int64) (int64, bool, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	seq, found := l.sequences[position]
+	return seq, found, nil
+}
+
+// Compile-time interface check
+var _ Ledger = (*MemoryLedger)(nil)
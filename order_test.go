@@ -0,0 +1,92 @@
+package doremid
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestOrderProof(t *testing.T) {
+	ctx := context.Background()
+	generator := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 3,
+		Separator:              "-",
+	})
+	ledger := NewMemoryLedger()
+	key := []byte("marketplace key")
+
+	// Issue in an order unrelated to position
+	ids := []string{"fati-bb0", "domi-1a2", "sola-123"}
+	for i, id := range ids {
+		seq, err := generator.Record(ctx, ledger, id)
+		if err != nil || seq != int64(i) {
+			t.Fatalf("expected sequence %d for '%s', got %d (err: %v)", i, id, seq, err)
+		}
+	}
+	if seq, _ := generator.Record(ctx, ledger, "fati-bb0"); seq != 0 {
+		t.Errorf("expected re-recording to keep sequence 0, got %d", seq)
+	}
+
+	proof, err := generator.ProveOrder(ctx, ledger, "fati-bb0", "sola-123", key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := generator.VerifyOrder(proof, key); err != nil {
+		t.Errorf("expected valid proof, got %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		tamper   func(p *Proof)
+		key      []byte
+		expected error
+	}{
+		{"wrong key", func(p *Proof) {}, []byte("other"), ErrInvalidProof},
+		{"swapped IDs", func(p *Proof) { p.A, p.B = p.B, p.A }, key, ErrInvalidProof},
+		{"changed sequence", func(p *Proof) { p.SequenceB = 5 }, key, ErrInvalidProof},
+		{"changed time", func(p *Proof) { p.Created = p.Created.Add(1e9) }, key, ErrInvalidProof},
+		{"invalid ID", func(p *Proof) { p.A = "invalid" }, key, ErrInvalidID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tampered := proof
+			tt.tamper(&tampered)
+			if err := generator.VerifyOrder(tampered, tt.key); !errors.Is(err, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestProveOrderErrors(t *testing.T) {
+	ctx := context.Background()
+	generator := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 3,
+		Separator:              "-",
+	})
+	ledger := NewMemoryLedger()
+	generator.Record(ctx, ledger, "domi-1a2")
+	generator.Record(ctx, ledger, "fati-bb0")
+
+	tests := []struct {
+		name     string
+		a, b     string
+		expected error
+	}{
+		{"wrong order", "fati-bb0", "domi-1a2", ErrNotOrdered},
+		{"same ID", "domi-1a2", "domi-1a2", ErrNotOrdered},
+		{"unrecorded", "domi-1a2", "sola-123", ErrNotRecorded},
+		{"invalid", "domi-1a2", "invalid", ErrInvalidID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := generator.ProveOrder(ctx, ledger, tt.a, tt.b, []byte("key")); !errors.Is(err, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package doremid
+
+import (
+	"container/heap"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/binary"
+	"sync"
+	"time"
+)
+
+// DefaultOTCTTL is the validity window of one-time codes used when OTCConfig.TTL is zero
+const DefaultOTCTTL = 10 * time.Minute
+
+// ReplayCache remembers verified one-time codes so each can be used only once.
+type ReplayCache interface {
+	// Use records key as used until expires.
+	// Returns true if key was already recorded and has not expired.
+	Use(key string, expires time.Time) (bool, error)
+}
+
+// OTCConfig configures an OTCGenerator
+type OTCConfig struct {
+	// Secret is the HMAC key codes are derived from. It must not be empty.
+	Secret []byte
+
+	// TTL is the minimum time a code stays valid; codes are accepted for up to
+	// twice as long. Zero uses DefaultOTCTTL.
+	TTL time.Duration
+
+	// Generator determines the code format. Nil uses two notes and three
+	// characters, e.g. "domi-1a2", giving 84,672 possible codes.
+	Generator *Generator
+
+	// ReplayCache rejects codes that were already verified. Nil allows a code
+	// to be verified repeatedly until it expires.
+	ReplayCache ReplayCache
+
+	// Clock tells the time. Nil uses the system clock.
+	Clock Clock
+}
+
+// OTCGenerator issues short, pronounceable one-time codes for confirmation flows
+// such as email or SMS verification.
+//
+// Codes are derived with an HMAC from the purpose and a time window, so the server
+// stores nothing per code: Verify recomputes the expected code. The purpose should
+// identify both the action and the subject, e.g. "confirm-email:alice@example.com".
+// Codes are short by design, so callers must rate-limit verification attempts.
+// An OTCGenerator is safe for concurrent use.
+type OTCGenerator struct {
+	secret []byte
+	ttl    time.Duration
+	g      *Generator
+	cache  ReplayCache
+	clock  Clock
+}
+
+// NewOTCGenerator creates a one-time code generator.
+// Returns a *ConfigError if the secret is empty or the TTL is negative.
+func NewOTCGenerator(config OTCConfig) (*OTCGenerator, error) {
+	if len(config.Secret) == 0 {
+		return nil, &ConfigError{Field: "Secret", Reason: "must not be empty"}
+	}
+	if config.TTL < 0 {
+		return nil, &ConfigError{Field: "TTL", Reason: "must not be negative"}
+	}
+
+	o := &OTCGenerator{
+		secret: append([]byte(nil), config.Secret...),
+		ttl:    config.TTL,
+		g:      config.Generator,
+		cache:  config.ReplayCache,
+		clock:  clockOrWall(config.Clock),
+	}
+	if o.ttl == 0 {
+		o.ttl = DefaultOTCTTL
+	}
+	if o.g == nil {
+		o.g = New(Config{JustIntonationDigits: 2, EqualTemperamentDigits: 3, Separator: "-"})
+	}
+	return o, nil
+}
+
+// Issue returns the code for purpose and the time it stops being accepted
+func (o *OTCGenerator) Issue(purpose string) (code string, expires time.Time) {
+	window := o.window(o.clock.Now())
+	return o.code(purpose, window), o.windowStart(window + 2)
+}
+
+// Verify checks that code was issued for purpose and has not expired.
+// Codes are compared by position, so any spelling the generator parses, e.g.
+// another case with Config.LenientCase, matches and counts as the same use.
+// Returns ErrInvalidCode if it does not match, ErrCodeReused if the replay cache
+// has already seen it, a *FormatError if code is malformed, or any error returned
+// by the replay cache.
+func (o *OTCGenerator) Verify(code, purpose string) error {
+	pos, err := o.g.decode(code)
+	if err != nil {
+		return err
+	}
+	var got [8]byte
+	binary.BigEndian.PutUint64(got[:], uint64(pos))
+
+	current := o.window(o.clock.Now())
+	for _, window := range []int64{current, current - 1} {
+		var expected [8]byte
+		binary.BigEndian.PutUint64(expected[:], uint64(o.position(purpose, window)))
+		if subtle.ConstantTimeCompare(expected[:], got[:]) != 1 {
+			continue
+		}
+		if o.cache == nil {
+			return nil
+		}
+
+		used, err := o.cache.Use(purpose+"\x00"+o.g.PositionToID(pos), o.windowStart(window+2))
+		if err != nil {
+			return err
+		}
+		if used {
+			return ErrCodeReused
+		}
+		return nil
+	}
+	return ErrInvalidCode
+}
+
+// code derives the code for purpose in window
+func (o *OTCGenerator) code(purpose string, window int64) string {
+	return o.g.PositionToID(o.position(purpose, window))
+}
+
+// position derives the position of the code for purpose in window
+func (o *OTCGenerator) position(purpose string, window int64) int64 {
+	mac := hmac.New(sha256.New, o.secret)
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], uint64(len(purpose)))
+	mac.Write(buf[:])
+	mac.Write([]byte(purpose))
+	binary.BigEndian.PutUint64(buf[:], uint64(window))
+	mac.Write(buf[:])
+
+	sum := binary.BigEndian.Uint64(mac.Sum(nil))
+	return int64(sum % uint64(o.g.MaxCombinations()))
+}
+
+// window returns the index of the time window containing t
+func (o *OTCGenerator) window(t time.Time) int64 {
+	return t.UnixNano() / int64(o.ttl)
+}
+
+// windowStart returns the start time of window
+func (o *OTCGenerator) windowStart(window int64) time.Time {
+	return time.Unix(0, window*int64(o.ttl))
+}
+
+// MemoryReplayCache is an in-memory ReplayCache that forgets keys once they
+// expire. It is safe for concurrent use.
+type MemoryReplayCache struct {
+	mu     sync.Mutex
+	keys   map[string]time.Time
+	expiry replayHeap // Keys ordered by expiry, so eviction only looks at expired ones
+	clock  Clock
+}
+
+// NewMemoryReplayCache creates an empty in-memory replay cache telling expiry
+// by clock, which should be the OTCConfig.Clock of the generator it serves so
+// one clock drives both issuing and replay checks. Nil uses the system clock.
+func NewMemoryReplayCache(clock Clock) *MemoryReplayCache {
+	return &MemoryReplayCache{keys: make(map[string]time.Time), clock: clockOrWall(clock)}
+}
+
+// Use records key as used until expires and reports whether it was already recorded
+func (c *MemoryReplayCache) Use(key string, expires time.Time) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.clock.Now()
+	for len(c.expiry) > 0 && !c.expiry[0].expires.After(now) {
+		delete(c.keys, heap.Pop(&c.expiry).(replayEntry).key)
+	}
+
+	if _, found := c.keys[key]; found {
+		return true, nil
+	}
+	c.keys[key] = expires
+	heap.Push(&c.expiry, replayEntry{key: key, expires: expires})
+	return false, nil
+}
+
+// replayEntry is a key of a MemoryReplayCache and the time it expires
+type replayEntry struct {
+	key     string
+	expires time.Time
+}
+
+// replayHeap is a min-heap of replay entries ordered by expiry
+type replayHeap []replayEntry
+
+func (h replayHeap) Len() int           { return len(h) }
+func (h replayHeap) Less(i, j int) bool { return h[i].expires.Before(h[j].expires) }
+func (h replayHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *replayHeap) Push(x any)        { *h = append(*h, x.(replayEntry)) }
+func (h *replayHeap) Pop() any {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
+
+// Compile-time interface check
+var _ ReplayCache = (*MemoryReplayCache)(nil)
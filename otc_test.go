@@ -0,0 +1,143 @@
+package doremid
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOTCGenerator(t *testing.T) {
+	clock := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	newOTC := func(secret string, cache ReplayCache) *OTCGenerator {
+		o, err := NewOTCGenerator(OTCConfig{Secret: []byte(secret), TTL: 10 * time.Minute, ReplayCache: cache})
+		if err != nil {
+			t.Fatal(err)
+		}
+		o.clock = ClockFunc(func() time.Time { return clock })
+		return o
+	}
+
+	o := newOTC("secret", nil)
+	code, expires := o.Issue("confirm-email:alice@example.com")
+	if len(code) != 8 {
+		t.Errorf("expected an 8 character code, got '%s'", code)
+	}
+	if !expires.Equal(clock.Add(20 * time.Minute)) {
+		t.Errorf("expected expiry %v, got %v", clock.Add(20*time.Minute), expires)
+	}
+	if again, _ := o.Issue("confirm-email:alice@example.com"); again != code {
+		t.Errorf("expected the same code within a window, got '%s' and '%s'", code, again)
+	}
+
+	tests := []struct {
+		name     string
+		otc      *OTCGenerator
+		code     string
+		purpose  string
+		elapsed  time.Duration
+		expected error
+	}{
+		{"valid", o, code, "confirm-email:alice@example.com", 0, nil},
+		{"previous window", o, code, "confirm-email:alice@example.com", 15 * time.Minute, nil},
+		{"expired", o, code, "confirm-email:alice@example.com", 20 * time.Minute, ErrInvalidCode},
+		{"other purpose", o, code, "confirm-email:bob@example.com", 0, ErrInvalidCode},
+		{"other secret", newOTC("other", nil), code, "confirm-email:alice@example.com", 0, ErrInvalidCode},
+		{"malformed", o, "not-a-code", "confirm-email:alice@example.com", 0, ErrInvalidID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.otc.clock = ClockFunc(func() time.Time { return clock.Add(tt.elapsed) })
+			defer func() { tt.otc.clock = ClockFunc(func() time.Time { return clock }) }()
+
+			err := tt.otc.Verify(tt.code, tt.purpose)
+			if tt.expected == nil && err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+			if tt.expected != nil && !errors.Is(err, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestOTCGeneratorReplay(t *testing.T) {
+	cache := NewMemoryReplayCache(nil)
+	o, _ := NewOTCGenerator(OTCConfig{Secret: []byte("secret"), ReplayCache: cache})
+	code, _ := o.Issue("reset-password:42")
+
+	if err := o.Verify(code, "reset-password:42"); err != nil {
+		t.Fatalf("expected first verification to succeed, got %v", err)
+	}
+	if err := o.Verify(code, "reset-password:42"); !errors.Is(err, ErrCodeReused) {
+		t.Errorf("expected ErrCodeReused, got %v", err)
+	}
+
+	// Expired entries are forgotten, unexpired ones kept
+	now := time.Now()
+	cache.Use("late", now.Add(3*time.Hour))
+	cache.Use("early", now.Add(30*time.Minute))
+	cache.clock = ClockFunc(func() time.Time { return now.Add(time.Hour) })
+	cache.Use("other", now.Add(2*time.Hour))
+	if len(cache.keys) != 2 || len(cache.expiry) != 2 {
+		t.Errorf("expected expired keys to be evicted, got %d keys", len(cache.keys))
+	}
+	if used, _ := cache.Use("late", now.Add(3*time.Hour)); !used {
+		t.Error("expected an unexpired key to be kept")
+	}
+	if used, _ := cache.Use("early", now.Add(3*time.Hour)); used {
+		t.Error("expected an expired key to be usable again")
+	}
+}
+
+func TestOTCGeneratorCanonicalCode(t *testing.T) {
+	o, _ := NewOTCGenerator(OTCConfig{
+		Secret:      []byte("secret"),
+		Generator:   New(Config{JustIntonationDigits: 2, EqualTemperamentDigits: 3, Separator: "-", LenientCase: true}),
+		ReplayCache: NewMemoryReplayCache(nil),
+	})
+	code, _ := o.Issue("reset-password:42")
+
+	if err := o.Verify(strings.ToUpper(code), "reset-password:42"); err != nil {
+		t.Fatalf("expected another spelling of the code to verify, got %v", err)
+	}
+	if err := o.Verify(code, "reset-password:42"); !errors.Is(err, ErrCodeReused) {
+		t.Errorf("expected ErrCodeReused for the original spelling, got %v", err)
+	}
+}
+
+func TestOTCGeneratorReplayClock(t *testing.T) {
+	// A clock in the past drives both issuing and expiry
+	clock := ClockFunc(func() time.Time { return time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC) })
+	o, _ := NewOTCGenerator(OTCConfig{Secret: []byte("secret"), ReplayCache: NewMemoryReplayCache(clock), Clock: clock})
+	code, _ := o.Issue("reset-password:42")
+
+	if err := o.Verify(code, "reset-password:42"); err != nil {
+		t.Fatalf("expected first verification to succeed, got %v", err)
+	}
+	if err := o.Verify(code, "reset-password:42"); !errors.Is(err, ErrCodeReused) {
+		t.Errorf("expected ErrCodeReused, got %v", err)
+	}
+}
+
+func TestOTCGeneratorConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		config OTCConfig
+		field  string
+	}{
+		{"empty secret", OTCConfig{}, "Secret"},
+		{"negative ttl", OTCConfig{Secret: []byte("secret"), TTL: -time.Second}, "TTL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewOTCGenerator(tt.config)
+			var configErr *ConfigError
+			if !errors.As(err, &configErr) || configErr.Field != tt.field {
+				t.Errorf("expected ConfigError for %s, got %v", tt.field, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package doremid
+
+// NextID returns the ID at the position after id, so consumers can walk the
+// sequential ID space by ID, e.g. to resume a listing after its last ID.
+// Reserved and filtered positions are stepped over.
+// Returns a *FormatError if id is invalid, or a *RangeError if the next
+// position lies outside the range the generator may mint.
+func (g *Generator) NextID(id string) (string, error) {
+	pos, err := g.decode(id)
+	if err != nil {
+		return "", err
+	}
+	return g.Mint(g.nextAllowed(pos + 1))
+}
+
+// PrevID returns the ID at the position before id, stepping over reserved
+// and filtered positions.
+// Returns a *FormatError if id is invalid, or a *RangeError if the previous
+// position lies outside the range the generator may mint.
+func (g *Generator) PrevID(id string) (string, error) {
+	pos, err := g.decode(id)
+	if err != nil {
+		return "", err
+	}
+	return g.Mint(g.prevAllowed(pos - 1))
+}
+
+// RangeIDs returns the sequential IDs from startID through endID inclusive,
+// e.g. a page of a listing whose bounds are IDs rather than positions:
+//
+//	page, err := generator.RangeIDs(cursor, lastOfPage)
+//	next, err := generator.NextID(page[len(page)-1])
+//
+// Reserved and filtered positions are left out. All IDs are held in memory;
+// use GenerateSeq to stream large ranges.
+// Returns a *FormatError if either ID is invalid, or a *RangeError if either
+// lies outside the range the generator may mint or endID precedes startID.
+func (g *Generator) RangeIDs(startID, endID string) ([]string, error) {
+	start, err := g.decode(startID)
+	if err != nil {
+		return nil, err
+	}
+	end, err := g.decode(endID)
+	if err != nil {
+		return nil, err
+	}
+
+	r := g.mintRange()
+	if err := checkRange(start, r); err != nil {
+		return nil, err
+	}
+	if err := checkRange(end, Range{Start: start, End: r.End}); err != nil {
+		return nil, err
+	}
+	return g.BatchGenerateIDs(end-start+1, start), nil
+}
@@ -0,0 +1,99 @@
+package doremid
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestNextPrevID(t *testing.T) {
+	generator := New(Config{JustIntonationDigits: 2, EqualTemperamentDigits: 3, Separator: "-"})
+	last := generator.PositionToID(generator.MaxCombinations() - 1)
+
+	tests := []struct {
+		name       string
+		id         string
+		next, prev string
+		nextErr    error
+		prevErr    error
+	}{
+		{"middle", "domi-1a2", "domi-1a3", "domi-1a1", nil, nil},
+		{"carry", "domi-bbb", "dofa-000", "domi-bba", nil, nil},
+		{"first", "dodo-000", "dodo-001", "", nil, ErrOutOfRange},
+		{"last", last, "", "titi-bba", ErrOutOfRange, nil},
+		{"invalid", "invalid", "", "", ErrInvalidID, ErrInvalidID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next, err := generator.NextID(tt.id)
+			if next != tt.next || !errors.Is(err, tt.nextErr) {
+				t.Errorf("expected next '%s' (err: %v), got '%s' (err: %v)", tt.next, tt.nextErr, next, err)
+			}
+			prev, err := generator.PrevID(tt.id)
+			if prev != tt.prev || !errors.Is(err, tt.prevErr) {
+				t.Errorf("expected prev '%s' (err: %v), got '%s' (err: %v)", tt.prev, tt.prevErr, prev, err)
+			}
+		})
+	}
+
+	restricted := generator.Restrict(Range{Start: 100, End: 200})
+	var rangeErr *RangeError
+	if _, err := restricted.NextID(restricted.PositionToID(199)); !errors.As(err, &rangeErr) || rangeErr.Position != 200 || rangeErr.Max != 200 {
+		t.Errorf("expected a RangeError at the end of the restriction, got %v", err)
+	}
+	if _, err := restricted.PrevID(restricted.PositionToID(100)); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("expected ErrOutOfRange at the start of the restriction, got %v", err)
+	}
+}
+
+func TestRangeIDs(t *testing.T) {
+	generator := New(Config{JustIntonationDigits: 2, EqualTemperamentDigits: 3, Separator: "-"})
+
+	ids, err := generator.RangeIDs("domi-bba", "dofa-001")
+	if expected := []string{"domi-bba", "domi-bbb", "dofa-000", "dofa-001"}; err != nil || !slices.Equal(ids, expected) {
+		t.Errorf("expected %v, got %v (err: %v)", expected, ids, err)
+	}
+	if ids, err := generator.RangeIDs("domi-1a2", "domi-1a2"); err != nil || !slices.Equal(ids, []string{"domi-1a2"}) {
+		t.Errorf("expected a single ID, got %v (err: %v)", ids, err)
+	}
+
+	// Paginate the whole restricted range, four IDs at a time
+	restricted := generator.Restrict(Range{Start: 10, End: 20})
+	var all []string
+	cursor := restricted.PositionToID(10)
+	for {
+		end := restricted.PositionToID(min(restricted.IDToPosition(cursor)+3, 19))
+		page, err := restricted.RangeIDs(cursor, end)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		all = append(all, page...)
+		if cursor, err = restricted.NextID(end); errors.Is(err, ErrOutOfRange) {
+			break
+		}
+	}
+	if expected := generator.BatchGenerateIDs(10, 10); !slices.Equal(all, expected) {
+		t.Errorf("expected %v, got %v", expected, all)
+	}
+
+	tests := []struct {
+		name       string
+		generator  *Generator
+		start, end string
+		expected   error
+	}{
+		{"reversed", generator, "domi-1a3", "domi-1a2", ErrOutOfRange},
+		{"start outside restriction", restricted, restricted.PositionToID(5), restricted.PositionToID(15), ErrOutOfRange},
+		{"end outside restriction", restricted, restricted.PositionToID(15), restricted.PositionToID(25), ErrOutOfRange},
+		{"invalid start", generator, "invalid", "domi-1a2", ErrInvalidID},
+		{"invalid end", generator, "domi-1a2", "invalid", ErrInvalidID},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if ids, err := tt.generator.RangeIDs(tt.start, tt.end); !errors.Is(err, tt.expected) || ids != nil {
+				t.Errorf("expected %v, got %v (err: %v)", tt.expected, ids, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package doremid
+
+// ParsedID is an ID broken into its parts
+type ParsedID struct {
+	Prefix   string // Namespace prefix without its separator, empty if none is configured
+	Just     string // Musical note part, e.g. "domi"
+	Equal    string // Character part, e.g. "1a2"
+	Check    string // Check character, empty unless Config.Checksum is set
+	Position int64
+}
+
+// Parse validates id and breaks it into its parts.
+// Returns a *FormatError describing the first problem if id is invalid.
+func (g *Generator) Parse(id string) (ParsedID, error) {
+	pos, err := g.decode(id)
+	if err != nil {
+		return ParsedID{}, err
+	}
+	id = g.ungroup(g.unalias(id))
+
+	justEnd := len(g.prefix)
+	for i := 0; i < g.JustIntonationDigits; i++ {
+		_, width, _ := g.nextNote(id, justEnd)
+		justEnd += width
+	}
+	equalStart := justEnd + len(g.Separator)
+	equalEnd := equalStart + g.EqualTemperamentDigits
+
+	return ParsedID{
+		Prefix:   g.namespace,
+		Just:     id[len(g.prefix):justEnd],
+		Equal:    id[equalStart:equalEnd],
+		Check:    id[equalEnd:],
+		Position: pos,
+	}, nil
+}
+
+// Digits returns the index of each note of id in the note alphabet followed by
+// the index of each character in the character set, e.g. [0 2 1 10 2] for
+// "domi-1a2". Any check character is not included.
+// Returns a *FormatError if id is invalid.
+func (g *Generator) Digits(id string) ([]int, error) {
+	if _, err := g.decode(id); err != nil {
+		return nil, err
+	}
+	id = g.ungroup(g.unalias(id))
+
+	digits := make([]int, 0, g.JustIntonationDigits+g.EqualTemperamentDigits)
+	offset := len(g.prefix)
+	for i := 0; i < g.JustIntonationDigits; i++ {
+		index, width, _ := g.nextNote(id, offset)
+		digits = append(digits, index)
+		offset += width
+	}
+	offset += len(g.Separator)
+	for i := 0; i < g.EqualTemperamentDigits; i++ {
+		digits = append(digits, g.equalTemperamentMap[id[offset+i]])
+	}
+	return digits, nil
+}
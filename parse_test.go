@@ -0,0 +1,106 @@
+package doremid
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   Config
+		id       string
+		expected ParsedID
+	}{
+		{
+			"default",
+			Config{JustIntonationDigits: 2, EqualTemperamentDigits: 3, Separator: "-"},
+			"domi-1a2",
+			ParsedID{Just: "domi", Equal: "1a2", Position: 3722},
+		},
+		{
+			"prefix and checksum",
+			Config{JustIntonationDigits: 2, EqualTemperamentDigits: 3, Separator: "-", Prefix: "usr", Checksum: true},
+			"usr_domi-1a26",
+			ParsedID{Prefix: "usr", Just: "domi", Equal: "1a2", Check: "6", Position: 3722},
+		},
+		{
+			"invalid",
+			Config{JustIntonationDigits: 2, EqualTemperamentDigits: 2, Notes: "do re mi fa sol la ti"},
+			"sollx00",
+			ParsedID{},
+		},
+		{
+			"custom notes",
+			Config{JustIntonationDigits: 2, EqualTemperamentDigits: 2, Notes: "do re mi fa sol la ti"},
+			"solla00",
+			ParsedID{Just: "solla", Equal: "00", Position: (4*7 + 5) * 144},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := New(tt.config).Parse(tt.id)
+			if tt.expected == (ParsedID{}) {
+				if !errors.Is(err, ErrInvalidID) {
+					t.Errorf("expected ErrInvalidID, got %v", err)
+				}
+				return
+			}
+			if err != nil || parsed != tt.expected {
+				t.Errorf("expected %+v, got %+v (err: %v)", tt.expected, parsed, err)
+			}
+		})
+	}
+}
+
+func TestParseDiagnostics(t *testing.T) {
+	generator := New(Config{JustIntonationDigits: 2, EqualTemperamentDigits: 3, Separator: "-", Checksum: true})
+
+	tests := []struct {
+		id       string
+		expected error
+		offset   int
+	}{
+		{"domi-1a2", ErrInvalidFormat, -1},
+		{"doxx-1a26", ErrBadCharacter, 2},
+		{"domi_1a26", ErrInvalidFormat, 4},
+		{"domi-1a27", ErrChecksum, 8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			_, err := generator.Parse(tt.id)
+			var formatErr *FormatError
+			if !errors.As(err, &formatErr) || !errors.Is(err, tt.expected) || formatErr.Offset != tt.offset {
+				t.Errorf("expected %v at offset %d, got %v", tt.expected, tt.offset, err)
+			}
+		})
+	}
+}
+
+func TestDigits(t *testing.T) {
+	tests := []struct {
+		config   Config
+		id       string
+		expected []int
+	}{
+		{Config{JustIntonationDigits: 2, EqualTemperamentDigits: 3, Separator: "-"}, "domi-1a2", []int{0, 2, 1, 10, 2}},
+		{Config{JustIntonationDigits: 2, EqualTemperamentDigits: 3, Separator: "-", Prefix: "usr", Checksum: true}, "usr_domi-1a26", []int{0, 2, 1, 10, 2}},
+		{Config{JustIntonationDigits: 2, EqualTemperamentDigits: 1, Notes: "do re mi fa sol la ti"}, "soldo3", []int{4, 0, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			digits, err := New(tt.config).Digits(tt.id)
+			if err != nil || !slices.Equal(digits, tt.expected) {
+				t.Errorf("expected %v, got %v (err: %v)", tt.expected, digits, err)
+			}
+		})
+	}
+
+	if _, err := NewWithDefaults().Digits("domi-1a2"); !errors.Is(err, ErrInvalidID) {
+		t.Errorf("expected ErrInvalidID, got %v", err)
+	}
+}
@@ -0,0 +1,43 @@
+package doremid
+
+// PartitionRange splits the positions the generator may mint into
+// totalPartitions contiguous, non-overlapping ranges of near-equal size and
+// returns the half-open range [start, end) of partition partitionIndex, so
+// workers can each mint sequential IDs from their own share of the space
+// without a central counter. The first len%totalPartitions partitions hold one
+// extra position. Returns an empty range (start == end) if totalPartitions is
+// not positive or partitionIndex is not in [0, totalPartitions).
+func (g *Generator) PartitionRange(totalPartitions, partitionIndex int) (start, end int64) {
+	r := g.partition(totalPartitions, partitionIndex)
+	return r.Start, r.End
+}
+
+// BatchGenerateIDsForPartition generates up to count sequential IDs of
+// partition partitionIndex, see PartitionRange, starting offset positions into
+// the partition. A worker keeps its own offset, advancing it by the number of
+// IDs returned. Fewer IDs are returned once the partition is used up, and none
+// for invalid partitions or a negative offset.
+func (g *Generator) BatchGenerateIDsForPartition(totalPartitions, partitionIndex int, count, offset int64) []string {
+	r := g.partition(totalPartitions, partitionIndex)
+	if count <= 0 || offset < 0 || offset >= r.Len() {
+		return []string{}
+	}
+	return g.Restrict(r).BatchGenerateIDs(min(count, r.Len()-offset), r.Start+offset)
+}
+
+// partition returns partition index of n over the mint range
+func (g *Generator) partition(n, index int) Range {
+	mintRange := g.mintRange()
+	if n <= 0 || index < 0 || index >= n {
+		return Range{Start: mintRange.Start, End: mintRange.Start}
+	}
+
+	// Spread the remainder over the first partitions, avoiding overflow of len*index
+	size, rest := mintRange.Len()/int64(n), mintRange.Len()%int64(n)
+	start := mintRange.Start + int64(index)*size + min(int64(index), rest)
+	end := start + size
+	if int64(index) < rest {
+		end++
+	}
+	return Range{Start: start, End: end}
+}
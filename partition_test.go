@@ -0,0 +1,102 @@
+package doremid
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestPartitionRange(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 3,
+		Separator:              "-",
+	})
+
+	tests := []struct {
+		name          string
+		total         int
+		index         int
+		expectedStart int64
+		expectedEnd   int64
+	}{
+		{"single partition", 1, 0, 0, 84672},
+		{"first of two", 2, 0, 0, 42336},
+		{"second of two", 2, 1, 42336, 84672},
+		{"first takes the remainder", 5, 0, 0, 16935},
+		{"second takes the remainder", 5, 1, 16935, 33870},
+		{"last", 5, 4, 67738, 84672},
+		{"zero partitions", 0, 0, 0, 0},
+		{"negative index", 3, -1, 0, 0},
+		{"index too large", 3, 3, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := generator.PartitionRange(tt.total, tt.index)
+			if start != tt.expectedStart || end != tt.expectedEnd {
+				t.Errorf("expected [%d, %d), got [%d, %d)", tt.expectedStart, tt.expectedEnd, start, end)
+			}
+		})
+	}
+
+	// Partitions tile the space, even with more partitions than positions
+	tiny := New(Config{JustIntonationDigits: 1, EqualTemperamentDigits: 0})
+	for _, total := range []int{1, 3, 7, 10} {
+		next := int64(0)
+		for i := range total {
+			start, end := tiny.PartitionRange(total, i)
+			if start != next || end < start {
+				t.Fatalf("expected partition %d of %d to start at %d, got [%d, %d)", i, total, next, start, end)
+			}
+			next = end
+		}
+		if next != 7 {
+			t.Errorf("expected %d partitions to cover 7 positions, got %d", total, next)
+		}
+	}
+
+	// Partitions of a restricted generator divide its range
+	restricted := generator.Restrict(Range{Start: 100, End: 200})
+	if start, end := restricted.PartitionRange(4, 3); start != 175 || end != 200 {
+		t.Errorf("expected [175, 200), got [%d, %d)", start, end)
+	}
+}
+
+func TestBatchGenerateIDsForPartition(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 3,
+		Separator:              "-",
+	})
+
+	// Three workers mint their partitions in blocks without overlapping
+	var all []string
+	for worker := range 3 {
+		offset := int64(0)
+		for {
+			ids := generator.BatchGenerateIDsForPartition(3, worker, 5000, offset)
+			if len(ids) == 0 {
+				break
+			}
+			offset += int64(len(ids))
+			all = append(all, ids...)
+		}
+		if start, end := generator.PartitionRange(3, worker); offset != end-start {
+			t.Errorf("expected worker %d to mint %d IDs, got %d", worker, end-start, offset)
+		}
+	}
+	if !slices.Equal(all, generator.BatchGenerateIDs(84672, 0)) {
+		t.Error("expected the partitions to mint every ID exactly once, in order")
+	}
+
+	ids := generator.BatchGenerateIDsForPartition(2, 1, 3, 10)
+	if !slices.Equal(ids, []string{generator.PositionToID(42346), generator.PositionToID(42347), generator.PositionToID(42348)}) {
+		t.Errorf("expected 3 IDs from position 42346, got %v", ids)
+	}
+
+	for _, args := range [][4]int64{{0, 0, 10, 0}, {2, 2, 10, 0}, {2, 0, 0, 0}, {2, 0, 10, -1}, {2, 0, 10, 42336}} {
+		if ids := generator.BatchGenerateIDsForPartition(int(args[0]), int(args[1]), args[2], args[3]); len(ids) != 0 {
+			t.Errorf("expected no IDs for %v, got %d", args, len(ids))
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package doremid
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// EncodeBytes encodes data as an ID, treating the ID space as a mixed-radix
+// numeral system. Payloads are numbered by length and then value, so every
+// byte string, including leading zero bytes, maps to exactly one ID and back.
+// Returns an error matching ErrOutOfRange if data is longer than MaxPayload.
+func (g *Generator) EncodeBytes(data []byte) (string, error) {
+	if len(data) > g.MaxPayload() {
+		return "", fmt.Errorf("%w: payload of %d bytes exceeds %d", ErrOutOfRange, len(data), g.MaxPayload())
+	}
+
+	// Skip the positions of all shorter payloads
+	position := new(big.Int).SetBytes(data)
+	size := big.NewInt(1)
+	for i := 0; i < len(data); i++ {
+		position.Add(position, size)
+		size.Lsh(size, 8)
+	}
+	return g.PositionToIDBig(position)
+}
+
+// DecodeBytes returns the payload encoded in id by EncodeBytes.
+// Returns a *FormatError if id is invalid.
+func (g *Generator) DecodeBytes(id string) ([]byte, error) {
+	position, err := g.IDToPositionBig(id)
+	if err != nil {
+		return nil, err
+	}
+
+	length := 0
+	size := big.NewInt(1)
+	for position.Cmp(size) >= 0 {
+		position.Sub(position, size)
+		size.Lsh(size, 8)
+		length++
+	}
+	return position.FillBytes(make([]byte, length)), nil
+}
+
+// MaxPayload returns the number of bytes up to which EncodeBytes can encode
+// every payload
+func (g *Generator) MaxPayload() int {
+	// Payloads of up to n bytes need 1 + 256 + ... + 256^n positions
+	maxCombinations := g.MaxCombinationsBig()
+	needed := big.NewInt(1)
+	size := big.NewInt(1)
+	n := 0
+	for {
+		size.Lsh(size, 8)
+		needed.Add(needed, size)
+		if needed.Cmp(maxCombinations) > 0 {
+			return n
+		}
+		n++
+	}
+}
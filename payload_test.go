@@ -0,0 +1,85 @@
+package doremid
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestEncodeBytes(t *testing.T) {
+	// 7^16 * 12^16 is about 2^102, holding every payload of up to 12 bytes
+	generator := New(Config{
+		JustIntonationDigits:   16,
+		EqualTemperamentDigits: 16,
+		Separator:              "-",
+	})
+	if generator.MaxPayload() != 12 {
+		t.Fatalf("expected a 12 byte capacity, got %d", generator.MaxPayload())
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", []byte{}},
+		{"zero byte", []byte{0}},
+		{"leading zeros", []byte{0, 0, 1}},
+		{"uint64 key", []byte{0xde, 0xad, 0xbe, 0xef, 0, 0, 0, 42}},
+		{"full", bytes.Repeat([]byte{0xff}, 12)},
+	}
+
+	seen := make(map[string]bool)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := generator.EncodeBytes(tt.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if seen[id] {
+				t.Errorf("expected a distinct ID, got '%s' twice", id)
+			}
+			seen[id] = true
+
+			decoded, err := generator.DecodeBytes(id)
+			if err != nil || !bytes.Equal(decoded, tt.data) {
+				t.Errorf("expected %x, got %x (err: %v)", tt.data, decoded, err)
+			}
+		})
+	}
+
+	if id, _ := generator.EncodeBytes(nil); id != generator.PositionToID(0) {
+		t.Errorf("expected the empty payload at position 0, got '%s'", id)
+	}
+	if _, err := generator.EncodeBytes(make([]byte, 13)); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("expected ErrOutOfRange, got %v", err)
+	}
+	if _, err := generator.DecodeBytes("invalid"); !errors.Is(err, ErrInvalidID) {
+		t.Errorf("expected ErrInvalidID, got %v", err)
+	}
+}
+
+func TestDecodeBytesEverySmallID(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 2,
+		Separator:              "-",
+	})
+	if generator.MaxPayload() != 1 {
+		t.Errorf("expected 1 byte of capacity in 7056 positions, got %d", generator.MaxPayload())
+	}
+
+	// Every ID decodes, and re-encoding gives the same ID
+	for pos := int64(0); pos < generator.MaxCombinations(); pos++ {
+		id := generator.PositionToID(pos)
+		data, err := generator.DecodeBytes(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(data) > generator.MaxPayload() {
+			continue // EncodeBytes rejects the longer payloads of the last positions
+		}
+		if back, err := generator.EncodeBytes(data); err != nil || back != id {
+			t.Fatalf("expected '%s' for %x, got '%s' (err: %v)", id, data, back, err)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package doremid
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/binary"
+	"math/bits"
+)
+
+// feistelRounds is the number of Feistel rounds; four already give a strong
+// pseudorandom permutation, more leave a margin for small domains
+const feistelRounds = 8
+
+// ObfuscatedPositionToID returns the ID for a sequential counter value after
+// running it through a keyed permutation of [0, MaxCombinations()). Consecutive
+// counters map to unrelated-looking IDs, yet every counter maps to a distinct ID
+// and IDToObfuscatedPosition recovers it.
+//
+// The permutation is a balanced Feistel network keyed by Config.PermutationKey,
+// with cycle walking to stay inside the ID space. Without a key it is the
+// identity, so the result equals PositionToID.
+// Returns an empty string if position is outside [0, MaxCombinations()).
+func (g *Generator) ObfuscatedPositionToID(position int64) string {
+	max := g.MaxCombinations()
+	if position < 0 || position >= max {
+		return ""
+	}
+	return g.PositionToID(permute(g.permutation, position, max, true))
+}
+
+// IDToObfuscatedPosition inverts ObfuscatedPositionToID, returning the counter
+// value id was generated from, or -1 if id is invalid
+func (g *Generator) IDToObfuscatedPosition(id string) int64 {
+	pos, err := g.decode(id)
+	if err != nil {
+		return -1
+	}
+	return permute(g.permutation, pos, g.MaxCombinations(), false)
+}
+
+// newPermutation derives the round function cipher from key
+func newPermutation(key string) cipher.Block {
+	sum := sha256.Sum256([]byte(key))
+	block, _ := aes.NewCipher(sum[:]) // A 32 byte key is always valid
+	return block
+}
+
+// permute applies the permutation keyed by block, or its inverse, to x in
+// [0, max). A nil block is the identity. Cycle walking re-applies it until the
+// result falls inside the domain; the Feistel domain is less than four times
+// max, so few walks are needed.
+func permute(block cipher.Block, x, max int64, forward bool) int64 {
+	if block == nil || max < 2 {
+		return x
+	}
+	return int64(permuteUint64(block, uint64(x), uint64(max), forward))
+}
+
+// permuteUint64 is permute for unsigned values, where a max of 0 stands for
+// 2^64 and needs no cycle walking. block must not be nil.
+func permuteUint64(block cipher.Block, y, max uint64, forward bool) uint64 {
+	half := (bits.Len64(max-1) + 1) / 2
+	for {
+		if forward {
+			y = feistel(block, y, half)
+		} else {
+			y = feistelInverse(block, y, half)
+		}
+		if max == 0 || y < max {
+			return y
+		}
+	}
+}
+
+// feistel encrypts x, split into two halves of half bits
+func feistel(block cipher.Block, x uint64, half int) uint64 {
+	mask := uint64(1)<<half - 1
+	left, right := x>>half, x&mask
+	for round := 0; round < feistelRounds; round++ {
+		left, right = right, left^(roundFunction(block, round, right)&mask)
+	}
+	return left<<half | right
+}
+
+// feistelInverse decrypts x, split into two halves of half bits
+func feistelInverse(block cipher.Block, x uint64, half int) uint64 {
+	mask := uint64(1)<<half - 1
+	left, right := x>>half, x&mask
+	for round := feistelRounds - 1; round >= 0; round-- {
+		left, right = right^(roundFunction(block, round, left)&mask), left
+	}
+	return left<<half | right
+}
+
+// roundFunction is the keyed pseudorandom function of one round
+func roundFunction(block cipher.Block, round int, value uint64) uint64 {
+	var buf [aes.BlockSize]byte
+	buf[0] = byte(round)
+	binary.BigEndian.PutUint64(buf[8:], value)
+	block.Encrypt(buf[:], buf[:])
+	return binary.BigEndian.Uint64(buf[:8])
+}
@@ -0,0 +1,85 @@
+package doremid
+
+import "testing"
+
+func TestObfuscatedPositions(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 2,
+		Separator:              "-",
+		PermutationKey:         "secret",
+	})
+	max := generator.MaxCombinations()
+
+	seen := make(map[string]bool, max)
+	sequential := 0
+	for pos := int64(0); pos < max; pos++ {
+		id := generator.ObfuscatedPositionToID(pos)
+		if id == "" || seen[id] {
+			t.Fatalf("expected a distinct ID for position %d, got '%s'", pos, id)
+		}
+		seen[id] = true
+
+		if back := generator.IDToObfuscatedPosition(id); back != pos {
+			t.Fatalf("expected '%s' to map back to %d, got %d", id, pos, back)
+		}
+		if pos > 0 && generator.IDToPosition(id) == generator.IDToPosition(generator.ObfuscatedPositionToID(pos-1))+1 {
+			sequential++
+		}
+	}
+	if sequential > 10 {
+		t.Errorf("expected consecutive counters to scatter, %d stayed sequential", sequential)
+	}
+
+	other := New(Config{JustIntonationDigits: 2, EqualTemperamentDigits: 2, Separator: "-", PermutationKey: "other"})
+	if other.ObfuscatedPositionToID(1) == generator.ObfuscatedPositionToID(1) && other.ObfuscatedPositionToID(2) == generator.ObfuscatedPositionToID(2) {
+		t.Error("expected different keys to give different permutations")
+	}
+
+	tests := []struct {
+		name     string
+		position int64
+		expected string
+	}{
+		{"negative", -1, ""},
+		{"beyond max", max, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if id := generator.ObfuscatedPositionToID(tt.position); id != tt.expected {
+				t.Errorf("expected '%s', got '%s'", tt.expected, id)
+			}
+		})
+	}
+	if pos := generator.IDToObfuscatedPosition("invalid"); pos != -1 {
+		t.Errorf("expected -1 for an invalid ID, got %d", pos)
+	}
+}
+
+func TestObfuscatedPositionsLargeSpace(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   8,
+		EqualTemperamentDigits: 10,
+		Separator:              "-",
+		PermutationKey:         "secret",
+	})
+
+	for _, pos := range []int64{0, 1, 2, 1 << 40, generator.MaxCombinations() - 1} {
+		id := generator.ObfuscatedPositionToID(pos)
+		if back := generator.IDToObfuscatedPosition(id); back != pos {
+			t.Errorf("expected '%s' to map back to %d, got %d", id, pos, back)
+		}
+	}
+}
+
+func TestObfuscatedPositionsWithoutKey(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 2,
+		Separator:              "-",
+	})
+
+	if id := generator.ObfuscatedPositionToID(42); id != generator.PositionToID(42) {
+		t.Errorf("expected identity without a key, got '%s'", id)
+	}
+}
@@ -0,0 +1,88 @@
+package doremid
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// PositionFormat configures FormatPositionWith
+type PositionFormat struct {
+	// Base writes positions with the digits of BaseCharacters, from 2 to 36.
+	// Zero uses 10.
+	Base int
+
+	// Width pads positions with leading zeros to at least Width digits. Zero
+	// uses the width of the largest position of the generator in Base.
+	Width int
+
+	// Separator, when non-empty, follows the position with Separator and the
+	// ID at that position, e.g. "03722_domi-1a2" with "_" for 2 notes and 3
+	// characters, so file names carrying IDs list in position order
+	Separator string
+}
+
+// FormatPosition writes position in decimal, padded with leading zeros to the
+// width of the largest position, e.g. "000003722" with the default
+// configuration, so positions in file names or other strings sort in numeric
+// order.
+// Returns "" if position is outside the ID space.
+func (g *Generator) FormatPosition(position int64) string {
+	s, _ := g.FormatPositionWith(position, PositionFormat{})
+	return s
+}
+
+// FormatPositionWith writes position padded as format describes, see
+// FormatPosition.
+// Returns a *RangeError if position is outside the ID space and a
+// *ConfigError if format is invalid.
+func (g *Generator) FormatPositionWith(position int64, format PositionFormat) (string, error) {
+	base := format.Base
+	if base == 0 {
+		base = 10
+	}
+	if base < 2 || base > len(BaseCharacters) {
+		return "", &ConfigError{Field: "Base", Reason: "must be between 2 and 36"}
+	}
+	if format.Width < 0 {
+		return "", &ConfigError{Field: "Width", Reason: "must not be negative"}
+	}
+	if maxValue := g.MaxCombinationsBig(); position < 0 || maxValue.IsInt64() && position >= maxValue.Int64() {
+		return "", &RangeError{Position: position, Min: 0, Max: g.MaxCombinations()}
+	}
+
+	width := format.Width
+	if width == 0 {
+		width = g.positionWidth(base)
+	}
+	digits := strconv.FormatInt(position, base)
+	s := strings.Repeat("0", max(width-len(digits), 0)) + digits
+	if format.Separator != "" {
+		s += format.Separator + g.PositionToID(position)
+	}
+	return s, nil
+}
+
+// positionWidth returns the number of digits of the largest position in base,
+// at most those of math.MaxInt64
+func (g *Generator) positionWidth(base int) int {
+	largest := g.MaxCombinationsBig()
+	largest.Sub(largest, big.NewInt(1))
+	return min(len(largest.Text(base)), len(strconv.FormatInt(math.MaxInt64, base)))
+}
+
+// WidthForCapacity returns the length in bytes of the longest ID a generator
+// built from config produces, counting any prefix, version marker, group
+// separators and check character, e.g. to size a database column before
+// creating the generator. With MinCombinations, the digit counts it picks are
+// used. Every ID has this length unless custom notes differ in length.
+// Returns a *ConfigError if config is invalid.
+func WidthForCapacity(config Config) (int, error) {
+	g, err := newGenerator(config)
+	if err != nil {
+		return 0, err
+	}
+	_, longest := g.idLengths()
+	return longest, nil
+}
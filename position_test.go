@@ -0,0 +1,116 @@
+package doremid
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+func TestFormatPosition(t *testing.T) {
+	generator := New(Config{JustIntonationDigits: 2, EqualTemperamentDigits: 3, Separator: "-"})
+
+	tests := []struct {
+		position int64
+		expected string
+	}{
+		{0, "00000"},
+		{3722, "03722"},
+		{84671, "84671"},
+		{-1, ""},
+		{84672, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(strconv.FormatInt(tt.position, 10), func(t *testing.T) {
+			if s := generator.FormatPosition(tt.position); s != tt.expected {
+				t.Errorf("expected '%s', got '%s'", tt.expected, s)
+			}
+		})
+	}
+
+	// Padded positions sort in numeric order
+	var formatted []string
+	for _, position := range []int64{7, 80, 900, 1000, 84671} {
+		formatted = append(formatted, generator.FormatPosition(position))
+	}
+	if !slices.IsSorted(formatted) {
+		t.Errorf("expected sorted strings, got %v", formatted)
+	}
+
+	if s := NewWithDefaults().FormatPosition(3722); s != "000003722" {
+		t.Errorf("expected '000003722', got '%s'", s)
+	}
+	large := New(Config{JustIntonationDigits: 20, EqualTemperamentDigits: 20, Separator: "-"})
+	if s := large.FormatPosition(42); len(s) != len(strconv.FormatInt(math.MaxInt64, 10)) {
+		t.Errorf("expected the width of the largest int64, got '%s'", s)
+	}
+}
+
+func TestFormatPositionWith(t *testing.T) {
+	generator := New(Config{JustIntonationDigits: 2, EqualTemperamentDigits: 3, Separator: "-"})
+
+	tests := []struct {
+		name     string
+		format   PositionFormat
+		expected string
+	}{
+		{"default", PositionFormat{}, "03722"},
+		{"hex", PositionFormat{Base: 16}, "00e8a"},
+		{"binary", PositionFormat{Base: 2}, "00000111010001010"},
+		{"wider", PositionFormat{Width: 8}, "00003722"},
+		{"narrower", PositionFormat{Width: 2}, "3722"},
+		{"with ID", PositionFormat{Separator: "_"}, "03722_domi-1a2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := generator.FormatPositionWith(3722, tt.format)
+			if err != nil || s != tt.expected {
+				t.Errorf("expected '%s', got '%s' (err: %v)", tt.expected, s, err)
+			}
+		})
+	}
+
+	for _, format := range []PositionFormat{{Base: 1}, {Base: 37}, {Width: -1}} {
+		if _, err := generator.FormatPositionWith(3722, format); !errors.Is(err, ErrInvalidConfig) {
+			t.Errorf("expected ErrInvalidConfig for %+v, got %v", format, err)
+		}
+	}
+	if _, err := generator.FormatPositionWith(84672, PositionFormat{}); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("expected ErrOutOfRange, got %v", err)
+	}
+}
+
+func TestWidthForCapacity(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   Config
+		expected int
+	}{
+		{"default", DefaultConfig(), 14},
+		{"prefix and checksum", Config{JustIntonationDigits: 2, EqualTemperamentDigits: 3, Separator: "-", Prefix: "usr", Checksum: true}, 13},
+		{"version", Config{JustIntonationDigits: 2, EqualTemperamentDigits: 3, Separator: "-", Version: 2}, 9},
+		{"capacity", Config{MinCombinations: 1000000, Separator: "-"}, 9},
+		{"grouped", Config{JustIntonationDigits: 4, EqualTemperamentDigits: 5, Separator: "-", GroupSize: 2, NoteGroupSeparator: "."}, 15},
+		{"notes of different lengths", Config{JustIntonationDigits: 2, EqualTemperamentDigits: 3, Separator: "-", Notes: "ut re mi fa sol la si"}, 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			width, err := WidthForCapacity(tt.config)
+			if err != nil || width != tt.expected {
+				t.Fatalf("expected %d, got %d (err: %v)", tt.expected, width, err)
+			}
+			generator := New(tt.config)
+			if id := generator.PositionToID(generator.MaxCombinations() - 1); len(id) > width {
+				t.Errorf("expected at most %d bytes, got '%s'", width, id)
+			}
+		})
+	}
+
+	if _, err := WidthForCapacity(Config{MinCombinations: -1}); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig, got %v", err)
+	}
+}
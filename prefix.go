@@ -0,0 +1,32 @@
+package doremid
+
+import "strings"
+
+// ParsePrefix returns the namespace prefix of id, the text before the first
+// separator, without knowing which generator issued it, e.g. to route "usr_..."
+// and "ord_..." IDs to their generators. Returns false if id has no separator.
+// An empty separator uses DefaultPrefixSeparator.
+func ParsePrefix(id, separator string) (string, bool) {
+	if separator == "" {
+		separator = DefaultPrefixSeparator
+	}
+	prefix, _, found := strings.Cut(id, separator)
+	if !found || prefix == "" {
+		return "", false
+	}
+	return prefix, true
+}
+
+// Prefix returns the namespace prefix of the generator's IDs without its separator,
+// empty if none is configured
+func (g *Generator) Prefix() string {
+	return g.namespace
+}
+
+// prefixSeparator returns the separator following config.Prefix
+func prefixSeparator(config Config) string {
+	if config.PrefixSeparator == "" {
+		return DefaultPrefixSeparator
+	}
+	return config.PrefixSeparator
+}
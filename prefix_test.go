@@ -0,0 +1,124 @@
+package doremid
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestPrefix(t *testing.T) {
+	users := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 3,
+		Separator:              "-",
+		Prefix:                 "usr",
+	})
+
+	if id := users.PositionToID(3722); id != "usr_domi-1a2" {
+		t.Errorf("expected 'usr_domi-1a2', got '%s'", id)
+	}
+	if id := users.NewID(); !strings.HasPrefix(id, "usr_") || users.IDToPosition(id) < 0 {
+		t.Errorf("expected a valid prefixed ID, got '%s'", id)
+	}
+	if users.Prefix() != "usr" {
+		t.Errorf("expected prefix 'usr', got '%s'", users.Prefix())
+	}
+
+	tests := []struct {
+		id       string
+		expected int64
+	}{
+		{"usr_domi-1a2", 3722},
+		{"domi-1a2", -1},
+		{"ord_domi-1a2", -1},
+		{"usr-domi-1a2", -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			if pos := users.IDToPosition(tt.id); pos != tt.expected {
+				t.Errorf("expected %d, got %d", tt.expected, pos)
+			}
+		})
+	}
+
+	if err := users.Validate("ord_domi-1a2"); !errors.Is(err, ErrInvalidFormat) {
+		t.Errorf("expected ErrInvalidFormat for a foreign prefix, got %v", err)
+	}
+
+	// Prefixes compose with checksums, custom separators and big positions
+	orders := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 3,
+		Separator:              "-",
+		Prefix:                 "ord",
+		PrefixSeparator:        ":",
+		Checksum:               true,
+	})
+	id := orders.PositionToID(3722)
+	if id != "ord:domi-1a26" || orders.IDToPosition(id) != 3722 {
+		t.Errorf("expected 'ord:domi-1a26', got '%s'", id)
+	}
+	if position, err := orders.IDToPositionBig(id); err != nil || position.Int64() != 3722 {
+		t.Errorf("expected big position 3722, got %v (err: %v)", position, err)
+	}
+}
+
+func TestPrefixDisplayAndDebug(t *testing.T) {
+	users := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 3,
+		Separator:              "-",
+		Prefix:                 "usr",
+	})
+
+	form, _ := users.DisplayForm(katakana)
+	display, err := form.Display("usr_domi-1a2")
+	if err != nil || display != "usr_ドミ・１Ａ２" {
+		t.Errorf("expected 'usr_ドミ・１Ａ２', got '%s' (err: %v)", display, err)
+	}
+	if canonical, err := form.Canonical(display); err != nil || canonical != "usr_domi-1a2" {
+		t.Errorf("expected 'usr_domi-1a2', got '%s' (err: %v)", canonical, err)
+	}
+	if _, err := form.Canonical("ドミ・１Ａ２"); !errors.Is(err, ErrInvalidFormat) {
+		t.Errorf("expected ErrInvalidFormat without the prefix, got %v", err)
+	}
+
+	layout := users.Debug().Layout
+	if layout[0] != (LayoutField{Name: "prefix", Offset: 0, Length: 4}) || layout[1].Offset != 4 || layout[3].Offset != 9 {
+		t.Errorf("unexpected layout %+v", layout)
+	}
+}
+
+func TestParsePrefix(t *testing.T) {
+	tests := []struct {
+		id        string
+		separator string
+		expected  string
+		found     bool
+	}{
+		{"usr_domi-1a2", "", "usr", true},
+		{"ord:domi-1a26", ":", "ord", true},
+		{"domi-1a2", "", "", false},
+		{"_domi-1a2", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			prefix, found := ParsePrefix(tt.id, tt.separator)
+			if prefix != tt.expected || found != tt.found {
+				t.Errorf("expected ('%s', %v), got ('%s', %v)", tt.expected, tt.found, prefix, found)
+			}
+		})
+	}
+}
+
+func TestPrefixConfig(t *testing.T) {
+	_, err := NewE(Config{JustIntonationDigits: 2, Prefix: "my_users"})
+	if !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig for a prefix containing its separator, got %v", err)
+	}
+	if _, err := NewE(Config{JustIntonationDigits: 2, Prefix: "my_users", PrefixSeparator: ":"}); err != nil {
+		t.Errorf("expected a valid config, got %v", err)
+	}
+}
@@ -0,0 +1,108 @@
+package doremid
+
+import "sync"
+
+// IDPool hands out random IDs precomputed by a background goroutine, so that
+// latency-sensitive paths obtain an ID with a single buffer read and no random
+// number generation or division on the hot path.
+//
+// IDs have the same distribution as NewID and respect any restriction of the
+// generator the pool was created from. An IDPool is safe for concurrent use.
+type IDPool struct {
+	ids       chan string
+	done      chan struct{}
+	exhausted chan struct{} // Closed when the generator stops yielding IDs
+	once      sync.Once
+	wg        sync.WaitGroup
+}
+
+// Prewarm starts a pool buffering up to n random IDs, refilled in the background
+// as IDs are taken. The pool uses its own random source, so g remains usable.
+// n is clamped to at least 1. Call Close to stop the refill goroutine. The
+// goroutine also stops once the generator is exhausted, e.g. by a restriction
+// to positions that are all reserved.
+func (g *Generator) Prewarm(n int64) *IDPool {
+	clone := *g
+	clone.rand = g.forkRand()
+
+	p := &IDPool{
+		ids:       make(chan string, max(n, 1)),
+		done:      make(chan struct{}),
+		exhausted: make(chan struct{}),
+	}
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		for {
+			id := clone.NewID()
+			if id == "" {
+				close(p.exhausted)
+				return
+			}
+			select {
+			case p.ids <- id:
+			case <-p.done:
+				return
+			}
+		}
+	}()
+	return p
+}
+
+// Next returns a buffered ID, waiting for the refill goroutine if the buffer is
+// empty. Returns an empty string once the pool is closed or exhausted and drained.
+func (p *IDPool) Next() string {
+	id, _ := p.NextE()
+	return id
+}
+
+// NextE is like Next but reports why no ID is left: ErrSpaceExhausted once the
+// generator is exhausted, or ErrPoolClosed once the pool is closed.
+func (p *IDPool) NextE() (string, error) {
+	select {
+	case id := <-p.ids:
+		return id, nil
+	default:
+	}
+
+	select {
+	case id := <-p.ids:
+		return id, nil
+	case <-p.done:
+	case <-p.exhausted:
+	}
+	if id, ok := p.TryNext(); ok {
+		return id, nil
+	}
+	select {
+	case <-p.exhausted:
+		return "", ErrSpaceExhausted
+	default:
+		return "", ErrPoolClosed
+	}
+}
+
+// TryNext returns a buffered ID without waiting.
+// Returns false if the buffer is currently empty.
+func (p *IDPool) TryNext() (string, bool) {
+	select {
+	case id := <-p.ids:
+		return id, true
+	default:
+		return "", false
+	}
+}
+
+// Len returns the number of IDs currently buffered
+func (p *IDPool) Len() int {
+	return len(p.ids)
+}
+
+// Close stops refilling the pool. IDs already buffered can still be taken.
+func (p *IDPool) Close() {
+	p.once.Do(func() {
+		close(p.done)
+	})
+	p.wg.Wait()
+}
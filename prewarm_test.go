@@ -0,0 +1,109 @@
+package doremid
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPrewarm(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 3,
+		Separator:              "-",
+	})
+
+	pool := generator.Prewarm(64)
+	defer pool.Close()
+
+	// Wait for the buffer to fill
+	deadline := time.Now().Add(time.Second)
+	for pool.Len() < 64 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if pool.Len() != 64 {
+		t.Fatalf("expected 64 buffered IDs, got %d", pool.Len())
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if id := pool.Next(); generator.IDToPosition(id) < 0 {
+					t.Errorf("pool returned invalid ID '%s'", id)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestPrewarmRestricted(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 3,
+		Separator:              "-",
+	}).Restrict(Range{Start: 100, End: 110})
+
+	pool := generator.Prewarm(8)
+	defer pool.Close()
+
+	for i := 0; i < 50; i++ {
+		if pos := generator.IDToPosition(pool.Next()); pos < 100 || pos >= 110 {
+			t.Errorf("expected position in [100, 110), got %d", pos)
+		}
+	}
+}
+
+func TestPrewarmClose(t *testing.T) {
+	pool := NewWithDefaults().Prewarm(4)
+	pool.Close()
+	pool.Close()
+
+	for i := 0; i < 4; i++ {
+		pool.Next()
+	}
+	if id := pool.Next(); id != "" {
+		t.Errorf("expected empty ID from drained pool, got '%s'", id)
+	}
+	if _, ok := pool.TryNext(); ok {
+		t.Error("expected TryNext to fail on a drained pool")
+	}
+	if _, err := pool.NextE(); !errors.Is(err, ErrPoolClosed) {
+		t.Errorf("expected ErrPoolClosed, got %v", err)
+	}
+}
+
+func TestPrewarmExhausted(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   1,
+		EqualTemperamentDigits: 1,
+		Separator:              "-",
+		ReservedPositions:      []int64{0, 1, 2},
+	}).Restrict(Range{Start: 0, End: 3})
+
+	pool := generator.Prewarm(4)
+	defer pool.Close()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if id, err := pool.NextE(); id != "" || !errors.Is(err, ErrSpaceExhausted) {
+			t.Errorf("expected ErrSpaceExhausted, got '%s' and %v", id, err)
+		}
+		if id := pool.Next(); id != "" {
+			t.Errorf("expected empty ID from exhausted pool, got '%s'", id)
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected Next to return once the generator is exhausted")
+	}
+	if pool.Len() != 0 {
+		t.Errorf("expected no buffered IDs, got %d", pool.Len())
+	}
+}
@@ -0,0 +1,6 @@
+//go:build race
+
+package doremid
+
+// raceEnabled reports whether the race detector is on, which adds allocations
+const raceEnabled = true
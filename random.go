@@ -0,0 +1,121 @@
+package doremid
+
+import (
+	crand "crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/rand"
+	randv2 "math/rand/v2"
+	"sync"
+)
+
+// RandSource supplies the random bytes behind random IDs through Config.Rand.
+// crypto/rand.Reader, a hardware RNG device opened as a file and a seeded
+// math/rand/v2.ChaCha8 all implement it. Generation panics if Read fails,
+// so a source must not run dry.
+type RandSource interface {
+	io.Reader
+}
+
+// cryptoSource is a math/rand source backed by crypto/rand.
+// It holds no state, so it is safe for concurrent use.
+type cryptoSource struct{}
+
+// Int63 returns a uniformly distributed non-negative 63-bit integer
+func (cryptoSource) Int63() int64 {
+	return int64(cryptoSource{}.Uint64() & (1<<63 - 1))
+}
+
+// Uint64 returns a uniformly distributed 64-bit integer
+func (cryptoSource) Uint64() uint64 {
+	var buf [8]byte
+	crand.Read(buf[:])
+	return binary.BigEndian.Uint64(buf[:])
+}
+
+// Seed is a no-op; crypto/rand cannot be seeded
+func (cryptoSource) Seed(int64) {}
+
+// NewSeeded creates a generator like New whose random IDs are reproducible:
+// generators with the same config and seed produce the same stream of NewID
+// and BatchGenerateRandomIDs results, on every run and platform, as long as
+// calls happen in the same order. Use it in tests and simulations, never for
+// IDs that must be unpredictable. It sets Config.Rand to a ChaCha8 generator
+// keyed by seed.
+func NewSeeded(config Config, seed int64) *Generator {
+	var key [32]byte
+	binary.BigEndian.PutUint64(key[:], uint64(seed))
+	config.Rand = randv2.NewChaCha8(key)
+	g := New(config)
+	g.seeded = true
+	return g
+}
+
+// readerSource is a math/rand source reading from a RandSource. Reads are
+// serialized by a mutex, so it is safe for concurrent use.
+type readerSource struct {
+	mu  sync.Mutex
+	r   RandSource
+	buf [8]byte
+}
+
+// Int63 returns a non-negative 63-bit integer read from the source
+func (s *readerSource) Int63() int64 {
+	return int64(s.Uint64() & (1<<63 - 1))
+}
+
+// Uint64 returns a 64-bit integer read from the source
+func (s *readerSource) Uint64() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := io.ReadFull(s.r, s.buf[:]); err != nil {
+		panic(fmt.Sprintf("doremid: reading Config.Rand: %v", err))
+	}
+	return binary.BigEndian.Uint64(s.buf[:])
+}
+
+// Seed is a no-op; the source seeds itself
+func (*readerSource) Seed(int64) {}
+
+// forkRand returns an independent random source of the same kind as g's,
+// for components that generate IDs concurrently with g
+func (g *Generator) forkRand() *rand.Rand {
+	if g.randSource != nil {
+		// A configured source is shared, keeping it the only source of randomness
+		return g.rand
+	}
+	if g.secureRandom {
+		return rand.New(cryptoSource{})
+	}
+
+	rng := g.acquireRand()
+	defer g.releaseRand(rng)
+	return rand.New(rand.NewSource(rng.Int63()))
+}
+
+// newRandPool creates a pool of math/rand sources, each seeded from crypto/rand
+// so that sources created at the same instant still differ
+func newRandPool() *sync.Pool {
+	return &sync.Pool{
+		New: func() any {
+			return rand.New(rand.NewSource(cryptoSource{}.Int63()))
+		},
+	}
+}
+
+// acquireRand returns a random source for the calling goroutine.
+// It must be handed back with releaseRand.
+func (g *Generator) acquireRand() *rand.Rand {
+	if g.randPool != nil {
+		return g.randPool.Get().(*rand.Rand)
+	}
+	return g.rand
+}
+
+// releaseRand returns a source obtained from acquireRand
+func (g *Generator) releaseRand(rng *rand.Rand) {
+	if g.randPool != nil {
+		g.randPool.Put(rng)
+	}
+}
@@ -0,0 +1,213 @@
+package doremid
+
+import (
+	"bytes"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestSecureRandom(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 3,
+		Separator:              "-",
+		SecureRandom:           true,
+	})
+
+	seen := make(map[string]bool)
+	for i := 0; i < 100; i++ {
+		id := generator.NewID()
+		if generator.IDToPosition(id) < 0 {
+			t.Fatalf("generated invalid ID '%s'", id)
+		}
+		seen[id] = true
+	}
+	if len(seen) < 90 {
+		t.Errorf("too few distinct IDs from secure source: %d", len(seen))
+	}
+
+	batch := generator.BatchGenerateRandomIDs(500)
+	unique := make(map[string]bool)
+	for _, id := range batch {
+		unique[id] = true
+	}
+	if len(batch) != 500 || len(unique) != 500 {
+		t.Errorf("expected 500 unique IDs, got %d of %d", len(unique), len(batch))
+	}
+
+	if rng := generator.Debug().RNG; rng != "crypto/rand" {
+		t.Errorf("expected crypto/rand, got %s", rng)
+	}
+
+	pool := generator.Prewarm(4)
+	defer pool.Close()
+	if id := pool.Next(); generator.IDToPosition(id) < 0 {
+		t.Errorf("prewarmed pool returned invalid ID '%s'", id)
+	}
+}
+
+func TestCryptoSource(t *testing.T) {
+	var source cryptoSource
+	for i := 0; i < 1000; i++ {
+		if source.Int63() < 0 {
+			t.Fatal("Int63 returned a negative value")
+		}
+	}
+}
+
+func TestConcurrentGenerator(t *testing.T) {
+	for _, config := range []Config{
+		{JustIntonationDigits: 2, EqualTemperamentDigits: 3, Separator: "-", Concurrent: true},
+		{JustIntonationDigits: 2, EqualTemperamentDigits: 3, Separator: "-", SecureRandom: true},
+	} {
+		generator := New(config)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 200; j++ {
+					if id := generator.NewID(); generator.IDToPosition(id) < 0 {
+						t.Errorf("generated invalid ID '%s'", id)
+						return
+					}
+				}
+				if ids := generator.BatchGenerateRandomIDs(50); len(ids) != 50 {
+					t.Errorf("expected 50 IDs, got %d", len(ids))
+				}
+				generator.Restrict(Range{Start: 10, End: 20}).NewID()
+			}()
+		}
+		wg.Wait()
+	}
+
+	if rng := New(Config{JustIntonationDigits: 1, EqualTemperamentDigits: 1, Concurrent: true}).Debug().RNG; rng != "math/rand (per-goroutine pool)" {
+		t.Errorf("unexpected RNG %s", rng)
+	}
+}
+
+func TestRandSource(t *testing.T) {
+	seeded := func(seed byte) Config {
+		return Config{
+			JustIntonationDigits:   2,
+			EqualTemperamentDigits: 3,
+			Separator:              "-",
+			Rand:                   rand.NewChaCha8([32]byte{seed}),
+		}
+	}
+
+	t.Run("same seed, same IDs", func(t *testing.T) {
+		a, b := New(seeded(1)), New(seeded(1))
+		for i := 0; i < 50; i++ {
+			if idA, idB := a.NewID(), b.NewID(); idA != idB {
+				t.Fatalf("expected equal IDs, got '%s' and '%s'", idA, idB)
+			}
+		}
+		if batchA, batchB := a.BatchGenerateRandomIDs(100), b.BatchGenerateRandomIDs(100); !slices.Equal(batchA, batchB) {
+			t.Error("expected equal batches from equally seeded generators")
+		}
+		if idA, idB := a.Restrict(Range{Start: 10, End: 1000}).NewID(), b.Restrict(Range{Start: 10, End: 1000}).NewID(); idA != idB {
+			t.Errorf("expected equal restricted IDs, got '%s' and '%s'", idA, idB)
+		}
+	})
+
+	t.Run("different seeds, different IDs", func(t *testing.T) {
+		a, b := New(seeded(1)), New(seeded(2))
+		if batchA, batchB := a.BatchGenerateRandomIDs(20), b.BatchGenerateRandomIDs(20); slices.Equal(batchA, batchB) {
+			t.Error("expected different batches from differently seeded generators")
+		}
+	})
+
+	t.Run("overrides the built-in sources", func(t *testing.T) {
+		config := seeded(3)
+		config.SecureRandom, config.Concurrent = true, true
+		generator := New(config)
+		if rng := generator.Debug().RNG; rng != "*rand.ChaCha8 (Config.Rand)" {
+			t.Errorf("expected *rand.ChaCha8 (Config.Rand), got %s", rng)
+		}
+		if id := New(seeded(3)).NewID(); generator.NewID() != id {
+			t.Error("expected SecureRandom and Concurrent to be ignored")
+		}
+		if generator.BruteForceCost(BruteForceParams{}).PredictableRNG {
+			t.Error("expected a configured source not to be reported as predictable")
+		}
+	})
+
+	t.Run("concurrent use", func(t *testing.T) {
+		generator := New(seeded(4))
+		pool := generator.Prewarm(4)
+		defer pool.Close()
+
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 100; j++ {
+					if id := generator.NewID(); generator.IDToPosition(id) < 0 {
+						t.Errorf("generated invalid ID '%s'", id)
+						return
+					}
+				}
+			}()
+		}
+		wg.Wait()
+		if id := pool.Next(); generator.IDToPosition(id) < 0 {
+			t.Errorf("prewarmed pool returned invalid ID '%s'", id)
+		}
+	})
+
+	t.Run("exhausted source panics", func(t *testing.T) {
+		config := seeded(0)
+		config.Rand = bytes.NewReader(make([]byte, 5*8)) // 8 bytes per symbol
+		generator := New(config)
+		if id := generator.PositionToID(0); generator.NewID() != id {
+			t.Errorf("expected all-zero bytes to give position 0 '%s'", id)
+		}
+
+		defer func() {
+			r := recover()
+			if msg, ok := r.(string); !ok || !strings.Contains(msg, "Config.Rand") {
+				t.Errorf("expected a panic naming Config.Rand, got %v", r)
+			}
+		}()
+		generator.NewID()
+	})
+}
+
+func TestNewSeeded(t *testing.T) {
+	config := Config{JustIntonationDigits: 2, EqualTemperamentDigits: 3, Separator: "-", Concurrent: true}
+	stream := func(g *Generator) []string {
+		ids := []string{g.NewID(), g.NewID()}
+		ids = append(ids, g.BatchGenerateRandomIDs(50)...)
+		return append(ids, g.Restrict(Range{Start: 100, End: 200}).NewID())
+	}
+
+	a, b := stream(NewSeeded(config, 42)), stream(NewSeeded(config, 42))
+	if !slices.Equal(a, b) {
+		t.Errorf("expected identical streams for the same seed, got %v and %v", a[:3], b[:3])
+	}
+	if c := stream(NewSeeded(config, 43)); slices.Equal(a, c) {
+		t.Error("expected different streams for different seeds")
+	}
+	if d := stream(NewSeeded(config, -42)); slices.Equal(a, d) {
+		t.Error("expected different streams for seeds of opposite sign")
+	}
+
+	// The stream is fixed by the seed alone, so it must not change between releases
+	if id := NewSeeded(config, 1).NewID(); id != "fare-51a" {
+		t.Errorf("expected the first ID of seed 1 to be stable, got '%s'", id)
+	}
+
+	// Anyone knowing the seed can reproduce the IDs, restricted or not
+	seeded := NewSeeded(config, 42)
+	for _, g := range []*Generator{seeded, seeded.Restrict(Range{Start: 100, End: 200})} {
+		if !g.BruteForceCost(BruteForceParams{}).PredictableRNG {
+			t.Error("expected a seeded generator to be reported as predictable")
+		}
+	}
+}
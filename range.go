@@ -0,0 +1,100 @@
+package doremid
+
+import "fmt"
+
+// Range is a half-open range of positions [Start, End)
+type Range struct {
+	Start int64
+	End   int64
+}
+
+// Len returns the number of positions in the range, 0 if it is empty
+func (r Range) Len() int64 {
+	if r.End <= r.Start {
+		return 0
+	}
+	return r.End - r.Start
+}
+
+// Contains reports whether position lies within the range
+func (r Range) Contains(position int64) bool {
+	return position >= r.Start && position < r.End
+}
+
+// intersect returns the positions contained in both ranges
+func (r Range) intersect(other Range) Range {
+	result := Range{Start: max(r.Start, other.Start), End: min(r.End, other.End)}
+	if result.End < result.Start {
+		result.End = result.Start
+	}
+	return result
+}
+
+// Restrict returns a generator that can only mint IDs with positions inside r.
+//
+// The returned generator shares the configuration of g. NewID, the batch methods,
+// Mint and the store-backed methods never produce positions outside the range;
+// parsing and conversion methods are unaffected. Restricting an already restricted
+// generator can only narrow its range, so a delegated keyspace slice can be
+// subdivided further but never widened. A range that does not overlap the allowed
+// positions yields a generator that mints nothing. The returned generator has its
+// own random source, so it can be used concurrently with g.
+func (g *Generator) Restrict(r Range) *Generator {
+	restricted := g.mintRange().intersect(r)
+
+	clone := *g
+	clone.restriction = &restricted
+	if g.randPool == nil {
+		clone.rand = g.forkRand()
+	}
+	return &clone
+}
+
+// MintRange returns the range of positions the generator may mint
+func (g *Generator) MintRange() Range {
+	return g.mintRange()
+}
+
+// Mint returns the ID for position, or a *RangeError if the generator may not mint it,
+// an error matching ErrReserved if position is reserved, or ErrFiltered if its
+// notes spell a word banned by Config.WordFilter
+func (g *Generator) Mint(position int64) (string, error) {
+	if err := checkRange(position, g.mintRange()); err != nil {
+		return "", err
+	}
+	if g.reserved.contains(position) {
+		return "", fmt.Errorf("%w: %d", ErrReserved, position)
+	}
+	if g.filtered(position) {
+		return "", fmt.Errorf("%w: %d", ErrFiltered, position)
+	}
+	return g.PositionToID(position), nil
+}
+
+// mintRange returns the allowed range, covering the whole space if unrestricted
+func (g *Generator) mintRange() Range {
+	if g.restriction != nil {
+		return *g.restriction
+	}
+	return Range{Start: 0, End: g.MaxCombinations()}
+}
+
+// appendRestrictedID appends a random ID inside the mint range to dst, drawn
+// from the unreserved positions and re-rolled while its notes are filtered.
+// Appends nothing if no position is found.
+func (g *Generator) appendRestrictedID(dst []byte) []byte {
+	r := g.mintRange()
+	n := g.EffectiveCombinations()
+	if n == 0 {
+		return dst
+	}
+
+	rng := g.acquireRand()
+	defer g.releaseRand(rng)
+	for attempt := 0; attempt <= maxRerolls; attempt++ {
+		if pos := g.reserved.nth(r, rng.Int63n(n)); !g.filtered(pos) {
+			return g.AppendPositionID(dst, pos)
+		}
+	}
+	return dst
+}
@@ -0,0 +1,153 @@
+package doremid
+
+import (
+	"context"
+	"sync"
+	"testing"
+)
+
+func TestRange(t *testing.T) {
+	r := Range{Start: 10, End: 20}
+	if r.Len() != 10 {
+		t.Errorf("expected length 10, got %d", r.Len())
+	}
+	if !r.Contains(10) || !r.Contains(19) || r.Contains(20) || r.Contains(9) {
+		t.Error("Contains does not respect half-open bounds")
+	}
+	if (Range{Start: 5, End: 3}).Len() != 0 {
+		t.Error("inverted range should be empty")
+	}
+}
+
+func TestRestrict(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   1,
+		EqualTemperamentDigits: 2,
+		Separator:              "-",
+	})
+	restricted := generator.Restrict(Range{Start: 100, End: 110})
+
+	t.Run("random IDs stay in range", func(t *testing.T) {
+		for i := 0; i < 200; i++ {
+			pos := restricted.IDToPosition(restricted.NewID())
+			if pos < 100 || pos >= 110 {
+				t.Fatalf("position %d outside restricted range", pos)
+			}
+		}
+	})
+
+	t.Run("random batches stay in range", func(t *testing.T) {
+		ids := restricted.BatchGenerateRandomIDs(10)
+		if len(ids) != 10 {
+			t.Fatalf("expected 10 IDs, got %d", len(ids))
+		}
+		for _, id := range ids {
+			if pos := restricted.IDToPosition(id); pos < 100 || pos >= 110 {
+				t.Errorf("position %d outside restricted range", pos)
+			}
+		}
+		if ids := restricted.BatchGenerateRandomIDs(11); len(ids) != 0 {
+			t.Errorf("expected empty result when count exceeds range, got %d IDs", len(ids))
+		}
+	})
+
+	t.Run("sequential batches are clipped", func(t *testing.T) {
+		if ids := restricted.BatchGenerateIDs(5, 0); len(ids) != 0 {
+			t.Errorf("expected no IDs before range start, got %d", len(ids))
+		}
+		if ids := restricted.BatchGenerateIDs(5, 107); len(ids) != 3 {
+			t.Errorf("expected 3 IDs up to range end, got %d", len(ids))
+		}
+	})
+
+	t.Run("mint errors outside range", func(t *testing.T) {
+		if _, err := restricted.Mint(99); err == nil {
+			t.Error("expected error for position before range")
+		}
+		if _, err := restricted.Mint(110); err == nil {
+			t.Error("expected error for position after range")
+		}
+		if id, err := restricted.Mint(105); err != nil || restricted.IDToPosition(id) != 105 {
+			t.Errorf("unexpected result '%s' (err: %v)", id, err)
+		}
+	})
+
+	t.Run("parsing is unaffected", func(t *testing.T) {
+		if pos := restricted.IDToPosition("do-00"); pos != 0 {
+			t.Errorf("expected position 0, got %d", pos)
+		}
+	})
+
+	t.Run("restriction can only narrow", func(t *testing.T) {
+		nested := restricted.Restrict(Range{Start: 0, End: 1000})
+		if nested.MintRange() != (Range{Start: 100, End: 110}) {
+			t.Errorf("expected range to stay [100, 110), got %+v", nested.MintRange())
+		}
+		disjoint := restricted.Restrict(Range{Start: 200, End: 300})
+		if disjoint.MintRange().Len() != 0 || disjoint.NewID() != "" {
+			t.Error("disjoint restriction should mint nothing")
+		}
+	})
+
+	t.Run("parent is unaffected", func(t *testing.T) {
+		if generator.MintRange() != (Range{Start: 0, End: generator.MaxCombinations()}) {
+			t.Errorf("parent range changed to %+v", generator.MintRange())
+		}
+	})
+}
+
+func TestRestrictConcurrentWithParent(t *testing.T) {
+	generator := New(Config{JustIntonationDigits: 2, EqualTemperamentDigits: 2, Separator: "-"})
+	restricted := generator.Restrict(Range{Start: 100, End: 200})
+
+	// Run under -race: the restricted generator must not share the parent's source
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		<-start
+		for i := 0; i < 10000; i++ {
+			generator.NewID()
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		<-start
+		for i := 0; i < 10000; i++ {
+			id := restricted.NewID()
+			if pos, err := restricted.IDToPositionE(id); err != nil || pos < 100 || pos >= 200 {
+				t.Errorf("expected a position in [100, 200) for %q, got %d (%v)", id, pos, err)
+				return
+			}
+		}
+	}()
+	close(start)
+	wg.Wait()
+}
+
+func TestRestrictWithStores(t *testing.T) {
+	ctx := context.Background()
+	generator := New(Config{
+		JustIntonationDigits:   1,
+		EqualTemperamentDigits: 1,
+		Separator:              "-",
+	})
+	restricted := generator.Restrict(Range{Start: 0, End: 5})
+
+	if _, err := restricted.AllocateIDs(ctx, NewMemoryAllocator(100), 6); err == nil {
+		t.Error("expected error for block outside range")
+	}
+
+	registry := NewMemoryRegistry()
+	for i := 0; i < 5; i++ {
+		if _, err := restricted.NewRegisteredID(ctx, registry); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	for pos := int64(0); pos < 5; pos++ {
+		if found, _ := registry.Contains(ctx, pos); !found {
+			t.Errorf("expected position %d to be registered", pos)
+		}
+	}
+}
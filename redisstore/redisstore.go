@@ -0,0 +1,67 @@
+// Package redisstore implements the doremid MetaStore interface on top of
+// Redis, so the snapshots of a doremid.MetaCache are shared by every instance
+// of a service.
+//
+// It lives in its own module so that the core doremid package stays free of
+// third-party dependencies.
+package redisstore
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/doremi-id/doremid"
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultKeyPrefix is the key prefix used when none is given
+const DefaultKeyPrefix = "doremid/meta/"
+
+// MetaStore is a Redis-backed doremid.MetaStore keeping each snapshot under its
+// key prefix followed by the decimal position. It is safe for concurrent use.
+type MetaStore struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+// NewMetaStore wraps a Redis client, cluster client or ring, keeping snapshots
+// under prefix. An empty prefix uses DefaultKeyPrefix. The caller keeps
+// ownership of client.
+func NewMetaStore(client redis.UniversalClient, prefix string) *MetaStore {
+	if prefix == "" {
+		prefix = DefaultKeyPrefix
+	}
+	return &MetaStore{client: client, prefix: prefix}
+}
+
+// Get returns the snapshot cached for position, or false if there is none
+func (s *MetaStore) Get(ctx context.Context, position int64) ([]byte, bool, error) {
+	value, err := s.client.Get(ctx, s.key(position)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+// Set caches value for position, expiring after ttl; zero never expires
+func (s *MetaStore) Set(ctx context.Context, position int64, value []byte, ttl time.Duration) error {
+	return s.client.Set(ctx, s.key(position), value, ttl).Err()
+}
+
+// Delete removes the snapshot cached for position
+func (s *MetaStore) Delete(ctx context.Context, position int64) error {
+	return s.client.Del(ctx, s.key(position)).Err()
+}
+
+// key returns the Redis key of position
+func (s *MetaStore) key(position int64) string {
+	return s.prefix + strconv.FormatInt(position, 10)
+}
+
+// Compile-time interface checks
+var _ doremid.MetaStore = (*MetaStore)(nil)
@@ -0,0 +1,91 @@
+package redisstore
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/doremi-id/doremid"
+	"github.com/redis/go-redis/v9"
+)
+
+func newTestStore(t *testing.T) (*MetaStore, *miniredis.Miniredis) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return NewMetaStore(client, ""), server
+}
+
+func TestMetaStore(t *testing.T) {
+	ctx := context.Background()
+	store, server := newTestStore(t)
+
+	if _, found, err := store.Get(ctx, 7); found || err != nil {
+		t.Errorf("expected missing snapshot, got found=%v (err: %v)", found, err)
+	}
+	if err := store.Set(ctx, 7, []byte("record"), time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if value, found, err := store.Get(ctx, 7); !found || err != nil || string(value) != "record" {
+		t.Errorf("expected record, got %q (found: %v, err: %v)", value, found, err)
+	}
+	if value, _ := server.Get("doremid/meta/7"); value != "record" {
+		t.Errorf("expected key doremid/meta/7, got %q", value)
+	}
+
+	server.FastForward(time.Minute)
+	if _, found, _ := store.Get(ctx, 7); found {
+		t.Error("expected snapshot to expire")
+	}
+
+	store.Set(ctx, 8, []byte("forever"), 0)
+	server.FastForward(24 * time.Hour)
+	if _, found, _ := store.Get(ctx, 8); !found {
+		t.Error("expected snapshot without TTL to persist")
+	}
+	if err := store.Delete(ctx, 8); err != nil {
+		t.Fatal(err)
+	}
+	if _, found, _ := store.Get(ctx, 8); found {
+		t.Error("expected deleted snapshot")
+	}
+}
+
+func TestMetaStoreUnavailable(t *testing.T) {
+	store, server := newTestStore(t)
+	server.Close()
+
+	if _, _, err := store.Get(context.Background(), 7); err == nil {
+		t.Error("expected error from closed server")
+	}
+}
+
+func TestMetaCacheSharing(t *testing.T) {
+	ctx := context.Background()
+	store, _ := newTestStore(t)
+	generator := doremid.NewWithDefaults()
+
+	// Two instances of a service share snapshots through Redis
+	var loads atomic.Int64
+	config := doremid.MetaCacheConfig{
+		Store: store,
+		TTL:   time.Hour,
+		Load: func(context.Context, int64) ([]byte, error) {
+			loads.Add(1)
+			return []byte("record"), nil
+		},
+	}
+	first, _ := generator.NewMetaCache(config)
+	second, _ := generator.NewMetaCache(config)
+
+	for _, cache := range []*doremid.MetaCache{first, second} {
+		if value, err := cache.Get(ctx, "dodododo-00000"); err != nil || string(value) != "record" {
+			t.Errorf("expected record, got %q (err: %v)", value, err)
+		}
+	}
+	if loads.Load() != 1 {
+		t.Errorf("expected 1 load, got %d", loads.Load())
+	}
+}
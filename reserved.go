@@ -0,0 +1,104 @@
+package doremid
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+)
+
+// reservedSet is a sorted set of positions that generation skips. The nil set
+// reserves nothing.
+type reservedSet []int64
+
+// reserve resolves the reserved IDs and positions of config
+func (g *Generator) reserve(config Config) error {
+	if len(config.ReservedIDs) == 0 && len(config.ReservedPositions) == 0 {
+		return nil
+	}
+	if !g.MaxCombinationsBig().IsInt64() {
+		return &ConfigError{Field: "ReservedIDs", Reason: "requires an ID space that fits an int64"}
+	}
+
+	reserved := make(reservedSet, 0, len(config.ReservedIDs)+len(config.ReservedPositions))
+	for _, id := range config.ReservedIDs {
+		pos, err := g.decode(id)
+		if err != nil {
+			return &ConfigError{Field: "ReservedIDs", Reason: fmt.Sprintf("%q is not a valid ID", id)}
+		}
+		reserved = append(reserved, pos)
+	}
+	for _, pos := range config.ReservedPositions {
+		if pos < 0 || pos >= g.MaxCombinations() {
+			return &ConfigError{Field: "ReservedPositions", Reason: fmt.Sprintf("%d is outside [0, %d)", pos, g.MaxCombinations())}
+		}
+		reserved = append(reserved, pos)
+	}
+	slices.Sort(reserved)
+	g.reserved = slices.Compact(reserved)
+	return nil
+}
+
+// Reserved reports whether position is reserved through Config.ReservedIDs or
+// Config.ReservedPositions
+func (g *Generator) Reserved(position int64) bool {
+	return g.reserved.contains(position)
+}
+
+// EffectiveCombinations returns the number of IDs the generator can still
+// produce: the positions it may mint, less the reserved ones. It equals
+// MaxCombinations for an unrestricted generator without reservations.
+func (g *Generator) EffectiveCombinations() int64 {
+	r := g.mintRange()
+	return r.Len() - g.reserved.count(r)
+}
+
+// contains reports whether position is reserved
+func (s reservedSet) contains(position int64) bool {
+	_, found := slices.BinarySearch(s, position)
+	return found
+}
+
+// count returns the number of reserved positions in r
+func (s reservedSet) count(r Range) int64 {
+	if r.Len() == 0 {
+		return 0
+	}
+	start, _ := slices.BinarySearch(s, r.Start)
+	end, _ := slices.BinarySearch(s, r.End)
+	return int64(end - start)
+}
+
+// nth returns the unreserved position of r with index k, counting from zero.
+// k must be less than the number of unreserved positions in r.
+func (s reservedSet) nth(r Range, k int64) int64 {
+	base, _ := slices.BinarySearch(s, r.Start)
+	rest := s[base:]
+	// The reserved positions at or below the answer p = r.Start+k+m number m,
+	// and are exactly those with rest[j]-j <= r.Start+k
+	m := sort.Search(len(rest), func(j int) bool {
+		return rest[j]-int64(j) > r.Start+k
+	})
+	return r.Start + k + int64(m)
+}
+
+// prev returns the last unreserved position at or before position
+func (s reservedSet) prev(position int64) int64 {
+	i, found := slices.BinarySearch(s, position)
+	for found {
+		position--
+		i--
+		found = i >= 0 && s[i] == position
+	}
+	return position
+}
+
+// next returns the first unreserved position at or after position
+func (s reservedSet) next(position int64) int64 {
+	i, found := slices.BinarySearch(s, position)
+	for found {
+		position++
+		i++
+		found = i < len(s) && s[i] == position
+	}
+	return position
+}
@@ -0,0 +1,182 @@
+package doremid
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestReserved(t *testing.T) {
+	// 1 note and 1 character: 84 positions, 3 of them reserved
+	generator := New(Config{
+		JustIntonationDigits:   1,
+		EqualTemperamentDigits: 1,
+		Separator:              "-",
+		ReservedIDs:            []string{"do-0", "fa-5"},
+		ReservedPositions:      []int64{83, 0},
+	})
+	reserved := []int64{0, 41, 83}
+
+	if n := generator.EffectiveCombinations(); n != 81 {
+		t.Errorf("expected 81 effective combinations, got %d", n)
+	}
+	if n := generator.MaxCombinations(); n != 84 {
+		t.Errorf("expected MaxCombinations to stay 84, got %d", n)
+	}
+	for _, pos := range reserved {
+		if !generator.Reserved(pos) {
+			t.Errorf("expected position %d to be reserved", pos)
+		}
+		if _, err := generator.Mint(pos); !errors.Is(err, ErrReserved) {
+			t.Errorf("expected ErrReserved minting %d, got %v", pos, err)
+		}
+		if id := generator.PositionToID(pos); generator.IDToPosition(id) != pos {
+			t.Errorf("expected reserved ID '%s' to still parse", id)
+		}
+	}
+
+	t.Run("random", func(t *testing.T) {
+		for i := 0; i < 2000; i++ {
+			if pos := generator.IDToPosition(generator.NewID()); slices.Contains(reserved, pos) {
+				t.Fatalf("generated reserved position %d", pos)
+			}
+		}
+		ids := generator.BatchGenerateRandomIDs(81)
+		positions, _ := generator.BatchIDToPositions(ids)
+		slices.Sort(positions)
+		if len(positions) != 81 || slices.ContainsFunc(positions, generator.Reserved) || len(slices.Compact(positions)) != 81 {
+			t.Errorf("expected every unreserved position once, got %v", positions)
+		}
+		if _, err := generator.BatchGenerateRandomIDsCtx(context.Background(), 82); !errors.Is(err, ErrInvalidCount) {
+			t.Errorf("expected ErrInvalidCount beyond the effective capacity, got %v", err)
+		}
+
+		registry := NewMemoryRegistry()
+		for i := 0; i < 81; i++ {
+			if id, err := generator.NewRegisteredID(context.Background(), registry); err != nil || generator.Reserved(generator.IDToPosition(id)) {
+				t.Fatalf("expected an unreserved registered ID, got '%s' (err: %v)", id, err)
+			}
+		}
+		if _, err := generator.NewRegisteredID(context.Background(), registry); !errors.Is(err, ErrSpaceExhausted) {
+			t.Errorf("expected ErrSpaceExhausted, got %v", err)
+		}
+	})
+
+	t.Run("sequential", func(t *testing.T) {
+		expected := []string{"do-1", "do-2", "do-3"}
+		if ids := generator.BatchGenerateIDs(4, 0); !slices.Equal(ids, expected) {
+			t.Errorf("expected %v, got %v", expected, ids)
+		}
+		if ids := slices.Collect(generator.GenerateSeq(0, 4)); !slices.Equal(ids, expected) {
+			t.Errorf("expected %v, got %v", expected, ids)
+		}
+		var buf bytes.Buffer
+		if n, err := generator.WriteBatch(&buf, 4, 0, ' '); err != nil || n != 3 || buf.String() != "do-1 do-2 do-3 " {
+			t.Errorf("expected 3 IDs written, got %d: %q (err: %v)", n, buf.String(), err)
+		}
+		if ids := generator.BatchGenerateIDs(100, 40); len(ids) != 42 || slices.Contains(ids, "fa-5") {
+			t.Errorf("expected 42 IDs without fa-5, got %d", len(ids))
+		}
+		if ids, err := generator.RangeIDs("fa-4", "fa-6"); err != nil || !slices.Equal(ids, []string{"fa-4", "fa-6"}) {
+			t.Errorf("expected fa-4 and fa-6, got %v (err: %v)", ids, err)
+		}
+		if id, err := generator.NextID("fa-4"); err != nil || id != "fa-6" {
+			t.Errorf("expected fa-6 after fa-4, got '%s' (err: %v)", id, err)
+		}
+		if id, err := generator.PrevID("fa-6"); err != nil || id != "fa-4" {
+			t.Errorf("expected fa-4 before fa-6, got '%s' (err: %v)", id, err)
+		}
+		if _, err := generator.PrevID("do-1"); !errors.Is(err, ErrOutOfRange) {
+			t.Errorf("expected ErrOutOfRange before the first unreserved ID, got %v", err)
+		}
+
+		sequential, err := generator.NewSequentialGenerator(context.Background(), SequentialConfig{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var issued []string
+		for {
+			id, err := sequential.Next(context.Background())
+			if errors.Is(err, ErrSpaceExhausted) {
+				break
+			}
+			issued = append(issued, id)
+		}
+		if len(issued) != 81 || slices.Contains(issued, "fa-5") {
+			t.Errorf("expected 81 unreserved IDs, got %d", len(issued))
+		}
+	})
+
+	t.Run("restricted", func(t *testing.T) {
+		restricted := generator.Restrict(Range{Start: 40, End: 43})
+		if n := restricted.EffectiveCombinations(); n != 2 {
+			t.Errorf("expected 2 effective combinations, got %d", n)
+		}
+		for i := 0; i < 100; i++ {
+			if id := restricted.NewID(); id != "fa-4" && id != "fa-6" {
+				t.Fatalf("expected fa-4 or fa-6, got '%s'", id)
+			}
+		}
+		if id := generator.Restrict(Range{Start: 41, End: 42}).NewID(); id != "" {
+			t.Errorf("expected no ID from a fully reserved range, got '%s'", id)
+		}
+	})
+
+	if debug := strings.Join(generator.Debug().Transforms, ","); !strings.Contains(debug, "skip 3 reserved positions") {
+		t.Errorf("expected the reservations in the debug transforms, got %s", debug)
+	}
+}
+
+func TestReservedSet(t *testing.T) {
+	s := reservedSet{2, 3, 4, 7, 10}
+	r := Range{Start: 1, End: 12}
+
+	// Unreserved positions of r: 1 5 6 8 9 11
+	for k, expected := range []int64{1, 5, 6, 8, 9, 11} {
+		if pos := s.nth(r, int64(k)); pos != expected {
+			t.Errorf("expected position %d at index %d, got %d", expected, k, pos)
+		}
+	}
+	if pos := s.nth(Range{Start: 4, End: 12}, 0); pos != 5 {
+		t.Errorf("expected 5, got %d", pos)
+	}
+	if n := s.count(Range{Start: 3, End: 10}); n != 3 {
+		t.Errorf("expected 3 reserved positions, got %d", n)
+	}
+	if pos := s.next(2); pos != 5 {
+		t.Errorf("expected 5, got %d", pos)
+	}
+	if pos := s.prev(4); pos != 1 {
+		t.Errorf("expected 1, got %d", pos)
+	}
+	var empty reservedSet
+	if empty.nth(r, 3) != 4 || empty.next(3) != 3 || empty.prev(3) != 3 || empty.count(r) != 0 {
+		t.Error("expected the empty set to reserve nothing")
+	}
+}
+
+func TestReservedConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+		field  string
+	}{
+		{"invalid ID", Config{JustIntonationDigits: 1, EqualTemperamentDigits: 1, Separator: "-", ReservedIDs: []string{"xx-0"}}, "ReservedIDs"},
+		{"negative position", Config{JustIntonationDigits: 1, EqualTemperamentDigits: 1, ReservedPositions: []int64{-1}}, "ReservedPositions"},
+		{"position beyond space", Config{JustIntonationDigits: 1, EqualTemperamentDigits: 1, ReservedPositions: []int64{84}}, "ReservedPositions"},
+		{"space beyond int64", Config{JustIntonationDigits: 20, EqualTemperamentDigits: 20, ReservedPositions: []int64{1}}, "ReservedIDs"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewE(tt.config)
+			var configErr *ConfigError
+			if !errors.As(err, &configErr) || configErr.Field != tt.field {
+				t.Errorf("expected a %s ConfigError, got %v", tt.field, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package doremid
+
+import "context"
+
+// DefaultCheckpointInterval is the number of IDs a ResumableBatch emits between checkpoints
+const DefaultCheckpointInterval = 100_000
+
+// ResumableBatch generates a large range of sequential IDs in chunks, checkpointing
+// its cursor to a CounterStore after every chunk so that a crashed job can Resume
+// where it left off instead of starting over.
+//
+// Delivery is at-least-once: the chunk being processed when a crash happens is
+// emitted again after Resume. A ResumableBatch is not safe for concurrent use.
+type ResumableBatch struct {
+	// CheckpointInterval is the number of IDs emitted per chunk.
+	// Zero or negative uses DefaultCheckpointInterval.
+	CheckpointInterval int64
+
+	g      *Generator
+	store  CounterStore
+	name   string
+	end    int64
+	cursor int64
+}
+
+// NewResumableBatch creates a batch producing count sequential IDs starting at
+// startPosition, checkpointed in store under name.
+//
+// Returns ErrInvalidCount if count is not positive, or a *RangeError if the range
+// lies outside the positions the generator may mint.
+func (g *Generator) NewResumableBatch(store CounterStore, name string, startPosition, count int64) (*ResumableBatch, error) {
+	if count <= 0 {
+		return nil, ErrInvalidCount
+	}
+	if err := checkRange(startPosition, g.mintRange()); err != nil {
+		return nil, err
+	}
+	if err := checkRange(startPosition+count-1, g.mintRange()); err != nil {
+		return nil, err
+	}
+
+	return &ResumableBatch{
+		g:      g,
+		store:  store,
+		name:   name,
+		end:    startPosition + count,
+		cursor: startPosition,
+	}, nil
+}
+
+// Resume moves the cursor to the last checkpoint, if one has been saved.
+// Returns a *RangeError if the checkpoint does not belong to this batch's range.
+func (b *ResumableBatch) Resume(ctx context.Context) error {
+	var cursor int64
+	var found bool
+	err := b.g.withRetry(ctx, func() (err error) {
+		cursor, found, err = b.store.Load(ctx, b.name)
+		return err
+	})
+	if err != nil || !found {
+		return err
+	}
+	if cursor != b.end {
+		if err := checkRange(cursor, Range{Start: b.cursor, End: b.end}); err != nil {
+			return err
+		}
+	}
+
+	b.cursor = cursor
+	return nil
+}
+
+// Run emits the remaining IDs to fn in chunks of CheckpointInterval, saving the
+// cursor after fn accepts each chunk. It stops at the first error from fn, the
+// store or ctx; the failed chunk is emitted again on the next Run.
+func (b *ResumableBatch) Run(ctx context.Context, fn func(ids []string) error) error {
+	interval := b.CheckpointInterval
+	if interval <= 0 {
+		interval = DefaultCheckpointInterval
+	}
+
+	for b.cursor < b.end {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		count := min(interval, b.end-b.cursor)
+		if err := fn(b.g.BatchGenerateIDs(count, b.cursor)); err != nil {
+			return err
+		}
+		if err := b.g.withRetry(ctx, func() error { return b.store.Save(ctx, b.name, b.cursor+count) }); err != nil {
+			return err
+		}
+		b.cursor += count
+	}
+	return nil
+}
+
+// Cursor returns the position of the next ID to be emitted
+func (b *ResumableBatch) Cursor() int64 {
+	return b.cursor
+}
+
+// Done reports whether every ID in the batch has been emitted and checkpointed
+func (b *ResumableBatch) Done() bool {
+	return b.cursor >= b.end
+}
@@ -0,0 +1,99 @@
+package doremid
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestResumableBatch(t *testing.T) {
+	ctx := context.Background()
+	generator := New(Config{
+		JustIntonationDigits:   1,
+		EqualTemperamentDigits: 2,
+		Separator:              "-",
+	})
+	store := NewMemoryCounterStore()
+	errCrash := errors.New("crash")
+
+	batch, err := generator.NewResumableBatch(store, "nightly", 100, 50)
+	if err != nil {
+		t.Fatal(err)
+	}
+	batch.CheckpointInterval = 8
+
+	// Crash while processing the third chunk
+	var emitted []string
+	chunks := 0
+	err = batch.Run(ctx, func(ids []string) error {
+		chunks++
+		if chunks == 3 {
+			return errCrash
+		}
+		emitted = append(emitted, ids...)
+		return nil
+	})
+	if !errors.Is(err, errCrash) {
+		t.Fatalf("expected crash, got %v", err)
+	}
+
+	// A fresh batch resumes after the last checkpoint
+	resumed, _ := generator.NewResumableBatch(store, "nightly", 100, 50)
+	resumed.CheckpointInterval = 8
+	if err := resumed.Resume(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if resumed.Cursor() != 116 {
+		t.Errorf("expected cursor 116, got %d", resumed.Cursor())
+	}
+	if err := resumed.Run(ctx, func(ids []string) error {
+		emitted = append(emitted, ids...)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if !resumed.Done() {
+		t.Error("expected batch to be done")
+	}
+
+	expected := generator.BatchGenerateIDs(50, 100)
+	if len(emitted) != len(expected) {
+		t.Fatalf("expected %d IDs, got %d", len(expected), len(emitted))
+	}
+	for i := range expected {
+		if emitted[i] != expected[i] {
+			t.Fatalf("expected '%s' at %d, got '%s'", expected[i], i, emitted[i])
+		}
+	}
+
+	// Resuming a finished batch emits nothing
+	finished, _ := generator.NewResumableBatch(store, "nightly", 100, 50)
+	finished.Resume(ctx)
+	finished.Run(ctx, func(ids []string) error {
+		t.Errorf("unexpected chunk %v", ids)
+		return nil
+	})
+}
+
+func TestResumableBatchErrors(t *testing.T) {
+	ctx := context.Background()
+	generator := New(Config{
+		JustIntonationDigits:   1,
+		EqualTemperamentDigits: 1,
+		Separator:              "-",
+	})
+	store := NewMemoryCounterStore()
+
+	if _, err := generator.NewResumableBatch(store, "job", 0, 0); !errors.Is(err, ErrInvalidCount) {
+		t.Errorf("expected ErrInvalidCount, got %v", err)
+	}
+	if _, err := generator.NewResumableBatch(store, "job", 80, 10); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("expected ErrOutOfRange, got %v", err)
+	}
+
+	store.Save(ctx, "job", 5)
+	batch, _ := generator.NewResumableBatch(store, "job", 10, 10)
+	if err := batch.Resume(ctx); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("expected ErrOutOfRange for foreign checkpoint, got %v", err)
+	}
+}
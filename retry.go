@@ -0,0 +1,102 @@
+package doremid
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Defaults used when the corresponding RetryPolicy field is zero
+const (
+	DefaultRetryMaxAttempts    = 5
+	DefaultRetryInitialBackoff = 50 * time.Millisecond
+	DefaultRetryMaxBackoff     = 5 * time.Second
+)
+
+// RetryPolicy configures retries of failed store calls
+type RetryPolicy struct {
+	// MaxAttempts is the number of calls made before giving up, including the
+	// first. Zero uses DefaultRetryMaxAttempts.
+	MaxAttempts int
+
+	// InitialBackoff is the wait before the first retry. It doubles after every
+	// retry up to MaxBackoff, and each wait is jittered to between half and all
+	// of it. Zero uses DefaultRetryInitialBackoff.
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the wait between retries. Zero uses DefaultRetryMaxBackoff.
+	MaxBackoff time.Duration
+
+	// Retryable reports whether err is transient. Nil retries every error except
+	// context errors and the package's own errors such as ErrSpaceExhausted.
+	Retryable func(err error) bool
+}
+
+// WithRetry returns a generator whose store-backed methods retry transient store
+// errors with jittered exponential backoff: NewRegisteredID, AllocateIDs and the
+// hybrid, sequential and resumable generators created from it.
+//
+// The returned generator shares the configuration of g. Retries stop early when
+// the context is done or its deadline would pass during the next wait.
+func (g *Generator) WithRetry(policy RetryPolicy) *Generator {
+	if policy.MaxAttempts <= 0 {
+		policy.MaxAttempts = DefaultRetryMaxAttempts
+	}
+	if policy.InitialBackoff <= 0 {
+		policy.InitialBackoff = DefaultRetryInitialBackoff
+	}
+	if policy.MaxBackoff <= 0 {
+		policy.MaxBackoff = DefaultRetryMaxBackoff
+	}
+	if policy.Retryable == nil {
+		policy.Retryable = transient
+	}
+
+	clone := *g
+	clone.retry = &policy
+	return &clone
+}
+
+// withRetry calls fn, retrying it according to the generator's policy.
+// Giving up on a done context returns the last error joined with the context's.
+func (g *Generator) withRetry(ctx context.Context, fn func() error) error {
+	err := fn()
+	if g.retry == nil {
+		return err
+	}
+
+	backoff := g.retry.InitialBackoff
+	for attempt := 1; err != nil && attempt < g.retry.MaxAttempts && g.retry.Retryable(err); attempt++ {
+		rng := g.acquireRand()
+		wait := backoff/2 + time.Duration(rng.Int63n(int64(backoff/2)+1))
+		g.releaseRand(rng)
+
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+			return errors.Join(err, context.DeadlineExceeded)
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return errors.Join(err, ctx.Err())
+		case <-timer.C:
+		}
+
+		err = fn()
+		backoff = min(backoff*2, g.retry.MaxBackoff)
+	}
+	return err
+}
+
+// transient reports whether err may succeed when retried
+func transient(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	for _, permanent := range []error{ErrInvalidID, ErrOutOfRange, ErrInvalidConfig, ErrInvalidCount, ErrSpaceExhausted, ErrInvalidTransition} {
+		if errors.Is(err, permanent) {
+			return false
+		}
+	}
+	return true
+}
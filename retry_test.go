@@ -0,0 +1,124 @@
+package doremid
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+var errUnavailable = errors.New("store unavailable")
+
+// flakyAllocator fails a number of calls before delegating to a MemoryAllocator
+type flakyAllocator struct {
+	failures int
+	err      error
+	calls    int
+	next     *MemoryAllocator
+}
+
+func (a *flakyAllocator) Allocate(ctx context.Context, count int64) (int64, error) {
+	a.calls++
+	if a.calls <= a.failures {
+		return 0, a.err
+	}
+	return a.next.Allocate(ctx, count)
+}
+
+func TestWithRetry(t *testing.T) {
+	ctx := context.Background()
+	generator := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 3,
+		Separator:              "-",
+	})
+	retrying := generator.WithRetry(RetryPolicy{MaxAttempts: 4, InitialBackoff: time.Millisecond})
+
+	tests := []struct {
+		name     string
+		g        *Generator
+		failures int
+		err      error
+		calls    int
+		expected error
+	}{
+		{"recovers", retrying, 3, errUnavailable, 4, nil},
+		{"gives up", retrying, 4, errUnavailable, 4, errUnavailable},
+		{"permanent error", retrying, 1, ErrSpaceExhausted, 1, ErrSpaceExhausted},
+		{"without retry", generator, 1, errUnavailable, 1, errUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &flakyAllocator{failures: tt.failures, err: tt.err, next: NewMemoryAllocator(100)}
+			ids, err := tt.g.AllocateIDs(ctx, a, 3)
+			if !errors.Is(err, tt.expected) || (err == nil && len(ids) != 3) {
+				t.Errorf("expected %v, got %v (%d IDs)", tt.expected, err, len(ids))
+			}
+			if a.calls != tt.calls {
+				t.Errorf("expected %d calls, got %d", tt.calls, a.calls)
+			}
+		})
+	}
+}
+
+func TestWithRetryContext(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 3,
+		Separator:              "-",
+	}).WithRetry(RetryPolicy{MaxAttempts: 10, InitialBackoff: time.Second})
+
+	// A deadline that would pass during the wait ends retries at once
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	a := &flakyAllocator{failures: 10, err: errUnavailable, next: NewMemoryAllocator(100)}
+	start := time.Now()
+	_, err := generator.AllocateIDs(ctx, a, 1)
+	if !errors.Is(err, errUnavailable) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the store error and DeadlineExceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond || a.calls != 1 {
+		t.Errorf("expected to give up without waiting, took %v and %d calls", elapsed, a.calls)
+	}
+
+	// Cancellation interrupts a wait
+	ctx, cancel = context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+	if _, err := generator.AllocateIDs(ctx, a, 1); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected Canceled, got %v", err)
+	}
+}
+
+func TestWithRetryStores(t *testing.T) {
+	ctx := context.Background()
+	generator := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 3,
+		Separator:              "-",
+	}).WithRetry(RetryPolicy{InitialBackoff: time.Millisecond})
+
+	s, err := generator.NewSequentialGenerator(ctx, SequentialConfig{Store: &flakyState{failures: 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Next(ctx); err != nil {
+		t.Errorf("expected the sequential generator to retry its store, got %v", err)
+	}
+}
+
+// flakyState fails a number of saves before succeeding
+type flakyState struct {
+	failures int
+	calls    int
+}
+
+func (s *flakyState) Load(context.Context) (int64, bool, error) { return 0, false, nil }
+
+func (s *flakyState) Save(context.Context, int64) error {
+	s.calls++
+	if s.calls <= s.failures {
+		return errUnavailable
+	}
+	return nil
+}
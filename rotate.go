@@ -0,0 +1,166 @@
+package doremid
+
+import (
+	"sync"
+	"time"
+)
+
+// Rotation is the schedule on which a RotatingGenerator moves to a new region
+type Rotation int
+
+const (
+	// RotateDaily moves to a new region at midnight
+	RotateDaily Rotation = iota
+
+	// RotateMonthly moves to a new region at midnight on the first of each month
+	RotateMonthly
+)
+
+// RotatingConfig configures a RotatingGenerator
+type RotatingConfig struct {
+	// Epoch is a time within the first rotation period. Its location decides
+	// where calendar days and months begin. It must be set.
+	Epoch time.Time
+
+	// Rotation is the schedule for moving to a new region
+	Rotation Rotation
+
+	// PrefixNotes is the number of leading notes identifying a region, giving
+	// notes^PrefixNotes regions for the number of notes in the alphabet. Zero
+	// uses one note.
+	PrefixNotes int
+
+	// Clock tells the time. Nil uses the system clock.
+	Clock Clock
+}
+
+// RotatingGenerator issues random IDs from a region of the ID space that changes
+// on a schedule. Each period, counted from the epoch, uses the region whose
+// leading notes encode the period number, so every ID records which period
+// issued it and a whole period can be dropped with one key range deletion.
+//
+// Regions are reused once every region has had its turn, so retention must drop
+// a region before it comes around again. Each region holds 1/notes^PrefixNotes
+// of the ID space, so generated IDs collide correspondingly sooner. It is safe
+// for concurrent use.
+type RotatingGenerator struct {
+	g        *Generator
+	epoch    time.Time // Midnight starting period 0
+	rotation Rotation
+	regions  int64
+	size     int64 // Positions per region
+	clock    Clock
+
+	mu      sync.Mutex // Guards current and serializes draws from its random source
+	period  int64      // Period current is restricted to
+	current *Generator // g restricted to the region of period, nil until first use
+}
+
+// Rotating returns a RotatingGenerator issuing IDs with the configuration of g.
+// Any restriction of g still applies within each region.
+// Returns a *ConfigError if Epoch is missing, Rotation is unknown or PrefixNotes
+// is outside [1, JustIntonationDigits].
+func (g *Generator) Rotating(config RotatingConfig) (*RotatingGenerator, error) {
+	prefixNotes := config.PrefixNotes
+	if prefixNotes == 0 {
+		prefixNotes = 1
+	}
+
+	switch {
+	case config.Epoch.IsZero():
+		return nil, &ConfigError{Field: "Epoch", Reason: "must be set"}
+	case config.Rotation != RotateDaily && config.Rotation != RotateMonthly:
+		return nil, &ConfigError{Field: "Rotation", Reason: "must be RotateDaily or RotateMonthly"}
+	case prefixNotes < 1 || prefixNotes > g.JustIntonationDigits:
+		return nil, &ConfigError{Field: "PrefixNotes", Reason: "must be between 1 and JustIntonationDigits"}
+	}
+
+	regions := int64(g.intPow(g.justIntonationLen, prefixNotes))
+	r := &RotatingGenerator{
+		g:        g,
+		rotation: config.Rotation,
+		regions:  regions,
+		size:     g.MaxCombinations() / regions,
+		clock:    clockOrWall(config.Clock),
+	}
+	r.epoch = r.periodStart(config.Epoch)
+	return r, nil
+}
+
+// NewID returns a random ID from the current period's region.
+// Returns a *RangeError if the clock is before the epoch, or ErrSpaceExhausted
+// if a restriction of the generator excludes the whole region.
+func (r *RotatingGenerator) NewID() (string, error) {
+	period := r.Period(r.clock.Now())
+	if period < 0 {
+		return "", &RangeError{Position: period, Min: 0, Max: r.regions}
+	}
+
+	r.mu.Lock()
+	if r.current == nil || r.period != period {
+		r.period, r.current = period, r.g.Restrict(r.PeriodRange(period))
+	}
+	id := r.current.NewID()
+	r.mu.Unlock()
+	if id == "" {
+		return "", ErrSpaceExhausted
+	}
+	return id, nil
+}
+
+// Period returns the number of rotation periods between the epoch and t,
+// negative if t is before the epoch
+func (r *RotatingGenerator) Period(t time.Time) int64 {
+	t = t.In(r.epoch.Location())
+	if r.rotation == RotateMonthly {
+		return int64(t.Year()-r.epoch.Year())*12 + int64(t.Month()-r.epoch.Month())
+	}
+
+	// Count calendar days in UTC so daylight saving changes do not skew the result
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	epochDay := time.Date(r.epoch.Year(), r.epoch.Month(), r.epoch.Day(), 0, 0, 0, 0, time.UTC)
+	days := day.Sub(epochDay) / (24 * time.Hour)
+	return int64(days)
+}
+
+// PeriodStart returns the time period begins
+func (r *RotatingGenerator) PeriodStart(period int64) time.Time {
+	if r.rotation == RotateMonthly {
+		return r.epoch.AddDate(0, int(period), 0)
+	}
+	return r.epoch.AddDate(0, 0, int(period))
+}
+
+// PeriodOf returns the most recent period, up to the current one, that issued id.
+// As regions are reused, IDs older than one full cycle are attributed to a later period.
+// Returns a *FormatError if id is invalid.
+func (r *RotatingGenerator) PeriodOf(id string) (int64, error) {
+	pos, err := r.g.decode(id)
+	if err != nil {
+		return -1, err
+	}
+
+	region := pos / r.size
+	current := r.Period(r.clock.Now())
+	return current - ((current-region)%r.regions+r.regions)%r.regions, nil
+}
+
+// PeriodRange returns the positions of the region used by period, e.g. for
+// deleting all of its IDs with KeyRange once it falls out of retention
+func (r *RotatingGenerator) PeriodRange(period int64) Range {
+	region := (period%r.regions + r.regions) % r.regions
+	return Range{Start: region * r.size, End: (region + 1) * r.size}
+}
+
+// Regions returns the number of periods before a region is reused
+func (r *RotatingGenerator) Regions() int64 {
+	return r.regions
+}
+
+// periodStart returns midnight at the start of the period containing t
+func (r *RotatingGenerator) periodStart(t time.Time) time.Time {
+	if r.rotation == RotateMonthly {
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
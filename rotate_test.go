@@ -0,0 +1,165 @@
+package doremid
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRotatingGeneratorDaily(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 3,
+		Separator:              "-",
+	})
+	epoch := time.Date(2025, 3, 1, 15, 30, 0, 0, time.UTC)
+	r, err := generator.Rotating(RotatingConfig{Epoch: epoch})
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock := epoch
+	r.clock = ClockFunc(func() time.Time { return clock })
+
+	if r.Regions() != 7 {
+		t.Fatalf("expected 7 regions, got %d", r.Regions())
+	}
+
+	tests := []struct {
+		clock  time.Time
+		period int64
+		prefix string
+	}{
+		{time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), 0, "do"},
+		{time.Date(2025, 3, 2, 23, 59, 0, 0, time.UTC), 1, "re"},
+		{time.Date(2025, 3, 7, 12, 0, 0, 0, time.UTC), 6, "ti"},
+		{time.Date(2025, 3, 8, 12, 0, 0, 0, time.UTC), 7, "do"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.clock.Format(time.DateOnly), func(t *testing.T) {
+			clock = tt.clock
+			for i := 0; i < 20; i++ {
+				id, err := r.NewID()
+				if err != nil || id[:2] != tt.prefix {
+					t.Fatalf("expected an ID starting with '%s', got '%s' (err: %v)", tt.prefix, id, err)
+				}
+				if period, _ := r.PeriodOf(id); period != tt.period {
+					t.Fatalf("expected period %d for '%s', got %d", tt.period, id, period)
+				}
+			}
+			if start := r.PeriodStart(tt.period); !start.Equal(time.Date(2025, 3, 1+int(tt.period), 0, 0, 0, 0, time.UTC)) {
+				t.Errorf("unexpected start %v of period %d", start, tt.period)
+			}
+		})
+	}
+
+	// In period 8 the 'do' region was last used in period 7 and 'ti' in period 6
+	clock = time.Date(2025, 3, 9, 0, 0, 0, 0, time.UTC)
+	if period, _ := r.PeriodOf("dore-000"); period != 7 {
+		t.Errorf("expected period 7, got %d", period)
+	}
+	if period, _ := r.PeriodOf("tido-000"); period != 6 {
+		t.Errorf("expected period 6, got %d", period)
+	}
+
+	if got := r.PeriodRange(8); got != (Range{Start: 12096, End: 24192}) {
+		t.Errorf("unexpected range %+v", got)
+	}
+
+	clock = epoch.AddDate(0, 0, -1)
+	if _, err := r.NewID(); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("expected ErrOutOfRange before the epoch, got %v", err)
+	}
+	if _, err := r.PeriodOf("invalid"); !errors.Is(err, ErrInvalidID) {
+		t.Errorf("expected ErrInvalidID, got %v", err)
+	}
+}
+
+func TestRotatingGeneratorMonthly(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   3,
+		EqualTemperamentDigits: 2,
+		Separator:              "-",
+	})
+	tokyo := time.FixedZone("JST", 9*60*60)
+	r, err := generator.Rotating(RotatingConfig{
+		Epoch:       time.Date(2024, 11, 20, 0, 0, 0, 0, tokyo),
+		Rotation:    RotateMonthly,
+		PrefixNotes: 2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 2025-02-01 00:30 in Tokyo is still January in UTC
+	clock := time.Date(2025, 1, 31, 15, 30, 0, 0, time.UTC)
+	r.clock = ClockFunc(func() time.Time { return clock })
+
+	id, _ := r.NewID()
+	if id[:4] != "dofa" {
+		t.Errorf("expected an ID in region 3 ('dofa'), got '%s'", id)
+	}
+	if start := r.PeriodStart(3); !start.Equal(time.Date(2025, 2, 1, 0, 0, 0, 0, tokyo)) {
+		t.Errorf("unexpected period start %v", start)
+	}
+	if r.Regions() != 49 {
+		t.Errorf("expected 49 regions, got %d", r.Regions())
+	}
+}
+
+func TestRotatingGeneratorConcurrent(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 3,
+		Separator:              "-",
+	})
+	epoch := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	r, err := generator.Rotating(RotatingConfig{Epoch: epoch, Clock: ClockFunc(func() time.Time { return epoch.Add(25 * time.Hour) })})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				if id, err := r.NewID(); err != nil || !strings.HasPrefix(id, "re") {
+					t.Errorf("expected an ID of period 1, got '%s' (err: %v)", id, err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestRotatingConfig(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 3,
+		Separator:              "-",
+	})
+	epoch := time.Now()
+
+	tests := []struct {
+		name   string
+		config RotatingConfig
+	}{
+		{"missing epoch", RotatingConfig{}},
+		{"unknown rotation", RotatingConfig{Epoch: epoch, Rotation: 5}},
+		{"too many prefix notes", RotatingConfig{Epoch: epoch, PrefixNotes: 3}},
+		{"negative prefix notes", RotatingConfig{Epoch: epoch, PrefixNotes: -1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := generator.Rotating(tt.config); !errors.Is(err, ErrInvalidConfig) {
+				t.Errorf("expected ErrInvalidConfig, got %v", err)
+			}
+		})
+	}
+}
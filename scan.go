@@ -0,0 +1,155 @@
+package doremid
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Match is a valid ID found by a Scanner
+type Match struct {
+	ID       string // The ID in compact form, see Compact
+	Offset   int64  // Byte offset of the ID in the stream
+	Position int64
+}
+
+// Scanner finds the IDs of a generator in a stream such as a log file, for
+// building audit tooling over existing text.
+//
+// Candidates are found with a pattern built from the generator's configuration
+// and must stand alone, not directly preceded or followed by a letter or digit.
+// Grouped and compact IDs are both found, and with Config.LenientCase IDs in
+// any case.
+// Each candidate is then validated like IDToPositionE, including any check
+// character, and must lie within the positions the generator may mint, so a
+// restricted generator finds only the IDs of its range. Streams are read a line
+// at a time. A Scanner is not safe for concurrent use.
+type Scanner struct {
+	g        *Generator
+	r        *bufio.Reader
+	pattern  *regexp.Regexp
+	offset   int64   // Offset of the next line
+	pending  []Match // Matches of the current line not yet returned
+	match    Match
+	rejected int64
+	err      error
+}
+
+// NewScanner returns a Scanner reading from r
+func (g *Generator) NewScanner(r io.Reader) *Scanner {
+	return &Scanner{g: g, r: bufio.NewReader(r), pattern: regexp.MustCompile(g.pattern())}
+}
+
+// pattern returns a regular expression matching candidate IDs of the generator,
+// with each group separator optional where the grouped form has one and in any
+// case with Config.LenientCase
+func (g *Generator) pattern() string {
+	notes := make([]string, g.justIntonationLen)
+	for i, note := range g.justIntonationBytes {
+		notes[i] = regexp.QuoteMeta(string(note))
+	}
+	note := "(?:" + strings.Join(notes, "|") + ")"
+
+	var characters strings.Builder
+	for _, c := range g.equalTemperamentBytes {
+		fmt.Fprintf(&characters, `\x{%02x}`, c)
+	}
+	character := "[" + characters.String() + "]"
+
+	// Runs of notes or characters between group breaks are counted, e.g.
+	// "(?:do|re){2}(?:-)?(?:do|re){2}" for 4 notes in groups of 2
+	var pattern strings.Builder
+	run := func(digits int, unit string, groupBreak func(int) string) {
+		start := 0
+		for i := 1; i <= digits; i++ {
+			if sep := groupBreak(i); i == digits || sep != "" {
+				fmt.Fprintf(&pattern, "%s{%d}", unit, i-start)
+				if i < digits {
+					fmt.Fprintf(&pattern, "(?:%s)?", regexp.QuoteMeta(sep))
+				}
+				start = i
+			}
+		}
+	}
+	if g.lenientCase {
+		pattern.WriteString("(?i)")
+	}
+	pattern.WriteString(regexp.QuoteMeta(g.prefix))
+	run(g.JustIntonationDigits, note, g.noteGroupBreak)
+	pattern.WriteString(regexp.QuoteMeta(g.Separator))
+	run(g.EqualTemperamentDigits+g.checksumLen(), character, func(i int) string {
+		if i >= g.EqualTemperamentDigits {
+			return ""
+		}
+		return g.characterGroupBreak(i)
+	})
+	return pattern.String()
+}
+
+// Scan advances to the next valid ID, which is then available through Match.
+// It returns false at the end of the stream or on a read error, reported by Err.
+func (s *Scanner) Scan() bool {
+	for len(s.pending) == 0 {
+		if s.err != nil {
+			return false
+		}
+
+		line, err := s.r.ReadString('\n')
+		if err != nil {
+			s.err = err
+		}
+		s.scanLine(line)
+		s.offset += int64(len(line))
+	}
+
+	s.match, s.pending = s.pending[0], s.pending[1:]
+	return true
+}
+
+// scanLine queues the valid IDs of line
+func (s *Scanner) scanLine(line string) {
+	for _, loc := range s.pattern.FindAllStringIndex(line, -1) {
+		start, end := loc[0], loc[1]
+		before, _ := utf8.DecodeLastRuneInString(line[:start])
+		after, _ := utf8.DecodeRuneInString(line[end:])
+		if isWordRune(before) || isWordRune(after) {
+			continue
+		}
+
+		id := s.g.ungroup(s.g.unalias(line[start:end]))
+		pos, err := s.g.decode(id)
+		if err != nil || !s.g.mintRange().Contains(pos) {
+			s.rejected++
+			continue
+		}
+		s.pending = append(s.pending, Match{ID: strings.Clone(id), Offset: s.offset + int64(start), Position: pos})
+	}
+}
+
+// isWordRune reports whether r would extend an adjacent ID
+func isWordRune(r rune) bool {
+	return r != utf8.RuneError && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
+
+// Match returns the ID found by the last call to Scan
+func (s *Scanner) Match() Match {
+	return s.match
+}
+
+// Rejected returns the number of candidates so far that had the shape of an ID but
+// failed validation, e.g. a mistyped check character or a position outside the range
+func (s *Scanner) Rejected() int64 {
+	return s.rejected
+}
+
+// Err returns the first error reading the stream, or nil at its end
+func (s *Scanner) Err() error {
+	if s.err == io.EOF {
+		return nil
+	}
+	return s.err
+}
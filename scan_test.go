@@ -0,0 +1,114 @@
+package doremid
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestScanner(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   Config
+		input    string
+		expected []Match
+		rejected int64
+	}{
+		{
+			"log lines",
+			Config{JustIntonationDigits: 2, EqualTemperamentDigits: 3, Separator: "-"},
+			"INFO created domi-1a2\nWARN retry id=dodo-000, user=\"fati-b0b\"\n",
+			[]Match{{"domi-1a2", 13, 3722}, {"dodo-000", 36, 0}, {"fati-b0b", 52, 48251}},
+			0,
+		},
+		{
+			"embedded in longer tokens",
+			Config{JustIntonationDigits: 2, EqualTemperamentDigits: 3, Separator: "-"},
+			"xdomi-1a2 domi-1a2b dodomi-1a2 ok:domi-1a2",
+			[]Match{{"domi-1a2", 34, 3722}},
+			0,
+		},
+		{
+			"checksum",
+			Config{JustIntonationDigits: 2, EqualTemperamentDigits: 3, Separator: "-", Checksum: true},
+			"domi-1a26 domi-1a27",
+			[]Match{{"domi-1a26", 0, 3722}},
+			1,
+		},
+		{
+			"prefix and custom alphabet",
+			Config{JustIntonationDigits: 2, EqualTemperamentDigits: 2, Notes: "do re mi fa sol la ti", Characters: "a.-", Prefix: "usr"},
+			"usr_solla.- ord_solla.- usr_dodoaa",
+			[]Match{{"usr_solla.-", 0, (4*7+5)*9 + 5}, {"usr_dodoaa", 24, 0}},
+			0,
+		},
+		{
+			"grouped",
+			Config{JustIntonationDigits: 4, EqualTemperamentDigits: 4, Separator: "_", GroupSize: 2, NoteGroupSeparator: "-", CharacterGroupSeparator: "."},
+			"ok dodo-dodo_71.89 dodododo_7189 dod-odo_7189",
+			[]Match{{"dodododo_7189", 3, 12345}, {"dodododo_7189", 19, 12345}},
+			0,
+		},
+		{
+			"lenient case",
+			Config{JustIntonationDigits: 2, EqualTemperamentDigits: 3, Separator: "-", Prefix: "usr", LenientCase: true},
+			"USR_DOMI-1A2 usr_Domi-1a2",
+			[]Match{{"usr_domi-1a2", 0, 3722}, {"usr_domi-1a2", 13, 3722}},
+			0,
+		},
+		{
+			"multibyte text",
+			Config{JustIntonationDigits: 2, EqualTemperamentDigits: 3, Separator: "-"},
+			"音 domi-1a2 é",
+			[]Match{{"domi-1a2", 4, 3722}},
+			0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(tt.config).NewScanner(iotest.OneByteReader(strings.NewReader(tt.input)))
+
+			var matches []Match
+			for s.Scan() {
+				matches = append(matches, s.Match())
+			}
+			if s.Err() != nil {
+				t.Fatal(s.Err())
+			}
+			if len(matches) != len(tt.expected) {
+				t.Fatalf("expected %v, got %v", tt.expected, matches)
+			}
+			for i := range matches {
+				if matches[i] != tt.expected[i] {
+					t.Errorf("expected %v, got %v", tt.expected[i], matches[i])
+				}
+			}
+			if s.Rejected() != tt.rejected {
+				t.Errorf("expected %d rejected, got %d", tt.rejected, s.Rejected())
+			}
+		})
+	}
+}
+
+func TestScannerRestricted(t *testing.T) {
+	generator := New(Config{JustIntonationDigits: 2, EqualTemperamentDigits: 3, Separator: "-"}).Restrict(Range{Start: 0, End: 1000})
+	s := generator.NewScanner(strings.NewReader("dodo-000 domi-1a2"))
+	if !s.Scan() || s.Match().ID != "dodo-000" || s.Scan() {
+		t.Errorf("expected only dodo-000, got %v", s.Match())
+	}
+	if s.Rejected() != 1 {
+		t.Errorf("expected 1 rejected, got %d", s.Rejected())
+	}
+}
+
+func TestScannerError(t *testing.T) {
+	s := NewWithDefaults().NewScanner(iotest.TimeoutReader(strings.NewReader("dodododo-00000\n")))
+	if !s.Scan() || s.Match().ID != "dodododo-00000" {
+		t.Errorf("expected dodododo-00000, got %v", s.Match())
+	}
+	if s.Scan() || !errors.Is(s.Err(), iotest.ErrTimeout) {
+		t.Errorf("expected ErrTimeout, got %v", s.Err())
+	}
+}
@@ -0,0 +1,261 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Codec encodes and decodes IDs for one compiled Schema.
+// It is immutable and safe for concurrent use.
+type Codec struct {
+	schema   Schema
+	segments []segment
+	max      int64
+	checksum bool
+	// Alphabet of the check symbol, shared with the last segment
+	check *segment
+}
+
+// Parsed is the result of decoding an ID
+type Parsed struct {
+	Name     string           // Schema name
+	Version  int              // Schema version
+	Position int64            // Overall position of the ID
+	Segments map[string]int64 // Position of each segment within its own space
+}
+
+// segment is a compiled Segment with its lookup tables
+type segment struct {
+	name      string
+	separator string
+	symbols   []string
+	lookup    map[string]int
+	maxLen    int
+	digits    int
+	span      int64 // Number of distinct values: len(symbols)^digits
+}
+
+// Compile validates the schema and builds its Codec
+func (s *Schema) Compile() (*Codec, error) {
+	if len(s.Segments) == 0 {
+		return nil, errors.New("schema: at least one segment is required")
+	}
+
+	c := &Codec{schema: *s, max: 1}
+	c.schema.Segments = append([]Segment(nil), s.Segments...)
+
+	if s.Tag != "" {
+		if err := validateText(s.Tag); err != nil {
+			return nil, fmt.Errorf("schema: tag %q %w", s.Tag, err)
+		}
+	}
+
+	for _, spec := range s.Segments {
+		symbols, err := spec.Alphabet.resolve()
+		if err == nil {
+			err = validateAlphabet(symbols)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("schema: segment %q: %w", spec.Name, err)
+		}
+		if spec.Separator != "" {
+			if err := validateText(spec.Separator); err != nil {
+				return nil, fmt.Errorf("schema: segment %q: separator %q %w", spec.Name, spec.Separator, err)
+			}
+		}
+		if spec.Digits <= 0 {
+			return nil, fmt.Errorf("schema: segment %q: digits must be positive", spec.Name)
+		}
+
+		seg := segment{
+			name:      spec.Name,
+			separator: spec.Separator,
+			symbols:   symbols,
+			lookup:    make(map[string]int, len(symbols)),
+			digits:    spec.Digits,
+			span:      1,
+		}
+		for i, symbol := range symbols {
+			seg.lookup[symbol] = i
+			seg.maxLen = max(seg.maxLen, len(symbol))
+		}
+		for i := 0; i < spec.Digits; i++ {
+			if seg.span > math.MaxInt64/int64(len(symbols)) {
+				return nil, fmt.Errorf("schema: segment %q: capacity overflows int64", spec.Name)
+			}
+			seg.span *= int64(len(symbols))
+		}
+		if c.max > math.MaxInt64/seg.span {
+			return nil, errors.New("schema: total capacity overflows int64")
+		}
+		c.max *= seg.span
+
+		c.segments = append(c.segments, seg)
+	}
+
+	switch s.Checksum {
+	case "", ChecksumNone:
+	case ChecksumMod:
+		c.checksum = true
+		c.check = &c.segments[len(c.segments)-1]
+	default:
+		return nil, fmt.Errorf("schema: unknown checksum %q", s.Checksum)
+	}
+
+	return c, nil
+}
+
+// Schema returns a copy of the schema the codec was compiled from
+func (c *Codec) Schema() Schema {
+	s := c.schema
+	s.Segments = append([]Segment(nil), c.schema.Segments...)
+	return s
+}
+
+// MaxCombinations returns the number of distinct IDs
+func (c *Codec) MaxCombinations() int64 {
+	return c.max
+}
+
+// Encode converts a position to its ID.
+// Returns an error if the position is out of range.
+func (c *Codec) Encode(position int64) (string, error) {
+	if position < 0 || position >= c.max {
+		return "", fmt.Errorf("schema: position %d out of range [0, %d)", position, c.max)
+	}
+
+	// Split the position into per-segment values, least significant segment last
+	values := make([]int64, len(c.segments))
+	for i := len(c.segments) - 1; i >= 0; i-- {
+		values[i] = position % c.segments[i].span
+		position /= c.segments[i].span
+	}
+
+	var b strings.Builder
+	b.WriteString(c.schema.Tag)
+	weight, sum := 1, 0
+	for i := range c.segments {
+		seg := &c.segments[i]
+		b.WriteString(seg.separator)
+
+		digits := make([]int, seg.digits)
+		for j := seg.digits - 1; j >= 0; j-- {
+			digits[j] = int(values[i] % int64(len(seg.symbols)))
+			values[i] /= int64(len(seg.symbols))
+		}
+		for _, d := range digits {
+			b.WriteString(seg.symbols[d])
+			sum += weight * d
+			weight++
+		}
+	}
+
+	if c.checksum {
+		b.WriteString(c.check.symbols[sum%len(c.check.symbols)])
+	}
+	return b.String(), nil
+}
+
+// Decode converts an ID back to its position
+func (c *Codec) Decode(id string) (int64, error) {
+	parsed, err := c.Parse(id)
+	if err != nil {
+		return -1, err
+	}
+	return parsed.Position, nil
+}
+
+// Parse decodes an ID into its overall position and per-segment positions.
+// Input must be valid UTF-8, and in NFC unless the schema sets UnicodeNormalize.
+func (c *Codec) Parse(id string) (Parsed, error) {
+	if !utf8.ValidString(id) {
+		return Parsed{}, errors.New("schema: input is not valid UTF-8")
+	}
+	if c.schema.UnicodeNormalize {
+		id = norm.NFC.String(id)
+	} else if !norm.NFC.IsNormalString(id) {
+		return Parsed{}, errors.New("schema: input is not NFC normalized")
+	}
+
+	rest, found := strings.CutPrefix(id, c.schema.Tag)
+	if !found {
+		return Parsed{}, fmt.Errorf("schema: missing tag %q", c.schema.Tag)
+	}
+
+	parsed := Parsed{
+		Name:     c.schema.Name,
+		Version:  c.schema.Version,
+		Segments: make(map[string]int64, len(c.segments)),
+	}
+	weight, sum := 1, 0
+	for i := range c.segments {
+		seg := &c.segments[i]
+		if rest, found = strings.CutPrefix(rest, seg.separator); !found {
+			return Parsed{}, fmt.Errorf("schema: segment %q: missing separator %q", seg.name, seg.separator)
+		}
+
+		value := int64(0)
+		for j := 0; j < seg.digits; j++ {
+			d, n := seg.next(rest)
+			if n == 0 {
+				return Parsed{}, fmt.Errorf("schema: segment %q: invalid symbol at offset %d", seg.name, len(id)-len(rest))
+			}
+			rest = rest[n:]
+			value = value*int64(len(seg.symbols)) + int64(d)
+			sum += weight * d
+			weight++
+		}
+
+		parsed.Segments[seg.name] = value
+		parsed.Position = parsed.Position*seg.span + value
+	}
+
+	if c.checksum {
+		d, n := c.check.next(rest)
+		if n == 0 {
+			return Parsed{}, errors.New("schema: missing or invalid check symbol")
+		}
+		if d != sum%len(c.check.symbols) {
+			return Parsed{}, errors.New("schema: checksum mismatch")
+		}
+		rest = rest[n:]
+	}
+
+	if rest != "" {
+		return Parsed{}, fmt.Errorf("schema: unexpected trailing input %q", rest)
+	}
+	return parsed, nil
+}
+
+// next matches the symbol at the start of s, returning its index and length.
+// A length of 0 means no symbol matched. Because alphabets are prefix-free, at
+// most one symbol can match. A match that would split a character, e.g. a base
+// letter followed by a combining mark, is not a match.
+func (seg *segment) next(s string) (int, int) {
+	for n := 1; n <= seg.maxLen && n <= len(s); n++ {
+		d, found := seg.lookup[s[:n]]
+		if !found {
+			continue
+		}
+		if n < len(s) && s[n] >= utf8.RuneSelf && norm.NFC.FirstBoundaryInString(s[n:]) != 0 {
+			return 0, 0
+		}
+		return d, n
+	}
+	return 0, 0
+}
+
+// Migrate re-encodes an ID from one schema version to another by position.
+// Returns an error if the ID is invalid under from or its position does not fit in to.
+func Migrate(id string, from, to *Codec) (string, error) {
+	pos, err := from.Decode(id)
+	if err != nil {
+		return "", err
+	}
+	return to.Encode(pos)
+}
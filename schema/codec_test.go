@@ -0,0 +1,155 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/doremi-id/doremid"
+)
+
+func mustCompile(t *testing.T, s Schema) *Codec {
+	t.Helper()
+	c, err := s.Compile()
+	if err != nil {
+		t.Fatalf("unexpected compile error: %v", err)
+	}
+	return c
+}
+
+func TestCodecMatchesReferenceFormat(t *testing.T) {
+	// The default doremid format expressed as a schema
+	c := mustCompile(t, Schema{
+		Name: "default",
+		Segments: []Segment{
+			{Name: "just", Alphabet: Alphabet{Preset: AlphabetSolfege}, Digits: 4},
+			{Name: "equal", Separator: "-", Alphabet: Alphabet{Preset: AlphabetChromatic}, Digits: 5},
+		},
+	})
+	reference := doremid.NewWithDefaults()
+
+	if c.MaxCombinations() != reference.MaxCombinations() {
+		t.Errorf("expected max combinations %d, got %d", reference.MaxCombinations(), c.MaxCombinations())
+	}
+	for _, pos := range []int64{0, 1, 12345, 9999999, reference.MaxCombinations() - 1} {
+		id, err := c.Encode(pos)
+		if err != nil || id != reference.PositionToID(pos) {
+			t.Errorf("Encode(%d): expected '%s', got '%s' (err: %v)", pos, reference.PositionToID(pos), id, err)
+		}
+		back, err := c.Decode(id)
+		if err != nil || back != pos {
+			t.Errorf("Decode('%s'): expected %d, got %d (err: %v)", id, pos, back, err)
+		}
+	}
+}
+
+func TestCodecTagAndChecksum(t *testing.T) {
+	s, err := LoadFile("testdata/orders.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := mustCompile(t, *s)
+
+	id, err := c.Encode(1000)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if id != "o2dododo-06b44" {
+		t.Errorf("expected 'o2dododo-06b44', got '%s'", id)
+	}
+
+	parsed, err := c.Parse(id)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if parsed.Name != "orders" || parsed.Version != 2 || parsed.Position != 1000 {
+		t.Errorf("unexpected parse result %+v", parsed)
+	}
+	if parsed.Segments["melody"] != 0 || parsed.Segments["serial"] != 1000 {
+		t.Errorf("unexpected segment positions %v", parsed.Segments)
+	}
+
+	for _, invalid := range []string{
+		"dododo-06b44",    // missing tag
+		"o2dododo-06b45",  // checksum mismatch
+		"o2dododo-06b4",   // missing check symbol
+		"o2dododo_06b44",  // wrong separator
+		"o2doxodo-06b44",  // unknown symbol
+		"o2dododo-06b440", // trailing input
+	} {
+		if _, err := c.Decode(invalid); err == nil {
+			t.Errorf("Decode('%s'): expected error", invalid)
+		}
+	}
+}
+
+func TestCodecVariableWidthSymbols(t *testing.T) {
+	c := mustCompile(t, Schema{
+		Segments: []Segment{{Name: "code", Alphabet: Alphabet{Symbols: []string{"sol", "ut", "re", "x"}}, Digits: 3}},
+	})
+
+	for pos := int64(0); pos < c.MaxCombinations(); pos++ {
+		id, err := c.Encode(pos)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if back, err := c.Decode(id); err != nil || back != pos {
+			t.Errorf("round-trip of %d via '%s' returned %d (err: %v)", pos, id, back, err)
+		}
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	v1 := mustCompile(t, Schema{Version: 1, Segments: []Segment{
+		{Name: "just", Alphabet: Alphabet{Preset: AlphabetSolfege}, Digits: 2},
+	}})
+	v2 := mustCompile(t, Schema{Version: 2, Tag: "v2.", Segments: []Segment{
+		{Name: "just", Alphabet: Alphabet{Preset: AlphabetSolfege}, Digits: 3},
+	}})
+
+	migrated, err := Migrate("tiso", v1, v2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if migrated != "v2.dotiso" {
+		t.Errorf("expected 'v2.dotiso', got '%s'", migrated)
+	}
+
+	if _, err := Migrate("v2.tiso", v1, v2); err == nil {
+		t.Error("expected error for invalid source ID")
+	}
+	if _, err := Migrate("v2.tititi", v2, v1); err == nil {
+		t.Error("expected error when position does not fit the target schema")
+	}
+}
+
+func TestCodecUnicodeSymbols(t *testing.T) {
+	segments := []Segment{{Name: "code", Alphabet: Alphabet{Symbols: []string{"\u00e9", "e", "\u00df", "\u00fc"}}, Digits: 2}}
+	strict := mustCompile(t, Schema{Segments: segments})
+	normalizing := mustCompile(t, Schema{Segments: segments, UnicodeNormalize: true})
+
+	// "é" in NFC followed by "e", and the same text with "é" decomposed
+	composed, decomposed := "\u00e9e", "e\u0301e"
+
+	for _, c := range []*Codec{strict, normalizing} {
+		if pos, err := c.Decode(composed); err != nil || pos != 1 {
+			t.Errorf("expected position 1 for composed input, got %d (err: %v)", pos, err)
+		}
+		if id, _ := c.Encode(1); id != composed {
+			t.Errorf("expected '%s', got '%s'", composed, id)
+		}
+	}
+
+	if _, err := strict.Decode(decomposed); err == nil {
+		t.Error("expected decomposed input to be rejected without normalization")
+	}
+	if pos, err := normalizing.Decode(decomposed); err != nil || pos != 1 {
+		t.Errorf("expected position 1 for normalized input, got %d (err: %v)", pos, err)
+	}
+
+	// A base letter must not match when a combining mark follows it
+	if _, err := strict.Decode("e\u0323e"); err == nil {
+		t.Error("expected symbol followed by a combining mark to be rejected")
+	}
+	if _, err := strict.Decode("e\xff"); err == nil {
+		t.Error("expected invalid UTF-8 to be rejected")
+	}
+}
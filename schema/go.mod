@@ -0,0 +1,11 @@
+module github.com/doremi-id/doremid/schema
+
+go 1.24
+
+require github.com/doremi-id/doremid v0.0.0
+
+require gopkg.in/yaml.v3 v3.0.1
+
+require golang.org/x/text v0.25.0
+
+replace github.com/doremi-id/doremid => ../
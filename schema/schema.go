@@ -0,0 +1,203 @@
+// Package schema describes versioned DoReMi ID formats declaratively.
+//
+// A Schema lists the segments of an ID, their alphabets, an optional checksum
+// and a version, and can be loaded from YAML:
+//
+//	name: orders
+//	version: 2
+//	tag: o2
+//	segments:
+//	  - name: melody
+//	    alphabet: solfege
+//	    digits: 3
+//	  - name: serial
+//	    separator: "-"
+//	    alphabet: chromatic
+//	    digits: 4
+//	checksum: mod
+//
+// Compile turns a Schema into an efficient Codec. Evolving the ID format then
+// becomes a data change (a new schema version) plus a migration between codecs,
+// rather than code surgery. The package lives in its own module so the core
+// doremid package stays free of third-party dependencies.
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/doremi-id/doremid"
+
+	"golang.org/x/text/unicode/norm"
+	"gopkg.in/yaml.v3"
+)
+
+// Preset alphabet names usable in Segment.Alphabet
+const (
+	// AlphabetSolfege is the just intonation note set: do re mi fa so la ti
+	AlphabetSolfege = "solfege"
+	// AlphabetChromatic is the twelve-tone equal temperament set: 0-9 a b
+	AlphabetChromatic = "chromatic"
+)
+
+// Checksum algorithms usable in Schema.Checksum
+const (
+	// ChecksumNone disables the check symbol
+	ChecksumNone = "none"
+	// ChecksumMod appends a weighted mod-N check symbol drawn from the last segment's alphabet
+	ChecksumMod = "mod"
+)
+
+// presets maps preset names to their symbols
+var presets = map[string][]string{
+	AlphabetSolfege:   {"do", "re", "mi", "fa", "so", "la", "ti"},
+	AlphabetChromatic: {"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "a", "b"},
+}
+
+// Schema declares an ID format
+type Schema struct {
+	// Name identifies the ID family, e.g. "orders"
+	Name string `yaml:"name" json:"name"`
+
+	// Version distinguishes incompatible revisions of the same family
+	Version int `yaml:"version" json:"version"`
+
+	// Tag is an optional literal prefix emitted before the first segment,
+	// letting parsers tell versions apart
+	Tag string `yaml:"tag,omitempty" json:"tag,omitempty"`
+
+	// Segments are encoded most significant first
+	Segments []Segment `yaml:"segments" json:"segments"`
+
+	// Checksum selects the check symbol algorithm: "" or "none", or "mod"
+	Checksum string `yaml:"checksum,omitempty" json:"checksum,omitempty"`
+
+	// UnicodeNormalize converts parsed input to NFC before matching symbols.
+	// When false, input that is not already in NFC is rejected rather than
+	// silently mismatched, so decomposed and precomposed forms never mix.
+	UnicodeNormalize bool `yaml:"unicode_normalize,omitempty" json:"unicode_normalize,omitempty"`
+}
+
+// Segment declares one part of an ID
+type Segment struct {
+	// Name identifies the segment in errors and parsed output
+	Name string `yaml:"name" json:"name"`
+
+	// Separator is emitted before the segment (after the tag for the first one)
+	Separator string `yaml:"separator,omitempty" json:"separator,omitempty"`
+
+	// Alphabet is a preset name or an explicit, prefix-free list of symbols
+	Alphabet Alphabet `yaml:"alphabet" json:"alphabet"`
+
+	// Digits is the number of symbols in the segment
+	Digits int `yaml:"digits" json:"digits"`
+}
+
+// Alphabet is either a preset name or an explicit list of symbols.
+// In YAML it is written as a scalar preset name or a sequence of symbols.
+type Alphabet struct {
+	Preset  string
+	Symbols []string
+}
+
+// UnmarshalYAML accepts a preset name or a list of symbols
+func (a *Alphabet) UnmarshalYAML(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		a.Preset = node.Value
+		return nil
+	case yaml.SequenceNode:
+		return node.Decode(&a.Symbols)
+	default:
+		return fmt.Errorf("schema: line %d: alphabet must be a preset name or a list of symbols", node.Line)
+	}
+}
+
+// MarshalYAML writes presets as scalars and explicit alphabets as sequences
+func (a Alphabet) MarshalYAML() (any, error) {
+	if a.Preset != "" {
+		return a.Preset, nil
+	}
+	return a.Symbols, nil
+}
+
+// resolve returns the symbols of the alphabet
+func (a Alphabet) resolve() ([]string, error) {
+	if a.Preset != "" {
+		symbols, found := presets[a.Preset]
+		if !found {
+			return nil, fmt.Errorf("unknown alphabet preset %q", a.Preset)
+		}
+		return symbols, nil
+	}
+	return a.Symbols, nil
+}
+
+// Load reads a YAML schema from r
+func Load(r io.Reader) (*Schema, error) {
+	decoder := yaml.NewDecoder(r)
+	decoder.KnownFields(true)
+
+	var s Schema
+	if err := decoder.Decode(&s); err != nil {
+		return nil, fmt.Errorf("schema: %w", err)
+	}
+	return &s, nil
+}
+
+// LoadFile reads a YAML schema from the file at path
+func LoadFile(path string) (*Schema, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Load(f)
+}
+
+// Validate checks the schema for errors without compiling it
+func (s *Schema) Validate() error {
+	_, err := s.Compile()
+	return err
+}
+
+// validateAlphabet checks that symbols are non-empty, unique and prefix-free,
+// so a sequence of symbols can be split back without ambiguity, and that every
+// symbol is a whole sequence of characters in NFC
+func validateAlphabet(symbols []string) error {
+	if len(symbols) < 2 {
+		return errors.New("alphabet needs at least 2 symbols")
+	}
+	for i, a := range symbols {
+		if a == "" {
+			return errors.New("alphabet contains an empty symbol")
+		}
+		if err := validateText(a); err != nil {
+			return fmt.Errorf("symbol %q %w", a, err)
+		}
+		for j, b := range symbols {
+			if i != j && strings.HasPrefix(b, a) {
+				return fmt.Errorf("symbol %q is a prefix of %q", a, b)
+			}
+		}
+	}
+	return nil
+}
+
+// validateText checks that s is valid UTF-8 in NFC that starts with a new
+// character, so it cannot combine with whatever precedes it in an ID
+func validateText(s string) error {
+	if err := doremid.ValidateSymbol(s); err != nil {
+		return err
+	}
+	switch {
+	case !norm.NFC.IsNormalString(s):
+		return errors.New("is not NFC normalized")
+	case norm.NFC.FirstBoundaryInString(s) != 0:
+		return errors.New("starts with a combining character")
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package schema
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadFile(t *testing.T) {
+	s, err := LoadFile("testdata/orders.yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if s.Name != "orders" || s.Version != 2 || s.Tag != "o2" || s.Checksum != ChecksumMod {
+		t.Errorf("unexpected header %+v", s)
+	}
+	if len(s.Segments) != 2 {
+		t.Fatalf("expected 2 segments, got %d", len(s.Segments))
+	}
+	if s.Segments[0].Alphabet.Preset != AlphabetSolfege || s.Segments[1].Separator != "-" {
+		t.Errorf("unexpected segments %+v", s.Segments)
+	}
+}
+
+func TestLoadExplicitAlphabet(t *testing.T) {
+	s, err := Load(strings.NewReader(`
+name: tickets
+version: 1
+segments:
+  - name: code
+    alphabet: [sol, ut, re]
+    digits: 2
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := s.Segments[0].Alphabet.Symbols; len(got) != 3 || got[0] != "sol" {
+		t.Errorf("unexpected alphabet %v", got)
+	}
+
+	if _, err := Load(strings.NewReader("name: x\nunknown: 1\n")); err == nil {
+		t.Error("expected error for unknown field")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema Schema
+	}{
+		{"no segments", Schema{Name: "x"}},
+		{"unknown preset", Schema{Segments: []Segment{{Name: "a", Alphabet: Alphabet{Preset: "klingon"}, Digits: 1}}}},
+		{"zero digits", Schema{Segments: []Segment{{Name: "a", Alphabet: Alphabet{Preset: AlphabetSolfege}}}}},
+		{"ambiguous alphabet", Schema{Segments: []Segment{{Name: "a", Alphabet: Alphabet{Symbols: []string{"so", "sol"}}, Digits: 1}}}},
+		{"single symbol", Schema{Segments: []Segment{{Name: "a", Alphabet: Alphabet{Symbols: []string{"do"}}, Digits: 1}}}},
+		{"overflow", Schema{Segments: []Segment{{Name: "a", Alphabet: Alphabet{Preset: AlphabetChromatic}, Digits: 40}}}},
+		{"unknown checksum", Schema{Checksum: "crc", Segments: []Segment{{Name: "a", Alphabet: Alphabet{Preset: AlphabetSolfege}, Digits: 1}}}},
+		{"decomposed symbol", Schema{Segments: []Segment{{Name: "a", Alphabet: Alphabet{Symbols: []string{"e\u0301", "a"}}, Digits: 1}}}},
+		{"combining symbol", Schema{Segments: []Segment{{Name: "a", Alphabet: Alphabet{Symbols: []string{"\u0301", "a"}}, Digits: 1}}}},
+		{"invalid UTF-8 symbol", Schema{Segments: []Segment{{Name: "a", Alphabet: Alphabet{Symbols: []string{"\xff", "a"}}, Digits: 1}}}},
+		{"decomposed separator", Schema{Segments: []Segment{{Name: "a", Separator: "\u0301", Alphabet: Alphabet{Preset: AlphabetSolfege}, Digits: 1}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.schema.Validate(); err == nil {
+				t.Error("expected validation error")
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package doremid
+
+import "iter"
+
+// GenerateSeq returns an iterator over sequential IDs starting from a specific position.
+// It is the lazy counterpart of BatchGenerateIDs: IDs are produced one at a time as
+// the caller ranges over the sequence, so memory use is constant regardless of count.
+//
+//	for id := range generator.GenerateSeq(0, 10_000_000) {
+//		fmt.Fprintln(w, id)
+//	}
+//
+// The same limits apply as for BatchGenerateIDs: the sequence is empty for a
+// non-positive count or invalid start, stops at the end of the allowed range and
+// leaves out reserved and filtered positions.
+func (g *Generator) GenerateSeq(startPosition, count int64) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		for _, id := range g.GenerateSeq2(startPosition, count) {
+			if !yield(id) {
+				return
+			}
+		}
+	}
+}
+
+// GenerateSeq2 is like GenerateSeq but also yields the position of each ID
+func (g *Generator) GenerateSeq2(startPosition, count int64) iter.Seq2[int64, string] {
+	return func(yield func(int64, string) bool) {
+		mintRange := g.mintRange()
+		if count <= 0 || startPosition < mintRange.Start || startPosition >= mintRange.End {
+			return
+		}
+
+		end := mintRange.End
+		if count < end-startPosition {
+			end = startPosition + count
+		}
+		walker := g.walker()
+		for pos := walker.next(startPosition); pos < end; pos = walker.next(pos + 1) {
+			if !yield(pos, g.PositionToID(pos)) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package doremid
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestGenerateSeq(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   1,
+		EqualTemperamentDigits: 1,
+		Separator:              "-",
+	})
+
+	tests := []struct {
+		name          string
+		generator     *Generator
+		startPosition int64
+		count         int64
+	}{
+		{"from start", generator, 0, 5},
+		{"middle", generator, 40, 10},
+		{"clamped at end", generator, 80, 10},
+		{"zero count", generator, 0, 0},
+		{"negative count", generator, 0, -1},
+		{"negative start", generator, -1, 5},
+		{"start beyond max", generator, 84, 5},
+		{"restricted", generator.Restrict(Range{Start: 10, End: 20}), 15, 10},
+		{"before restriction", generator.Restrict(Range{Start: 10, End: 20}), 5, 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expected := tt.generator.BatchGenerateIDs(tt.count, tt.startPosition)
+			got := slices.Collect(tt.generator.GenerateSeq(tt.startPosition, tt.count))
+			if !slices.Equal(got, expected) && !(len(got) == 0 && len(expected) == 0) {
+				t.Errorf("expected %v, got %v", expected, got)
+			}
+		})
+	}
+}
+
+func TestGenerateSeq2(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 3,
+		Separator:              "-",
+	})
+
+	next := int64(3720)
+	for pos, id := range generator.GenerateSeq2(3720, 1000) {
+		if pos != next {
+			t.Fatalf("expected position %d, got %d", next, pos)
+		}
+		if generator.IDToPosition(id) != pos {
+			t.Fatalf("expected '%s' at position %d", id, pos)
+		}
+		next++
+		if pos == 3722 {
+			break
+		}
+	}
+	if next != 3723 {
+		t.Errorf("expected iteration to stop after break, next is %d", next)
+	}
+}
+
+func TestGenerateSeqHuge(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   6,
+		EqualTemperamentDigits: 6,
+		Separator:              "-",
+	})
+
+	// Taking a prefix of an enormous sequence must not allocate the whole batch
+	allocs := testing.AllocsPerRun(10, func() {
+		n := 0
+		for range generator.GenerateSeq(0, 1<<40) {
+			n++
+			if n == 100 {
+				break
+			}
+		}
+	})
+	if allocs > 1000 {
+		t.Errorf("expected allocations proportional to consumed IDs, got %.0f", allocs)
+	}
+}
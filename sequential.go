@@ -0,0 +1,109 @@
+package doremid
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// SequentialConfig configures a SequentialGenerator
+type SequentialConfig struct {
+	// Store persists the last reserved position so issuance resumes after a
+	// restart. Nil keeps the counter in memory only.
+	Store StateStore
+
+	// ReserveSize is the number of positions reserved per write to Store.
+	// Larger values mean fewer writes; unused reserved positions are skipped
+	// after a restart. Zero or negative reserves one position at a time.
+	ReserveSize int64
+}
+
+// SequentialGenerator issues IDs in position order from an atomic counter,
+// resuming after the last reserved position across restarts.
+//
+// Positions are reserved in Store before IDs are handed out, so no ID is issued
+// twice even after a crash. Within a reservation, Next only increments the
+// counter. It mints within any restriction of its generator and is safe for
+// concurrent use.
+type SequentialGenerator struct {
+	g           *Generator
+	store       StateStore
+	reserveSize int64
+	end         int64
+
+	next  atomic.Int64 // Next position to issue
+	limit atomic.Int64 // First position not covered by the reservation
+	mu    sync.Mutex   // Serializes reservations
+}
+
+// NewSequentialGenerator creates a sequential generator continuing after the
+// position saved in config.Store, or from the start of the generator's range.
+// Returns any error returned by the store.
+func (g *Generator) NewSequentialGenerator(ctx context.Context, config SequentialConfig) (*SequentialGenerator, error) {
+	mintRange := g.mintRange()
+	s := &SequentialGenerator{
+		g:           g,
+		store:       config.Store,
+		reserveSize: max(config.ReserveSize, 1),
+		end:         mintRange.End,
+	}
+
+	start := mintRange.Start
+	if s.store != nil {
+		var last int64
+		var found bool
+		err := g.withRetry(ctx, func() (err error) {
+			last, found, err = s.store.Load(ctx)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			start = max(start, last+1)
+		}
+	} else {
+		// Without a store the whole range is reserved up front
+		s.limit.Store(s.end)
+	}
+	s.next.Store(start)
+	return s, nil
+}
+
+// Next issues the ID at the next position.
+// Returns ErrSpaceExhausted once the range is used up, or any error returned by
+// the store, in which case the position is skipped.
+func (s *SequentialGenerator) Next(ctx context.Context) (string, error) {
+	pos := s.next.Add(1) - 1
+	for pos < s.end && !s.g.allowed(pos) {
+		// Jump past the rest of a filtered note sequence unless another
+		// goroutine has moved on already
+		s.next.CompareAndSwap(pos+1, s.g.nextAllowed(pos))
+		pos = s.next.Add(1) - 1
+	}
+	if pos >= s.end {
+		s.next.Store(s.end) // Keep the counter from overflowing
+		return "", ErrSpaceExhausted
+	}
+	if pos < s.limit.Load() {
+		return s.g.PositionToID(pos), nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Another goroutine may have reserved past pos while this one waited
+	if pos >= s.limit.Load() {
+		limit := min(pos+s.reserveSize, s.end)
+		if err := s.g.withRetry(ctx, func() error { return s.store.Save(ctx, limit-1) }); err != nil {
+			return "", err
+		}
+		s.limit.Store(limit)
+	}
+	return s.g.PositionToID(pos), nil
+}
+
+// Position returns the next position to be issued
+func (s *SequentialGenerator) Position() int64 {
+	return min(s.next.Load(), s.end)
+}
@@ -0,0 +1,120 @@
+package doremid
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestSequentialGenerator(t *testing.T) {
+	ctx := context.Background()
+	generator := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 3,
+		Separator:              "-",
+	})
+	counters := NewMemoryCounterStore()
+	state := CounterState(counters, "orders")
+
+	s, err := generator.NewSequentialGenerator(ctx, SequentialConfig{Store: state, ReserveSize: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := int64(0); i < 3; i++ {
+		id, err := s.Next(ctx)
+		if err != nil || id != generator.PositionToID(i) {
+			t.Fatalf("expected '%s', got '%s' (err: %v)", generator.PositionToID(i), id, err)
+		}
+	}
+	if saved, _, _ := counters.Load(ctx, "orders"); saved != 9 {
+		t.Errorf("expected reservation up to position 9, got %d", saved)
+	}
+
+	// After a restart the unused reservation is skipped, never reissued
+	restarted, err := generator.NewSequentialGenerator(ctx, SequentialConfig{Store: state, ReserveSize: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id, _ := restarted.Next(ctx); id != generator.PositionToID(10) {
+		t.Errorf("expected position 10 after restart, got '%s'", id)
+	}
+	if restarted.Position() != 11 {
+		t.Errorf("expected next position 11, got %d", restarted.Position())
+	}
+}
+
+func TestSequentialGeneratorConcurrent(t *testing.T) {
+	ctx := context.Background()
+	generator := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 3,
+		Separator:              "-",
+	}).Restrict(Range{Start: 100, End: 1100})
+
+	s, _ := generator.NewSequentialGenerator(ctx, SequentialConfig{Store: CounterState(NewMemoryCounterStore(), "c"), ReserveSize: 7})
+
+	var mu sync.Mutex
+	seen := make(map[string]bool)
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				id, err := s.Next(ctx)
+				if errors.Is(err, ErrSpaceExhausted) {
+					return
+				}
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				mu.Lock()
+				if seen[id] {
+					t.Errorf("duplicate ID '%s'", id)
+				}
+				seen[id] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(seen) != 1000 {
+		t.Errorf("expected every position in the range issued once, got %d IDs", len(seen))
+	}
+	if s.Position() != 1100 {
+		t.Errorf("expected position at the end of the range, got %d", s.Position())
+	}
+}
+
+type failingState struct{}
+
+func (failingState) Load(context.Context) (int64, bool, error) { return 0, false, nil }
+func (failingState) Save(context.Context, int64) error         { return errors.New("unavailable") }
+
+func TestSequentialGeneratorErrors(t *testing.T) {
+	ctx := context.Background()
+	generator := New(Config{
+		JustIntonationDigits:   1,
+		EqualTemperamentDigits: 1,
+		Separator:              "-",
+	})
+
+	// Without a store the counter lives in memory
+	s, _ := generator.NewSequentialGenerator(ctx, SequentialConfig{})
+	for i := 0; i < 84; i++ {
+		if _, err := s.Next(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := s.Next(ctx); !errors.Is(err, ErrSpaceExhausted) {
+		t.Errorf("expected ErrSpaceExhausted, got %v", err)
+	}
+
+	failing, _ := generator.NewSequentialGenerator(ctx, SequentialConfig{Store: failingState{}})
+	if id, err := failing.Next(ctx); err == nil {
+		t.Errorf("expected a store error, got '%s'", id)
+	}
+}
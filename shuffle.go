@@ -0,0 +1,34 @@
+package doremid
+
+import "iter"
+
+// ShufflePositions returns every position of rs exactly once, in an order that
+// looks random but is fully determined by key, e.g. to re-issue a pre-printed
+// sequential inventory in randomized order or to run a lottery draw over
+// numbered tickets. The range is never held in memory, so any size can be
+// shuffled, and iteration can stop and later resume at the same index with
+// ShufflePositionsFrom.
+//
+// The order is the keyed Feistel permutation behind ObfuscatedPositionToID
+// applied to offsets within rs, so it cannot be predicted from the positions
+// already drawn without knowing key. Shuffling the whole ID space with
+// Config.PermutationKey as key yields the obfuscated sequence. The sequence is
+// empty if rs is.
+func ShufflePositions(rs Range, key []byte) iter.Seq[int64] {
+	return ShufflePositionsFrom(rs, key, 0)
+}
+
+// ShufflePositionsFrom returns the positions of ShufflePositions from index
+// start on, e.g. to resume a draw after the start positions already taken.
+// Negative start begins at the first index; start past the end yields nothing.
+func ShufflePositionsFrom(rs Range, key []byte, start int64) iter.Seq[int64] {
+	return func(yield func(int64) bool) {
+		n := rs.Len()
+		block := newPermutation(string(key))
+		for i := max(start, 0); i < n; i++ {
+			if !yield(rs.Start + permute(block, i, n, true)) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package doremid
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestShufflePositions(t *testing.T) {
+	tests := []struct {
+		name string
+		rs   Range
+	}{
+		{"empty", Range{Start: 5, End: 5}},
+		{"single", Range{Start: 7, End: 8}},
+		{"two", Range{Start: 0, End: 2}},
+		{"odd size", Range{Start: 1000, End: 1999}},
+		{"power of two", Range{Start: 1 << 20, End: 1<<20 + 4096}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			positions := slices.Collect(ShufflePositions(tt.rs, []byte("draw 2026")))
+			if int64(len(positions)) != tt.rs.Len() {
+				t.Fatalf("expected %d positions, got %d", tt.rs.Len(), len(positions))
+			}
+
+			// A permutation of the range
+			sorted := slices.Sorted(slices.Values(positions))
+			for i, pos := range sorted {
+				if pos != tt.rs.Start+int64(i) {
+					t.Fatalf("expected every position of the range once, got %d at %d", pos, i)
+				}
+			}
+
+			if again := slices.Collect(ShufflePositions(tt.rs, []byte("draw 2026"))); !slices.Equal(positions, again) {
+				t.Error("expected the same order for the same key")
+			}
+		})
+	}
+}
+
+func TestShufflePositionsOrder(t *testing.T) {
+	rs := Range{Start: 0, End: 1000}
+	first := slices.Collect(ShufflePositions(rs, []byte("a")))
+	second := slices.Collect(ShufflePositions(rs, []byte("b")))
+
+	if slices.Equal(first, second) {
+		t.Error("expected different orders for different keys")
+	}
+	fixed := 0
+	for i, pos := range first {
+		if pos == int64(i) {
+			fixed++
+		}
+	}
+	if fixed > 10 {
+		t.Errorf("expected few positions to stay in place, got %d", fixed)
+	}
+
+	// Stopping early yields a prefix of the full order
+	var prefix []int64
+	for pos := range ShufflePositions(rs, []byte("a")) {
+		if len(prefix) == 10 {
+			break
+		}
+		prefix = append(prefix, pos)
+	}
+	if !slices.Equal(prefix, first[:10]) {
+		t.Errorf("expected %v, got %v", first[:10], prefix)
+	}
+}
+
+func TestShufflePositionsFrom(t *testing.T) {
+	rs := Range{Start: 100, End: 1100}
+	key := []byte("draw 2026")
+	full := slices.Collect(ShufflePositions(rs, key))
+
+	tests := []struct {
+		start    int64
+		expected []int64
+	}{
+		{0, full},
+		{-5, full},
+		{1, full[1:]},
+		{400, full[400:]},
+		{999, full[999:]},
+		{1000, nil},
+		{5000, nil},
+	}
+
+	for _, tt := range tests {
+		if resumed := slices.Collect(ShufflePositionsFrom(rs, key, tt.start)); !slices.Equal(resumed, tt.expected) {
+			t.Errorf("expected %d positions from index %d, got %d", len(tt.expected), tt.start, len(resumed))
+		}
+	}
+}
+
+func TestShufflePositionsMatchesObfuscation(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 2,
+		Separator:              "-",
+		PermutationKey:         "secret",
+	})
+
+	i := int64(0)
+	for pos := range ShufflePositions(Range{Start: 0, End: generator.MaxCombinations()}, []byte("secret")) {
+		if id := generator.ObfuscatedPositionToID(i); id != generator.PositionToID(pos) {
+			t.Fatalf("expected '%s' at %d, got '%s'", id, i, generator.PositionToID(pos))
+		}
+		i++
+	}
+}
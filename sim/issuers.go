@@ -0,0 +1,55 @@
+package sim
+
+import (
+	"context"
+	"sync"
+
+	"github.com/doremi-id/doremid"
+)
+
+// Random issues unregistered random IDs from g, modelling services that rely on
+// the size of the ID space alone to avoid collisions
+func Random(g *doremid.Generator) Issuer {
+	var mu sync.Mutex
+	return func(context.Context, int) (string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return g.NewID(), nil
+	}
+}
+
+// Registered issues random IDs deduplicated through r
+func Registered(g *doremid.Generator, r doremid.Registry) Issuer {
+	var mu sync.Mutex
+	return func(ctx context.Context, _ int) (string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return g.NewRegisteredID(ctx, r)
+	}
+}
+
+// Allocated issues sequential IDs, each worker reserving blocks of blockSize
+// positions from a and handing them out one at a time
+func Allocated(g *doremid.Generator, a doremid.Allocator, blockSize int64) Issuer {
+	var mu sync.Mutex
+	blocks := make(map[int][]string)
+
+	return func(ctx context.Context, worker int) (string, error) {
+		mu.Lock()
+		block := blocks[worker]
+		mu.Unlock()
+
+		if len(block) == 0 {
+			ids, err := g.AllocateIDs(ctx, a, blockSize)
+			if err != nil {
+				return "", err
+			}
+			block = ids
+		}
+
+		mu.Lock()
+		blocks[worker] = block[1:]
+		mu.Unlock()
+		return block[0], nil
+	}
+}
@@ -0,0 +1,43 @@
+package sim
+
+import (
+	"context"
+	"testing"
+
+	"github.com/doremi-id/doremid"
+)
+
+func TestIssuers(t *testing.T) {
+	ctx := context.Background()
+	generator := doremid.New(doremid.Config{
+		JustIntonationDigits:   1,
+		EqualTemperamentDigits: 2,
+		Separator:              "-",
+	})
+
+	tests := []struct {
+		name       string
+		issuer     Issuer
+		collisions bool
+	}{
+		{"random", Random(generator), true},
+		{"registered", Registered(generator, doremid.NewMemoryRegistry()), false},
+		{"allocated", Allocated(generator, doremid.NewMemoryAllocator(generator.MaxCombinations()), 16), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 800 IDs out of 1008 make random collisions virtually certain
+			report, err := Run(ctx, Config{Workers: 8, IDsPerWorker: 100, Issuer: tt.issuer})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if report.Issued != 800 {
+				t.Errorf("expected 800 issued IDs, got %d", report.Issued)
+			}
+			if (report.CollisionCount > 0) != tt.collisions {
+				t.Errorf("expected collisions %v, got:\n%s", tt.collisions, report)
+			}
+		})
+	}
+}
@@ -0,0 +1,207 @@
+// Package sim simulates distributed ID issuance so that teams can validate an
+// allocation design before deploying it.
+//
+// A simulation runs a number of concurrent workers, each issuing IDs through an
+// Issuer at a configurable rate, optionally injecting failures, and checks every
+// issued ID for collisions:
+//
+//	report, err := sim.Run(ctx, sim.Config{
+//		Workers:      16,
+//		IDsPerWorker: 10_000,
+//		FailureRate:  0.01,
+//		Issuer:       sim.Registered(generator, doremid.NewMemoryRegistry()),
+//	})
+//	fmt.Println(report)
+package sim
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/doremi-id/doremid"
+)
+
+// ErrInjected is returned to the simulation in place of an issued ID when a
+// failure is injected
+var ErrInjected = errors.New("sim: injected failure")
+
+// maxCollisionSamples is the number of collisions recorded in a Report
+const maxCollisionSamples = 10
+
+// Issuer issues one ID on behalf of worker. It must be safe for concurrent use.
+type Issuer func(ctx context.Context, worker int) (string, error)
+
+// Config describes a simulation
+type Config struct {
+	// Workers is the number of concurrent workers, at least 1
+	Workers int
+
+	// IDsPerWorker is the number of issuance attempts each worker makes, at least 1
+	IDsPerWorker int64
+
+	// Rate limits each worker to this many attempts per second, 0 for no limit
+	Rate float64
+
+	// FailureRate is the probability in [0, 1] that an attempt fails with
+	// ErrInjected instead of reaching the Issuer
+	FailureRate float64
+
+	// Issuer is the allocation strategy under test
+	Issuer Issuer
+
+	// Seed makes failure injection reproducible, 0 uses the current time
+	Seed int64
+}
+
+// Collision records an ID issued more than once
+type Collision struct {
+	ID      string
+	Workers [2]int // The worker that issued the ID first and the one that issued it again
+}
+
+// Report summarises a simulation
+type Report struct {
+	Attempts         int64         // Issuance attempts made by all workers
+	Issued           int64         // IDs successfully issued
+	Unique           int64         // Distinct IDs among those issued
+	InjectedFailures int64         // Attempts failed by failure injection
+	Errors           int64         // Attempts failed by the Issuer
+	Collisions       []Collision   // The first collisions detected, at most 10
+	CollisionCount   int64         // Total number of collisions detected
+	Elapsed          time.Duration // Wall-clock duration of the simulation
+}
+
+// Throughput returns the number of IDs issued per second
+func (r *Report) Throughput() float64 {
+	if r.Elapsed <= 0 {
+		return 0
+	}
+	return float64(r.Issued) / r.Elapsed.Seconds()
+}
+
+// String formats the report for humans
+func (r *Report) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "attempts:          %d\n", r.Attempts)
+	fmt.Fprintf(&b, "issued:            %d (%d unique)\n", r.Issued, r.Unique)
+	fmt.Fprintf(&b, "injected failures: %d\n", r.InjectedFailures)
+	fmt.Fprintf(&b, "issuer errors:     %d\n", r.Errors)
+	fmt.Fprintf(&b, "collisions:        %d\n", r.CollisionCount)
+	for _, c := range r.Collisions {
+		fmt.Fprintf(&b, "  %s issued by workers %d and %d\n", c.ID, c.Workers[0], c.Workers[1])
+	}
+	fmt.Fprintf(&b, "elapsed:           %s (%.0f IDs/s)\n", r.Elapsed, r.Throughput())
+	return b.String()
+}
+
+// Run executes the simulation described by config and returns its report.
+// It stops early if ctx is done, returning the partial report and ctx's error.
+func Run(ctx context.Context, config Config) (*Report, error) {
+	if config.Workers < 1 {
+		return nil, &doremid.ConfigError{Field: "Workers", Reason: "must be at least 1"}
+	}
+	if config.IDsPerWorker < 1 {
+		return nil, &doremid.ConfigError{Field: "IDsPerWorker", Reason: "must be at least 1"}
+	}
+	if !(config.FailureRate >= 0 && config.FailureRate <= 1) {
+		return nil, &doremid.ConfigError{Field: "FailureRate", Reason: "must be between 0 and 1"}
+	}
+	if !(config.Rate >= 0) || math.IsInf(config.Rate, 1) {
+		return nil, &doremid.ConfigError{Field: "Rate", Reason: "must be finite and not negative"}
+	}
+	if config.Issuer == nil {
+		return nil, &doremid.ConfigError{Field: "Issuer", Reason: "must not be nil"}
+	}
+
+	seed := config.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	s := &simulation{config: config, issuedBy: make(map[string]int)}
+	start := time.Now()
+
+	var wg sync.WaitGroup
+	for worker := 0; worker < config.Workers; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.work(ctx, worker, rand.New(rand.NewSource(seed+int64(worker))))
+		}()
+	}
+	wg.Wait()
+
+	s.report.Elapsed = time.Since(start)
+	s.report.Unique = int64(len(s.issuedBy))
+	return &s.report, ctx.Err()
+}
+
+// simulation holds the shared state of a running simulation
+type simulation struct {
+	config Config
+
+	mu       sync.Mutex
+	report   Report
+	issuedBy map[string]int
+}
+
+// work runs the attempts of a single worker
+func (s *simulation) work(ctx context.Context, worker int, rng *rand.Rand) {
+	var ticker *time.Ticker
+	if s.config.Rate > 0 {
+		// Rates beyond one attempt per nanosecond cannot be paced any finer
+		ticker = time.NewTicker(max(time.Duration(float64(time.Second)/s.config.Rate), 1))
+		defer ticker.Stop()
+	}
+
+	for i := int64(0); i < s.config.IDsPerWorker; i++ {
+		if ticker != nil {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		} else if ctx.Err() != nil {
+			return
+		}
+
+		var id string
+		err := ErrInjected
+		if rng.Float64() >= s.config.FailureRate {
+			id, err = s.config.Issuer(ctx, worker)
+		}
+		s.record(worker, id, err)
+	}
+}
+
+// record accounts for the outcome of one attempt
+func (s *simulation) record(worker int, id string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.report.Attempts++
+	switch {
+	case errors.Is(err, ErrInjected):
+		s.report.InjectedFailures++
+		return
+	case err != nil:
+		s.report.Errors++
+		return
+	}
+
+	s.report.Issued++
+	if first, found := s.issuedBy[id]; found {
+		s.report.CollisionCount++
+		if len(s.report.Collisions) < maxCollisionSamples {
+			s.report.Collisions = append(s.report.Collisions, Collision{ID: id, Workers: [2]int{first, worker}})
+		}
+		return
+	}
+	s.issuedBy[id] = worker
+}
@@ -0,0 +1,100 @@
+package sim
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/doremi-id/doremid"
+)
+
+func TestRun(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("detects collisions", func(t *testing.T) {
+		constant := func(context.Context, int) (string, error) { return "do-0", nil }
+
+		report, err := Run(ctx, Config{Workers: 4, IDsPerWorker: 25, Issuer: constant})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if report.Issued != 100 || report.Unique != 1 || report.CollisionCount != 99 {
+			t.Errorf("unexpected report:\n%s", report)
+		}
+		if len(report.Collisions) != maxCollisionSamples {
+			t.Errorf("expected %d collision samples, got %d", maxCollisionSamples, len(report.Collisions))
+		}
+		if !strings.Contains(report.String(), "do-0 issued by workers") {
+			t.Errorf("expected collisions in report:\n%s", report)
+		}
+	})
+
+	t.Run("injects failures", func(t *testing.T) {
+		generator := doremid.New(doremid.Config{JustIntonationDigits: 4, EqualTemperamentDigits: 5, Separator: "-"})
+
+		report, err := Run(ctx, Config{Workers: 4, IDsPerWorker: 500, FailureRate: 0.5, Issuer: Random(generator), Seed: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if report.Attempts != 2000 || report.Issued+report.InjectedFailures != 2000 {
+			t.Errorf("attempts do not add up:\n%s", report)
+		}
+		if report.InjectedFailures < 800 || report.InjectedFailures > 1200 {
+			t.Errorf("expected about 1000 injected failures, got %d", report.InjectedFailures)
+		}
+	})
+
+	t.Run("counts issuer errors", func(t *testing.T) {
+		failing := func(context.Context, int) (string, error) { return "", errors.New("unavailable") }
+
+		report, _ := Run(ctx, Config{Workers: 2, IDsPerWorker: 5, Issuer: failing})
+		if report.Errors != 10 || report.Issued != 0 {
+			t.Errorf("unexpected report:\n%s", report)
+		}
+	})
+
+	t.Run("stops when cancelled", func(t *testing.T) {
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		report, err := Run(cancelled, Config{Workers: 2, IDsPerWorker: 5, Rate: 1, Issuer: Random(doremid.NewWithDefaults())})
+		if !errors.Is(err, context.Canceled) || report.Attempts != 0 {
+			t.Errorf("expected no attempts after cancellation, got %d (err: %v)", report.Attempts, err)
+		}
+	})
+}
+
+func TestRunConfig(t *testing.T) {
+	issuer := Random(doremid.NewWithDefaults())
+
+	tests := []struct {
+		name   string
+		config Config
+	}{
+		{"no workers", Config{IDsPerWorker: 1, Issuer: issuer}},
+		{"no IDs", Config{Workers: 1, Issuer: issuer}},
+		{"failure rate above one", Config{Workers: 1, IDsPerWorker: 1, FailureRate: 2, Issuer: issuer}},
+		{"NaN failure rate", Config{Workers: 1, IDsPerWorker: 1, FailureRate: math.NaN(), Issuer: issuer}},
+		{"negative rate", Config{Workers: 1, IDsPerWorker: 1, Rate: -1, Issuer: issuer}},
+		{"NaN rate", Config{Workers: 1, IDsPerWorker: 1, Rate: math.NaN(), Issuer: issuer}},
+		{"infinite rate", Config{Workers: 1, IDsPerWorker: 1, Rate: math.Inf(1), Issuer: issuer}},
+		{"no issuer", Config{Workers: 1, IDsPerWorker: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Run(context.Background(), tt.config); !errors.Is(err, doremid.ErrInvalidConfig) {
+				t.Errorf("expected ErrInvalidConfig, got %v", err)
+			}
+		})
+	}
+
+	t.Run("rate too high to pace", func(t *testing.T) {
+		report, err := Run(context.Background(), Config{Workers: 1, IDsPerWorker: 10, Rate: 1e12, Issuer: issuer})
+		if err != nil || report.Attempts != 10 {
+			t.Errorf("expected 10 attempts, got %+v and %v", report, err)
+		}
+	})
+}
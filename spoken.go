@@ -0,0 +1,288 @@
+package doremid
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// spokenNotes lists how solfège syllables are said, SpellOut using the first
+// form. Other notes are said as written.
+var spokenNotes = map[string][]string{
+	"do":  {"doh", "doe", "dough"},
+	"re":  {"ray", "rae", "rey"},
+	"mi":  {"mee", "me"},
+	"fa":  {"fah", "far"},
+	"so":  {"soh", "sew", "sow"},
+	"sol": {"sole", "soul"},
+	"la":  {"lah"},
+	"ti":  {"tee", "tea"},
+	"si":  {"see", "sea"},
+	"ut":  {"oot"},
+}
+
+// spokenCharacters lists how ASCII characters are said, SpellOut using the first
+// form: digits as numbers, letters in the NATO phonetic alphabet or by their
+// names, and punctuation by name. Other characters are said as written.
+var spokenCharacters = map[byte][]string{
+	'0': {"zero", "oh", "nought"}, '1': {"one", "won"}, '2': {"two", "to", "too"},
+	'3': {"three", "tree"}, '4': {"four", "for", "fower"}, '5': {"five", "fife"},
+	'6': {"six"}, '7': {"seven"}, '8': {"eight", "ate"}, '9': {"nine", "niner"},
+
+	'a': {"alpha", "alfa", "ay"}, 'b': {"bravo", "bee", "be"}, 'c': {"charlie", "cee", "see", "sea"},
+	'd': {"delta", "dee"}, 'e': {"echo", "ee"}, 'f': {"foxtrot", "ef", "eff"}, 'g': {"golf", "gee"},
+	'h': {"hotel", "aitch"}, 'i': {"india", "eye"}, 'j': {"juliett", "juliet", "jay"},
+	'k': {"kilo", "kay"}, 'l': {"lima", "el", "ell"}, 'm': {"mike", "em"}, 'n': {"november", "en"},
+	'o': {"oscar"}, 'p': {"papa", "pee"}, 'q': {"quebec", "cue", "queue"}, 'r': {"romeo", "ar", "are"},
+	's': {"sierra", "ess"}, 't': {"tango", "tee", "tea"}, 'u': {"uniform", "you"},
+	'v': {"victor", "vee"}, 'w': {"whiskey", "whisky"}, 'x': {"x-ray", "xray", "ex"},
+	'y': {"yankee", "why"}, 'z': {"zulu", "zed", "zee"},
+
+	'-': {"dash", "hyphen", "minus"}, '_': {"underscore"}, '.': {"dot", "point", "period"},
+	':': {"colon"}, '/': {"slash"}, '~': {"tilde"}, '+': {"plus"}, '#': {"hash"},
+	'=': {"equals"}, '*': {"star"}, '|': {"bar", "pipe"},
+}
+
+// spokenWords maps every form of spokenCharacters to its character
+var spokenWords = func() map[string]byte {
+	words := make(map[string]byte)
+	for c, forms := range spokenCharacters {
+		for _, form := range forms {
+			words[form] = c
+		}
+	}
+	return words
+}()
+
+// SpellOut renders id as words that are hard to mishear when read out, e.g. in
+// voice support: notes as English solfège, characters as numbers or in the NATO
+// phonetic alphabet and punctuation by name, so "domi-1a2" becomes
+// "doh mee dash one alpha two". An upper case letter is said as "capital" and
+// the letter where the characters include both cases. ParseSpoken reads the
+// words back.
+// Returns a *FormatError if id is invalid.
+func (g *Generator) SpellOut(id string) (string, error) {
+	compact, err := g.Compact(id)
+	if err != nil {
+		return "", err
+	}
+
+	var words []string
+	words = spellOut(words, g.prefix)
+	offset := len(g.prefix)
+	for i := 0; i < g.JustIntonationDigits; i++ {
+		_, width, _ := g.nextNote(compact, offset)
+		note := compact[offset : offset+width]
+		if forms, found := spokenNotes[strings.ToLower(note)]; found {
+			note = forms[0]
+		}
+		words = append(words, note)
+		offset += width
+	}
+	words = spellOut(words, g.Separator)
+	for _, c := range []byte(compact[offset+len(g.Separator):]) {
+		if _, found := g.equalTemperamentMap[lowerASCII(c)]; found && c >= 'A' && c <= 'Z' {
+			words = append(words, "capital")
+		}
+		words = spellOut(words, string(c))
+	}
+	return strings.Join(words, " "), nil
+}
+
+// spellOut appends the words for each character of s to words
+func spellOut(words []string, s string) []string {
+	for _, r := range s {
+		if r < utf8.RuneSelf && spokenCharacters[lowerASCII(byte(r))] != nil {
+			words = append(words, spokenCharacters[lowerASCII(byte(r))][0])
+		} else {
+			words = append(words, string(r))
+		}
+	}
+	return words
+}
+
+// lowerASCII returns the lower case of an ASCII letter and c itself otherwise
+func lowerASCII(c byte) byte {
+	if c >= 'A' && c <= 'Z' {
+		return c + 'a' - 'A'
+	}
+	return c
+}
+
+// ParseSpoken reads words written down from a SpellOut read-out back into the
+// ID they stand for, in canonical form. It is forgiving of how the words were
+// heard: case and trailing punctuation are ignored, common variants such as
+// "dough" for do, "oh" for zero or "bee" for b are accepted, as are the
+// characters themselves, "double" and "triple" repeat the next word, and the
+// separator may be left out.
+// Returns a *FormatError if spoken is invalid or exceeds MaxParseLength;
+// offsets refer to its bytes.
+func (g *Generator) ParseSpoken(spoken string) (string, error) {
+	// Leave room for every symbol to be said in up to 32 bytes, within
+	// MaxParseLength unless it is disabled
+	characters := g.EqualTemperamentDigits + g.checksumLen()
+	symbols := utf8.RuneCountInString(g.prefix+g.Separator) + g.JustIntonationDigits + characters
+	longest := 32 * symbols
+	if g.MaxParseLength >= 0 {
+		longest = min(longest, g.MaxParseLength)
+	}
+	if len(spoken) > longest {
+		return "", &FormatError{Input: truncate(spoken, longest), Offset: -1, Reason: fmt.Sprintf("spoken ID exceeds %d bytes", longest), Err: ErrInvalidFormat}
+	}
+
+	p := newSpokenParser(spoken)
+	id := []byte(g.prefix)
+	if !p.say(g.prefix) {
+		return "", &FormatError{Input: spoken, Offset: 0, Reason: fmt.Sprintf("missing prefix %q", g.prefix), Err: ErrInvalidFormat}
+	}
+
+	for i := 0; i < g.JustIntonationDigits; i++ {
+		word, offset := p.next()
+		note, found := g.spokenNote(word)
+		if !found {
+			return "", p.errorAt(offset, word, "unknown spoken note")
+		}
+		id = append(id, g.justIntonationBytes[note]...)
+	}
+
+	mark := p.i
+	if !p.say(g.Separator) {
+		p.i = mark
+	}
+	id = append(id, g.Separator...)
+
+	for i := 0; i < characters; i++ {
+		upper := false
+		word, offset := p.next()
+		if word == "capital" {
+			upper = true
+			word, offset = p.next()
+		}
+		c, found := g.spokenCharacter(word, upper)
+		if !found {
+			return "", p.errorAt(offset, word, "unknown spoken character")
+		}
+		id = append(id, c)
+	}
+	if word, offset := p.next(); word != "" {
+		return "", &FormatError{Input: spoken, Offset: offset, Symbol: word, Reason: "unexpected trailing words", Err: ErrInvalidFormat}
+	}
+
+	// Check the ID, e.g. its check character
+	return g.Canonicalize(string(id))
+}
+
+// spokenNote returns the index of the note said by word, a note, an alias or
+// how either is said
+func (g *Generator) spokenNote(word string) (int, bool) {
+	for i, note := range g.justIntonationBytes {
+		if strings.EqualFold(word, string(note)) {
+			return i, true
+		}
+	}
+	for _, alias := range g.aliases {
+		if strings.EqualFold(word, alias.spelling) {
+			return alias.note, true
+		}
+	}
+	for i, note := range g.justIntonationBytes {
+		if slices.Contains(spokenNotes[strings.ToLower(string(note))], word) {
+			return i, true
+		}
+	}
+	for _, alias := range g.aliases {
+		if slices.Contains(spokenNotes[strings.ToLower(alias.spelling)], word) {
+			return alias.note, true
+		}
+	}
+	return 0, false
+}
+
+// spokenCharacter returns the character of the generator said by word, in upper
+// case if upper is set or the characters only include the upper case letter
+func (g *Generator) spokenCharacter(word string, upper bool) (byte, bool) {
+	c, found := spokenWords[word]
+	if !found && len(word) == 1 {
+		c, found = word[0], true
+	}
+	if !found || upper && (c < 'a' || c > 'z') {
+		return 0, false
+	}
+	if _, lower := g.equalTemperamentMap[c]; c >= 'a' && c <= 'z' && (upper || !lower) {
+		c -= 'a' - 'A'
+	}
+	_, known := g.equalTemperamentMap[c]
+	return c, known
+}
+
+// spokenParser reads the words of a spoken ID in order
+type spokenParser struct {
+	input   string
+	words   []string
+	offsets []int
+	i       int
+}
+
+// newSpokenParser splits spoken into lower case words without trailing
+// punctuation, repeating those after "double" and "triple"
+func newSpokenParser(spoken string) *spokenParser {
+	p := &spokenParser{input: spoken}
+	words, offsets := splitWords(spoken, 0)
+	clean := func(word string) string {
+		return strings.TrimRight(strings.ToLower(word), ",.;:!?")
+	}
+	for i := 0; i < len(words); i++ {
+		word, repeat := clean(words[i]), 1
+		if (word == "double" || word == "triple") && i+1 < len(words) {
+			if repeat = 2; word == "triple" {
+				repeat = 3
+			}
+			i++
+			word = clean(words[i])
+		}
+		if word == "" {
+			continue
+		}
+		for range repeat {
+			p.words = append(p.words, word)
+			p.offsets = append(p.offsets, offsets[i])
+		}
+	}
+	return p
+}
+
+// next returns the next word and its byte offset, or "" and the input length at
+// the end
+func (p *spokenParser) next() (string, int) {
+	if p.i >= len(p.words) {
+		return "", len(p.input)
+	}
+	p.i++
+	return p.words[p.i-1], p.offsets[p.i-1]
+}
+
+// say consumes the words of s, said character by character, reporting whether
+// they match
+func (p *spokenParser) say(s string) bool {
+	for _, r := range s {
+		word, _ := p.next()
+		c, found := spokenWords[word]
+		switch {
+		case strings.EqualFold(word, string(r)):
+		case found && r < utf8.RuneSelf && c == lowerASCII(byte(r)):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// errorAt returns a *FormatError for word at offset, or for missing words at
+// the end of the input
+func (p *spokenParser) errorAt(offset int, word, reason string) error {
+	if word == "" {
+		return &FormatError{Input: p.input, Offset: -1, Reason: "too few words", Err: ErrInvalidFormat}
+	}
+	return &FormatError{Input: p.input, Offset: offset, Symbol: word, Reason: reason, Err: ErrBadCharacter}
+}
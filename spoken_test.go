@@ -0,0 +1,125 @@
+package doremid
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSpellOut(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+		id     string
+		spoken string
+	}{
+		{"default", Config{}, "domi-1a2", "doh mee dash one alpha two"},
+		{"all notes", Config{JustIntonationDigits: 7}, "doremifasolati-000", "doh ray mee fah soh lah tee dash zero zero zero"},
+		{"checksum", Config{Checksum: true}, "domi-1a26", "doh mee dash one alpha two six"},
+		{"prefix", Config{Prefix: "usr"}, "usr_domi-1a2", "uniform sierra romeo underscore doh mee dash one alpha two"},
+		{"grouped", Config{GroupSize: 1, NoteGroupSeparator: ".", CharacterGroupSeparator: "."}, "do.mi-1.a.2", "doh mee dash one alpha two"},
+		{"highest", Config{}, "titi-bbb", "tee tee dash bravo bravo bravo"},
+		{"custom notes", Config{Notes: "ut re mi fa sol la si"}, "solsi-1a2", "sole see dash one alpha two"},
+		{"unknown notes", Config{Notes: "ka ki ku"}, "kaku-1a2", "ka ku dash one alpha two"},
+		{"upper case", Config{Characters: "0123456789ABCDEF"}, "domi-1A2", "doh mee dash one alpha two"},
+		{"both cases", Config{Characters: "01aAbB"}, "domi-1Ab", "doh mee dash one capital alpha bravo"},
+		{"upper case notes", Config{Case: UpperCase}, "DOMI-1A2", "doh mee dash one alpha two"},
+		{"title case notes", Config{Case: TitleCase}, "DoMi-1a2", "doh mee dash one alpha two"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.config.JustIntonationDigits == 0 {
+				tt.config.JustIntonationDigits = 2
+			}
+			tt.config.EqualTemperamentDigits = 3
+			if tt.config.Separator == "" {
+				tt.config.Separator = "-"
+			}
+			generator := New(tt.config)
+
+			spoken, err := generator.SpellOut(tt.id)
+			if err != nil || spoken != tt.spoken {
+				t.Errorf("expected '%s', got '%s' (err: %v)", tt.spoken, spoken, err)
+			}
+			id, err := generator.ParseSpoken(tt.spoken)
+			if err != nil || id != tt.id {
+				t.Errorf("expected '%s', got '%s' (err: %v)", tt.id, id, err)
+			}
+		})
+	}
+
+	if _, err := NewWithDefaults().SpellOut("domi_1a2"); !errors.Is(err, ErrInvalidID) {
+		t.Errorf("expected ErrInvalidID, got %v", err)
+	}
+}
+
+func TestParseSpoken(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 3,
+		Separator:              "-",
+		InputAliases:           map[string]string{"sol": "so"},
+	})
+
+	valid := []struct {
+		spoken string
+		id     string
+	}{
+		{"doh mee dash one alpha two", "domi-1a2"},
+		{"Dough, Me - Won, Alfa, Two.", "domi-1a2"},
+		{"do mi one a 2", "domi-1a2"},
+		{"doe me hyphen won ay too", "domi-1a2"},
+		{"doh mee dash double oh bravo", "domi-00b"},
+		{"tee tee triple bee", "titi-bbb"},
+		{"sole lah dash oh oh oh", "sola-000"},
+		{"  doh\tmee\ndash one alpha two  ", "domi-1a2"},
+	}
+	for _, tt := range valid {
+		if id, err := generator.ParseSpoken(tt.spoken); err != nil || id != tt.id {
+			t.Errorf("expected '%s' from '%s', got '%s' (err: %v)", tt.id, tt.spoken, id, err)
+		}
+	}
+
+	tests := []struct {
+		spoken   string
+		offset   int
+		symbol   string
+		expected error
+	}{
+		{"doh moo dash one alpha two", 4, "moo", ErrBadCharacter},
+		{"doh mee dash one charlie two", 17, "charlie", ErrBadCharacter},
+		{"doh mee dash one alpha", -1, "", ErrInvalidFormat},
+		{"doh mee dash one alpha two three", 27, "three", ErrInvalidFormat},
+		{"doh mee dash one capital alpha two", 25, "alpha", ErrBadCharacter},
+		{"doh mee dash one capital one two", 25, "one", ErrBadCharacter},
+		{"doh", -1, "", ErrInvalidFormat},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spoken, func(t *testing.T) {
+			_, err := generator.ParseSpoken(tt.spoken)
+			if !errors.Is(err, tt.expected) {
+				t.Fatalf("expected %v, got %v", tt.expected, err)
+			}
+			var formatErr *FormatError
+			if !errors.As(err, &formatErr) || formatErr.Offset != tt.offset || formatErr.Symbol != tt.symbol {
+				t.Errorf("expected offset %d and symbol %q, got %v", tt.offset, tt.symbol, err)
+			}
+		})
+	}
+
+	checked := New(Config{JustIntonationDigits: 2, EqualTemperamentDigits: 3, Separator: "-", Checksum: true})
+	if _, err := checked.ParseSpoken("doh mee dash one alpha two seven"); !errors.Is(err, ErrChecksum) {
+		t.Errorf("expected ErrChecksum, got %v", err)
+	}
+	prefixed := New(Config{JustIntonationDigits: 2, EqualTemperamentDigits: 3, Separator: "-", Prefix: "usr"})
+	if _, err := prefixed.ParseSpoken("oscar romeo delta underscore doh mee dash one alpha two"); !errors.Is(err, ErrInvalidFormat) {
+		t.Errorf("expected ErrInvalidFormat for a missing prefix, got %v", err)
+	}
+	if id, err := prefixed.ParseSpoken("u s r underscore doh mee one alpha two"); err != nil || id != "usr_domi-1a2" {
+		t.Errorf("expected 'usr_domi-1a2', got '%s' (err: %v)", id, err)
+	}
+	if _, err := generator.ParseSpoken(string(make([]byte, 1000))); !errors.Is(err, ErrInvalidFormat) {
+		t.Errorf("expected ErrInvalidFormat for oversized input, got %v", err)
+	}
+}
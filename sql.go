@@ -0,0 +1,77 @@
+package doremid
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+// PositionScheme is implemented by a Scheme whose IDFor values are stored in
+// databases as compact int64 positions instead of strings:
+//
+//	func (orderIDs) StorePositions() bool { return true }
+type PositionScheme interface {
+	Scheme
+	StorePositions() bool
+}
+
+// Value implements driver.Valuer, storing the canonical string.
+// The zero ID is stored as NULL.
+func (id ID) Value() (driver.Value, error) {
+	if id.IsZero() {
+		return nil, nil
+	}
+	return id.value, nil
+}
+
+// Scan implements sql.Scanner, accepting a string or an int64 position validated
+// against DefaultConfig. NULL scans to the zero ID.
+func (id *ID) Scan(src any) error {
+	return id.scan(defaultGenerator, src)
+}
+
+// Value implements driver.Valuer, storing the int64 position if S implements
+// PositionScheme and the canonical string otherwise. The zero ID is stored as NULL.
+func (id IDFor[S]) Value() (driver.Value, error) {
+	if id.IsZero() {
+		return nil, nil
+	}
+	var scheme S
+	if ps, ok := any(scheme).(PositionScheme); ok && ps.StorePositions() {
+		return id.position, nil
+	}
+	return id.value, nil
+}
+
+// Scan implements sql.Scanner, accepting a string or an int64 position validated
+// against S. NULL scans to the zero ID.
+func (id *IDFor[S]) Scan(src any) error {
+	var scheme S
+	return id.ID.scan(scheme.Generator(), src)
+}
+
+func (id *ID) scan(g *Generator, src any) error {
+	switch v := src.(type) {
+	case nil:
+		*id = ID{}
+		return nil
+	case string:
+		return id.unmarshalText(g, []byte(v))
+	case []byte:
+		return id.unmarshalText(g, v)
+	case int64:
+		parsed, err := g.IDAt(v)
+		if err != nil {
+			return err
+		}
+		*id = parsed
+		return nil
+	default:
+		return fmt.Errorf("doremid: cannot scan %T into an ID", src)
+	}
+}
+
+// Compile-time interface checks
+var (
+	_ driver.Valuer = ID{}
+	_ driver.Valuer = IDFor[PositionScheme]{}
+)
@@ -0,0 +1,86 @@
+package doremid
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+)
+
+type positionIDs struct{ shortIDs }
+
+func (positionIDs) StorePositions() bool { return true }
+
+// Compile-time interface checks
+var (
+	_ sql.Scanner = (*ID)(nil)
+	_ sql.Scanner = (*IDFor[shortIDs])(nil)
+)
+
+func TestIDValueAndScan(t *testing.T) {
+	id, _ := NewWithDefaults().ParseID("domifaso-1a2b3")
+
+	value, err := id.Value()
+	if err != nil || value != "domifaso-1a2b3" {
+		t.Errorf("expected 'domifaso-1a2b3', got %v (err: %v)", value, err)
+	}
+	if value, _ := (ID{}).Value(); value != nil {
+		t.Errorf("expected NULL for the zero ID, got %v", value)
+	}
+
+	tests := []struct {
+		name     string
+		src      any
+		expected ID
+		err      error
+	}{
+		{"string", "domifaso-1a2b3", id, nil},
+		{"bytes", []byte("domifaso-1a2b3"), id, nil},
+		{"position", id.Position(), id, nil},
+		{"null", nil, ID{}, nil},
+		{"invalid string", "domi-1a2", ID{}, ErrInvalidID},
+		{"invalid position", int64(-1), ID{}, ErrOutOfRange},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var scanned ID
+			err := scanned.Scan(tt.src)
+			if tt.err == nil && (err != nil || scanned != tt.expected) {
+				t.Errorf("expected %#v, got %#v (err: %v)", tt.expected, scanned, err)
+			}
+			if tt.err != nil && !errors.Is(err, tt.err) {
+				t.Errorf("expected %v, got %v", tt.err, err)
+			}
+		})
+	}
+
+	var scanned ID
+	if err := scanned.Scan(3.5); err == nil {
+		t.Error("expected error for an unsupported type")
+	}
+}
+
+func TestIDForValueAndScan(t *testing.T) {
+	var asString IDFor[shortIDs]
+	if err := asString.Scan("domi-1a2"); err != nil {
+		t.Fatal(err)
+	}
+	if value, _ := asString.Value(); value != "domi-1a2" {
+		t.Errorf("expected 'domi-1a2', got %v", value)
+	}
+
+	var asPosition IDFor[positionIDs]
+	if err := asPosition.Scan(int64(3722)); err != nil {
+		t.Fatal(err)
+	}
+	if asPosition.String() != "domi-1a2" {
+		t.Errorf("expected 'domi-1a2', got '%s'", asPosition)
+	}
+	if value, _ := asPosition.Value(); value != int64(3722) {
+		t.Errorf("expected position 3722, got %v", value)
+	}
+
+	if err := asPosition.Scan("domifaso-1a2b3"); !errors.Is(err, ErrInvalidID) {
+		t.Errorf("expected ErrInvalidID for another configuration, got %v", err)
+	}
+}
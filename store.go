@@ -0,0 +1,435 @@
+package doremid
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Registry records issued positions so that services can deduplicate issuance
+// across restarts and processes.
+type Registry interface {
+	// Register marks position as issued.
+	// Returns false if the position was already registered.
+	Register(ctx context.Context, position int64) (bool, error)
+
+	// Contains reports whether position has been registered
+	Contains(ctx context.Context, position int64) (bool, error)
+}
+
+// Allocator reserves blocks of sequential positions from a shared counter.
+type Allocator interface {
+	// Allocate reserves count consecutive positions and returns the first one.
+	// Returns ErrSpaceExhausted if fewer than count positions remain.
+	Allocate(ctx context.Context, count int64) (int64, error)
+}
+
+// Tombstoner is implemented by registries that can retire issued positions.
+// A tombstoned position stays registered, so it is never issued again.
+type Tombstoner interface {
+	// Tombstone retires position
+	Tombstone(ctx context.Context, position int64) error
+
+	// Tombstoned reports whether position has been retired
+	Tombstoned(ctx context.Context, position int64) (bool, error)
+}
+
+// AliasStore maps alias positions to target positions, e.g. when a record is merged
+// into another and its old ID must keep resolving.
+type AliasStore interface {
+	// Remap points alias at target, replacing any previous mapping
+	Remap(ctx context.Context, alias, target int64) error
+
+	// Resolve returns the target of alias, or false if alias is not mapped
+	Resolve(ctx context.Context, alias int64) (int64, bool, error)
+
+	// Unmap removes the mapping for alias
+	Unmap(ctx context.Context, alias int64) error
+}
+
+// CounterStore persists named counters, e.g. the cursors of resumable jobs.
+type CounterStore interface {
+	// Load returns the value of counter name, or false if it has never been saved
+	Load(ctx context.Context, name string) (int64, bool, error)
+
+	// Save sets counter name to value
+	Save(ctx context.Context, name string, value int64) error
+}
+
+// StateStore persists the last position a SequentialGenerator may have issued.
+type StateStore interface {
+	// Load returns the last saved position, or false if none has been saved
+	Load(ctx context.Context) (int64, bool, error)
+
+	// Save records position as the last one that may have been issued
+	Save(ctx context.Context, position int64) error
+}
+
+// MetaStore caches metadata snapshots keyed by position, e.g. the serialized
+// record an ID refers to. See MetaCache.
+type MetaStore interface {
+	// Get returns the snapshot cached for position, or false if there is none
+	Get(ctx context.Context, position int64) ([]byte, bool, error)
+
+	// Set caches value for position, expiring after ttl; zero never expires
+	Set(ctx context.Context, position int64, value []byte, ttl time.Duration) error
+
+	// Delete removes the snapshot cached for position
+	Delete(ctx context.Context, position int64) error
+}
+
+// Watchable is implemented by stores that publish change notifications, so
+// services caching ID lookups can invalidate entries precisely.
+type Watchable interface {
+	// OnChange registers fn for every committed change and returns a function removing it
+	OnChange(fn ChangeFunc) (unsubscribe func())
+}
+
+// registeredIDAttempts is the number of random positions tried before
+// NewRegisteredID falls back to scanning for a free position
+const registeredIDAttempts = 32
+
+// NewRegisteredID generates a random ID that has not been issued before according
+// to r, registering it before returning.
+//
+// A few random positions are tried first; if all of them are taken, the remaining
+// positions are scanned in order from a random offset, so a free position is always
+// found while one exists.
+// Returns ErrSpaceExhausted if every position is registered or filtered by
+// Config.WordFilter, or any error returned by the registry.
+func (g *Generator) NewRegisteredID(ctx context.Context, r Registry) (string, error) {
+	mintRange := g.mintRange()
+	size := g.EffectiveCombinations()
+	if size == 0 {
+		return "", ErrSpaceExhausted
+	}
+
+	rng := g.acquireRand()
+	defer g.releaseRand(rng)
+
+	for attempt := 0; attempt < registeredIDAttempts; attempt++ {
+		pos := g.reserved.nth(mintRange, rng.Int63n(size))
+		if g.filtered(pos) {
+			continue
+		}
+		var ok bool
+		err := g.withRetry(ctx, func() (err error) {
+			ok, err = r.Register(ctx, pos)
+			return err
+		})
+		if err != nil {
+			return "", err
+		}
+		if ok {
+			return g.PositionToID(pos), nil
+		}
+	}
+
+	offset := rng.Int63n(size)
+	for i := int64(0); i < size; i++ {
+		pos := g.reserved.nth(mintRange, (offset+i)%size)
+		if g.filtered(pos) {
+			continue
+		}
+		var ok bool
+		err := g.withRetry(ctx, func() (err error) {
+			ok, err = r.Register(ctx, pos)
+			return err
+		})
+		if err != nil {
+			return "", err
+		}
+		if ok {
+			return g.PositionToID(pos), nil
+		}
+	}
+	return "", ErrSpaceExhausted
+}
+
+// AllocateIDs reserves count sequential positions from a and returns their IDs,
+// leaving out positions reserved through Config.ReservedIDs or filtered through
+// Config.WordFilter.
+//
+// Returns ErrSpaceExhausted if the allocator cannot satisfy the request, any
+// error returned by the allocator, or a *RangeError if the allocated block lies
+// outside the positions the generator may mint.
+func (g *Generator) AllocateIDs(ctx context.Context, a Allocator, count int64) ([]string, error) {
+	if count <= 0 {
+		return []string{}, nil
+	}
+
+	var start int64
+	err := g.withRetry(ctx, func() (err error) {
+		start, err = a.Allocate(ctx, count)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := checkRange(start, g.mintRange()); err != nil {
+		return nil, err
+	}
+	if err := checkRange(start+count-1, g.mintRange()); err != nil {
+		return nil, err
+	}
+	return g.BatchGenerateIDs(count, start), nil
+}
+
+// MemoryRegistry is an in-memory Registry and Tombstoner, useful for tests and
+// single-process services. It is safe for concurrent use.
+type MemoryRegistry struct {
+	ChangeNotifier
+
+	mu sync.RWMutex
+	// positions maps each registered position to whether it is tombstoned
+	positions map[int64]bool
+}
+
+// NewMemoryRegistry creates an empty in-memory registry
+func NewMemoryRegistry() *MemoryRegistry {
+	return &MemoryRegistry{positions: make(map[int64]bool)}
+}
+
+// Register marks position as issued
+func (r *MemoryRegistry) Register(_ context.Context, position int64) (bool, error) {
+	r.mu.Lock()
+	if _, found := r.positions[position]; found {
+		r.mu.Unlock()
+		return false, nil
+	}
+	r.positions[position] = false
+	r.mu.Unlock()
+
+	r.Notify(Change{Kind: ChangeRegistered, Position: position, Target: -1})
+	return true, nil
+}
+
+// Tombstone retires position, registering it first if necessary
+func (r *MemoryRegistry) Tombstone(_ context.Context, position int64) error {
+	r.mu.Lock()
+	if r.positions[position] {
+		r.mu.Unlock()
+		return nil
+	}
+	r.positions[position] = true
+	r.mu.Unlock()
+
+	r.Notify(Change{Kind: ChangeTombstoned, Position: position, Target: -1})
+	return nil
+}
+
+// Tombstoned reports whether position has been retired
+func (r *MemoryRegistry) Tombstoned(_ context.Context, position int64) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.positions[position], nil
+}
+
+// Contains reports whether position has been registered
+func (r *MemoryRegistry) Contains(_ context.Context, position int64) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	_, found := r.positions[position]
+	return found, nil
+}
+
+// MemoryAllocator is an in-memory Allocator handing out positions in [0, limit).
+// It is safe for concurrent use.
+type MemoryAllocator struct {
+	mu    sync.Mutex
+	next  int64
+	limit int64
+}
+
+// NewMemoryAllocator creates an allocator for positions in [0, limit),
+// typically the generator's MaxCombinations
+func NewMemoryAllocator(limit int64) *MemoryAllocator {
+	return &MemoryAllocator{limit: limit}
+}
+
+// Allocate reserves count consecutive positions and returns the first one
+func (a *MemoryAllocator) Allocate(_ context.Context, count int64) (int64, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if count <= 0 {
+		return -1, ErrInvalidCount
+	}
+	if count > a.limit-a.next {
+		return -1, ErrSpaceExhausted
+	}
+	start := a.next
+	a.next += count
+	return start, nil
+}
+
+// MemoryAliasStore is an in-memory AliasStore. It is safe for concurrent use.
+type MemoryAliasStore struct {
+	ChangeNotifier
+
+	mu      sync.RWMutex
+	targets map[int64]int64
+}
+
+// NewMemoryAliasStore creates an empty in-memory alias store
+func NewMemoryAliasStore() *MemoryAliasStore {
+	return &MemoryAliasStore{targets: make(map[int64]int64)}
+}
+
+// Remap points alias at target, replacing any previous mapping
+func (s *MemoryAliasStore) Remap(_ context.Context, alias, target int64) error {
+	s.mu.Lock()
+	if current, found := s.targets[alias]; found && current == target {
+		s.mu.Unlock()
+		return nil
+	}
+	s.targets[alias] = target
+	s.mu.Unlock()
+
+	s.Notify(Change{Kind: ChangeRemapped, Position: alias, Target: target})
+	return nil
+}
+
+// Resolve returns the target of alias, or false if alias is not mapped
+func (s *MemoryAliasStore) Resolve(_ context.Context, alias int64) (int64, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	target, found := s.targets[alias]
+	if !found {
+		return -1, false, nil
+	}
+	return target, true, nil
+}
+
+// Unmap removes the mapping for alias
+func (s *MemoryAliasStore) Unmap(_ context.Context, alias int64) error {
+	s.mu.Lock()
+	if _, found := s.targets[alias]; !found {
+		s.mu.Unlock()
+		return nil
+	}
+	delete(s.targets, alias)
+	s.mu.Unlock()
+
+	s.Notify(Change{Kind: ChangeUnmapped, Position: alias, Target: -1})
+	return nil
+}
+
+// MemoryCounterStore is an in-memory CounterStore. It is safe for concurrent use.
+type MemoryCounterStore struct {
+	mu     sync.RWMutex
+	values map[string]int64
+}
+
+// NewMemoryCounterStore creates an empty in-memory counter store
+func NewMemoryCounterStore() *MemoryCounterStore {
+	return &MemoryCounterStore{values: make(map[string]int64)}
+}
+
+// Load returns the value of counter name, or false if it has never been saved
+func (s *MemoryCounterStore) Load(_ context.Context, name string) (int64, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	value, found := s.values[name]
+	return value, found, nil
+}
+
+// Save sets counter name to value
+func (s *MemoryCounterStore) Save(_ context.Context, name string, value int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.values[name] = value
+	return nil
+}
+
+// MemoryMetaStore is an in-memory MetaStore. Expired snapshots are dropped when
+// read. It is safe for concurrent use.
+type MemoryMetaStore struct {
+	mu      sync.Mutex
+	entries map[int64]metaEntry
+	clock   Clock
+}
+
+type metaEntry struct {
+	value   []byte
+	expires time.Time // Zero if the entry never expires
+}
+
+// NewMemoryMetaStore creates an empty in-memory meta store
+func NewMemoryMetaStore() *MemoryMetaStore {
+	return &MemoryMetaStore{entries: make(map[int64]metaEntry), clock: WallClock{}}
+}
+
+// Get returns the snapshot cached for position, or false if there is none
+func (s *MemoryMetaStore) Get(_ context.Context, position int64) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, found := s.entries[position]
+	if !found {
+		return nil, false, nil
+	}
+	if !entry.expires.IsZero() && !s.clock.Now().Before(entry.expires) {
+		delete(s.entries, position)
+		return nil, false, nil
+	}
+	return entry.value, true, nil
+}
+
+// Set caches value for position, expiring after ttl; zero never expires
+func (s *MemoryMetaStore) Set(_ context.Context, position int64, value []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := metaEntry{value: value}
+	if ttl > 0 {
+		entry.expires = s.clock.Now().Add(ttl)
+	}
+	s.entries[position] = entry
+	return nil
+}
+
+// Delete removes the snapshot cached for position
+func (s *MemoryMetaStore) Delete(_ context.Context, position int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, position)
+	return nil
+}
+
+// CounterState returns a StateStore keeping its position in counter name of store
+func CounterState(store CounterStore, name string) StateStore {
+	return counterState{store: store, name: name}
+}
+
+type counterState struct {
+	store CounterStore
+	name  string
+}
+
+func (s counterState) Load(ctx context.Context) (int64, bool, error) {
+	return s.store.Load(ctx, s.name)
+}
+
+func (s counterState) Save(ctx context.Context, position int64) error {
+	return s.store.Save(ctx, s.name, position)
+}
+
+// Compile-time interface checks
+var (
+	_ Registry   = (*MemoryRegistry)(nil)
+	_ Tombstoner = (*MemoryRegistry)(nil)
+	_ Watchable  = (*MemoryRegistry)(nil)
+	_ Allocator  = (*MemoryAllocator)(nil)
+	_ AliasStore = (*MemoryAliasStore)(nil)
+	_ Watchable  = (*MemoryAliasStore)(nil)
+
+	_ CounterStore = (*MemoryCounterStore)(nil)
+	_ StateStore   = counterState{}
+	_ MetaStore    = (*MemoryMetaStore)(nil)
+)
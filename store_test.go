@@ -0,0 +1,245 @@
+package doremid
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMemoryRegistry(t *testing.T) {
+	ctx := context.Background()
+	registry := NewMemoryRegistry()
+
+	if ok, err := registry.Register(ctx, 42); !ok || err != nil {
+		t.Errorf("first registration should succeed, got %v (err: %v)", ok, err)
+	}
+	if ok, _ := registry.Register(ctx, 42); ok {
+		t.Error("second registration of the same position should fail")
+	}
+	if found, _ := registry.Contains(ctx, 42); !found {
+		t.Error("registered position should be contained")
+	}
+	if found, _ := registry.Contains(ctx, 43); found {
+		t.Error("unregistered position should not be contained")
+	}
+}
+
+func TestNewRegisteredID(t *testing.T) {
+	ctx := context.Background()
+	generator := New(Config{
+		JustIntonationDigits:   1,
+		EqualTemperamentDigits: 1,
+		Separator:              "-",
+	})
+	registry := NewMemoryRegistry()
+
+	seen := make(map[string]bool)
+	for i := int64(0); i < 20; i++ {
+		id, err := generator.NewRegisteredID(ctx, registry)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if seen[id] {
+			t.Errorf("duplicate ID '%s'", id)
+		}
+		seen[id] = true
+	}
+}
+
+func TestMemoryAllocator(t *testing.T) {
+	ctx := context.Background()
+	allocator := NewMemoryAllocator(10)
+
+	if _, err := allocator.Allocate(ctx, 0); err == nil {
+		t.Error("expected error for zero count")
+	}
+
+	var wg sync.WaitGroup
+	starts := make(chan int64, 5)
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			start, err := allocator.Allocate(ctx, 2)
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			starts <- start
+		}()
+	}
+	wg.Wait()
+	close(starts)
+
+	seen := make(map[int64]bool)
+	for start := range starts {
+		if seen[start] || start%2 != 0 {
+			t.Errorf("unexpected block start %d", start)
+		}
+		seen[start] = true
+	}
+
+	if _, err := allocator.Allocate(ctx, 1); !errors.Is(err, ErrSpaceExhausted) {
+		t.Errorf("expected ErrSpaceExhausted, got %v", err)
+	}
+}
+
+func TestAllocateIDs(t *testing.T) {
+	ctx := context.Background()
+	generator := New(Config{
+		JustIntonationDigits:   1,
+		EqualTemperamentDigits: 1,
+		Separator:              "-",
+	})
+	allocator := NewMemoryAllocator(generator.MaxCombinations())
+
+	first, err := generator.AllocateIDs(ctx, allocator, 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, err := generator.AllocateIDs(ctx, allocator, 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if first[0] != "do-0" || second[0] != "do-3" {
+		t.Errorf("expected blocks to start at do-0 and do-3, got '%s' and '%s'", first[0], second[0])
+	}
+
+	if _, err := generator.AllocateIDs(ctx, allocator, 100); !errors.Is(err, ErrSpaceExhausted) {
+		t.Errorf("expected ErrSpaceExhausted, got %v", err)
+	}
+}
+
+func TestMemoryRegistryTombstone(t *testing.T) {
+	ctx := context.Background()
+	registry := NewMemoryRegistry()
+
+	var changes []Change
+	registry.OnChange(func(c Change) { changes = append(changes, c) })
+
+	registry.Register(ctx, 1)
+	registry.Tombstone(ctx, 1)
+	registry.Tombstone(ctx, 1)
+	registry.Tombstone(ctx, 2)
+
+	if tombstoned, _ := registry.Tombstoned(ctx, 1); !tombstoned {
+		t.Error("position 1 should be tombstoned")
+	}
+	if ok, _ := registry.Register(ctx, 2); ok {
+		t.Error("tombstoned position must not be registered again")
+	}
+
+	expected := []Change{
+		{Kind: ChangeRegistered, Position: 1, Target: -1},
+		{Kind: ChangeTombstoned, Position: 1, Target: -1},
+		{Kind: ChangeTombstoned, Position: 2, Target: -1},
+	}
+	if len(changes) != len(expected) {
+		t.Fatalf("expected %d changes, got %v", len(expected), changes)
+	}
+	for i := range expected {
+		if changes[i] != expected[i] {
+			t.Errorf("change[%d]: expected %+v, got %+v", i, expected[i], changes[i])
+		}
+	}
+}
+
+func TestMemoryAliasStore(t *testing.T) {
+	ctx := context.Background()
+	aliases := NewMemoryAliasStore()
+
+	var changes []Change
+	unsubscribe := aliases.OnChange(func(c Change) { changes = append(changes, c) })
+
+	aliases.Remap(ctx, 10, 20)
+	aliases.Remap(ctx, 10, 20)
+	aliases.Remap(ctx, 10, 30)
+
+	if target, found, _ := aliases.Resolve(ctx, 10); !found || target != 30 {
+		t.Errorf("expected alias 10 to resolve to 30, got %d (found: %v)", target, found)
+	}
+
+	aliases.Unmap(ctx, 10)
+	if _, found, _ := aliases.Resolve(ctx, 10); found {
+		t.Error("unmapped alias should not resolve")
+	}
+
+	unsubscribe()
+	aliases.Remap(ctx, 11, 12)
+
+	expected := []ChangeKind{ChangeRemapped, ChangeRemapped, ChangeUnmapped}
+	if len(changes) != len(expected) {
+		t.Fatalf("expected %d changes, got %v", len(expected), changes)
+	}
+	for i, kind := range expected {
+		if changes[i].Kind != kind {
+			t.Errorf("change[%d]: expected %s, got %s", i, kind, changes[i].Kind)
+		}
+	}
+	if changes[1].Target != 30 {
+		t.Errorf("expected remap target 30, got %d", changes[1].Target)
+	}
+}
+
+func TestNewRegisteredIDExhaustion(t *testing.T) {
+	ctx := context.Background()
+	generator := New(Config{
+		JustIntonationDigits:   1,
+		EqualTemperamentDigits: 1,
+		Separator:              "-",
+	})
+	registry := NewMemoryRegistry()
+
+	for i := int64(0); i < generator.MaxCombinations(); i++ {
+		if _, err := generator.NewRegisteredID(ctx, registry); err != nil {
+			t.Fatalf("ID %d: unexpected error: %v", i, err)
+		}
+	}
+	if _, err := generator.NewRegisteredID(ctx, registry); !errors.Is(err, ErrSpaceExhausted) {
+		t.Errorf("expected ErrSpaceExhausted, got %v", err)
+	}
+}
+
+func TestMemoryCounterStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryCounterStore()
+
+	if _, found, err := store.Load(ctx, "job"); found || err != nil {
+		t.Errorf("expected missing counter, got found=%v (err: %v)", found, err)
+	}
+	if err := store.Save(ctx, "job", 42); err != nil {
+		t.Fatal(err)
+	}
+	if value, found, _ := store.Load(ctx, "job"); !found || value != 42 {
+		t.Errorf("expected 42, got %d (found: %v)", value, found)
+	}
+}
+
+func TestMemoryMetaStore(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0)
+	store := NewMemoryMetaStore()
+	store.clock = ClockFunc(func() time.Time { return now })
+
+	store.Set(ctx, 1, []byte("forever"), 0)
+	store.Set(ctx, 2, []byte("brief"), time.Minute)
+	if value, found, _ := store.Get(ctx, 2); !found || string(value) != "brief" {
+		t.Errorf("expected brief, got %q (found: %v)", value, found)
+	}
+
+	now = now.Add(time.Minute)
+	if _, found, _ := store.Get(ctx, 2); found {
+		t.Error("expected expired snapshot to be dropped")
+	}
+	if value, found, _ := store.Get(ctx, 1); !found || string(value) != "forever" {
+		t.Errorf("expected forever, got %q (found: %v)", value, found)
+	}
+
+	store.Delete(ctx, 1)
+	if _, found, err := store.Get(ctx, 1); found || err != nil {
+		t.Errorf("expected deleted snapshot, got found=%v (err: %v)", found, err)
+	}
+}
@@ -0,0 +1,181 @@
+package doremid
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// TimeOrderedConfig configures a TimeOrderedGenerator
+type TimeOrderedConfig struct {
+	// Epoch is the time encoded as timestamp zero, e.g. the product launch rather
+	// than 1970, so fewer symbols cover its lifetime. It must be set.
+	Epoch time.Time
+
+	// Resolution is the duration of one timestamp tick, e.g. time.Second,
+	// time.Millisecond or time.Microsecond. Zero uses one millisecond.
+	Resolution time.Duration
+
+	// TimestampSymbols is the number of leading symbols, notes first and then
+	// characters, that encode the timestamp. The remaining symbols are random and
+	// at least one must remain. Zero derives it from Lifetime.
+	TimestampSymbols int
+
+	// Lifetime is how long after Epoch IDs must be issued. If TimestampSymbols is
+	// zero, the fewest symbols covering it are used, leaving the rest random.
+	// Zero uses the note part for the timestamp.
+	Lifetime time.Duration
+
+	// Clock tells the time. Nil uses the system clock.
+	Clock Clock
+}
+
+// TimeOrderedGenerator issues KSUID or ULID style IDs whose leading symbols
+// encode the creation time and whose remaining symbols are random.
+//
+// IDs are strictly increasing in position order, which binary keys preserve, and
+// in string order when the generator is Sortable. Within one tick, or if the
+// clock goes backwards, the previous ID is incremented instead of drawing a new
+// random part, spilling into the next tick if it runs out, so a burst may carry
+// timestamps slightly ahead of the clock.
+//
+// A TimeOrderedGenerator uses the full ID space of its generator and ignores any
+// restriction, but skips reserved and filtered positions. It is safe for
+// concurrent use.
+type TimeOrderedGenerator struct {
+	g          *Generator
+	epoch      time.Time
+	resolution time.Duration
+	span       int64 // Positions per tick, covered by the random symbols
+	clock      Clock
+
+	mu   sync.Mutex
+	last int64 // Position of the previous ID, -1 before the first
+}
+
+// TimeOrdered returns a TimeOrderedGenerator issuing IDs with the configuration of g.
+// Returns a *ConfigError if Epoch is missing, Resolution or Lifetime is
+// negative, TimestampSymbols leaves no random symbol, or Lifetime cannot be
+// covered while leaving one.
+func (g *Generator) TimeOrdered(config TimeOrderedConfig) (*TimeOrderedGenerator, error) {
+	symbols := g.JustIntonationDigits + g.EqualTemperamentDigits
+	switch {
+	case config.Epoch.IsZero():
+		return nil, &ConfigError{Field: "Epoch", Reason: "must be set"}
+	case config.Resolution < 0:
+		return nil, &ConfigError{Field: "Resolution", Reason: "must not be negative"}
+	case config.Lifetime < 0:
+		return nil, &ConfigError{Field: "Lifetime", Reason: "must not be negative"}
+	case config.TimestampSymbols < 0 || config.TimestampSymbols >= symbols:
+		return nil, &ConfigError{Field: "TimestampSymbols", Reason: "must leave at least one random symbol"}
+	}
+
+	resolution := config.Resolution
+	if resolution == 0 {
+		resolution = time.Millisecond
+	}
+
+	timestampSymbols := config.TimestampSymbols
+	if timestampSymbols == 0 && config.Lifetime == 0 {
+		timestampSymbols = g.JustIntonationDigits
+	}
+	if timestampSymbols == 0 {
+		// Ticks needed, rounding up so the last partial tick is covered
+		needed := int64((config.Lifetime + resolution - 1) / resolution)
+		for n := 1; n < symbols && timestampSymbols == 0; n++ {
+			if g.MaxCombinations()/g.trailingSpan(n) >= needed {
+				timestampSymbols = n
+			}
+		}
+		if timestampSymbols == 0 {
+			return nil, &ConfigError{Field: "Lifetime", Reason: "exceeds the timestamps the ID space can encode"}
+		}
+	}
+
+	return &TimeOrderedGenerator{
+		g:          g,
+		epoch:      config.Epoch,
+		resolution: resolution,
+		span:       g.trailingSpan(timestampSymbols),
+		clock:      clockOrWall(config.Clock),
+		last:       -1,
+	}, nil
+}
+
+// trailingSpan returns the number of positions covered by the symbols after the
+// first leading ones, e.g. the positions per tick when the leading symbols
+// encode a timestamp
+func (g *Generator) trailingSpan(leading int) int64 {
+	// The trailing symbols are the last notes, if any, and the last characters
+	symbols := g.JustIntonationDigits + g.EqualTemperamentDigits
+	notes := max(g.JustIntonationDigits-leading, 0)
+	characters := min(symbols-leading, g.EqualTemperamentDigits)
+	return int64(g.intPow(g.justIntonationLen, notes)) * int64(g.intPow(g.equalTemperamentLen, characters))
+}
+
+// NewID issues an ID for the current time.
+// Returns a *RangeError if the clock is before the epoch, or ErrSpaceExhausted
+// once the clock passes the last timestamp, the ID space is used up or a tick
+// has no allowed position left.
+func (t *TimeOrderedGenerator) NewID() (string, error) {
+	now := t.clock.Now()
+	if now.Before(t.epoch) {
+		return "", &RangeError{Position: -1, Min: 0, Max: t.ticks()}
+	}
+	tick := int64(now.Sub(t.epoch) / t.resolution)
+	if tick >= t.ticks() {
+		return "", ErrSpaceExhausted
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var pos int64
+	if t.last >= 0 && t.last/t.span >= tick {
+		pos = t.last + 1
+	} else {
+		rng := t.g.acquireRand()
+		pos = tick*t.span + rng.Int63n(t.span)
+		t.g.releaseRand(rng)
+	}
+	if pos >= t.g.MaxCombinations() {
+		return "", ErrSpaceExhausted
+	}
+
+	// Skip reserved and filtered positions without leaving the tick, wrapping
+	// around to the first position after the previous ID
+	start, end := max(pos/t.span*t.span, t.last+1), (pos/t.span+1)*t.span
+	next := t.g.nextAllowed(pos)
+	if next >= end {
+		next = t.g.nextAllowed(start)
+	}
+	if next >= end {
+		return "", ErrSpaceExhausted
+	}
+	pos = next
+	t.last = pos
+	return t.g.PositionToID(pos), nil
+}
+
+// ExtractTimestamp returns the creation time encoded in id, truncated to the resolution.
+// Returns a *FormatError if id is invalid.
+func (t *TimeOrderedGenerator) ExtractTimestamp(id string) (time.Time, error) {
+	pos, err := t.g.decode(id)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return t.epoch.Add(time.Duration(pos/t.span) * t.resolution), nil
+}
+
+// Until returns the end of the last timestamp the generator can encode
+func (t *TimeOrderedGenerator) Until() time.Time {
+	if t.ticks() > math.MaxInt64/int64(t.resolution) {
+		return t.epoch.Add(math.MaxInt64)
+	}
+	return t.epoch.Add(time.Duration(t.ticks()) * t.resolution)
+}
+
+// ticks returns the number of timestamps the ID space covers
+func (t *TimeOrderedGenerator) ticks() int64 {
+	return t.g.MaxCombinations() / t.span
+}
@@ -0,0 +1,267 @@
+package doremid
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestTimeOrderedGenerator(t *testing.T) {
+	epoch := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	generator := New(Config{
+		JustIntonationDigits:   8,
+		EqualTemperamentDigits: 6,
+		Separator:              "-",
+	})
+	to, err := generator.TimeOrdered(TimeOrderedConfig{Epoch: epoch})
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock := epoch.Add(90*time.Minute + 1500*time.Microsecond)
+	to.clock = ClockFunc(func() time.Time { return clock })
+
+	// Many IDs within one millisecond are strictly increasing and carry its
+	// timestamp, or the next one if the random part ran out
+	tick := epoch.Add(90*time.Minute + time.Millisecond)
+	var previous int64 = -1
+	for i := 0; i < 1000; i++ {
+		id, err := to.NewID()
+		if err != nil {
+			t.Fatal(err)
+		}
+		pos := generator.IDToPosition(id)
+		if pos <= previous {
+			t.Fatalf("expected increasing positions, got %d after %d", pos, previous)
+		}
+		previous = pos
+
+		timestamp, err := to.ExtractTimestamp(id)
+		if err != nil || timestamp.Before(tick) || timestamp.After(tick.Add(time.Millisecond)) || (i == 0 && !timestamp.Equal(tick)) {
+			t.Fatalf("unexpected timestamp %v for '%s' (err: %v)", timestamp, id, err)
+		}
+	}
+
+	// A clock going backwards keeps IDs increasing
+	clock = epoch
+	if id, _ := to.NewID(); generator.IDToPosition(id) <= previous {
+		t.Errorf("expected an increasing ID after clock moved back, got '%s'", id)
+	}
+
+	if _, err := to.ExtractTimestamp("invalid"); !errors.Is(err, ErrInvalidID) {
+		t.Errorf("expected ErrInvalidID, got %v", err)
+	}
+
+	clock = epoch.Add(-time.Second)
+	if _, err := to.NewID(); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("expected ErrOutOfRange before the epoch, got %v", err)
+	}
+}
+
+func TestTimeOrderedIncrementsWithinTick(t *testing.T) {
+	epoch := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	generator := New(Config{
+		JustIntonationDigits:   4,
+		EqualTemperamentDigits: 2,
+		Separator:              "-",
+	})
+	to, err := generator.TimeOrdered(TimeOrderedConfig{Epoch: epoch})
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock := epoch.Add(time.Second)
+	to.clock = ClockFunc(func() time.Time { return clock })
+
+	// Only the first ID of a tick is random; the rest follow it
+	first, _ := to.NewID()
+	for i := int64(1); i < 5; i++ {
+		if id, _ := to.NewID(); generator.IDToPosition(id) != generator.IDToPosition(first)+i {
+			t.Fatalf("expected the position after the previous ID, got '%s'", id)
+		}
+	}
+
+	// A burst exhausting the tick spills into the next one
+	for range 144 {
+		if _, err := to.NewID(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	last, _ := to.NewID()
+	if timestamp, _ := to.ExtractTimestamp(last); !timestamp.After(epoch.Add(time.Second)) {
+		t.Errorf("expected a timestamp ahead of the clock, got %v", timestamp)
+	}
+}
+
+func TestTimeOrderedSkipsBlockedPositions(t *testing.T) {
+	epoch := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	var reserved []int64
+	for pos := int64(5 * 12); pos < 6*12; pos++ {
+		if pos != 5*12+7 {
+			reserved = append(reserved, pos)
+		}
+	}
+	config := Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 1,
+		Separator:              "-",
+		ReservedPositions:      reserved,
+		WordFilter:             NewWordFilter("dore"),
+	}
+
+	// Every random draw in tick 5 lands on its one allowed position
+	for range 5 {
+		generator := New(config)
+		to, _ := generator.TimeOrdered(TimeOrderedConfig{Epoch: epoch})
+		to.clock = ClockFunc(func() time.Time { return epoch.Add(5 * time.Millisecond) })
+		if id, err := to.NewID(); err != nil || generator.IDToPosition(id) != 5*12+7 {
+			t.Fatalf("expected the allowed position, got '%s' (err: %v)", id, err)
+		}
+		if id, err := to.NewID(); !errors.Is(err, ErrSpaceExhausted) {
+			t.Fatalf("expected ErrSpaceExhausted once the tick is used up, got '%s' (err: %v)", id, err)
+		}
+	}
+
+	// Tick 1 spells "dore"
+	generator := New(config)
+	to, _ := generator.TimeOrdered(TimeOrderedConfig{Epoch: epoch})
+	to.clock = ClockFunc(func() time.Time { return epoch.Add(time.Millisecond) })
+	if id, err := to.NewID(); !errors.Is(err, ErrSpaceExhausted) {
+		t.Errorf("expected ErrSpaceExhausted for a filtered tick, got '%s' (err: %v)", id, err)
+	}
+}
+
+func TestTimeOrderedLexicographic(t *testing.T) {
+	epoch := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	generator := New(Config{
+		JustIntonationDigits:   6,
+		EqualTemperamentDigits: 4,
+		Separator:              "-",
+		Notes:                  "do fa la mi re so ti",
+	})
+	if !generator.Sortable() {
+		t.Fatal("expected alphabetically ordered notes to be sortable")
+	}
+	to, _ := generator.TimeOrdered(TimeOrderedConfig{Epoch: epoch, Resolution: time.Second, TimestampSymbols: 7})
+
+	clock := epoch
+	to.clock = ClockFunc(func() time.Time { return clock })
+
+	var ids []string
+	for i := 0; i < 500; i++ {
+		// Several IDs per tick, ticks advancing irregularly
+		clock = clock.Add(time.Duration(i%3) * 700 * time.Millisecond)
+		id, err := to.NewID()
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, id)
+	}
+	if !slices.IsSorted(ids) {
+		t.Error("expected IDs in lexicographic order")
+	}
+
+	// The random part spills into the next tick once exhausted, 12^3 IDs per tick
+	clock = epoch
+	to, _ = generator.TimeOrdered(TimeOrderedConfig{Epoch: epoch, Resolution: time.Second, TimestampSymbols: 7})
+	to.clock = ClockFunc(func() time.Time { return clock })
+	var last string
+	for i := 0; i < 2000; i++ {
+		last, _ = to.NewID()
+	}
+	if timestamp, _ := to.ExtractTimestamp(last); !timestamp.After(epoch) {
+		t.Errorf("expected the timestamp to move ahead after spilling, got %v", timestamp)
+	}
+}
+
+func TestSortable(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   Config
+		expected bool
+	}{
+		{"default", Config{JustIntonationDigits: 2, EqualTemperamentDigits: 2}, false},
+		{"sorted notes", Config{JustIntonationDigits: 2, EqualTemperamentDigits: 2, Notes: "do fa la mi re so ti"}, true},
+		{"unsorted characters", Config{JustIntonationDigits: 2, EqualTemperamentDigits: 2, Notes: "a b", Characters: "ba"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := New(tt.config).Sortable(); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestTimeOrderedLifetime(t *testing.T) {
+	epoch := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	generator := New(Config{
+		JustIntonationDigits:   8,
+		EqualTemperamentDigits: 8,
+		Separator:              "-",
+	})
+	year := 365 * 24 * time.Hour
+
+	tests := []struct {
+		name       string
+		resolution time.Duration
+		lifetime   time.Duration
+		symbols    int
+	}{
+		// 7^8 is about 5.8 million, 7^8*12 about 69 million, 7^8*12^2 about 830 million
+		{"ten years in seconds", time.Second, 10 * year, 10},
+		{"one year in seconds", time.Second, year, 9},
+		{"one day in seconds", time.Second, 24 * time.Hour, 6},
+		{"one year in milliseconds", time.Millisecond, year, 12},
+		{"one hour in microseconds", time.Microsecond, time.Hour, 11},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			to, err := generator.TimeOrdered(TimeOrderedConfig{Epoch: epoch, Resolution: tt.resolution, Lifetime: tt.lifetime})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if to.span != generator.trailingSpan(tt.symbols) {
+				t.Errorf("expected %d timestamp symbols", tt.symbols)
+			}
+			if until := to.Until(); until.Before(epoch.Add(tt.lifetime)) {
+				t.Errorf("expected coverage past %v, got %v", epoch.Add(tt.lifetime), until)
+			}
+			if fewer := generator.MaxCombinations() / generator.trailingSpan(tt.symbols-1); fewer >= int64(tt.lifetime/tt.resolution) {
+				t.Errorf("expected %d symbols not to be enough, they cover %d ticks", tt.symbols-1, fewer)
+			}
+		})
+	}
+
+	if _, err := generator.TimeOrdered(TimeOrderedConfig{Epoch: epoch, Resolution: time.Nanosecond, Lifetime: 100 * year}); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig for an uncoverable lifetime, got %v", err)
+	}
+}
+
+func TestTimeOrderedConfig(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 3,
+		Separator:              "-",
+	})
+	epoch := time.Now()
+
+	tests := []struct {
+		name   string
+		config TimeOrderedConfig
+	}{
+		{"missing epoch", TimeOrderedConfig{}},
+		{"negative resolution", TimeOrderedConfig{Epoch: epoch, Resolution: -1}},
+		{"no random symbols", TimeOrderedConfig{Epoch: epoch, TimestampSymbols: 5}},
+		{"negative lifetime", TimeOrderedConfig{Epoch: epoch, Lifetime: -1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := generator.TimeOrdered(tt.config); !errors.Is(err, ErrInvalidConfig) {
+				t.Errorf("expected ErrInvalidConfig, got %v", err)
+			}
+		})
+	}
+}
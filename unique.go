@@ -0,0 +1,94 @@
+package doremid
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+// UniqueGenerator issues random IDs that never repeat until the ID space is
+// exhausted, remembering every issued position in a Registry. It mints within
+// any restriction of its generator and is safe for concurrent use if both the
+// generator and the registry are.
+type UniqueGenerator struct {
+	g         *Generator
+	registry  Registry
+	exhausted atomic.Bool
+}
+
+// NewUniqueGenerator creates a unique generator remembering issued IDs in r:
+// FilterRegistry(g.NewExactFilter()) for an exact in-memory bitset,
+// FilterRegistry over a bloom filter for a compact approximate set, or any
+// other Registry, e.g. one backed by a database.
+// Returns a *ConfigError if r is nil.
+func (g *Generator) NewUniqueGenerator(r Registry) (*UniqueGenerator, error) {
+	if r == nil {
+		return nil, &ConfigError{Field: "r", Reason: "must not be nil"}
+	}
+	return &UniqueGenerator{g: g, registry: r}, nil
+}
+
+// NewUniqueID returns a random ID the registry has not seen and registers it.
+// Returns ErrSpaceExhausted once every position is registered, without
+// consulting the registry again, or any error returned by the registry.
+func (u *UniqueGenerator) NewUniqueID(ctx context.Context) (string, error) {
+	if u.exhausted.Load() {
+		return "", ErrSpaceExhausted
+	}
+
+	id, err := u.g.NewRegisteredID(ctx, u.registry)
+	if errors.Is(err, ErrSpaceExhausted) {
+		// Registered positions are never released, so the space stays exhausted
+		u.exhausted.Store(true)
+	}
+	return id, err
+}
+
+// FilterRegistry returns a Registry keeping issued positions in filter, which
+// must belong to a generator with the same configuration. An exact filter, see
+// NewExactFilter, is a bitset that remembers every position. Any other bloom
+// filter uses less memory but occasionally reports a fresh position as
+// registered, so a UniqueGenerator using it skips those positions and may
+// report ErrSpaceExhausted before every position is issued.
+//
+// The registry locks filter, so it is safe for concurrent use as long as filter
+// is not used directly at the same time.
+func FilterRegistry(filter *BloomFilter) Registry {
+	return &filterRegistry{filter: filter}
+}
+
+type filterRegistry struct {
+	mu     sync.Mutex
+	filter *BloomFilter
+}
+
+func (r *filterRegistry) Register(_ context.Context, position int64) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	fresh := false
+	err := r.filter.eachPosition(position, func(bit uint64) bool {
+		if r.filter.bits[bit/64]&(1<<(bit%64)) == 0 {
+			r.filter.bits[bit/64] |= 1 << (bit % 64)
+			fresh = true
+		}
+		return true
+	})
+	return fresh, err
+}
+
+func (r *filterRegistry) Contains(_ context.Context, position int64) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	contains := true
+	err := r.filter.eachPosition(position, func(bit uint64) bool {
+		contains = r.filter.bits[bit/64]&(1<<(bit%64)) != 0
+		return contains
+	})
+	if errors.Is(err, ErrOutOfRange) {
+		return false, nil
+	}
+	return contains && err == nil, err
+}
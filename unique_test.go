@@ -0,0 +1,149 @@
+package doremid
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestUniqueGenerator(t *testing.T) {
+	ctx := context.Background()
+	generator := New(Config{
+		JustIntonationDigits:   1,
+		EqualTemperamentDigits: 2,
+		Separator:              "-",
+	})
+
+	registries := []struct {
+		name     string
+		registry Registry
+	}{
+		{"exact filter", FilterRegistry(generator.NewExactFilter())},
+		{"memory registry", NewMemoryRegistry()},
+	}
+
+	for _, tt := range registries {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := generator.NewUniqueGenerator(tt.registry)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Every one of the 7*12*12 IDs is issued exactly once
+			seen := make(map[string]bool)
+			for i := 0; i < 1008; i++ {
+				id, err := u.NewUniqueID(ctx)
+				if err != nil {
+					t.Fatalf("ID %d: %v", i, err)
+				}
+				if seen[id] {
+					t.Fatalf("expected unique IDs, got '%s' twice", id)
+				}
+				seen[id] = true
+			}
+
+			for range 2 {
+				if _, err := u.NewUniqueID(ctx); !errors.Is(err, ErrSpaceExhausted) {
+					t.Errorf("expected ErrSpaceExhausted, got %v", err)
+				}
+			}
+		})
+	}
+
+	if _, err := generator.NewUniqueGenerator(nil); err == nil {
+		t.Error("expected error for a nil registry")
+	}
+}
+
+func TestUniqueGeneratorConcurrent(t *testing.T) {
+	ctx := context.Background()
+	generator := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 2,
+		Separator:              "-",
+		Concurrent:             true,
+	})
+	u, _ := generator.NewUniqueGenerator(FilterRegistry(generator.NewExactFilter()))
+
+	var mu sync.Mutex
+	seen := make(map[string]bool)
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 500 {
+				id, err := u.NewUniqueID(ctx)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				mu.Lock()
+				if seen[id] {
+					t.Errorf("expected unique IDs, got '%s' twice", id)
+				}
+				seen[id] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestFilterRegistry(t *testing.T) {
+	ctx := context.Background()
+	generator := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 3,
+		Separator:              "-",
+	})
+	restricted := generator.Restrict(Range{Start: 100, End: 200})
+
+	exact := restricted.NewExactFilter()
+	if !exact.Exact() || exact.Bits() != 100 {
+		t.Fatalf("expected an exact filter of 100 bits, got %d bits (exact: %v)", exact.Bits(), exact.Exact())
+	}
+	r := FilterRegistry(exact)
+
+	if ok, err := r.Register(ctx, 150); !ok || err != nil {
+		t.Errorf("expected first registration to succeed, got %v (err: %v)", ok, err)
+	}
+	if ok, _ := r.Register(ctx, 150); ok {
+		t.Error("expected second registration to fail")
+	}
+	if ok, _ := r.Contains(ctx, 150); !ok {
+		t.Error("expected position 150 to be registered")
+	}
+	if ok, _ := r.Contains(ctx, 151); ok {
+		t.Error("expected position 151 not to be registered")
+	}
+
+	// The filter itself sees registered positions
+	if ok, _ := exact.Contains(generator.PositionToID(150)); !ok {
+		t.Error("expected the filter to contain position 150")
+	}
+
+	// An exact filter cannot hold positions outside its range
+	if ok, err := r.Contains(ctx, 500); ok || err != nil {
+		t.Errorf("expected position 500 not to be registered, got %v (err: %v)", ok, err)
+	}
+	if _, err := r.Register(ctx, 500); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("expected ErrOutOfRange, got %v", err)
+	}
+
+	// A bloom filter never forgets a registered position
+	bloom, err := generator.NewBloomFilter(1000, 0.01)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r = FilterRegistry(bloom)
+	for pos := int64(0); pos < 1000; pos++ {
+		r.Register(ctx, pos*7)
+	}
+	for pos := int64(0); pos < 1000; pos++ {
+		if ok, _ := r.Contains(ctx, pos*7); !ok {
+			t.Fatalf("expected position %d to be registered", pos*7)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package doremid
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// uuidSpace is the number of distinct UUIDs, 2^128
+var uuidSpace = new(big.Int).Lsh(big.NewInt(1), 128)
+
+// uuidGenerator converts between UUIDs and IDs
+var uuidGenerator = New(UUIDConfig())
+
+// UUIDConfig returns the configuration used by FromUUID and ToUUID: the default
+// configuration with just enough characters for every UUID to have an ID
+func UUIDConfig() Config {
+	config := DefaultConfig()
+	for New(config).MaxCombinationsBig().Cmp(uuidSpace) < 0 {
+		config.EqualTemperamentDigits++
+	}
+	return config
+}
+
+// FromUUID returns the ID whose position is uuid read as a big-endian 128-bit
+// number, e.g. to show a musical alias for a UUID-keyed record
+func FromUUID(uuid [16]byte) string {
+	id, _ := uuidGenerator.PositionToIDBig(new(big.Int).SetBytes(uuid[:]))
+	return id
+}
+
+// ToUUID returns the UUID converted to id by FromUUID.
+// Returns a *FormatError if id is invalid, or an error matching ErrOutOfRange if
+// its position exceeds 128 bits.
+func ToUUID(id string) ([16]byte, error) {
+	var uuid [16]byte
+	position, err := uuidGenerator.IDToPositionBig(id)
+	if err != nil {
+		return uuid, err
+	}
+	if position.Cmp(uuidSpace) >= 0 {
+		return uuid, fmt.Errorf("%w: %q is beyond the UUID space", ErrOutOfRange, id)
+	}
+	position.FillBytes(uuid[:])
+	return uuid, nil
+}
@@ -0,0 +1,50 @@
+package doremid
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestUUIDConfig(t *testing.T) {
+	config := UUIDConfig()
+	if config.JustIntonationDigits != 4 || config.EqualTemperamentDigits != 33 {
+		t.Errorf("expected 4 notes and 33 characters to cover 2^128, got %d and %d", config.JustIntonationDigits, config.EqualTemperamentDigits)
+	}
+}
+
+func TestUUIDRoundTrip(t *testing.T) {
+	tests := []struct {
+		name     string
+		uuid     [16]byte
+		expected string
+	}{
+		{"nil", [16]byte{}, "dodododo-" + strings.Repeat("0", 33)},
+		{"one", [16]byte{15: 1}, "dodododo-" + strings.Repeat("0", 32) + "1"},
+		{"v4", [16]byte{0x55, 0x0e, 0x84, 0x00, 0xe2, 0x9b, 0x41, 0xd4, 0xa7, 0x16, 0x44, 0x66, 0x55, 0x44, 0x00, 0x00}, "dolasomi-76911439435b6286263832bbb73a99400"},
+		{"max", [16]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id := FromUUID(tt.uuid)
+			if tt.expected != "" && id != tt.expected {
+				t.Errorf("expected '%s', got '%s'", tt.expected, id)
+			}
+			uuid, err := ToUUID(id)
+			if err != nil || uuid != tt.uuid {
+				t.Errorf("expected %x, got %x (err: %v)", tt.uuid, uuid, err)
+			}
+		})
+	}
+}
+
+func TestToUUIDErrors(t *testing.T) {
+	if _, err := ToUUID("invalid"); !errors.Is(err, ErrInvalidID) {
+		t.Errorf("expected ErrInvalidID, got %v", err)
+	}
+	last := "titititi-" + strings.Repeat("b", 33)
+	if _, err := ToUUID(last); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("expected ErrOutOfRange, got %v", err)
+	}
+}
@@ -0,0 +1,49 @@
+package doremid
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Version returns the version marked in the generator's IDs, zero if none
+func (g *Generator) Version() int {
+	return g.version
+}
+
+// IDVersion returns the version id is marked with, read as the character after
+// the generator's namespace prefix, e.g. to pick which generator parses it when
+// several versions are live. Generators of every version must share the Prefix
+// and Characters for the marker to be found, and IDs of an unmarked generator
+// may pass for marked ones if a note starts with a character, so mark every
+// generation. Returns false if id carries no valid marker; the rest of id is
+// not checked.
+func (g *Generator) IDVersion(id string) (int, bool) {
+	namespace := len(g.prefix)
+	if g.version > 0 {
+		namespace--
+	}
+	if !strings.HasPrefix(id, g.prefix[:namespace]) || len(id) <= namespace {
+		return 0, false
+	}
+	version, found := g.equalTemperamentMap[id[namespace]]
+	return version, found && version > 0
+}
+
+// Migrate re-encodes id, issued by from, as the ID of the same position in to,
+// so long-lived systems can grow their ID space without breaking old IDs: old
+// IDs keep resolving through from and can be rewritten to their new form, e.g.
+// when a version 1 generator with 4 notes gives way to a version 2 generator
+// with 6. Positions beyond the int64 range are supported.
+// Returns a *FormatError if from cannot parse id, or an error matching
+// ErrOutOfRange if its position does not fit to.
+func Migrate(id string, from, to *Generator) (string, error) {
+	position, err := from.IDToPositionBig(id)
+	if err != nil {
+		return "", err
+	}
+	migrated, err := to.PositionToIDBig(position)
+	if err != nil {
+		return "", fmt.Errorf("migrating %q: %w", id, err)
+	}
+	return migrated, nil
+}
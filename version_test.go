@@ -0,0 +1,102 @@
+package doremid
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestVersion(t *testing.T) {
+	generator := New(Config{JustIntonationDigits: 2, EqualTemperamentDigits: 3, Separator: "-", Version: 2})
+
+	tests := []struct {
+		name     string
+		position int64
+		id       string
+	}{
+		{"first", 0, "2dodo-000"},
+		{"middle", 3722, "2domi-1a2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if id := generator.PositionToID(tt.position); id != tt.id {
+				t.Errorf("expected '%s', got '%s'", tt.id, id)
+			}
+			if pos := generator.IDToPosition(tt.id); pos != tt.position {
+				t.Errorf("expected position %d, got %d", tt.position, pos)
+			}
+			if version, ok := generator.IDVersion(tt.id); !ok || version != 2 {
+				t.Errorf("expected version 2, got %d (ok: %v)", version, ok)
+			}
+		})
+	}
+
+	if id := generator.NewID(); id[0] != '2' || generator.Validate(id) != nil {
+		t.Errorf("expected a marked random ID, got '%s'", id)
+	}
+	if generator.Version() != 2 {
+		t.Errorf("expected version 2, got %d", generator.Version())
+	}
+	if err := generator.Validate("3domi-1a2"); !errors.Is(err, ErrVersionMismatch) || !errors.Is(err, ErrInvalidID) {
+		t.Errorf("expected ErrVersionMismatch, got %v", err)
+	}
+	for _, id := range []string{"domi-1a2", "0domi-1a2", "xdomi-1a2", ""} {
+		if version, ok := generator.IDVersion(id); ok {
+			t.Errorf("expected no version for '%s', got %d", id, version)
+		}
+	}
+
+	prefixed := New(Config{JustIntonationDigits: 2, EqualTemperamentDigits: 3, Separator: "-", Prefix: "usr", Version: 1, Checksum: true})
+	id := prefixed.PositionToID(3722)
+	if id[:5] != "usr_1" || prefixed.IDToPosition(id) != 3722 {
+		t.Errorf("expected a marked ID after the prefix, got '%s'", id)
+	}
+	if layout := prefixed.Debug().Layout; layout[0] != (LayoutField{Name: "prefix", Offset: 0, Length: 4}) || layout[1] != (LayoutField{Name: "version", Offset: 4, Length: 1}) {
+		t.Errorf("expected prefix and version fields, got %+v", layout[:2])
+	}
+}
+
+func TestVersionConfig(t *testing.T) {
+	for _, version := range []int{-1, 12} {
+		_, err := NewE(Config{JustIntonationDigits: 2, EqualTemperamentDigits: 3, Version: version})
+		var configErr *ConfigError
+		if !errors.As(err, &configErr) || configErr.Field != "Version" {
+			t.Errorf("expected a Version ConfigError for %d, got %v", version, err)
+		}
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	v1 := New(Config{JustIntonationDigits: 2, EqualTemperamentDigits: 3, Separator: "-", Version: 1})
+	v2 := New(Config{JustIntonationDigits: 3, EqualTemperamentDigits: 4, Separator: "-", Version: 2})
+	huge := New(Config{JustIntonationDigits: 20, EqualTemperamentDigits: 20, Separator: "-", Version: 3})
+
+	tests := []struct {
+		name     string
+		id       string
+		from, to *Generator
+		expected string
+	}{
+		{"grow", "1domi-1a2", v1, v2, "2dododo-21a2"},
+		{"shrink", "2dododo-21a2", v2, v1, "1domi-1a2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			migrated, err := Migrate(tt.id, tt.from, tt.to)
+			if err != nil || migrated != tt.expected {
+				t.Errorf("expected '%s', got '%s' (err: %v)", tt.expected, migrated, err)
+			}
+		})
+	}
+
+	if migrated, err := Migrate("1titi-bbb", v1, huge); err != nil || huge.IDToPosition(migrated) != v1.MaxCombinations()-1 {
+		t.Errorf("expected the last position in the large space, got '%s' (err: %v)", migrated, err)
+	}
+	if _, err := Migrate(v2.PositionToID(v2.MaxCombinations()-1), v2, v1); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("expected ErrOutOfRange, got %v", err)
+	}
+	if _, err := Migrate("1domi-1a2", v2, v1); !errors.Is(err, ErrInvalidID) {
+		t.Errorf("expected ErrInvalidID, got %v", err)
+	}
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"strings"
+)
+
+// Alphabets mirror the reference generator. They are plain strings so that the
+// core needs no maps or other runtime-initialized tables, which keeps TinyGo builds small.
+const (
+	justIntonationNotes   = "doremifasolati"
+	equalTemperamentChars = "0123456789ab"
+	justIntonationLen     = len(justIntonationNotes) / 2
+	equalTemperamentLen   = len(equalTemperamentChars)
+)
+
+// codec is a map-free implementation of the doremid ID format
+type codec struct {
+	justDigits  int
+	equalDigits int
+	separator   string
+	equalMax    int64
+	maxValue    int64
+}
+
+// newCodec creates a codec for the given configuration.
+// Returns false if the configuration cannot be represented, including when
+// the number of combinations overflows int64.
+func newCodec(justDigits, equalDigits int, separator string) (*codec, bool) {
+	if justDigits <= 0 || equalDigits <= 0 {
+		return nil, false
+	}
+
+	justMax, ok := intPow(justIntonationLen, justDigits)
+	if !ok {
+		return nil, false
+	}
+	equalMax, ok := intPow(equalTemperamentLen, equalDigits)
+	if !ok || justMax > math.MaxInt64/equalMax {
+		return nil, false
+	}
+	return &codec{
+		justDigits:  justDigits,
+		equalDigits: equalDigits,
+		separator:   separator,
+		equalMax:    equalMax,
+		maxValue:    justMax * equalMax,
+	}, true
+}
+
+// generate returns a random ID
+func (c *codec) generate() string {
+	id, _ := c.encode(rand.Int63n(c.maxValue))
+	return id
+}
+
+// encode converts a position to its ID.
+// Returns false if the position is out of range.
+func (c *codec) encode(position int64) (string, bool) {
+	if position < 0 || position >= c.maxValue {
+		return "", false
+	}
+
+	result := make([]byte, c.justDigits*2+len(c.separator)+c.equalDigits)
+
+	justValue := position / c.equalMax
+	for i := c.justDigits - 1; i >= 0; i-- {
+		note := int(justValue % int64(justIntonationLen))
+		copy(result[i*2:], justIntonationNotes[note*2:note*2+2])
+		justValue /= int64(justIntonationLen)
+	}
+
+	offset := c.justDigits * 2
+	copy(result[offset:], c.separator)
+	offset += len(c.separator)
+
+	equalValue := position % c.equalMax
+	for i := c.equalDigits - 1; i >= 0; i-- {
+		result[offset+i] = equalTemperamentChars[equalValue%int64(equalTemperamentLen)]
+		equalValue /= int64(equalTemperamentLen)
+	}
+
+	return string(result), true
+}
+
+// decode converts an ID back to its position.
+// Returns false if the ID is invalid.
+func (c *codec) decode(id string) (int64, bool) {
+	justLen := c.justDigits * 2
+	if len(id) != justLen+len(c.separator)+c.equalDigits {
+		return -1, false
+	}
+	if !strings.HasPrefix(id[justLen:], c.separator) {
+		return -1, false
+	}
+
+	justValue := int64(0)
+	for i := 0; i < justLen; i += 2 {
+		index := noteIndex(id[i], id[i+1])
+		if index < 0 {
+			return -1, false
+		}
+		justValue = justValue*int64(justIntonationLen) + int64(index)
+	}
+
+	equalValue := int64(0)
+	for i := justLen + len(c.separator); i < len(id); i++ {
+		index := strings.IndexByte(equalTemperamentChars, id[i])
+		if index < 0 {
+			return -1, false
+		}
+		equalValue = equalValue*int64(equalTemperamentLen) + int64(index)
+	}
+
+	return justValue*c.equalMax + equalValue, true
+}
+
+// validate reports whether id is a valid ID for the codec
+func (c *codec) validate(id string) bool {
+	_, ok := c.decode(id)
+	return ok
+}
+
+// noteIndex returns the index of the two-byte note, or -1 if it is not a note
+func noteIndex(a, b byte) int {
+	for i := 0; i < justIntonationLen; i++ {
+		if justIntonationNotes[i*2] == a && justIntonationNotes[i*2+1] == b {
+			return i
+		}
+	}
+	return -1
+}
+
+// intPow calculates an integer power by repeated multiplication.
+// Returns false if the result overflows int64.
+func intPow(base, exp int) (int64, bool) {
+	result := int64(1)
+	for range exp {
+		if result > math.MaxInt64/int64(base) {
+			return 0, false
+		}
+		result *= int64(base)
+	}
+	return result, true
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/doremi-id/doremid"
+	"github.com/doremi-id/doremid/conformance"
+)
+
+// coreAdapter runs the conformance suite against the map-free core
+type coreAdapter struct{}
+
+func (coreAdapter) PositionToID(config doremid.Config, position int64) (string, error) {
+	c, ok := newCodec(config.JustIntonationDigits, config.EqualTemperamentDigits, config.Separator)
+	if !ok {
+		return "", errInvalid
+	}
+	if id, ok := c.encode(position); ok {
+		return id, nil
+	}
+	return "", errInvalid
+}
+
+func (coreAdapter) IDToPosition(config doremid.Config, id string) (int64, error) {
+	c, ok := newCodec(config.JustIntonationDigits, config.EqualTemperamentDigits, config.Separator)
+	if !ok {
+		return -1, errInvalid
+	}
+	if pos, ok := c.decode(id); ok {
+		return pos, nil
+	}
+	return -1, errInvalid
+}
+
+func (coreAdapter) MaxCombinations(config doremid.Config) (int64, error) {
+	c, ok := newCodec(config.JustIntonationDigits, config.EqualTemperamentDigits, config.Separator)
+	if !ok {
+		return 0, errInvalid
+	}
+	return c.maxValue, nil
+}
+
+var errInvalid = errors.New("invalid")
+
+func TestCoreConformance(t *testing.T) {
+	conformance.Run(t, coreAdapter{})
+}
+
+func TestCoreGenerate(t *testing.T) {
+	c, _ := newCodec(2, 3, "-")
+	for i := 0; i < 100; i++ {
+		id := c.generate()
+		if !c.validate(id) {
+			t.Errorf("generated ID '%s' does not validate", id)
+		}
+	}
+}
+
+func TestNewCodecRejectsInvalidConfig(t *testing.T) {
+	if _, ok := newCodec(0, 3, "-"); ok {
+		t.Error("expected zero just intonation digits to be rejected")
+	}
+	if _, ok := newCodec(2, -1, "-"); ok {
+		t.Error("expected negative equal temperament digits to be rejected")
+	}
+	for _, digits := range [][2]int{{23, 1}, {1, 18}, {13, 9}, {1000, 1000}} {
+		if _, ok := newCodec(digits[0], digits[1], "-"); ok {
+			t.Errorf("expected %v digits to be rejected for overflowing int64", digits)
+		}
+	}
+	if c, ok := newCodec(10, 8, "-"); !ok || c.maxValue != 282475249*429981696 {
+		t.Errorf("expected a configuration within int64 to be accepted, got %v", c)
+	}
+}
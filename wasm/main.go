@@ -0,0 +1,97 @@
+//go:build js && wasm
+
+// Command wasm exposes doremid to JavaScript through syscall/js.
+//
+// Build with either toolchain:
+//
+//	GOOS=js GOARCH=wasm go build -o doremid.wasm ./wasm
+//	tinygo build -o doremid.wasm -target wasm ./wasm
+//
+// After the module is started it registers a global `doremid` object:
+//
+//	doremid.generate(config?)        // "dofamiso-a1b2c"
+//	doremid.decode(id, config?)      // position, or -1 if invalid
+//	doremid.encode(position, config?) // ID, or "" if out of range
+//	doremid.validate(id, config?)    // true or false
+//
+// config is an optional object with justIntonationDigits,
+// equalTemperamentDigits and separator properties.
+package main
+
+import "syscall/js"
+
+func main() {
+	js.Global().Set("doremid", js.ValueOf(map[string]any{
+		"generate": js.FuncOf(generate),
+		"decode":   js.FuncOf(decode),
+		"encode":   js.FuncOf(encode),
+		"validate": js.FuncOf(validate),
+	}))
+
+	// Keep the module alive so the callbacks remain valid
+	select {}
+}
+
+func generate(_ js.Value, args []js.Value) any {
+	c, ok := codecFromArgs(args, 0)
+	if !ok {
+		return ""
+	}
+	return c.generate()
+}
+
+func decode(_ js.Value, args []js.Value) any {
+	if len(args) < 1 || args[0].Type() != js.TypeString {
+		return -1
+	}
+	c, ok := codecFromArgs(args, 1)
+	if !ok {
+		return -1
+	}
+	pos, _ := c.decode(args[0].String())
+	return pos
+}
+
+func encode(_ js.Value, args []js.Value) any {
+	if len(args) < 1 || args[0].Type() != js.TypeNumber {
+		return ""
+	}
+	c, ok := codecFromArgs(args, 1)
+	if !ok {
+		return ""
+	}
+	id, _ := c.encode(int64(args[0].Float()))
+	return id
+}
+
+func validate(_ js.Value, args []js.Value) any {
+	if len(args) < 1 || args[0].Type() != js.TypeString {
+		return false
+	}
+	c, ok := codecFromArgs(args, 1)
+	if !ok {
+		return false
+	}
+	return c.validate(args[0].String())
+}
+
+// codecFromArgs builds a codec from the optional config object at args[index],
+// falling back to the default configuration for missing properties
+func codecFromArgs(args []js.Value, index int) (*codec, bool) {
+	justDigits, equalDigits, separator := 4, 5, "-"
+
+	if len(args) > index && args[index].Type() == js.TypeObject {
+		config := args[index]
+		if v := config.Get("justIntonationDigits"); v.Type() == js.TypeNumber {
+			justDigits = v.Int()
+		}
+		if v := config.Get("equalTemperamentDigits"); v.Type() == js.TypeNumber {
+			equalDigits = v.Int()
+		}
+		if v := config.Get("separator"); v.Type() == js.TypeString {
+			separator = v.String()
+		}
+	}
+
+	return newCodec(justDigits, equalDigits, separator)
+}
@@ -0,0 +1,13 @@
+//go:build !(js && wasm)
+
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func main() {
+	fmt.Fprintln(os.Stderr, "this command must be built with GOOS=js GOARCH=wasm or TinyGo's wasm target")
+	os.Exit(1)
+}
@@ -0,0 +1,305 @@
+// Package webhook posts signed JSON payloads to an HTTP endpoint when IDs change
+// lifecycle state, so external systems can react to new and revoked IDs without
+// polling.
+//
+// Every request carries two headers:
+//
+//	X-Doremid-Timestamp: 1735689600
+//	X-Doremid-Signature: sha256=<hex HMAC-SHA256 of "<timestamp>.<body>">
+//
+// Receivers check them with Verify. Failed deliveries are retried with
+// exponential backoff.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/doremi-id/doremid"
+)
+
+// Header names set on every delivery
+const (
+	TimestampHeader = "X-Doremid-Timestamp"
+	SignatureHeader = "X-Doremid-Signature"
+)
+
+// Defaults used when the corresponding Notifier field is zero
+const (
+	DefaultMaxAttempts    = 5
+	DefaultInitialBackoff = 500 * time.Millisecond
+	DefaultMaxBackoff     = 30 * time.Second
+	DefaultQueueSize      = 1024
+)
+
+var (
+	// ErrQueueFull is reported through OnError when an event is dropped because
+	// the delivery queue is full
+	ErrQueueFull = errors.New("webhook: delivery queue full")
+
+	// ErrInvalidSignature is returned by Verify when a signature does not match
+	ErrInvalidSignature = errors.New("webhook: invalid signature")
+)
+
+// Event is the JSON payload of a delivery
+type Event struct {
+	Type     string    `json:"type"` // Lifecycle state entered, e.g. "issued" or "revoked"
+	ID       string    `json:"id"`
+	Position int64     `json:"position"`
+	From     string    `json:"from"` // Lifecycle state left
+	Time     time.Time `json:"time"`
+}
+
+// StatusError reports a delivery rejected by the endpoint
+type StatusError struct {
+	StatusCode int
+}
+
+// Error implements the error interface
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("webhook: endpoint returned %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// Notifier delivers events to one endpoint. Fields must not be changed after
+// the first delivery. It is safe for concurrent use.
+type Notifier struct {
+	// URL is the endpoint receiving POST requests
+	URL string
+
+	// Secret is the HMAC-SHA256 key used to sign payloads
+	Secret []byte
+
+	// Client sends the requests. Nil uses http.DefaultClient.
+	Client *http.Client
+
+	// MaxAttempts is the number of delivery attempts per event
+	MaxAttempts int
+
+	// InitialBackoff is the wait before the first retry; it doubles per retry up to MaxBackoff
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+
+	// QueueSize bounds the events buffered by Attach
+	QueueSize int
+
+	// States selects the lifecycle states that trigger a delivery when attached.
+	// Nil selects doremid.StateIssued and doremid.StateRevoked.
+	States []doremid.State
+
+	// OnError is called when an attached delivery finally fails or is dropped
+	OnError func(Event, error)
+
+	// now and sleep are replaced in tests
+	now   func() time.Time
+	sleep func(context.Context, time.Duration) error
+}
+
+// New creates a notifier posting to url, signing payloads with secret
+func New(url string, secret []byte) *Notifier {
+	return &Notifier{URL: url, Secret: secret}
+}
+
+// Send delivers event synchronously, retrying network errors, 429 and 5xx
+// responses with exponential backoff. Other responses outside 2xx fail
+// immediately with a *StatusError.
+func (n *Notifier) Send(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	backoff := n.initialBackoff()
+	attempts := n.MaxAttempts
+	if attempts <= 0 {
+		attempts = DefaultMaxAttempts
+	}
+
+	for attempt := 1; ; attempt++ {
+		err = n.post(ctx, body)
+		if err == nil || !retryable(err) || attempt >= attempts {
+			return err
+		}
+
+		if err := n.wait(ctx, backoff); err != nil {
+			return err
+		}
+		backoff = min(backoff*2, n.maxBackoff())
+	}
+}
+
+// Attach delivers an event for every transition of l into one of States.
+// Deliveries happen in order on a background goroutine, so lifecycle hooks never
+// block on the network. The returned function detaches the notifier and waits
+// for queued events to be delivered.
+func (n *Notifier) Attach(l *doremid.Lifecycle) (detach func()) {
+	size := n.QueueSize
+	if size <= 0 {
+		size = DefaultQueueSize
+	}
+	queue := make(chan Event, size)
+
+	var mu sync.RWMutex
+	closed := false
+	unsubscribe := l.OnTransition(func(t doremid.Transition) {
+		if !n.selects(t.To) {
+			return
+		}
+		event := Event{Type: t.To.String(), ID: t.ID, Position: t.Position, From: t.From.String(), Time: n.timeNow()}
+
+		mu.RLock()
+		defer mu.RUnlock()
+		if closed {
+			return
+		}
+		select {
+		case queue <- event:
+		default:
+			n.report(event, ErrQueueFull)
+		}
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for event := range queue {
+			if err := n.Send(context.Background(), event); err != nil {
+				n.report(event, err)
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			unsubscribe()
+			mu.Lock()
+			closed = true
+			close(queue)
+			mu.Unlock()
+			<-done
+		})
+	}
+}
+
+// Sign returns the signature header value for body sent at timestamp
+func Sign(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the signature of a received delivery.
+// Returns ErrInvalidSignature if it does not match.
+func Verify(secret []byte, timestamp, signature string, body []byte) error {
+	if !hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// post makes a single delivery attempt
+func (n *Notifier) post(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	timestamp := strconv.FormatInt(n.timeNow().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(TimestampHeader, timestamp)
+	req.Header.Set(SignatureHeader, Sign(n.Secret, timestamp, body))
+
+	client := n.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &StatusError{StatusCode: resp.StatusCode}
+	}
+	return nil
+}
+
+// retryable reports whether a failed attempt may succeed if repeated
+func retryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode == http.StatusTooManyRequests || statusErr.StatusCode >= 500
+	}
+	return true
+}
+
+// selects reports whether entering state triggers a delivery
+func (n *Notifier) selects(state doremid.State) bool {
+	states := n.States
+	if states == nil {
+		states = []doremid.State{doremid.StateIssued, doremid.StateRevoked}
+	}
+	for _, s := range states {
+		if s == state {
+			return true
+		}
+	}
+	return false
+}
+
+func (n *Notifier) report(event Event, err error) {
+	if n.OnError != nil {
+		n.OnError(event, err)
+	}
+}
+
+func (n *Notifier) initialBackoff() time.Duration {
+	if n.InitialBackoff > 0 {
+		return n.InitialBackoff
+	}
+	return DefaultInitialBackoff
+}
+
+func (n *Notifier) maxBackoff() time.Duration {
+	if n.MaxBackoff > 0 {
+		return n.MaxBackoff
+	}
+	return DefaultMaxBackoff
+}
+
+func (n *Notifier) timeNow() time.Time {
+	if n.now != nil {
+		return n.now()
+	}
+	return time.Now()
+}
+
+// wait pauses for d or until ctx is done
+func (n *Notifier) wait(ctx context.Context, d time.Duration) error {
+	if n.sleep != nil {
+		return n.sleep(ctx, d)
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,164 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/doremi-id/doremid"
+)
+
+var secret = []byte("s3cret")
+
+// recorder is an endpoint that fails the first failures requests with status
+type recorder struct {
+	mu       sync.Mutex
+	status   int
+	failures int
+	events   []Event
+	attempts int
+}
+
+func (r *recorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.attempts++
+	if r.failures > 0 {
+		r.failures--
+		w.WriteHeader(r.status)
+		return
+	}
+
+	if err := Verify(secret, req.Header.Get(TimestampHeader), req.Header.Get(SignatureHeader), body); err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	var event Event
+	json.Unmarshal(body, &event)
+	r.events = append(r.events, event)
+}
+
+func newNotifier(url string) (*Notifier, *[]time.Duration) {
+	var waits []time.Duration
+	n := New(url, secret)
+	n.sleep = func(_ context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+	return n, &waits
+}
+
+func TestSendRetries(t *testing.T) {
+	endpoint := &recorder{status: http.StatusServiceUnavailable, failures: 3}
+	server := httptest.NewServer(endpoint)
+	defer server.Close()
+
+	n, waits := newNotifier(server.URL)
+	n.InitialBackoff = time.Second
+	n.MaxBackoff = 3 * time.Second
+
+	if err := n.Send(context.Background(), Event{Type: "issued", ID: "do-00"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if endpoint.attempts != 4 || len(endpoint.events) != 1 {
+		t.Errorf("expected 4 attempts and 1 event, got %d and %d", endpoint.attempts, len(endpoint.events))
+	}
+
+	expected := []time.Duration{time.Second, 2 * time.Second, 3 * time.Second}
+	if len(*waits) != len(expected) {
+		t.Fatalf("expected backoffs %v, got %v", expected, *waits)
+	}
+	for i := range expected {
+		if (*waits)[i] != expected[i] {
+			t.Errorf("expected backoffs %v, got %v", expected, *waits)
+		}
+	}
+}
+
+func TestSendFailures(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		attempts int
+	}{
+		{"client errors are not retried", http.StatusBadRequest, 1},
+		{"server errors give up after MaxAttempts", http.StatusInternalServerError, DefaultMaxAttempts},
+		{"rate limits are retried", http.StatusTooManyRequests, DefaultMaxAttempts},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			endpoint := &recorder{status: tt.status, failures: 100}
+			server := httptest.NewServer(endpoint)
+			defer server.Close()
+
+			n, _ := newNotifier(server.URL)
+			err := n.Send(context.Background(), Event{})
+
+			var statusErr *StatusError
+			if !errors.As(err, &statusErr) || statusErr.StatusCode != tt.status {
+				t.Errorf("expected *StatusError with %d, got %v", tt.status, err)
+			}
+			if endpoint.attempts != tt.attempts {
+				t.Errorf("expected %d attempts, got %d", tt.attempts, endpoint.attempts)
+			}
+		})
+	}
+}
+
+func TestAttach(t *testing.T) {
+	ctx := context.Background()
+	endpoint := &recorder{}
+	server := httptest.NewServer(endpoint)
+	defer server.Close()
+
+	generator := doremid.New(doremid.Config{
+		JustIntonationDigits:   1,
+		EqualTemperamentDigits: 2,
+		Separator:              "-",
+	})
+	lifecycle := generator.NewLifecycle(doremid.NewMemoryLifecycleStore())
+
+	n, _ := newNotifier(server.URL)
+	detach := n.Attach(lifecycle)
+
+	lifecycle.Issue(ctx, "re-1a")
+	lifecycle.Activate(ctx, "re-1a")
+	lifecycle.Revoke(ctx, "re-1a")
+	detach()
+	lifecycle.Recycle(ctx, "re-1a")
+
+	if len(endpoint.events) != 2 {
+		t.Fatalf("expected 2 events, got %+v", endpoint.events)
+	}
+	issued, revoked := endpoint.events[0], endpoint.events[1]
+	if issued.Type != "issued" || issued.ID != "re-1a" || issued.Position != generator.IDToPosition("re-1a") {
+		t.Errorf("unexpected issued event %+v", issued)
+	}
+	if revoked.Type != "revoked" || revoked.From != "active" {
+		t.Errorf("unexpected revoked event %+v", revoked)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	body := []byte(`{"type":"issued"}`)
+	signature := Sign(secret, "1735689600", body)
+
+	if err := Verify(secret, "1735689600", signature, body); err != nil {
+		t.Errorf("expected valid signature, got %v", err)
+	}
+	if err := Verify(secret, "1735689601", signature, body); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("expected ErrInvalidSignature for a different timestamp, got %v", err)
+	}
+	if err := Verify([]byte("other"), "1735689600", signature, body); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("expected ErrInvalidSignature for a different secret, got %v", err)
+	}
+}
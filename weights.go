@@ -0,0 +1,77 @@
+package doremid
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// weightTable holds the running totals of symbol weights, so that symbol i is
+// drawn for values in [table[i-1], table[i]). The nil table draws uniformly.
+type weightTable []float64
+
+// pick draws the index of one of n symbols
+func (t weightTable) pick(rng *rand.Rand, n int) int {
+	if t == nil {
+		return rng.Intn(n)
+	}
+	x := rng.Float64() * t[len(t)-1]
+	// Zero weights repeat the previous total, so they are never the first above x
+	return sort.Search(len(t), func(i int) bool { return t[i] > x })
+}
+
+// weigh builds the weight tables of config, whose weights follow the notes and
+// characters in the order they were listed
+func (g *Generator) weigh(config Config, notes []string, characters string) error {
+	var err error
+	symbols := make([]int, len(notes))
+	for i, note := range notes {
+		symbols[i] = g.justIntonationMap[note]
+	}
+	if g.justWeights, err = newWeightTable("JustWeights", config.JustWeights, symbols); err != nil {
+		return err
+	}
+
+	symbols = make([]int, len(characters))
+	for i := range len(characters) {
+		symbols[i] = g.equalTemperamentMap[characters[i]]
+	}
+	g.equalWeights, err = newWeightTable("EqualWeights", config.EqualWeights, symbols)
+	return err
+}
+
+// newWeightTable returns the weight table of the weights for the symbols with
+// the given indexes, or nil if there are no weights
+func newWeightTable(field string, weights []float64, symbols []int) (weightTable, error) {
+	if weights == nil {
+		return nil, nil
+	}
+	if len(weights) != len(symbols) {
+		return nil, &ConfigError{Field: field, Reason: fmt.Sprintf("needs %d weights, got %d", len(symbols), len(weights))}
+	}
+
+	ordered := make([]float64, len(weights))
+	for i, weight := range weights {
+		if weight < 0 || math.IsInf(weight, 0) || math.IsNaN(weight) {
+			return nil, &ConfigError{Field: field, Reason: fmt.Sprintf("weight %d must be finite and not negative", i)}
+		}
+		ordered[symbols[i]] = weight
+	}
+
+	table := make(weightTable, len(ordered))
+	total := 0.0
+	for i, weight := range ordered {
+		total += weight
+		table[i] = total
+	}
+	if total <= 0 || math.IsInf(total, 0) {
+		return nil, &ConfigError{Field: field, Reason: "weights must have a positive, finite total"}
+	}
+	return table, nil
+}
+
+// weighted reports whether random IDs are drawn with weights
+func (g *Generator) weighted() bool {
+	return g.justWeights != nil || g.equalWeights != nil
+}
@@ -0,0 +1,129 @@
+package doremid
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestWeights(t *testing.T) {
+	t.Run("single note", func(t *testing.T) {
+		generator := New(Config{
+			JustIntonationDigits:   3,
+			EqualTemperamentDigits: 2,
+			Separator:              "-",
+			JustWeights:            []float64{0, 0, 0, 0, 2, 0, 0},
+		})
+		for i := 0; i < 1000; i++ {
+			if id := generator.NewID(); !strings.HasPrefix(id, "sososo-") {
+				t.Fatalf("expected only so notes, got '%s'", id)
+			}
+		}
+	})
+
+	t.Run("proportions", func(t *testing.T) {
+		weights := make([]float64, 12)
+		weights[0], weights[11] = 1, 3
+		generator := New(Config{JustIntonationDigits: 1, EqualTemperamentDigits: 1, Separator: "-", EqualWeights: weights})
+
+		counts := map[byte]int{}
+		for i := 0; i < 10000; i++ {
+			id := generator.NewID()
+			counts[id[len(id)-1]]++
+		}
+		if len(counts) != 2 || math.Abs(float64(counts['b'])/10000-0.75) > 0.03 {
+			t.Errorf("expected about 75%% b and 25%% 0, got %v", counts)
+		}
+	})
+
+	t.Run("listed order", func(t *testing.T) {
+		// LexSortable reorders the notes, but weights follow DefaultNotes
+		generator := New(Config{
+			JustIntonationDigits:   2,
+			EqualTemperamentDigits: 1,
+			Separator:              "-",
+			LexSortable:            true,
+			JustWeights:            []float64{0, 1, 0, 0, 0, 0, 0},
+		})
+		for i := 0; i < 100; i++ {
+			if id := generator.NewID(); !strings.HasPrefix(id, "rere-") {
+				t.Fatalf("expected only re notes, got '%s'", id)
+			}
+		}
+	})
+
+	t.Run("positions unchanged", func(t *testing.T) {
+		config := Config{JustIntonationDigits: 2, EqualTemperamentDigits: 3, Separator: "-"}
+		uniform := New(config)
+		config.JustWeights = []float64{1, 2, 3, 4, 5, 6, 7}
+		weighted := New(config)
+		for _, pos := range []int64{0, 3722, 84671} {
+			if id := weighted.PositionToID(pos); id != uniform.PositionToID(pos) || weighted.IDToPosition(id) != pos {
+				t.Errorf("expected position %d to map as without weights, got '%s'", pos, id)
+			}
+		}
+	})
+
+	t.Run("reserved", func(t *testing.T) {
+		generator := New(Config{
+			JustIntonationDigits:   1,
+			EqualTemperamentDigits: 1,
+			Separator:              "-",
+			EqualWeights:           []float64{1, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+			JustWeights:            []float64{1, 0, 0, 0, 0, 0, 0},
+			ReservedIDs:            []string{"do-0"},
+		})
+		for i := 0; i < 100; i++ {
+			if id := generator.NewID(); id != "do-1" {
+				t.Fatalf("expected do-1, got '%s'", id)
+			}
+		}
+
+		blocked := New(Config{
+			JustIntonationDigits:   1,
+			EqualTemperamentDigits: 1,
+			Separator:              "-",
+			EqualWeights:           []float64{1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+			JustWeights:            []float64{1, 0, 0, 0, 0, 0, 0},
+			ReservedIDs:            []string{"do-0"},
+		})
+		if id := blocked.NewID(); id != "" {
+			t.Errorf("expected no ID when only reserved IDs have weight, got '%s'", id)
+		}
+	})
+}
+
+func TestWeightsConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		just    []float64
+		equal   []float64
+		field   string
+		invalid bool
+	}{
+		{"valid", []float64{1, 1, 1, 1, 1, 1, 1}, nil, "", false},
+		{"too few", []float64{1, 1}, nil, "JustWeights", true},
+		{"too many", nil, make([]float64, 13), "EqualWeights", true},
+		{"negative", []float64{1, 1, 1, 1, 1, 1, -1}, nil, "JustWeights", true},
+		{"not a number", []float64{1, 1, 1, 1, 1, 1, math.NaN()}, nil, "JustWeights", true},
+		{"infinite", []float64{1, 1, 1, 1, 1, 1, math.Inf(1)}, nil, "JustWeights", true},
+		{"zero total", nil, make([]float64, 12), "EqualWeights", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewE(Config{JustIntonationDigits: 2, EqualTemperamentDigits: 2, JustWeights: tt.just, EqualWeights: tt.equal})
+			if !tt.invalid {
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+				}
+				return
+			}
+			var configErr *ConfigError
+			if !errors.As(err, &configErr) || configErr.Field != tt.field {
+				t.Errorf("expected a %s ConfigError, got %v", tt.field, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package doremid
+
+import "strings"
+
+// WordFilter decides which note sequences spell words that generation avoids,
+// see Config.WordFilter
+type WordFilter interface {
+	// Match reports whether notes, the note part of an ID without prefix or
+	// group separators such as "fatimido", contains a banned word
+	Match(notes string) bool
+}
+
+// WordFilterFunc adapts a function to a WordFilter
+type WordFilterFunc func(notes string) bool
+
+// Match calls f(notes)
+func (f WordFilterFunc) Match(notes string) bool {
+	return f(notes)
+}
+
+// NewWordFilter returns a WordFilter matching note sequences that contain any
+// of words, ignoring case. Words match across note boundaries, e.g. "tit"
+// matches "dotiti".
+func NewWordFilter(words ...string) WordFilter {
+	list := make(wordList, 0, len(words))
+	for _, word := range words {
+		if word != "" {
+			list = append(list, strings.ToLower(word))
+		}
+	}
+	return list
+}
+
+// DefaultBannedWords returns the English words DefaultWordFilter bans, limited
+// to those the letters of common solfège syllables can spell. Extend it for
+// NewWordFilter to ban more.
+func DefaultBannedWords() []string {
+	return []string{"arse", "ass", "dildo", "fart", "fat", "idiot", "slut", "sodom", "tard", "tit", "turd"}
+}
+
+// DefaultWordFilter returns a WordFilter banning DefaultBannedWords
+func DefaultWordFilter() WordFilter {
+	return NewWordFilter(DefaultBannedWords()...)
+}
+
+// wordList is a WordFilter banning lowercase substrings
+type wordList []string
+
+// Match implements WordFilter
+func (l wordList) Match(notes string) bool {
+	notes = strings.ToLower(notes)
+	for _, word := range l {
+		if strings.Contains(notes, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// maxRerolls is the number of times random generation re-rolls an ID it may
+// not use, e.g. one spelling a filtered word, before giving up
+const maxRerolls = 1000
+
+// Filtered reports whether the notes of the ID at position spell a word banned
+// by Config.WordFilter
+func (g *Generator) Filtered(position int64) bool {
+	return g.filtered(position)
+}
+
+// filtered reports whether the notes at position spell a filtered word
+func (g *Generator) filtered(position int64) bool {
+	if g.wordFilter == nil || position < 0 {
+		return false
+	}
+	return g.filteredNotes(position / g.noteBlock())
+}
+
+// filteredNotes reports whether the note sequence with value justValue spells
+// a filtered word
+func (g *Generator) filteredNotes(justValue int64) bool {
+	notes := make([]byte, 0, g.JustIntonationDigits*g.maxNoteLen)
+	justLen := int64(g.justIntonationLen)
+	divisor := int64(g.intPow(g.justIntonationLen, g.JustIntonationDigits-1))
+	for i := 0; i < g.JustIntonationDigits; i++ {
+		notes = append(notes, g.justIntonationBytes[justValue/divisor%justLen]...)
+		divisor /= justLen
+	}
+	return g.wordFilter.Match(string(notes))
+}
+
+// noteBlock returns the number of consecutive positions sharing a note sequence
+func (g *Generator) noteBlock() int64 {
+	return int64(g.intPow(g.equalTemperamentLen, g.EqualTemperamentDigits))
+}
+
+// allowed reports whether generation may use position, being neither reserved
+// nor filtered
+func (g *Generator) allowed(position int64) bool {
+	return !g.reserved.contains(position) && !g.filtered(position)
+}
+
+// nextAllowed returns the first allowed position at or after position
+func (g *Generator) nextAllowed(position int64) int64 {
+	w := g.walker()
+	return w.next(position)
+}
+
+// prevAllowed returns the last allowed position at or before position, or a
+// negative one if there is none
+func (g *Generator) prevAllowed(position int64) int64 {
+	w := g.walker()
+	return w.prev(position)
+}
+
+// positionWalker finds allowed positions in order, consulting the word filter
+// once per note sequence rather than once per position
+type positionWalker struct {
+	g     *Generator
+	block int64 // Note sequence last found unfiltered, -1 before any
+}
+
+// walker returns a positionWalker for g
+func (g *Generator) walker() positionWalker {
+	return positionWalker{g: g, block: -1}
+}
+
+// next returns the first allowed position at or after position
+func (w *positionWalker) next(position int64) int64 {
+	for {
+		position = w.g.reserved.next(position)
+		if w.g.wordFilter == nil {
+			return position
+		}
+		size := w.g.noteBlock()
+		block := position / size
+		if block == w.block || !w.g.filteredNotes(block) {
+			w.block = block
+			return position
+		}
+		position = (block + 1) * size
+		if position >= w.g.MaxCombinations() {
+			return position
+		}
+	}
+}
+
+// prev returns the last allowed position at or before position, or a negative
+// one if there is none
+func (w *positionWalker) prev(position int64) int64 {
+	for {
+		position = w.g.reserved.prev(position)
+		if w.g.wordFilter == nil || position < 0 {
+			return position
+		}
+		size := w.g.noteBlock()
+		block := position / size
+		if block == w.block || !w.g.filteredNotes(block) {
+			w.block = block
+			return position
+		}
+		position = block*size - 1
+	}
+}
@@ -0,0 +1,167 @@
+package doremid
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestWordFilter(t *testing.T) {
+	tests := []struct {
+		name     string
+		filter   WordFilter
+		notes    string
+		expected bool
+	}{
+		{"word inside a note pair", NewWordFilter("fati"), "dofatimi", true},
+		{"word across notes", NewWordFilter("tit"), "dotiti", true},
+		{"ignores case", NewWordFilter("TIT"), "DOTITI", true},
+		{"no match", NewWordFilter("tit"), "dotimi", false},
+		{"empty words are ignored", NewWordFilter(""), "dore", false},
+		{"default list", DefaultWordFilter(), "sodomi", true},
+		{"default list passes", DefaultWordFilter(), "doremifa", false},
+		{"func", WordFilterFunc(func(notes string) bool { return strings.HasPrefix(notes, "do") }), "dore", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Match(tt.notes); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestWordFilterGeneration(t *testing.T) {
+	// "fati" is note value 27, so positions 324 to 335 are filtered
+	generator := New(Config{
+		JustIntonationDigits:   2,
+		EqualTemperamentDigits: 1,
+		Separator:              "-",
+		WordFilter:             NewWordFilter("fati"),
+	})
+
+	for _, tt := range []struct {
+		position int64
+		expected bool
+	}{{323, false}, {324, true}, {335, true}, {336, false}} {
+		if got := generator.Filtered(tt.position); got != tt.expected {
+			t.Errorf("expected Filtered(%d) to be %v, got %v", tt.position, tt.expected, got)
+		}
+	}
+	if _, err := generator.Mint(330); !errors.Is(err, ErrFiltered) {
+		t.Errorf("expected ErrFiltered, got %v", err)
+	}
+	if pos := generator.IDToPosition("fati-6"); pos != 330 {
+		t.Errorf("expected filtered IDs to still parse, got %d", pos)
+	}
+
+	t.Run("random", func(t *testing.T) {
+		for i := 0; i < 2000; i++ {
+			if id := generator.NewID(); strings.HasPrefix(id, "fati") {
+				t.Fatalf("generated filtered ID '%s'", id)
+			}
+		}
+		ids := generator.BatchGenerateRandomIDs(576)
+		if len(ids) != 576 || slices.ContainsFunc(ids, func(id string) bool { return strings.HasPrefix(id, "fati") }) {
+			t.Errorf("expected 576 unfiltered IDs, got %d", len(ids))
+		}
+		if _, err := generator.BatchGenerateRandomIDsCtx(context.Background(), 577); !errors.Is(err, ErrInvalidCount) {
+			t.Errorf("expected ErrInvalidCount beyond the unfiltered IDs, got %v", err)
+		}
+
+		registry := NewMemoryRegistry()
+		for i := 0; i < 576; i++ {
+			if id, err := generator.NewRegisteredID(context.Background(), registry); err != nil || strings.HasPrefix(id, "fati") {
+				t.Fatalf("expected an unfiltered registered ID, got '%s' (err: %v)", id, err)
+			}
+		}
+		if _, err := generator.NewRegisteredID(context.Background(), registry); !errors.Is(err, ErrSpaceExhausted) {
+			t.Errorf("expected ErrSpaceExhausted, got %v", err)
+		}
+	})
+
+	t.Run("sequential", func(t *testing.T) {
+		expected := []string{"fala-8", "fala-9", "fala-a", "fala-b", "sodo-0", "sodo-1", "sodo-2", "sodo-3"}
+		if ids := generator.BatchGenerateIDs(20, 320); !slices.Equal(ids, expected) {
+			t.Errorf("expected %v, got %v", expected, ids)
+		}
+		if ids := slices.Collect(generator.GenerateSeq(320, 20)); !slices.Equal(ids, expected) {
+			t.Errorf("expected %v, got %v", expected, ids)
+		}
+		var buf bytes.Buffer
+		if n, err := generator.WriteBatch(&buf, 20, 320, '\n'); err != nil || n != 8 {
+			t.Errorf("expected 8 IDs written, got %d (err: %v)", n, err)
+		}
+		if ids := generator.BatchGenerateIDs(12, 324); len(ids) != 0 {
+			t.Errorf("expected no IDs from a filtered window, got %v", ids)
+		}
+		if id, err := generator.NextID("fala-b"); err != nil || id != "sodo-0" {
+			t.Errorf("expected sodo-0 after fala-b, got '%s' (err: %v)", id, err)
+		}
+		if id, err := generator.PrevID("sodo-0"); err != nil || id != "fala-b" {
+			t.Errorf("expected fala-b before sodo-0, got '%s' (err: %v)", id, err)
+		}
+
+		sequential, err := generator.NewSequentialGenerator(context.Background(), SequentialConfig{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var issued []string
+		for {
+			id, err := sequential.Next(context.Background())
+			if errors.Is(err, ErrSpaceExhausted) {
+				break
+			}
+			issued = append(issued, id)
+		}
+		if len(issued) != 576 || slices.Contains(issued, "fati-0") {
+			t.Errorf("expected 576 unfiltered IDs, got %d", len(issued))
+		}
+	})
+
+	t.Run("restricted", func(t *testing.T) {
+		restricted := generator.Restrict(Range{Start: 320, End: 340})
+		for i := 0; i < 100; i++ {
+			if id := restricted.NewID(); strings.HasPrefix(id, "fati") {
+				t.Fatalf("generated filtered ID '%s'", id)
+			}
+		}
+		for _, input := range []string{"a", "b", "c", "d", "e"} {
+			if id := restricted.DeriveID(input); id == "" || strings.HasPrefix(id, "fati") {
+				t.Errorf("expected an unfiltered derived ID, got '%s'", id)
+			}
+		}
+
+		filtered := generator.Restrict(Range{Start: 324, End: 336})
+		if id := filtered.NewID(); id != "" {
+			t.Errorf("expected no ID from a filtered range, got '%s'", id)
+		}
+		if id := filtered.DeriveID("a"); id != "" {
+			t.Errorf("expected no derived ID from a filtered range, got '%s'", id)
+		}
+	})
+}
+
+func TestWordFilterLargeSpace(t *testing.T) {
+	generator := New(Config{
+		JustIntonationDigits:   20,
+		EqualTemperamentDigits: 20,
+		Separator:              "-",
+		WordFilter:             WordFilterFunc(func(notes string) bool { return !strings.HasPrefix(notes, "do") }),
+	})
+
+	for i := 0; i < 100; i++ {
+		if id := generator.NewID(); !strings.HasPrefix(id, "do") {
+			t.Fatalf("expected every ID to start with do, got '%s'", id)
+		}
+	}
+
+	rejecting := New(Config{JustIntonationDigits: 2, EqualTemperamentDigits: 1, WordFilter: WordFilterFunc(func(string) bool { return true })})
+	if id := rejecting.NewID(); id != "" {
+		t.Errorf("expected no ID when every note sequence is filtered, got '%s'", id)
+	}
+}